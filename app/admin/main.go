@@ -0,0 +1,78 @@
+// Admin is orvosi-admin, the command-line tool for operators.
+// It looks up users, moves medical records between emails, encodes and decodes hashids,
+// runs the migrations, exports, anonymizes, or erases the data of users.
+// Run it without command to see all commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/db/migrations"
+	"github.com/indrasaputra/orvosi-api/internal/admin"
+	"github.com/indrasaputra/orvosi-api/internal/builder"
+	"github.com/indrasaputra/orvosi-api/internal/cli"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/migration"
+)
+
+func main() {
+	cmd, err := config.ParseCommandLine(os.Args[0], os.Args[1:], os.Stderr)
+	cli.CheckConfigError(err)
+	cfg, err := config.Load(cmd)
+	cli.CheckConfigError(err)
+	if cmd.PrintConfig {
+		cfg.Print(os.Stdout)
+		return
+	}
+	if len(cmd.Args) == 0 {
+		cli.CheckConfigError(errors.New(admin.Usage))
+	}
+
+	hash, err := hashids.NewHashID(cfg.Hashid.MinLength, cfg.Hashid.Salt)
+	cli.CheckError(err)
+	hashids.SetHasher(hash)
+
+	db, err := builder.BuildDatabase(cfg)
+	cli.CheckError(err)
+	if db == nil {
+		log.Fatalf("%s backend keeps nothing to administer", cfg.Database.Backend)
+	}
+	cli.CheckError(builder.PingDatabase(context.Background(), cfg, db))
+	defer db.Close()
+
+	var migrate admin.MigrateFunc
+	if cfg.Database.Backend == builder.DatabaseBackendPostgres {
+		migrator, err := migration.NewMigrator(db, migrations.FS)
+		cli.CheckError(err)
+
+		migrate = func(ctx context.Context, args []string, out io.Writer) error {
+			return migration.RunCommand(ctx, migrator, args, out)
+		}
+		if cfg.Database.RequireLatestSchema && cmd.Args[0] != "migrate" {
+			cli.CheckError(migrator.CheckLatest(context.Background()))
+		}
+	}
+
+	store, err := builder.BuildAttachmentStorage(cfg)
+	cli.CheckError(err)
+
+	cipher, err := builder.BuildFieldCipher(cfg)
+	cli.CheckError(err)
+
+	// the operator expects to see their own change, so everything goes to the primary.
+	backend, err := builder.BuildBackend(cfg, db, nil, cipher)
+	cli.CheckError(err)
+
+	command := builder.BuildAdminCommand(cfg, backend, store, hash, migrate)
+	if err := command.Run(context.Background(), cmd.Args, os.Stdout); err != nil {
+		db.Close()
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
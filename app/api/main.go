@@ -3,71 +3,221 @@ package main
 import (
 	"context"
 	"fmt"
+	"log"
 	"os"
-	"os/signal"
 	"time"
 
 	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/db/migrations"
 	"github.com/indrasaputra/orvosi-api/internal/builder"
+	"github.com/indrasaputra/orvosi-api/internal/cli"
 	"github.com/indrasaputra/orvosi-api/internal/config"
 	"github.com/indrasaputra/orvosi-api/internal/http/middleware"
+	"github.com/indrasaputra/orvosi-api/internal/http/router"
 	"github.com/indrasaputra/orvosi-api/internal/http/server"
+	"github.com/indrasaputra/orvosi-api/internal/lifecycle"
+	"github.com/indrasaputra/orvosi-api/internal/migration"
+	"github.com/indrasaputra/orvosi-api/internal/settings"
 	"github.com/indrasaputra/orvosi-api/internal/tool"
-	_ "github.com/lib/pq"
-)
-
-const (
-	dbDriver           = "postgres"
-	contextTimeoutTime = 5 * time.Second
+	"github.com/indrasaputra/orvosi-api/usecase"
 )
 
 func main() {
-	cfg, err := config.NewConfig(".env")
-	checkError(err)
+	cmd, err := config.ParseCommandLine(os.Args[0], os.Args[1:], os.Stderr)
+	cli.CheckConfigError(err)
+	cfg, err := config.Load(cmd)
+	cli.CheckConfigError(err)
+	if cmd.PrintConfig {
+		cfg.Print(os.Stdout)
+		return
+	}
 
 	hash, err := hashids.NewHashID(cfg.Hashid.MinLength, cfg.Hashid.Salt)
-	checkError(err)
+	cli.CheckError(err)
 	hashids.SetHasher(hash)
 
-	db, err := builder.BuildSQLDatabase(dbDriver, cfg)
-	checkError(err)
+	lc := lifecycle.NewManager()
 
-	jwtDec := tool.NewIDTokenDecoder(cfg.Google.Audience)
-	jwtMidd := middleware.WithJWTDecoder(jwtDec.Decode)
+	db, err := builder.BuildDatabase(cfg)
+	cli.CheckError(err)
+	if db != nil {
+		cli.CheckError(builder.PingDatabase(context.Background(), cfg, db))
+		lc.Register("database pool", func(ctx context.Context) error {
+			return db.Close()
+		})
+	}
+
+	var migrator *migration.Migrator
+	if cfg.Database.Backend == builder.DatabaseBackendPostgres {
+		migrator, err = migration.NewMigrator(db, migrations.FS)
+		cli.CheckError(err)
+	}
+	if len(cmd.Args) > 0 && cmd.Args[0] == "migrate" {
+		runMigration(cfg, migrator, cmd.Args[1:])
+		return
+	}
+	if migrator != nil && cfg.Database.RequireLatestSchema {
+		cli.CheckError(migrator.CheckLatest(context.Background()))
+	}
 
-	signer := builder.BuildSigner(cfg, db)
-	medRecCreator := builder.BuildMedicalRecordCreator(cfg, db)
-	medRecFinder := builder.BuildMedicalRecordFinder(cfg, db)
-	medRecUpdater := builder.BuildMedicalRecordUpdater(cfg, db)
+	store, err := builder.BuildAttachmentStorage(cfg)
+	cli.CheckError(err)
 
-	srv := server.NewServer(jwtMidd, append(append(append(medRecCreator, medRecFinder...), medRecUpdater...), signer...))
-	runServer(srv, cfg.Port)
-	waitForShutdown(srv)
-}
+	cipher, err := builder.BuildFieldCipher(cfg)
+	cli.CheckError(err)
 
-func runServer(srv *server.Server, port string) {
-	go func() {
-		if err := srv.Start(fmt.Sprintf(":%s", port)); err != nil {
-			srv.Logger.Info("shutting down the server")
+	replicas, err := builder.BuildReplicaRouter(cfg, db)
+	cli.CheckError(err)
+	if replicas != nil {
+		lc.Register("replica pools", func(ctx context.Context) error {
+			return replicas.Close()
+		})
+		lc.Go("replica watcher", func(ctx context.Context) {
+			replicas.Watch(ctx, cfg.Database.ReplicaCheckInterval)
+		})
+	}
+
+	backend, err := builder.BuildBackend(cfg, db, replicas, cipher)
+	cli.CheckError(err)
+
+	emails, err := builder.BuildEmailValidator(cfg)
+	cli.CheckError(err)
+
+	rt := settings.NewSettings(cfg.Runtime, func() (config.Runtime, error) {
+		c, err := config.Load(cmd)
+		if err != nil {
+			return config.Runtime{}, err
 		}
-	}()
-}
+		return c.Runtime, nil
+	})
+	lc.Go("settings watcher", func(ctx context.Context) {
+		rt.Watch(ctx, cmd.ConfigFile, cfg.ConfigWatchInterval)
+	})
+
+	eraser := builder.BuildEraseUser(cfg, backend, store)
+	if cfg.Erasure.CheckInterval > 0 {
+		lc.Go("erasure worker", func(ctx context.Context) {
+			runErasures(ctx, eraser, cfg.Erasure.CheckInterval, cfg.Erasure.BatchSize)
+		})
+	}
+
+	sender, err := builder.BuildMailSender(cfg)
+	cli.CheckError(err)
+	if cfg.Mail.Sender == builder.MailSenderLog {
+		log.Printf("[Mail] MAIL_SENDER is %s, so the emails are only written to the log and never sent", builder.MailSenderLog)
+	}
+
+	signer := builder.BuildSignIn(cfg, backend)
+	jwtDec := tool.NewIDTokenDecoder(cfg.Google.Audience)
+	authMidd := middleware.Chain(middleware.WithJWTDecoder(jwtDec.Decode), middleware.WithUserResolver(signer.Resolve))
 
-func waitForShutdown(srv *server.Server) {
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
+	var routes []*router.Route
+	routes = append(routes, builder.BuildMedicalRecordCreator(cfg, backend)...)
+	routes = append(routes, builder.BuildMedicalRecordFinder(cfg, backend, rt)...)
+	routes = append(routes, builder.BuildMedicalRecordUpdater(cfg, backend)...)
+	routes = append(routes, builder.BuildMedicalRecordImporter(cfg, backend)...)
+	routes = append(routes, builder.BuildFHIRMedicalRecord(cfg, backend, rt)...)
+	routes = append(routes, builder.BuildAttachmentUploader(cfg, backend, store)...)
+	routes = append(routes, builder.BuildAttachmentFinder(cfg, backend, store)...)
+	routes = append(routes, builder.BuildAttachmentDeleter(cfg, backend, store)...)
+	routes = append(routes, builder.BuildSigner(cfg, signer)...)
+	routes = append(routes, builder.BuildUserProfile(cfg, backend)...)
+	routes = append(routes, builder.BuildUserEraser(cfg, eraser)...)
+	routes = append(routes, builder.BuildUserDataExporter(cfg, backend, store)...)
+	routes = append(routes, builder.BuildUserEmail(cfg, backend, emails, sender)...)
+	routes = append(routes, builder.BuildUserIdentity(cfg, backend, jwtDec)...)
+	routes = append(routes, builder.BuildHealthChecker(cfg, backend, lc)...)
+	routes = append(routes, builder.BuildAdminConfig(cfg, rt)...)
 
-	<-quit
+	srv := server.NewServer(authMidd, routes, rt, &cfg.HTTP)
+	start, err := buildStarter(cfg, srv, lc)
+	cli.CheckError(err)
+	runServer(srv, start, fmt.Sprintf(":%s", cfg.Port))
+	lc.Register("http server", srv.Shutdown)
 
-	ctx, cancel := context.WithTimeout(context.Background(), contextTimeoutTime)
-	defer cancel()
-	if err := srv.Shutdown(ctx); err != nil {
+	if err := lc.WaitForSignal(cfg.Shutdown.DrainPeriod, cfg.Shutdown.Timeout); err != nil {
 		srv.Logger.Fatal(err)
 	}
 }
 
-func checkError(err error) {
+// buildStarter chooses how the server is started: HTTPS if the certificate is set, h2c if it is asked, or plain HTTP.
+// The client certificate of internal callers is mapped onto their identity if the client authentication is enabled.
+func buildStarter(cfg *config.Config, srv *server.Server, lc *lifecycle.Manager) (func(address string) error, error) {
+	if cfg.TLS.CertFile == "" {
+		if cfg.HTTP.H2C {
+			return srv.StartH2C, nil
+		}
+		return srv.Start, nil
+	}
+
+	certs, err := server.NewCertificateReloader(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	lc.Go("certificate watcher", func(ctx context.Context) {
+		certs.Watch(ctx, cfg.TLS.ReloadInterval)
+	})
+
+	tc, err := server.NewTLSConfig(&cfg.TLS, certs)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	if cfg.TLS.ClientAuth != "none" {
+		identities, err := config.ParsePairs(cfg.TLS.ClientIdentities)
+		if err != nil {
+			return nil, err
+		}
+		srv.Use(middleware.WithClientIdentity(identities))
+	}
+	return func(address string) error {
+		return srv.StartTLSConfig(address, tc)
+	}, nil
+}
+
+// runMigration runs the migrate subcommand.
+// It fails if the database backend has no migration, since the schema of SQLite is applied on start
+// and the memory backend has no schema.
+func runMigration(cfg *config.Config, migrator *migration.Migrator, args []string) {
+	if migrator == nil {
+		fmt.Fprintf(os.Stderr, "the %s database backend has no migration, only %s has\n", cfg.Database.Backend, builder.DatabaseBackendPostgres)
+		os.Exit(1)
 	}
+	if err := migration.RunCommand(context.Background(), migrator, args, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// runErasures erases the users whose grace period has passed every interval until the context is done.
+// A full batch is followed by the next one right away.
+func runErasures(ctx context.Context, eraser usecase.EraseUser, interval time.Duration, batch uint) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				n, err := eraser.EraseDue(ctx, batch)
+				if n > 0 {
+					log.Printf("[Erasure-Worker] erased %d user(s)", n)
+				}
+				if err != nil {
+					log.Printf("[Erasure-Worker] %v", err)
+				}
+				if err != nil || uint(n) < batch || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+func runServer(srv *server.Server, start func(address string) error, address string) {
+	go func() {
+		if err := start(address); err != nil {
+			srv.Logger.Info("shutting down the server")
+		}
+	}()
 }
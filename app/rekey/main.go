@@ -0,0 +1,45 @@
+// Rekey encrypts the clinical text of all medical records using the active master key.
+// It is meant to be run in the background after a new master key is activated,
+// so records which are not read for a long time don't keep the old key alive.
+// Records which are read by the API are already re-encrypted lazily.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/indrasaputra/orvosi-api/internal/builder"
+	"github.com/indrasaputra/orvosi-api/internal/cli"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+)
+
+func main() {
+	cmd, err := config.ParseCommandLine(os.Args[0], os.Args[1:], os.Stderr)
+	cli.CheckConfigError(err)
+	cfg, err := config.Load(cmd)
+	cli.CheckConfigError(err)
+	if cmd.PrintConfig {
+		cfg.Print(os.Stdout)
+		return
+	}
+
+	db, err := builder.BuildDatabase(cfg)
+	cli.CheckError(err)
+	if db == nil {
+		log.Fatalf("%s backend keeps nothing to re-encrypt", cfg.Database.Backend)
+	}
+	cli.CheckError(builder.PingDatabase(context.Background(), cfg, db))
+	defer db.Close()
+
+	cipher, err := builder.BuildFieldCipher(cfg)
+	cli.CheckError(err)
+
+	rekeyer := repository.NewMedicalRecordRekeyer(db, cipher)
+	total, rerr := rekeyer.Rekey(context.Background(), cfg.Encryption.RekeyBatchSize)
+	log.Printf("re-encrypted %d medical record(s)", total)
+	if rerr != nil {
+		log.Fatal(rerr)
+	}
+}
@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS attachments (
+   id                  BIGSERIAL       PRIMARY KEY,
+   medical_record_id   BIGINT          NOT NULL REFERENCES medical_records (id) ON DELETE CASCADE,
+   filename            TEXT            NOT NULL,
+   content_type        VARCHAR(255)    NOT NULL,
+   size                BIGINT          NOT NULL,
+   checksum            CHAR(64)        NOT NULL,
+   storage_key         TEXT            UNIQUE NOT NULL,
+   created_at          TIMESTAMP,
+   updated_at          TIMESTAMP,
+   created_by          VARCHAR(200),
+   updated_by          VARCHAR(200)
+);
+
+CREATE INDEX IF NOT EXISTS index_on_medical_record_id_on_attachments
+ON attachments USING btree (medical_record_id);
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,12 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS erasures (
+   user_id        BIGINT          PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+   requested_at   TIMESTAMP       NOT NULL,
+   erase_after    TIMESTAMP       NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS index_on_erase_after_on_erasures
+ON erasures USING btree (erase_after);
+
+COMMIT;
\ No newline at end of file
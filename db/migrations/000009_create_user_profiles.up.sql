@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS user_profiles (
+   user_id              BIGINT          PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+   display_name         VARCHAR(100)    NOT NULL DEFAULT '',
+   specialty            VARCHAR(100)    NOT NULL DEFAULT '',
+   license_number       VARCHAR(50)     NOT NULL DEFAULT '',
+   timezone             VARCHAR(64)     NOT NULL DEFAULT '',
+   preferred_language   VARCHAR(16)     NOT NULL DEFAULT '',
+   updated_at           TIMESTAMP
+);
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,27 @@
+BEGIN;
+
+DROP TABLE IF EXISTS email_changes;
+
+ALTER TABLE medical_records
+ADD COLUMN email VARCHAR(255);
+
+UPDATE medical_records m SET email = u.email FROM users u WHERE u.id = m.user_id;
+
+ALTER TABLE medical_records
+ALTER COLUMN email SET NOT NULL;
+
+DROP INDEX IF EXISTS index_on_user_id_id_created_at_on_medical_records;
+
+ALTER TABLE medical_records
+DROP COLUMN user_id;
+
+-- the users created for the owners of records who have never signed in are removed,
+-- since the records are owned by the email again.
+DELETE FROM users WHERE google_id = 'unlinked:' || email;
+
+CREATE INDEX IF NOT EXISTS index_on_email_id_created_at_on_medical_records
+ON medical_records USING btree (email, id, created_at);
+
+DROP TABLE IF EXISTS user_identities;
+
+COMMIT;
\ No newline at end of file
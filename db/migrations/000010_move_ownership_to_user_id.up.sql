@@ -0,0 +1,50 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS user_identities (
+   id           BIGSERIAL       PRIMARY KEY,
+   user_id      BIGINT          NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+   provider     VARCHAR(32)     NOT NULL,
+   subject      VARCHAR(255)    NOT NULL,
+   email        VARCHAR(255)    NOT NULL DEFAULT '',
+   created_at   TIMESTAMP,
+   UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS index_on_user_id_on_user_identities
+ON user_identities USING btree (user_id);
+
+INSERT INTO user_identities (user_id, provider, subject, email, created_at)
+SELECT id, 'google', google_id, email, NOW() FROM users;
+
+-- the owner of records whose email has never signed in becomes a user without identity,
+-- who is linked once someone signs in using that email.
+INSERT INTO users (name, email, google_id, created_at, updated_at, created_by, updated_by)
+SELECT DISTINCT '', m.email, 'unlinked:' || m.email, NOW(), NOW(), m.email, m.email
+FROM medical_records m
+WHERE NOT EXISTS (SELECT 1 FROM users u WHERE u.email = m.email);
+
+ALTER TABLE medical_records
+ADD COLUMN user_id BIGINT REFERENCES users (id);
+
+UPDATE medical_records m SET user_id = u.id FROM users u WHERE u.email = m.email;
+
+ALTER TABLE medical_records
+ALTER COLUMN user_id SET NOT NULL;
+
+DROP INDEX IF EXISTS index_on_email_id_created_at_on_medical_records;
+
+ALTER TABLE medical_records
+DROP COLUMN email;
+
+CREATE INDEX IF NOT EXISTS index_on_user_id_id_created_at_on_medical_records
+ON medical_records USING btree (user_id, id, created_at);
+
+CREATE TABLE IF NOT EXISTS email_changes (
+   user_id        BIGINT          PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+   email          VARCHAR(255)    NOT NULL,
+   token_hash     CHAR(64)        NOT NULL,
+   requested_at   TIMESTAMP       NOT NULL,
+   expires_at     TIMESTAMP       NOT NULL
+);
+
+COMMIT;
\ No newline at end of file
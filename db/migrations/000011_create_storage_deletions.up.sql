@@ -0,0 +1,13 @@
+BEGIN;
+
+-- storage_deletions keeps the storage keys whose metadata has been deleted but whose content may not,
+-- so the content is deleted again until it succeeds.
+CREATE TABLE IF NOT EXISTS storage_deletions (
+   storage_key   TEXT          PRIMARY KEY,
+   created_at    TIMESTAMP     NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS index_on_created_at_on_storage_deletions
+ON storage_deletions USING btree (created_at);
+
+COMMIT;
\ No newline at end of file
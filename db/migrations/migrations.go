@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migration files,
+// so the binary can migrate the database without the files being shipped along.
+package migrations
+
+import "embed"
+
+// FS contains all migration files.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,31 @@
+-- The database created before the medical records were owned by user id keeps the owner's email in medical_records.
+-- It is equal to PostgreSQL migration 000010, except that SQLite can't add a NOT NULL column referencing another table,
+-- so the added user_id is only NOT NULL.
+
+CREATE TABLE IF NOT EXISTS user_identities (
+   id           INTEGER         PRIMARY KEY AUTOINCREMENT,
+   user_id      BIGINT          NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+   provider     VARCHAR(32)     NOT NULL,
+   subject      VARCHAR(255)    NOT NULL,
+   email        VARCHAR(255)    NOT NULL DEFAULT '',
+   created_at   TIMESTAMP,
+   UNIQUE (provider, subject)
+);
+
+INSERT INTO user_identities (user_id, provider, subject, email, created_at)
+SELECT id, 'google', google_id, email, CURRENT_TIMESTAMP FROM users;
+
+INSERT INTO users (name, email, google_id, created_at, updated_at, created_by, updated_by)
+SELECT DISTINCT '', m.email, 'unlinked:' || m.email, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, m.email, m.email
+FROM medical_records m
+WHERE NOT EXISTS (SELECT 1 FROM users u WHERE u.email = m.email);
+
+ALTER TABLE medical_records
+ADD COLUMN user_id BIGINT NOT NULL DEFAULT 0;
+
+UPDATE medical_records SET user_id = (SELECT u.id FROM users u WHERE u.email = medical_records.email);
+
+DROP INDEX IF EXISTS index_on_email_id_created_at_on_medical_records;
+
+ALTER TABLE medical_records
+DROP COLUMN email;
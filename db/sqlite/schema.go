@@ -0,0 +1,48 @@
+// Package sqlite holds the schema of SQLite database.
+// The schema is equal to the result of all PostgreSQL migrations in db/migrations.
+// It is applied as a whole every time the database is opened, so it must stay idempotent.
+// The database created by an older schema which can't be changed idempotently is upgraded before.
+package sqlite
+
+import (
+	"database/sql"
+	_ "embed" // embed schema.sql
+)
+
+// Schema is the whole SQLite schema.
+//
+//go:embed schema.sql
+var Schema string
+
+// OwnershipUpgrade moves the ownership of medical records from the owner's email to their user id.
+// It must be applied only once, to the database whose medical_records still has email column.
+//
+//go:embed ownership_upgrade.sql
+var OwnershipUpgrade string
+
+// Apply upgrades the database created by an older schema, if needed, then applies the schema.
+// The upgrade runs in a single transaction, so it either completes or leaves the database as is.
+func Apply(db *sql.DB) error {
+	var legacy int
+	row := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('medical_records') WHERE name = 'email'")
+	if err := row.Scan(&legacy); err != nil {
+		return err
+	}
+
+	if legacy > 0 {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(OwnershipUpgrade); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	_, err := db.Exec(Schema)
+	return err
+}
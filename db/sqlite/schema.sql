@@ -0,0 +1,91 @@
+CREATE TABLE IF NOT EXISTS users (
+   id          INTEGER        PRIMARY KEY AUTOINCREMENT,
+   "name"      TEXT           NOT NULL,
+   email       TEXT           UNIQUE NOT NULL,
+   google_id   TEXT           UNIQUE NOT NULL,
+   created_at  TIMESTAMP,
+   updated_at  TIMESTAMP,
+   created_by  VARCHAR(200),
+   updated_by  VARCHAR(200)
+);
+
+CREATE TABLE IF NOT EXISTS medical_records (
+   id           INTEGER         PRIMARY KEY AUTOINCREMENT,
+   user_id      BIGINT          NOT NULL REFERENCES users (id),
+   symptom      TEXT            NOT NULL,
+   diagnosis    TEXT            NOT NULL,
+   therapy      TEXT            NOT NULL,
+   result       TEXT            NOT NULL,
+   created_at   TIMESTAMP,
+   updated_at   TIMESTAMP,
+   created_by   VARCHAR(200),
+   updated_by   VARCHAR(200)
+);
+
+CREATE INDEX IF NOT EXISTS index_on_user_id_id_created_at_on_medical_records
+ON medical_records (user_id, id, created_at);
+
+CREATE TABLE IF NOT EXISTS attachments (
+   id                  INTEGER         PRIMARY KEY AUTOINCREMENT,
+   medical_record_id   BIGINT          NOT NULL REFERENCES medical_records (id) ON DELETE CASCADE,
+   filename            TEXT            NOT NULL,
+   content_type        VARCHAR(255)    NOT NULL,
+   size                BIGINT          NOT NULL,
+   checksum            CHAR(64)        NOT NULL,
+   storage_key         TEXT            UNIQUE NOT NULL,
+   created_at          TIMESTAMP,
+   updated_at          TIMESTAMP,
+   created_by          VARCHAR(200),
+   updated_by          VARCHAR(200)
+);
+
+CREATE INDEX IF NOT EXISTS index_on_medical_record_id_on_attachments
+ON attachments (medical_record_id);
+
+CREATE TABLE IF NOT EXISTS erasures (
+   user_id        BIGINT          PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+   requested_at   TIMESTAMP       NOT NULL,
+   erase_after    TIMESTAMP       NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS index_on_erase_after_on_erasures
+ON erasures (erase_after);
+
+CREATE TABLE IF NOT EXISTS user_profiles (
+   user_id              BIGINT          PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+   display_name         VARCHAR(100)    NOT NULL DEFAULT '',
+   specialty            VARCHAR(100)    NOT NULL DEFAULT '',
+   license_number       VARCHAR(50)     NOT NULL DEFAULT '',
+   timezone             VARCHAR(64)     NOT NULL DEFAULT '',
+   preferred_language   VARCHAR(16)     NOT NULL DEFAULT '',
+   updated_at           TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS user_identities (
+   id           INTEGER         PRIMARY KEY AUTOINCREMENT,
+   user_id      BIGINT          NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+   provider     VARCHAR(32)     NOT NULL,
+   subject      VARCHAR(255)    NOT NULL,
+   email        VARCHAR(255)    NOT NULL DEFAULT '',
+   created_at   TIMESTAMP,
+   UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS index_on_user_id_on_user_identities
+ON user_identities (user_id);
+
+CREATE TABLE IF NOT EXISTS email_changes (
+   user_id        BIGINT          PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+   email          VARCHAR(255)    NOT NULL,
+   token_hash     CHAR(64)        NOT NULL,
+   requested_at   TIMESTAMP       NOT NULL,
+   expires_at     TIMESTAMP       NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS storage_deletions (
+   storage_key   TEXT          PRIMARY KEY,
+   created_at    TIMESTAMP     NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS index_on_created_at_on_storage_deletions
+ON storage_deletions (created_at);
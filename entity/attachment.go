@@ -0,0 +1,18 @@
+package entity
+
+import "github.com/indrasaputra/hashids"
+
+// Attachment holds the metadata of a file attached to a medical record,
+// such as lab result or scan.
+// The file itself is kept in a storage and referenced by StorageKey.
+type Attachment struct {
+	ID              hashids.ID
+	MedicalRecordID hashids.ID
+	Filename        string
+	ContentType     string
+	Size            int64
+	// Checksum is the hex encoded SHA-256 of the file.
+	Checksum   string
+	StorageKey string
+	Auditable
+}
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/indrasaputra/hashids"
+)
+
+// EmailChange holds the request of a user to change their email into Email.
+// The email is changed once the token sent to Email is verified before ExpiresAt.
+// Only the hash of the token is kept.
+type EmailChange struct {
+	UserID      hashids.ID
+	Email       string
+	TokenHash   string
+	RequestedAt time.Time
+	ExpiresAt   time.Time
+}
@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/indrasaputra/hashids"
+)
+
+// Erasure holds the request of a user to erase all of their data.
+// The data is erased once EraseAfter has passed, unless the user cancels the request before.
+type Erasure struct {
+	UserID      hashids.ID
+	Email       string
+	RequestedAt time.Time
+	EraseAfter  time.Time
+}
@@ -5,45 +5,150 @@ import "fmt"
 var (
 	// ErrInternalServer indicates there is unexpected problem occurs in the system itself.
 	// The detail of the error/problem should be known in internal message.
-	ErrInternalServer = NewError("01-001", "Internal server error")
+	ErrInternalServer = NewError(KindInternal, "01-001", "Internal server error")
 	// ErrUnauthorized is returned when a request doesn't include authorization in its header.
 	// The authorization must be using bearer authorization.
 	// It also can be returned if the authorization is invalid.
-	ErrUnauthorized = NewError("01-002", "Request is unauthorized")
+	ErrUnauthorized = NewError(KindUnauthorized, "01-002", "Request is unauthorized")
 	// ErrInvalidGoogleToken is returned when the id token is invalid,
 	// whether it has expired or it is not google id token.
-	ErrInvalidGoogleToken = NewError("01-003", "Google ID Token is invalid")
+	ErrInvalidGoogleToken = NewError(KindUnauthorized, "01-003", "Google ID Token is invalid")
 	// ErrInvalidID is returned when the entity id can't be decoded or invalid.
-	ErrInvalidID = NewError("01-004", "Entity ID is invalid")
+	ErrInvalidID = NewError(KindValidation, "01-004", "Entity ID is invalid")
 	// ErrWrongContentType is returned when content-type in request's header is not as expected.
-	ErrWrongContentType = NewError("01-005", "Wrong content type")
+	ErrWrongContentType = NewError(KindUnsupportedMediaType, "01-005", "Wrong content type")
+	// ErrNotAcceptable is returned when none of the representations in request's Accept header is supported.
+	ErrNotAcceptable = NewError(KindNotAcceptable, "01-006", "Requested representation is not supported")
+	// ErrUnsupportedResourceType is returned when the requested FHIR resource type is not supported.
+	ErrUnsupportedResourceType = NewError(KindNotFound, "01-007", "Resource type is not supported")
+	// ErrServiceUnavailable is returned when a dependency of the system, such as the database, can't be reached.
+	ErrServiceUnavailable = NewError(KindUnavailable, "01-008", "Service is unavailable")
+	// ErrAlreadyExists is returned when the data conflicts with another data which must be unique.
+	ErrAlreadyExists = NewError(KindConflict, "01-009", "Data already exists")
+	// ErrConcurrentUpdate is returned when the data is being changed by another request at the same time.
+	// The request can be sent again.
+	ErrConcurrentUpdate = NewError(KindConflict, "01-010", "Data is being updated by another request. Please, try again")
+	// ErrTimeout is returned when the system doesn't finish the request in time.
+	ErrTimeout = NewError(KindUnavailable, "01-011", "Request timed out")
+	// ErrForbidden is returned when the user is known but isn't allowed to access the resource.
+	ErrForbidden = NewError(KindForbidden, "01-012", "Access to the resource is forbidden")
+	// ErrTooManyRequests is returned when the client sends more requests than the rate limit allows.
+	ErrTooManyRequests = NewError(KindTooManyRequests, "01-013", "Too many requests. Please, try again later")
+	// ErrRequestTooLarge is returned when the request body is larger than the limit of the route.
+	ErrRequestTooLarge = NewError(KindTooLarge, "01-014", "Request body is too large")
+	// ErrShuttingDown is returned by readiness check when the application is shutting down.
+	ErrShuttingDown = NewError(KindUnavailable, "01-015", "Service is shutting down")
+	// ErrRouteNotFound is returned when no route matches the path of the request.
+	ErrRouteNotFound = NewError(KindNotFound, "01-016", "Route not found")
+	// ErrMethodNotAllowed is returned when the route of the path doesn't accept the method of the request.
+	ErrMethodNotAllowed = NewError(KindMethodNotAllowed, "01-017", "Method is not allowed")
+	// ErrBadRequest is returned when the request can't be read, such as its body is malformed.
+	ErrBadRequest = NewError(KindValidation, "01-018", "Request is malformed")
 
 	// ErrEmptyMedicalRecord indicates that a medical record is empty or null.
-	ErrEmptyMedicalRecord = NewError("02-001", "MedicalRecord is empty")
+	ErrEmptyMedicalRecord = NewError(KindValidation, "02-001", "MedicalRecord is empty")
 	// ErrInvalidMedicalRecordAttribute indicates that a medical record is empty or null.
-	ErrInvalidMedicalRecordAttribute = NewError("02-002", "Medical record's attributes are invalid. Please, check all attributes")
+	ErrInvalidMedicalRecordAttribute = NewError(KindValidation, "02-002", "Medical record's attributes are invalid. Please, check all attributes")
 	// ErrInvalidMedicalRecordRequest indicates that a medical record request that is sent over HTTP is invalid.
-	ErrInvalidMedicalRecordRequest = NewError("02-003", "Medical record request is invalid. Please, check the JSON request")
+	ErrInvalidMedicalRecordRequest = NewError(KindValidation, "02-003", "Medical record request is invalid. Please, check the JSON request")
 	// ErrInvalidEmail indicates that the email passed is invalid.
 	// The validation is run using regex "^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$"
-	// and LookupMX from net package.
-	ErrInvalidEmail = NewError("02-004", "Email is invalid. Please, check the email")
+	// and, if it is enabled, the MX records of its domain.
+	ErrInvalidEmail = NewError(KindValidation, "02-004", "Email is invalid. Please, check the email")
 	// ErrMedicalRecordNotFound indicates that the medical record can't be found.
-	ErrMedicalRecordNotFound = NewError("02-005", "Medical record not found")
+	ErrMedicalRecordNotFound = NewError(KindNotFound, "02-005", "Medical record not found")
 	// ErrInvalidParam indicates that the query param(s) is invalid.
-	ErrInvalidParam = NewError("02-006", "Query param(s) is invalid")
+	ErrInvalidParam = NewError(KindValidation, "02-006", "Query param(s) is invalid")
+	// ErrInvalidImportFile indicates that the uploaded import file can't be read
+	// or its format (CSV or NDJSON) is not recognized.
+	ErrInvalidImportFile = NewError(KindValidation, "02-007", "Import file is invalid. Please, check the file and its format")
+	// ErrInvalidImportRow indicates that a single row in the import file can't be parsed.
+	ErrInvalidImportRow = NewError(KindValidation, "02-008", "Import row can't be parsed")
 
 	// ErrEmptyUser indicates that a user is empty or null.
-	ErrEmptyUser = NewError("03-001", "User is empty")
+	ErrEmptyUser = NewError(KindValidation, "03-001", "User is empty")
+	// ErrUserNotFound indicates that the user can't be found.
+	ErrUserNotFound = NewError(KindNotFound, "03-002", "User not found")
+	// ErrErasureNotScheduled indicates that the user has no pending erasure request.
+	ErrErasureNotScheduled = NewError(KindNotFound, "03-003", "Erasure is not scheduled")
+	// ErrInvalidProfileRequest indicates that a profile request that is sent over HTTP is invalid.
+	ErrInvalidProfileRequest = NewError(KindValidation, "03-004", "Profile request is invalid. Please, check the JSON request")
+	// ErrInvalidProfileAttribute indicates that some attributes of the profile are invalid.
+	ErrInvalidProfileAttribute = NewError(KindValidation, "03-005", "Profile's attributes are invalid. Please, check all attributes")
+	// ErrIdentityAlreadyLinked indicates that the identity is already linked to another user.
+	ErrIdentityAlreadyLinked = NewError(KindConflict, "03-006", "Identity is already linked to another user")
+	// ErrIdentityNotFound indicates that the identity can't be found.
+	ErrIdentityNotFound = NewError(KindNotFound, "03-007", "Identity not found")
+	// ErrLastIdentity indicates that the only identity of the user is about to be unlinked.
+	ErrLastIdentity = NewError(KindConflict, "03-008", "The only identity of the user can't be unlinked")
+	// ErrInvalidIdentityRequest indicates that an identity request that is sent over HTTP is invalid.
+	ErrInvalidIdentityRequest = NewError(KindValidation, "03-009", "Identity request is invalid. Please, check the JSON request")
+	// ErrInvalidEmailChangeRequest indicates that an email change request that is sent over HTTP is invalid.
+	ErrInvalidEmailChangeRequest = NewError(KindValidation, "03-010", "Email change request is invalid. Please, check the JSON request")
+	// ErrInvalidEmailChangeToken indicates that the email verification token is wrong, expired, or not requested at all.
+	ErrInvalidEmailChangeToken = NewError(KindValidation, "03-011", "Email verification token is invalid or has expired")
+	// ErrEmailAlreadyUsed indicates that the email belongs to another user.
+	ErrEmailAlreadyUsed = NewError(KindConflict, "03-012", "Email is already used by another user")
+
+	// ErrEmptyAttachment indicates that the uploaded attachment is empty or missing.
+	ErrEmptyAttachment = NewError(KindValidation, "04-001", "Attachment is empty")
+	// ErrAttachmentTooLarge indicates that the uploaded attachment exceeds the size limit.
+	ErrAttachmentTooLarge = NewError(KindTooLarge, "04-002", "Attachment is too large")
+	// ErrUnsupportedAttachmentType indicates that the content type of the uploaded attachment is not allowed.
+	// The content type is sniffed from the content, not taken from the request.
+	ErrUnsupportedAttachmentType = NewError(KindUnsupportedMediaType, "04-003", "Attachment type is not supported. Only PDF and image are allowed")
+	// ErrAttachmentChecksumMismatch indicates that the attachment's content doesn't match its checksum.
+	ErrAttachmentChecksumMismatch = NewError(KindValidation, "04-004", "Attachment checksum doesn't match")
+	// ErrAttachmentNotFound indicates that the attachment can't be found.
+	ErrAttachmentNotFound = NewError(KindNotFound, "04-005", "Attachment not found")
+	// ErrInvalidAttachmentRequest indicates that the attachment upload request is invalid.
+	ErrInvalidAttachmentRequest = NewError(KindValidation, "04-006", "Attachment request is invalid. Please, check the multipart form")
+)
+
+// Kind classifies errors by their cause,
+// so the delivery layer knows how to respond without knowing every error.
+type Kind int
+
+const (
+	// KindInternal is the kind of unexpected problem in the system itself.
+	// It is the zero value, so an error without kind is treated as internal.
+	KindInternal Kind = iota
+	// KindValidation is the kind of error caused by invalid input from the user.
+	KindValidation
+	// KindNotFound is the kind of error when the requested resource doesn't exist.
+	KindNotFound
+	// KindUnauthorized is the kind of error when the user can't be identified.
+	KindUnauthorized
+	// KindForbidden is the kind of error when the user is identified but isn't allowed to do the action.
+	KindForbidden
+	// KindConflict is the kind of error when the action conflicts with the current state of the resource.
+	KindConflict
+	// KindUnavailable is the kind of error when a dependency of the system can't serve the request for now.
+	KindUnavailable
+	// KindTooLarge is the kind of error when the input exceeds the size limit.
+	KindTooLarge
+	// KindUnsupportedMediaType is the kind of error when the format of the input isn't supported.
+	KindUnsupportedMediaType
+	// KindNotAcceptable is the kind of error when none of the requested representations is supported.
+	KindNotAcceptable
+	// KindTooManyRequests is the kind of error when the user sends requests faster than allowed.
+	KindTooManyRequests
+	// KindMethodNotAllowed is the kind of error when the resource doesn't accept the method of the request.
+	KindMethodNotAllowed
 )
 
 // Error represents a data structure for error.
 type Error struct {
+	// Kind classifies the error. It is not exposed to the user.
+	Kind Kind `json:"-"`
 	// Code represents error code.
 	Code string `json:"code"`
 	// Message represents error message.
 	// This is the message that exposed to the user.
 	Message string `json:"message"`
+	// InvalidParams lists the fields of the request that fail the validation.
+	// It is only exposed in problem details response.
+	InvalidParams []InvalidParam `json:"-"`
 	// internalMessage represents deep error message.
 	// This is should not be exposed to the user directly.
 	// This attributes should be used as log.
@@ -51,8 +156,9 @@ type Error struct {
 }
 
 // NewError creates an instance of Error.
-func NewError(code, message string) *Error {
+func NewError(kind Kind, code, message string) *Error {
 	return &Error{
+		Kind:            kind,
 		Code:            code,
 		Message:         message,
 		internalMessage: message,
@@ -69,8 +175,47 @@ func (err *Error) Error() string {
 // and can be accessed via Error() method.
 func WrapError(err *Error, message string) *Error {
 	return &Error{
+		Kind:            err.Kind,
 		Code:            err.Code,
 		Message:         err.Message,
+		InvalidParams:   err.InvalidParams,
 		internalMessage: fmt.Sprintf("%s. %s", err.internalMessage, message),
 	}
 }
+
+// InvalidParam represents a field of the request that fails the validation.
+type InvalidParam struct {
+	// Name is the name of the field as it is sent by the user.
+	Name string `json:"name"`
+	// Rule is the validation rule that the field fails, such as required.
+	Rule string `json:"rule"`
+	// Reason explains why the field fails the rule.
+	Reason string `json:"reason"`
+	// Param is the parameter of the rule, such as the maximum length, which fills the translated reason.
+	// It is not exposed to the user.
+	Param string `json:"-"`
+}
+
+// WithInvalidParams copies Error and attaches the invalid params to the copy.
+// The given Error is left untouched, since it is mostly a shared variable.
+func WithInvalidParams(err *Error, params []InvalidParam) *Error {
+	return &Error{
+		Kind:            err.Kind,
+		Code:            err.Code,
+		Message:         err.Message,
+		InvalidParams:   params,
+		internalMessage: fmt.Sprintf("%s. %d param(s) are invalid", err.internalMessage, len(params)),
+	}
+}
+
+// WithMessage copies Error and replaces the public message of the copy, such as by its translation.
+// The internal message is kept, so the log stays the same regardless of the user's language.
+func WithMessage(err *Error, message string) *Error {
+	return &Error{
+		Kind:            err.Kind,
+		Code:            err.Code,
+		Message:         message,
+		InvalidParams:   err.InvalidParams,
+		internalMessage: err.internalMessage,
+	}
+}
@@ -10,9 +10,10 @@ import (
 
 func TestNewError(t *testing.T) {
 	t.Run("successfully create an instance of Error", func(t *testing.T) {
-		err := entity.NewError("01-001", "Internal server error")
+		err := entity.NewError(entity.KindInternal, "01-001", "Internal server error")
 
 		assert.NotNil(t, err)
+		assert.Equal(t, entity.KindInternal, err.Kind)
 		assert.Equal(t, "01-001", err.Code)
 		assert.Equal(t, "Internal server error", err.Message)
 		assert.Equal(t, "Internal server error", err.Error())
@@ -27,7 +28,7 @@ func TestError_Error(t *testing.T) {
 		}
 
 		for _, msg := range messages {
-			err := entity.NewError("01-001", msg)
+			err := entity.NewError(entity.KindInternal, "01-001", msg)
 			assert.Equal(t, msg, err.Error())
 		}
 	})
@@ -39,7 +40,7 @@ func TestError_Error(t *testing.T) {
 		}
 
 		for _, msg := range messages {
-			err := entity.NewError("01-001", "public message")
+			err := entity.NewError(entity.KindInternal, "01-001", "public message")
 			err = entity.WrapError(err, msg)
 
 			assert.Equal(t, fmt.Sprintf("public message. %s", msg), err.Error())
@@ -48,16 +49,17 @@ func TestError_Error(t *testing.T) {
 }
 
 func TestWrapError(t *testing.T) {
-	t.Run("new error has the same code and public message as base", func(t *testing.T) {
-		ori := entity.NewError("01-001", "initial message")
+	t.Run("new error has the same kind, code, and public message as base", func(t *testing.T) {
+		ori := entity.NewError(entity.KindNotFound, "02-005", "initial message")
 
 		err := entity.WrapError(ori, "additional message #1")
+		assert.Equal(t, ori.Kind, err.Kind)
 		assert.Equal(t, ori.Code, err.Code)
 		assert.Equal(t, ori.Message, err.Message)
 	})
 
 	t.Run("message is wrapped exactly at the end of current message and separated by :", func(t *testing.T) {
-		err := entity.NewError("01-001", "initial message")
+		err := entity.NewError(entity.KindInternal, "01-001", "initial message")
 
 		err = entity.WrapError(err, "additional message #1")
 		assert.Equal(t, "initial message. additional message #1", err.Error())
@@ -66,3 +68,38 @@ func TestWrapError(t *testing.T) {
 		assert.Equal(t, "initial message. additional message #1. additional message #2", err.Error())
 	})
 }
+
+func TestWithInvalidParams(t *testing.T) {
+	params := []entity.InvalidParam{{Name: "symptom", Rule: "required", Reason: "must not be blank"}}
+
+	t.Run("base error is left untouched", func(t *testing.T) {
+		ori := entity.NewError(entity.KindValidation, "02-002", "initial message")
+
+		err := entity.WithInvalidParams(ori, params)
+		assert.Equal(t, ori.Kind, err.Kind)
+		assert.Equal(t, ori.Code, err.Code)
+		assert.Equal(t, ori.Message, err.Message)
+		assert.Equal(t, params, err.InvalidParams)
+		assert.Nil(t, ori.InvalidParams)
+	})
+
+	t.Run("wrapped error keeps the invalid params", func(t *testing.T) {
+		err := entity.WithInvalidParams(entity.NewError(entity.KindValidation, "02-002", "initial message"), params)
+
+		err = entity.WrapError(err, "additional message")
+		assert.Equal(t, params, err.InvalidParams)
+	})
+}
+
+func TestWithMessage(t *testing.T) {
+	t.Run("only public message is replaced", func(t *testing.T) {
+		ori := entity.WrapError(entity.NewError(entity.KindNotFound, "02-005", "initial message"), "internal")
+
+		err := entity.WithMessage(ori, "pesan")
+		assert.Equal(t, ori.Kind, err.Kind)
+		assert.Equal(t, ori.Code, err.Code)
+		assert.Equal(t, "pesan", err.Message)
+		assert.Equal(t, ori.Error(), err.Error())
+		assert.Equal(t, "initial message", ori.Message)
+	})
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/indrasaputra/hashids"
+)
+
+// ProviderGoogle is the identity provider of Google ID token.
+const ProviderGoogle = "google"
+
+// Identity links the subject of an identity provider to a user.
+// A user may have more than one identity, so they can sign in using any of them.
+// Email is the email which the provider told when the identity was linked.
+type Identity struct {
+	ID        hashids.ID
+	UserID    hashids.ID
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
@@ -14,10 +14,15 @@ type MedicalRecord struct {
 }
 
 // User holds user's information.
+// Email, Name, and GoogleID are taken from the ID token on every sign-in,
+// while Profile is filled in by the user.
+// EmailVerified is only known for the user decoded from the ID token and is never stored.
 type User struct {
-	ID       hashids.ID
-	Email    string
-	Name     string
-	GoogleID string
+	ID            hashids.ID
+	Email         string
+	EmailVerified bool
+	Name          string
+	GoogleID      string
+	Profile       Profile
 	Auditable
 }
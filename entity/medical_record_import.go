@@ -0,0 +1,27 @@
+package entity
+
+// ImportReport holds the result of importing medical records in bulk.
+type ImportReport struct {
+	// DryRun tells whether the import only validated the rows without persisting them.
+	DryRun bool
+	// Total is the number of rows read from the source.
+	Total int
+	// Valid is the number of rows that pass the validation.
+	Valid int
+	// Imported is the number of rows that are persisted.
+	// It is always zero on dry run.
+	Imported int
+	// Errors holds the rows that can't be imported and the reason.
+	// Only the first of them are kept, so a broken file doesn't make a huge report.
+	Errors []*ImportRowError
+	// OmittedErrors is the number of rows that can't be imported but are left out of Errors.
+	OmittedErrors int
+}
+
+// ImportRowError represents the error of a single row in an import.
+type ImportRowError struct {
+	// Row is the 1-based position of the data row in the source.
+	Row int
+	// Err is the reason why the row can't be imported.
+	Err *Error
+}
@@ -0,0 +1,21 @@
+package entity
+
+// Profile holds the details which the user fills in about themselves.
+// An empty field is not set.
+type Profile struct {
+	DisplayName       string
+	Specialty         string
+	LicenseNumber     string
+	Timezone          string
+	PreferredLanguage string
+}
+
+// ProfileUpdate holds the fields of a profile to be changed.
+// A nil field is kept as is, while an empty one is cleared.
+type ProfileUpdate struct {
+	DisplayName       *string
+	Specialty         *string
+	LicenseNumber     *string
+	Timezone          *string
+	PreferredLanguage *string
+}
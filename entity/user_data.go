@@ -0,0 +1,17 @@
+package entity
+
+// UserData holds everything stored about a user,
+// such as for exporting it to the user or the operator.
+type UserData struct {
+	User           *User
+	MedicalRecords []*MedicalRecord
+	// Attachments holds the metadata of the attachments of all medical records.
+	// The content is kept in the storage.
+	Attachments []*Attachment
+	// Erasure is the pending erasure request of the user, if any.
+	Erasure *Erasure
+	// Identities holds the identities the user signs in with.
+	Identities []*Identity
+	// EmailChange is the pending email change request of the user, if any.
+	EmailChange *EmailChange
+}
@@ -3,14 +3,20 @@ module github.com/indrasaputra/orvosi-api
 go 1.16
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/DATA-DOG/go-sqlmock v1.5.0
 	github.com/golang/mock v1.5.0
 	github.com/indrasaputra/hashids v0.1.0
-	github.com/joeshaw/envdecode v0.0.0-20200121155833-099f1fc765bd
+	github.com/jackc/pgconn v1.14.3
+	github.com/jackc/pgx/v4 v4.18.3
 	github.com/joho/godotenv v1.3.0
 	github.com/labstack/echo/v4 v4.2.1
-	github.com/lib/pq v1.10.0
-	github.com/pkg/errors v0.9.1
-	github.com/stretchr/testify v1.7.0
+	github.com/labstack/gommon v0.3.0
+	github.com/mattn/go-sqlite3 v1.14.16
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/stretchr/testify v1.8.1
+	golang.org/x/net v0.21.0
+	golang.org/x/time v0.0.0-20201208040808-7e3f01d25324
 	google.golang.org/api v0.42.0
+	gopkg.in/yaml.v3 v3.0.1
 )
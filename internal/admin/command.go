@@ -0,0 +1,233 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/usecase"
+)
+
+const defaultUserLimit = uint(50)
+
+// Usage describes the subcommands of orvosi-admin.
+const Usage = `usage: orvosi-admin [flags] <command>
+
+commands:
+  users list [from] [limit]      list users whose id is greater than from (default 0 and 50)
+  users find <email>             show the user who has the email
+  records reassign <from> <to>   move all medical records of email from to email to, who must be registered
+  hashid encode <id>...          encode ids using the configured salt
+  hashid decode <hash>...        decode hashids using the configured salt
+  migrate <command>              run the database migrations, see "orvosi-admin migrate"
+  export <email>                 write everything stored about the user as JSON
+  anonymize <email> --yes        replace the identity of the user with an alias. It can't be undone
+  erasures run                   erase the data of the users whose erasure grace period has passed`
+
+// Hasher encodes and decodes ids, such as *hashids.HashID.
+type Hasher interface {
+	// Encode encodes the id into its hash.
+	Encode(id hashids.ID) ([]byte, error)
+	// Decode decodes the hash into its id.
+	Decode(hash []byte) (hashids.ID, error)
+}
+
+// MigrateFunc runs the migrate subcommand using its args and writes the result to out,
+// such as migration.RunCommand using a migrator.
+type MigrateFunc func(ctx context.Context, args []string, out io.Writer) error
+
+// Command holds what the subcommands need.
+type Command struct {
+	Users      usecase.FindUser
+	Reassigner usecase.ReassignMedicalRecord
+	Exporter   usecase.ExportUserData
+	Anonymizer usecase.AnonymizeUser
+	Eraser     usecase.EraseUser
+	// EraseBatch is the maximum number of users erased at once.
+	EraseBatch uint
+	Hasher     Hasher
+	// Migrate is nil if the database backend has no migration.
+	Migrate MigrateFunc
+}
+
+// Run runs the subcommand using its args and writes the result to out.
+func (c *Command) Run(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(Usage)
+	}
+
+	switch args[0] {
+	case "users":
+		return c.runUsers(ctx, args[1:], out)
+	case "records":
+		return c.runRecords(ctx, args[1:], out)
+	case "hashid":
+		return c.runHashid(args[1:], out)
+	case "migrate":
+		if c.Migrate == nil {
+			return errors.New("the database backend has no migration")
+		}
+		return c.Migrate(ctx, args[1:], out)
+	case "export":
+		if len(args) != 2 {
+			return errors.New(Usage)
+		}
+		return c.export(ctx, args[1], out)
+	case "anonymize":
+		if len(args) < 2 || len(args) > 3 {
+			return errors.New(Usage)
+		}
+		if len(args) != 3 || args[2] != "--yes" {
+			return errors.New("anonymization can't be undone. Run it again with --yes to confirm")
+		}
+		return c.anonymize(ctx, args[1], out)
+	case "erasures":
+		if len(args) != 2 || args[1] != "run" {
+			return errors.New(Usage)
+		}
+		return c.eraseDue(ctx, out)
+	}
+	return errors.New(Usage)
+}
+
+func (c *Command) runUsers(ctx context.Context, args []string, out io.Writer) error {
+	switch {
+	case len(args) >= 1 && len(args) <= 3 && args[0] == "list":
+		from, limit := uint64(0), defaultUserLimit
+		if len(args) >= 2 {
+			var err error
+			if from, err = strconv.ParseUint(args[1], 10, 64); err != nil {
+				return fmt.Errorf("from %q is not a number", args[1])
+			}
+		}
+		if len(args) == 3 {
+			n, err := strconv.ParseUint(args[2], 10, 32)
+			if err != nil {
+				return fmt.Errorf("limit %q is not a number", args[2])
+			}
+			limit = uint(n)
+		}
+
+		users, err := c.Users.FindAll(ctx, from, limit)
+		if err != nil {
+			return err
+		}
+		return c.writeUsers(out, users)
+
+	case len(args) == 2 && args[0] == "find":
+		user, err := c.Users.FindByEmail(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		return c.writeUsers(out, []*entity.User{user})
+	}
+	return errors.New(Usage)
+}
+
+func (c *Command) runRecords(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) != 3 || args[0] != "reassign" {
+		return errors.New(Usage)
+	}
+
+	total, err := c.Reassigner.Reassign(ctx, args[1], args[2])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "reassigned %d medical record(s) from %s to %s\n", total, args[1], args[2])
+	return nil
+}
+
+func (c *Command) runHashid(args []string, out io.Writer) error {
+	if len(args) < 2 {
+		return errors.New(Usage)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+	switch args[0] {
+	case "encode":
+		fmt.Fprintln(w, "ID\tHASHID")
+		for _, arg := range args[1:] {
+			id, err := strconv.ParseUint(arg, 10, 64)
+			if err != nil {
+				return fmt.Errorf("id %q is not a number", arg)
+			}
+			hash, err := c.Hasher.Encode(hashids.ID(id))
+			if err != nil {
+				return fmt.Errorf("encode %d: %w", id, err)
+			}
+			fmt.Fprintf(w, "%d\t%s\n", id, hash)
+		}
+		return nil
+
+	case "decode":
+		fmt.Fprintln(w, "HASHID\tID")
+		for _, arg := range args[1:] {
+			id, err := c.Hasher.Decode([]byte(arg))
+			if err != nil {
+				return fmt.Errorf("decode %q: %w", arg, err)
+			}
+			fmt.Fprintf(w, "%s\t%d\n", arg, uint64(id))
+		}
+		return nil
+	}
+	return errors.New(Usage)
+}
+
+func (c *Command) export(ctx context.Context, email string, out io.Writer) error {
+	data, err := c.Exporter.Export(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(handler.NewUserDataResponse(data, time.Now().UTC()))
+}
+
+func (c *Command) anonymize(ctx context.Context, email string, out io.Writer) error {
+	alias, err := c.Anonymizer.Anonymize(ctx, email)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "anonymized %s as %s\n", email, alias)
+	return nil
+}
+
+// eraseDue erases in batches until no erasure is due.
+func (c *Command) eraseDue(ctx context.Context, out io.Writer) error {
+	total := 0
+	for {
+		n, err := c.Eraser.EraseDue(ctx, c.EraseBatch)
+		total += n
+		if err != nil {
+			fmt.Fprintf(out, "erased %d user(s)\n", total)
+			return err
+		}
+		if n == 0 || uint(n) < c.EraseBatch {
+			fmt.Fprintf(out, "erased %d user(s)\n", total)
+			return nil
+		}
+	}
+}
+
+func (c *Command) writeUsers(out io.Writer, users []*entity.User) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tHASHID\tEMAIL\tNAME\tGOOGLE ID\tCREATED AT")
+	for _, user := range users {
+		hash, err := c.Hasher.Encode(user.ID)
+		if err != nil {
+			return fmt.Errorf("encode %d: %w", uint64(user.ID), err)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", uint64(user.ID), hash, user.Email, user.Name, user.GoogleID, user.CreatedAt.Format(time.RFC3339))
+	}
+	return w.Flush()
+}
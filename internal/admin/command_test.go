@@ -0,0 +1,308 @@
+package admin_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/admin"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type CommandExecutor struct {
+	command    *admin.Command
+	users      *mock_usecase.MockFindUser
+	reassigner *mock_usecase.MockReassignMedicalRecord
+	exporter   *mock_usecase.MockExportUserData
+	anonymizer *mock_usecase.MockAnonymizeUser
+	eraser     *mock_usecase.MockEraseUser
+	hasher     *hashids.HashID
+	migrated   []string
+}
+
+func TestCommand_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("command or its argument is invalid", func(t *testing.T) {
+		tables := [][]string{
+			{}, {"sideways"},
+			{"users"}, {"users", "find"}, {"users", "list", "one"}, {"users", "list", "0", "ten"}, {"users", "list", "0", "10", "20"},
+			{"records"}, {"records", "reassign", "a@orvosi.com"}, {"records", "move", "a@orvosi.com", "b@orvosi.com"},
+			{"hashid", "encode"}, {"hashid", "encode", "one"}, {"hashid", "decode", "not-a-hash!"}, {"hashid", "shuffle", "1"},
+			{"export"}, {"export", "a@orvosi.com", "b@orvosi.com"},
+			{"anonymize"}, {"anonymize", "a@orvosi.com"}, {"anonymize", "a@orvosi.com", "--no"},
+			{"erasures"}, {"erasures", "stop"}, {"erasures", "run", "now"},
+		}
+
+		for _, args := range tables {
+			exec := createCommandExecutor(ctrl)
+			var out bytes.Buffer
+
+			err := exec.command.Run(context.Background(), args, &out)
+			assert.NotNil(t, err, "args: %v", args)
+		}
+	})
+
+	t.Run("successfully list users", func(t *testing.T) {
+		exec := createCommandExecutor(ctrl)
+		users := []*entity.User{
+			{ID: 2, Email: "a@orvosi.com", Name: "Doctor A", GoogleID: "google-a"},
+			{ID: 3, Email: "b@orvosi.com", Name: "Doctor B", GoogleID: "google-b"},
+		}
+		var out bytes.Buffer
+
+		exec.users.EXPECT().FindAll(context.Background(), uint64(1), uint(2)).Return(users, nil)
+		err := exec.command.Run(context.Background(), []string{"users", "list", "1", "2"}, &out)
+
+		assert.Nil(t, err)
+		assert.Regexp(t, `2\s+\w+\s+a@orvosi.com\s+Doctor A\s+google-a`, out.String())
+		assert.Regexp(t, `3\s+\w+\s+b@orvosi.com\s+Doctor B\s+google-b`, out.String())
+	})
+
+	t.Run("list users using the default page", func(t *testing.T) {
+		exec := createCommandExecutor(ctrl)
+		var out bytes.Buffer
+
+		exec.users.EXPECT().FindAll(context.Background(), uint64(0), uint(50)).Return([]*entity.User{}, nil)
+		err := exec.command.Run(context.Background(), []string{"users", "list"}, &out)
+
+		assert.Nil(t, err)
+		assert.Contains(t, out.String(), "EMAIL")
+	})
+
+	t.Run("user is not found", func(t *testing.T) {
+		exec := createCommandExecutor(ctrl)
+		var out bytes.Buffer
+
+		exec.users.EXPECT().FindByEmail(context.Background(), "a@orvosi.com").Return(nil, entity.ErrUserNotFound)
+		err := exec.command.Run(context.Background(), []string{"users", "find", "a@orvosi.com"}, &out)
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+	})
+
+	t.Run("successfully find user", func(t *testing.T) {
+		exec := createCommandExecutor(ctrl)
+		var out bytes.Buffer
+		hash, _ := exec.hasher.Encode(7)
+
+		exec.users.EXPECT().FindByEmail(context.Background(), "a@orvosi.com").Return(&entity.User{ID: 7, Email: "a@orvosi.com"}, nil)
+		err := exec.command.Run(context.Background(), []string{"users", "find", "a@orvosi.com"}, &out)
+
+		assert.Nil(t, err)
+		assert.Regexp(t, `7\s+`+string(hash)+`\s+a@orvosi.com`, out.String())
+	})
+
+	t.Run("reassign returns error", func(t *testing.T) {
+		exec := createCommandExecutor(ctrl)
+		var out bytes.Buffer
+
+		exec.reassigner.EXPECT().Reassign(context.Background(), "a@orvosi.com", "b@orvosi.com").Return(int64(0), entity.ErrUserNotFound)
+		err := exec.command.Run(context.Background(), []string{"records", "reassign", "a@orvosi.com", "b@orvosi.com"}, &out)
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+	})
+
+	t.Run("successfully reassign medical records", func(t *testing.T) {
+		exec := createCommandExecutor(ctrl)
+		var out bytes.Buffer
+
+		exec.reassigner.EXPECT().Reassign(context.Background(), "a@orvosi.com", "b@orvosi.com").Return(int64(3), nil)
+		err := exec.command.Run(context.Background(), []string{"records", "reassign", "a@orvosi.com", "b@orvosi.com"}, &out)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "reassigned 3 medical record(s) from a@orvosi.com to b@orvosi.com\n", out.String())
+	})
+
+	t.Run("successfully encode and decode hashids", func(t *testing.T) {
+		exec := createCommandExecutor(ctrl)
+		hash, _ := exec.hasher.Encode(42)
+		var out bytes.Buffer
+
+		err := exec.command.Run(context.Background(), []string{"hashid", "encode", "42"}, &out)
+		assert.Nil(t, err)
+		assert.Regexp(t, `42\s+`+string(hash), out.String())
+
+		out.Reset()
+		err = exec.command.Run(context.Background(), []string{"hashid", "decode", string(hash)}, &out)
+		assert.Nil(t, err)
+		assert.Regexp(t, string(hash)+`\s+42`, out.String())
+	})
+
+	t.Run("backend has no migration", func(t *testing.T) {
+		exec := createCommandExecutor(ctrl)
+		exec.command.Migrate = nil
+		var out bytes.Buffer
+
+		err := exec.command.Run(context.Background(), []string{"migrate", "up"}, &out)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("successfully run migrate subcommand", func(t *testing.T) {
+		exec := createCommandExecutor(ctrl)
+		var out bytes.Buffer
+
+		err := exec.command.Run(context.Background(), []string{"migrate", "status"}, &out)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"status"}, exec.migrated)
+	})
+
+	t.Run("export returns error", func(t *testing.T) {
+		exec := createCommandExecutor(ctrl)
+		var out bytes.Buffer
+
+		exec.exporter.EXPECT().Export(context.Background(), "a@orvosi.com").Return(nil, entity.ErrUserNotFound)
+		err := exec.command.Run(context.Background(), []string{"export", "a@orvosi.com"}, &out)
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("successfully export user data", func(t *testing.T) {
+		exec := createCommandExecutor(ctrl)
+		var out bytes.Buffer
+		data := &entity.UserData{
+			User:           &entity.User{ID: 1, Email: "a@orvosi.com", Name: "Doctor A", Profile: entity.Profile{Specialty: "Cardiology"}},
+			MedicalRecords: []*entity.MedicalRecord{{ID: 2, Symptom: "symptom", Auditable: entity.Auditable{CreatedAt: time.Now()}}},
+			Attachments:    []*entity.Attachment{{ID: 3, MedicalRecordID: 2, Filename: "lab.pdf"}},
+			Erasure:        &entity.Erasure{UserID: 1, Email: "a@orvosi.com", RequestedAt: time.Now(), EraseAfter: time.Now()},
+			Identities:     []*entity.Identity{{ID: 4, UserID: 1, Provider: entity.ProviderGoogle, Subject: "google-id"}},
+			EmailChange:    &entity.EmailChange{UserID: 1, Email: "b@orvosi.com"},
+		}
+
+		exec.exporter.EXPECT().Export(context.Background(), "a@orvosi.com").Return(data, nil)
+		err := exec.command.Run(context.Background(), []string{"export", "a@orvosi.com"}, &out)
+		assert.Nil(t, err)
+
+		var res struct {
+			User struct {
+				Email     string `json:"email"`
+				Specialty string `json:"specialty"`
+			} `json:"user"`
+			MedicalRecords []struct {
+				Symptom string `json:"symptom"`
+			} `json:"medical_records"`
+			Attachments []struct {
+				Filename string `json:"filename"`
+			} `json:"attachments"`
+			Erasure *struct {
+				EraseAfter time.Time `json:"erase_after"`
+			} `json:"erasure"`
+			Identities []struct {
+				Subject string `json:"subject"`
+			} `json:"identities"`
+			EmailChange *struct {
+				Email string `json:"email"`
+			} `json:"email_change"`
+		}
+		assert.Nil(t, json.Unmarshal(out.Bytes(), &res))
+		assert.Equal(t, "a@orvosi.com", res.User.Email)
+		assert.Equal(t, "Cardiology", res.User.Specialty)
+		if assert.Len(t, res.MedicalRecords, 1) {
+			assert.Equal(t, "symptom", res.MedicalRecords[0].Symptom)
+		}
+		if assert.Len(t, res.Attachments, 1) {
+			assert.Equal(t, "lab.pdf", res.Attachments[0].Filename)
+		}
+		assert.NotNil(t, res.Erasure)
+		if assert.Len(t, res.Identities, 1) {
+			assert.Equal(t, "google-id", res.Identities[0].Subject)
+		}
+		if assert.NotNil(t, res.EmailChange) {
+			assert.Equal(t, "b@orvosi.com", res.EmailChange.Email)
+		}
+	})
+
+	t.Run("anonymize returns error", func(t *testing.T) {
+		exec := createCommandExecutor(ctrl)
+		var out bytes.Buffer
+
+		exec.anonymizer.EXPECT().Anonymize(context.Background(), "a@orvosi.com").Return("", entity.ErrUserNotFound)
+		err := exec.command.Run(context.Background(), []string{"anonymize", "a@orvosi.com", "--yes"}, &out)
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+	})
+
+	t.Run("successfully anonymize user", func(t *testing.T) {
+		exec := createCommandExecutor(ctrl)
+		var out bytes.Buffer
+
+		exec.anonymizer.EXPECT().Anonymize(context.Background(), "a@orvosi.com").Return("anonymized-1@invalid", nil)
+		err := exec.command.Run(context.Background(), []string{"anonymize", "a@orvosi.com", "--yes"}, &out)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "anonymized a@orvosi.com as anonymized-1@invalid\n", out.String())
+	})
+
+	t.Run("erase due returns error", func(t *testing.T) {
+		exec := createCommandExecutor(ctrl)
+		var out bytes.Buffer
+
+		gomock.InOrder(
+			exec.eraser.EXPECT().EraseDue(context.Background(), uint(2)).Return(2, nil),
+			exec.eraser.EXPECT().EraseDue(context.Background(), uint(2)).Return(1, entity.ErrInternalServer),
+		)
+		err := exec.command.Run(context.Background(), []string{"erasures", "run"}, &out)
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Equal(t, "erased 3 user(s)\n", out.String())
+	})
+
+	t.Run("successfully erase due users in batches", func(t *testing.T) {
+		exec := createCommandExecutor(ctrl)
+		var out bytes.Buffer
+
+		gomock.InOrder(
+			exec.eraser.EXPECT().EraseDue(context.Background(), uint(2)).Return(2, nil),
+			exec.eraser.EXPECT().EraseDue(context.Background(), uint(2)).Return(2, nil),
+			exec.eraser.EXPECT().EraseDue(context.Background(), uint(2)).Return(1, nil),
+		)
+		err := exec.command.Run(context.Background(), []string{"erasures", "run"}, &out)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "erased 5 user(s)\n", out.String())
+	})
+}
+
+func createCommandExecutor(ctrl *gomock.Controller) *CommandExecutor {
+	hash, err := hashids.NewHashID(10, "salt")
+	if err != nil {
+		panic(err)
+	}
+
+	exec := &CommandExecutor{
+		users:      mock_usecase.NewMockFindUser(ctrl),
+		reassigner: mock_usecase.NewMockReassignMedicalRecord(ctrl),
+		exporter:   mock_usecase.NewMockExportUserData(ctrl),
+		anonymizer: mock_usecase.NewMockAnonymizeUser(ctrl),
+		eraser:     mock_usecase.NewMockEraseUser(ctrl),
+		hasher:     hash,
+	}
+	exec.command = &admin.Command{
+		Users:      exec.users,
+		Reassigner: exec.reassigner,
+		Exporter:   exec.exporter,
+		Anonymizer: exec.anonymizer,
+		Eraser:     exec.eraser,
+		EraseBatch: 2,
+		Hasher:     hash,
+		Migrate: func(ctx context.Context, args []string, out io.Writer) error {
+			exec.migrated = args
+			if len(args) == 0 {
+				return errors.New("usage")
+			}
+			return nil
+		},
+	}
+	return exec
+}
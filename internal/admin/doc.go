@@ -0,0 +1,4 @@
+// Package admin runs the subcommands of orvosi-admin, the command-line tool for operators.
+//
+// The subcommands call the usecases, so they follow the same business rules as the API.
+package admin
@@ -0,0 +1,24 @@
+package builder
+
+import (
+	"github.com/indrasaputra/orvosi-api/internal/admin"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/indrasaputra/orvosi-api/usecase"
+)
+
+// BuildAdminCommand builds the subcommands of orvosi-admin
+// starting from usecase down to repository.
+// The migrate is nil if the backend has no migration.
+func BuildAdminCommand(cfg *config.Config, backend *repository.Backend, storage usecase.AttachmentStorage, hasher admin.Hasher, migrate admin.MigrateFunc) *admin.Command {
+	return &admin.Command{
+		Users:      usecase.NewUserFinder(backend.UserSelector),
+		Reassigner: usecase.NewMedicalRecordReassigner(backend.MedicalRecordReassigner, backend.UserSelector, backend.Transactor),
+		Exporter:   usecase.NewUserDataExporter(backend.UserSelector, backend.MedicalRecordSelector, backend.AttachmentSelector, backend.UserEraser, backend.IdentityLinker, backend.EmailChanger),
+		Anonymizer: usecase.NewUserAnonymizer(backend.UserAnonymizer, backend.UserSelector, backend.Transactor),
+		Eraser:     BuildEraseUser(cfg, backend, storage),
+		EraseBatch: cfg.Erasure.BatchSize,
+		Hasher:     hasher,
+		Migrate:    migrate,
+	}
+}
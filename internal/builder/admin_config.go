@@ -0,0 +1,13 @@
+package builder
+
+import (
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/router"
+)
+
+// BuildAdminConfig builds the workflow showing the runtime settings in use.
+func BuildAdminConfig(cfg *config.Config, rt handler.CurrentSettings) []*router.Route {
+	hdr := handler.NewAdminConfig(rt)
+	return router.AdminConfig(hdr)
+}
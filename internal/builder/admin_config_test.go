@@ -0,0 +1,20 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/builder"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildAdminConfig(t *testing.T) {
+	t.Run("successfully build admin config", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+
+		routes := builder.BuildAdminConfig(cfg, settings.NewSettings(cfg.Runtime, nil))
+		assert.NotEmpty(t, routes)
+	})
+}
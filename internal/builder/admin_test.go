@@ -0,0 +1,31 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/internal/builder"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/repository/memory"
+	"github.com/indrasaputra/orvosi-api/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildAdminCommand(t *testing.T) {
+	t.Run("successfully build admin command", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+		hash, err := hashids.NewHashID(10, "salt")
+		assert.Nil(t, err)
+
+		cmd := builder.BuildAdminCommand(cfg, memory.NewBackend(), storage.NewFilesystem(t.TempDir()), hash, nil)
+
+		assert.NotNil(t, cmd.Users)
+		assert.NotNil(t, cmd.Reassigner)
+		assert.NotNil(t, cmd.Exporter)
+		assert.NotNil(t, cmd.Anonymizer)
+		assert.NotNil(t, cmd.Eraser)
+		assert.Equal(t, cfg.Erasure.BatchSize, cmd.EraseBatch)
+		assert.Nil(t, cmd.Migrate)
+	})
+}
@@ -0,0 +1,67 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/router"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/indrasaputra/orvosi-api/internal/storage"
+	"github.com/indrasaputra/orvosi-api/usecase"
+)
+
+const (
+	attachmentStorageFilesystem = "filesystem"
+	attachmentStorageS3         = "s3"
+)
+
+// BuildAttachmentStorage builds the storage of attachments from given config.
+func BuildAttachmentStorage(cfg *config.Config) (usecase.AttachmentStorage, error) {
+	switch cfg.Attachment.Storage {
+	case attachmentStorageFilesystem:
+		return storage.NewFilesystem(cfg.Attachment.Directory), nil
+	case attachmentStorageS3:
+		s3, err := storage.NewS3(storage.S3Config{
+			Endpoint:  cfg.Attachment.S3Endpoint,
+			Region:    cfg.Attachment.S3Region,
+			Bucket:    cfg.Attachment.S3Bucket,
+			AccessKey: cfg.Attachment.S3AccessKey,
+			SecretKey: cfg.Attachment.S3SecretKey,
+			PathStyle: cfg.Attachment.S3PathStyle,
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		return s3, nil
+	}
+	return nil, fmt.Errorf("unknown attachment storage: %s", cfg.Attachment.Storage)
+}
+
+// multipartOverhead is the room left in the body of attachment upload for the other parts of the multipart form,
+// so an attachment slightly larger than its limit is still reported as such.
+const multipartOverhead = 1 << 20
+
+// BuildAttachmentUploader builds attachment upload workflow
+// starting from handler down to repository and storage.
+func BuildAttachmentUploader(cfg *config.Config, backend *repository.Backend, store usecase.AttachmentStorage) []*router.Route {
+	uc := usecase.NewAttachmentUploader(backend.AttachmentInserter, store, cfg.Attachment.MaxSize)
+	hdr := handler.NewAttachmentUploader(uc)
+	return withBodyLimit(router.AttachmentUploader(hdr), cfg.Attachment.MaxSize+multipartOverhead)
+}
+
+// BuildAttachmentFinder builds attachment find workflow
+// starting from handler down to repository and storage.
+func BuildAttachmentFinder(cfg *config.Config, backend *repository.Backend, store usecase.AttachmentStorage) []*router.Route {
+	uc := usecase.NewAttachmentFinder(backend.AttachmentSelector, store)
+	hdr := handler.NewAttachmentFinder(uc)
+	return router.AttachmentFinder(hdr)
+}
+
+// BuildAttachmentDeleter builds attachment deletion workflow
+// starting from handler down to repository and storage.
+func BuildAttachmentDeleter(cfg *config.Config, backend *repository.Backend, store usecase.AttachmentStorage) []*router.Route {
+	uc := usecase.NewAttachmentDeleter(backend.AttachmentDeleter, backend.StorageDeleter, store, backend.Transactor)
+	hdr := handler.NewAttachmentDeleter(uc)
+	return router.AttachmentDeleter(hdr)
+}
@@ -0,0 +1,67 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/builder"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/repository/memory"
+	"github.com/indrasaputra/orvosi-api/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildAttachmentStorage(t *testing.T) {
+	t.Run("unknown storage", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+		cfg.Attachment.Storage = "ftp"
+
+		store, err := builder.BuildAttachmentStorage(cfg)
+		assert.NotNil(t, err)
+		assert.Nil(t, store)
+	})
+
+	t.Run("s3 config is invalid", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+		cfg.Attachment.Storage = "s3"
+		cfg.Attachment.S3Endpoint = ""
+
+		store, err := builder.BuildAttachmentStorage(cfg)
+		assert.NotNil(t, err)
+		assert.Nil(t, store)
+	})
+
+	t.Run("successfully build filesystem and s3 storage", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+
+		store, err := builder.BuildAttachmentStorage(cfg)
+		assert.Nil(t, err)
+		assert.IsType(t, &storage.Filesystem{}, store)
+
+		cfg.Attachment.Storage = "s3"
+		cfg.Attachment.S3Endpoint = "http://localhost:9000"
+		cfg.Attachment.S3Bucket = "orvosi"
+
+		store, err = builder.BuildAttachmentStorage(cfg)
+		assert.Nil(t, err)
+		assert.IsType(t, &storage.S3{}, store)
+	})
+}
+
+func TestBuildAttachment(t *testing.T) {
+	t.Run("successfully build attachment uploader, finder, and deleter", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+
+		backend := memory.NewBackend()
+		store := storage.NewFilesystem(t.TempDir())
+
+		uploader := builder.BuildAttachmentUploader(cfg, backend, store)
+		assert.NotEmpty(t, uploader)
+		assert.Greater(t, uploader[0].BodyLimit, cfg.Attachment.MaxSize)
+		assert.NotEmpty(t, builder.BuildAttachmentFinder(cfg, backend, store))
+		assert.NotEmpty(t, builder.BuildAttachmentDeleter(cfg, backend, store))
+	})
+}
@@ -1,29 +1,215 @@
 package builder
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/indrasaputra/orvosi-api/internal/repository/memory"
+	_ "github.com/jackc/pgx/v4/stdlib" // postgres driver
+)
+
+const (
+	// DatabaseBackendPostgres keeps the data in PostgreSQL.
+	DatabaseBackendPostgres = "postgres"
+	// DatabaseBackendSQLite keeps the data in a SQLite file.
+	DatabaseBackendSQLite = "sqlite"
+	// DatabaseBackendMemory keeps the data in memory.
+	DatabaseBackendMemory = "memory"
+
+	// PostgresDriver is the name of database/sql driver of PostgreSQL.
+	// It caches the prepared statements per connection, so a query is only parsed once.
+	PostgresDriver = "pgx"
 )
 
 // BuildSQLDatabase builds *sql.DB from given config.
+// It doesn't connect to the database yet. Use PingDatabase to make sure the database is reachable.
 func BuildSQLDatabase(driver string, cfg *config.Config) (*sql.DB, error) {
-	sqlCfg := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.Username,
-		cfg.Database.Password,
-		cfg.Database.Name,
-	)
-
-	db, err := sql.Open(driver, sqlCfg)
+	return openSQLDatabase(driver, cfg, "")
+}
+
+// BuildReplicaDatabases builds *sql.DB for each read replica in the config.
+// It returns nothing if there is no replica.
+func BuildReplicaDatabases(driver string, cfg *config.Config) ([]*sql.DB, error) {
+	var dbs []*sql.DB
+	for _, addr := range strings.Split(cfg.Database.ReplicaHosts, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+
+		db, err := openSQLDatabase(driver, cfg, addr)
+		if err != nil {
+			closeAll(dbs)
+			return nil, err
+		}
+		dbs = append(dbs, db)
+	}
+	return dbs, nil
+}
+
+// BuildReplicaRouter builds the router of reads to the read replicas of PostgreSQL backend.
+// It returns nil if the backend has no replica.
+func BuildReplicaRouter(cfg *config.Config, db *sql.DB) (*repository.ReplicaRouter, error) {
+	if cfg.Database.Backend != DatabaseBackendPostgres {
+		return nil, nil
+	}
+
+	replicas, err := BuildReplicaDatabases(PostgresDriver, cfg)
+	if err != nil || len(replicas) == 0 {
+		return nil, err
+	}
+	return repository.NewReplicaRouter(db, replicas, cfg.Database.ReadYourWritesWindow), nil
+}
+
+// BuildDataSourceName builds PostgreSQL connection URL from given config.
+// The host and port are replaced by addr, in form of `host` or `host:port`, if it is not empty.
+// All values are escaped, so the password may contain any character.
+func BuildDataSourceName(cfg *config.Config, addr string) (string, error) {
+	var dsn *url.URL
+	if cfg.Database.URL != "" {
+		var err error
+		if dsn, err = url.Parse(cfg.Database.URL); err != nil {
+			return "", fmt.Errorf("invalid database url: %v", err)
+		}
+		if dsn.Scheme != "postgres" && dsn.Scheme != "postgresql" {
+			return "", fmt.Errorf("invalid database url: unknown scheme %q", dsn.Scheme)
+		}
+	} else {
+		if cfg.Database.Username == "" || cfg.Database.Name == "" {
+			return "", fmt.Errorf("DATABASE_USERNAME and DATABASE_NAME are required unless DATABASE_URL is set")
+		}
+		dsn = &url.URL{
+			Scheme: "postgres",
+			User:   url.UserPassword(cfg.Database.Username, cfg.Database.Password),
+			Host:   net.JoinHostPort(cfg.Database.Host, cfg.Database.Port),
+			Path:   "/" + cfg.Database.Name,
+		}
+	}
+
+	if addr != "" {
+		host, err := replaceHost(dsn.Host, addr)
+		if err != nil {
+			return "", err
+		}
+		dsn.Host = host
+	}
+
+	query := dsn.Query()
+	if query.Get("sslmode") == "" {
+		query.Set("sslmode", cfg.Database.SSLMode)
+	}
+	setIfNotEmpty(query, "sslrootcert", cfg.Database.SSLRootCert)
+	setIfNotEmpty(query, "sslcert", cfg.Database.SSLCert)
+	setIfNotEmpty(query, "sslkey", cfg.Database.SSLKey)
+	if cfg.Database.StatementTimeout > 0 {
+		query.Set("statement_timeout", strconv.FormatInt(cfg.Database.StatementTimeout.Milliseconds(), 10))
+	}
+	if query.Get("statement_cache_capacity") == "" {
+		query.Set("statement_cache_capacity", strconv.Itoa(cfg.Database.StatementCacheCapacity))
+	}
+	dsn.RawQuery = query.Encode()
+
+	return dsn.String(), nil
+}
+
+// PingDatabase pings the database until it answers, waiting twice as long after each failure.
+// It gives up after the configured number of retries.
+func PingDatabase(ctx context.Context, cfg *config.Config, db *sql.DB) error {
+	backoff := cfg.Database.ConnectBackoff
+	for attempt := 0; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt >= cfg.Database.ConnectRetries {
+			return fmt.Errorf("database is unreachable after %d attempt(s): %v", attempt+1, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func openSQLDatabase(driver string, cfg *config.Config, addr string) (*sql.DB, error) {
+	dsn, err := BuildDataSourceName(cfg, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(driver, dsn)
 	if err != nil {
 		return nil, err
 	}
 
 	db.SetMaxOpenConns(cfg.Database.MaxOpenConns)
 	db.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime)
 
 	return db, nil
 }
+
+// replaceHost replaces the host of current, keeping its port if addr has none.
+func replaceHost(current, addr string) (string, error) {
+	if !strings.Contains(addr, ":") {
+		_, port, err := net.SplitHostPort(current)
+		if err != nil {
+			return addr, nil
+		}
+		return net.JoinHostPort(addr, port), nil
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return "", fmt.Errorf("invalid replica host %q: %v", addr, err)
+	}
+	return addr, nil
+}
+
+func setIfNotEmpty(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+func closeAll(dbs []*sql.DB) {
+	for _, db := range dbs {
+		db.Close()
+	}
+}
+
+// BuildDatabase builds *sql.DB of the configured backend.
+// It returns nil *sql.DB for memory backend.
+func BuildDatabase(cfg *config.Config) (*sql.DB, error) {
+	switch cfg.Database.Backend {
+	case DatabaseBackendPostgres:
+		return BuildSQLDatabase(PostgresDriver, cfg)
+	case DatabaseBackendSQLite:
+		return BuildSQLiteDatabase(cfg)
+	case DatabaseBackendMemory:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown database backend: %s", cfg.Database.Backend)
+}
+
+// BuildBackend builds the repositories of the configured backend.
+// The db must come from BuildDatabase and the router from BuildReplicaRouter using the same config.
+func BuildBackend(cfg *config.Config, db *sql.DB, router *repository.ReplicaRouter, cipher repository.FieldCipher) (*repository.Backend, error) {
+	switch cfg.Database.Backend {
+	case DatabaseBackendPostgres, DatabaseBackendSQLite:
+		return repository.NewSQLBackend(db, router, cipher, cfg.Database.TxMaxRetries), nil
+	case DatabaseBackendMemory:
+		return memory.NewBackend(), nil
+	}
+	return nil, fmt.Errorf("unknown database backend: %s", cfg.Database.Backend)
+}
@@ -0,0 +1,17 @@
+//go:build !sqlite
+// +build !sqlite
+
+package builder
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/indrasaputra/orvosi-api/internal/config"
+)
+
+// BuildSQLiteDatabase always returns error since SQLite backend is not built in.
+// The backend needs cgo, so it is only built with the sqlite build tag.
+func BuildSQLiteDatabase(cfg *config.Config) (*sql.DB, error) {
+	return nil, errors.New("sqlite backend is not built in, build with -tags sqlite")
+}
@@ -0,0 +1,24 @@
+//go:build !sqlite
+// +build !sqlite
+
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/builder"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSQLiteDatabase(t *testing.T) {
+	t.Run("sqlite is not built in", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+
+		db, err := builder.BuildSQLiteDatabase(cfg)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, db)
+	})
+}
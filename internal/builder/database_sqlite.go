@@ -0,0 +1,32 @@
+//go:build sqlite
+// +build sqlite
+
+package builder
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/indrasaputra/orvosi-api/db/sqlite"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver
+)
+
+const sqliteDriver = "sqlite3"
+
+// BuildSQLiteDatabase opens the SQLite database file from given config and applies the schema.
+// The database created by an older schema is upgraded first.
+// Only one connection is used since SQLite allows one writer at a time.
+func BuildSQLiteDatabase(cfg *config.Config) (*sql.DB, error) {
+	db, err := sql.Open(sqliteDriver, fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000", cfg.Database.SQLitePath))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := sqlite.Apply(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
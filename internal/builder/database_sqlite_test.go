@@ -0,0 +1,110 @@
+//go:build sqlite
+// +build sqlite
+
+package builder_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/builder"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSQLiteDatabase(t *testing.T) {
+	t.Run("successfully build sql.DB and apply the schema", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+		cfg.Database.SQLitePath = filepath.Join(t.TempDir(), "orvosi.db")
+
+		db, err := builder.BuildSQLiteDatabase(cfg)
+		assert.Nil(t, err)
+		defer db.Close()
+
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM medical_records").Scan(&count)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("schema can be applied more than once", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+		cfg.Database.SQLitePath = filepath.Join(t.TempDir(), "orvosi.db")
+
+		db, err := builder.BuildSQLiteDatabase(cfg)
+		assert.Nil(t, err)
+		db.Close()
+
+		db, err = builder.BuildSQLiteDatabase(cfg)
+		assert.Nil(t, err)
+		db.Close()
+	})
+
+	t.Run("upgrade the database whose medical records are owned by email", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+		cfg.Database.SQLitePath = filepath.Join(t.TempDir(), "orvosi.db")
+
+		legacy, err := sql.Open("sqlite3", cfg.Database.SQLitePath)
+		assert.Nil(t, err)
+		_, err = legacy.Exec(legacySQLiteSchema)
+		assert.Nil(t, err)
+		legacy.Close()
+
+		db, err := builder.BuildSQLiteDatabase(cfg)
+		assert.Nil(t, err)
+		defer db.Close()
+
+		var owner, identities int
+		err = db.QueryRow("SELECT COUNT(*) FROM medical_records m JOIN users u ON u.id = m.user_id WHERE u.email = 'user@email.com'").Scan(&owner)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, owner)
+		err = db.QueryRow("SELECT COUNT(*) FROM medical_records m JOIN users u ON u.id = m.user_id WHERE u.email = 'unknown@email.com' AND u.google_id = 'unlinked:unknown@email.com'").Scan(&owner)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, owner)
+		err = db.QueryRow("SELECT COUNT(*) FROM user_identities WHERE provider = 'google' AND subject = 'google-id'").Scan(&identities)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, identities)
+
+		db.Close()
+		db, err = builder.BuildSQLiteDatabase(cfg)
+		assert.Nil(t, err)
+		err = db.QueryRow("SELECT COUNT(*) FROM user_identities").Scan(&identities)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, identities)
+	})
+}
+
+const legacySQLiteSchema = `
+CREATE TABLE users (
+   id          INTEGER        PRIMARY KEY AUTOINCREMENT,
+   "name"      TEXT           NOT NULL,
+   email       TEXT           UNIQUE NOT NULL,
+   google_id   TEXT           UNIQUE NOT NULL,
+   created_at  TIMESTAMP,
+   updated_at  TIMESTAMP,
+   created_by  VARCHAR(200),
+   updated_by  VARCHAR(200)
+);
+CREATE TABLE medical_records (
+   id           INTEGER         PRIMARY KEY AUTOINCREMENT,
+   email        VARCHAR(255)    NOT NULL,
+   symptom      TEXT            NOT NULL,
+   diagnosis    TEXT            NOT NULL,
+   therapy      TEXT            NOT NULL,
+   result       TEXT            NOT NULL,
+   created_at   TIMESTAMP,
+   updated_at   TIMESTAMP,
+   created_by   VARCHAR(200),
+   updated_by   VARCHAR(200)
+);
+CREATE INDEX index_on_email_id_created_at_on_medical_records ON medical_records (email, id, created_at);
+INSERT INTO users (name, email, google_id) VALUES ('User', 'user@email.com', 'google-id');
+INSERT INTO medical_records (email, symptom, diagnosis, therapy, result) VALUES
+   ('user@email.com', 's', 'd', 't', ''),
+   ('user@email.com', 's', 'd', 't', ''),
+   ('unknown@email.com', 's', 'd', 't', '');
+`
@@ -0,0 +1,26 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/tool"
+	"github.com/indrasaputra/orvosi-api/usecase"
+)
+
+const (
+	emailValidationSyntax = "syntax"
+	emailValidationMX     = "mx"
+)
+
+// BuildEmailValidator builds the validator of user's email from given config.
+// The validator should be shared by the workflows, so the MX cache is shared as well.
+func BuildEmailValidator(cfg *config.Config) (usecase.ValidateEmail, error) {
+	switch cfg.Email.Validation {
+	case emailValidationSyntax:
+		return usecase.NewEmailSyntaxValidator(), nil
+	case emailValidationMX:
+		return tool.NewMXEmailValidator(nil, cfg.Email.MXCacheTTL, cfg.Email.MXNegativeCacheTTL), nil
+	}
+	return nil, fmt.Errorf("unknown email validation: %s", cfg.Email.Validation)
+}
@@ -0,0 +1,45 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/builder"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/tool"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildEmailValidator(t *testing.T) {
+	t.Run("unknown validation", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+		cfg.Email.Validation = "unknown"
+
+		val, err := builder.BuildEmailValidator(cfg)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, val)
+	})
+
+	t.Run("syntax validation is the default", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+
+		val, err := builder.BuildEmailValidator(cfg)
+
+		assert.Nil(t, err)
+		assert.IsType(t, &usecase.EmailSyntaxValidator{}, val)
+	})
+
+	t.Run("successfully build mx validation", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+		cfg.Email.Validation = "mx"
+
+		val, err := builder.BuildEmailValidator(cfg)
+
+		assert.Nil(t, err)
+		assert.IsType(t, &tool.MXEmailValidator{}, val)
+	})
+}
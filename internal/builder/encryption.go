@@ -0,0 +1,34 @@
+package builder
+
+import (
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/encryption"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+)
+
+// BuildFieldCipher builds the cipher of clinical text from given config.
+// The master keys are taken from the local KMS file if it is set, otherwise from the keys.
+// If none of them is set, clinical text is kept as plaintext.
+func BuildFieldCipher(cfg *config.Config) (repository.FieldCipher, error) {
+	if cfg.Encryption.KMSFile != "" {
+		kms, err := encryption.LoadLocalKMS(cfg.Encryption.KMSFile)
+		if err != nil {
+			return nil, err
+		}
+		return encryption.NewEnvelope(kms), nil
+	}
+
+	if cfg.Encryption.Keys == "" {
+		return encryption.Plaintext{}, nil
+	}
+
+	keys, err := encryption.ParseKeys(cfg.Encryption.Keys)
+	if err != nil {
+		return nil, err
+	}
+	kms, err := encryption.NewLocalKMS(keys, cfg.Encryption.ActiveKey)
+	if err != nil {
+		return nil, err
+	}
+	return encryption.NewEnvelope(kms), nil
+}
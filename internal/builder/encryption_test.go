@@ -0,0 +1,61 @@
+package builder_test
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/builder"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/encryption"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildFieldCipher(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", encryption.KeySize)))
+
+	t.Run("keys or kms file is invalid", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+
+		cfg.Encryption.Keys = "v1:" + key
+		cfg.Encryption.ActiveKey = "v2"
+		cipher, err := builder.BuildFieldCipher(cfg)
+		assert.NotNil(t, err)
+		assert.Nil(t, cipher)
+
+		cfg.Encryption.KMSFile = filepath.Join(t.TempDir(), "missing.json")
+		cipher, err = builder.BuildFieldCipher(cfg)
+		assert.NotNil(t, err)
+		assert.Nil(t, cipher)
+	})
+
+	t.Run("clinical text is kept as plaintext without keys", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+
+		cipher, err := builder.BuildFieldCipher(cfg)
+		assert.Nil(t, err)
+		assert.Equal(t, encryption.Plaintext{}, cipher)
+	})
+
+	t.Run("successfully build envelope from keys and kms file", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+
+		cfg.Encryption.Keys = "v1:" + key
+		cfg.Encryption.ActiveKey = "v1"
+		cipher, err := builder.BuildFieldCipher(cfg)
+		assert.Nil(t, err)
+		assert.IsType(t, &encryption.Envelope{}, cipher)
+
+		path := filepath.Join(t.TempDir(), "kms.json")
+		os.WriteFile(path, []byte(`{"active":"v1","keys":{"v1":"`+key+`"}}`), 0o600)
+		cfg.Encryption.KMSFile = path
+		cipher, err = builder.BuildFieldCipher(cfg)
+		assert.Nil(t, err)
+		assert.IsType(t, &encryption.Envelope{}, cipher)
+	})
+}
@@ -0,0 +1,17 @@
+package builder
+
+import (
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/router"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/indrasaputra/orvosi-api/usecase"
+)
+
+// BuildFHIRMedicalRecord builds medical record workflow represented in FHIR R4
+// starting from handler down to repository.
+func BuildFHIRMedicalRecord(cfg *config.Config, backend *repository.Backend, rt usecase.RuntimeSettings) []*router.Route {
+	uc := usecase.NewMedicalRecordFinder(backend.MedicalRecordSelector, rt)
+	hdr := handler.NewFHIRMedicalRecord(uc)
+	return router.FHIRMedicalRecord(hdr)
+}
@@ -0,0 +1,23 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/builder"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/repository/memory"
+	"github.com/indrasaputra/orvosi-api/internal/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildFHIRMedicalRecord(t *testing.T) {
+	t.Run("successfully build fhir medical record", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+
+		backend := memory.NewBackend()
+
+		routes := builder.BuildFHIRMedicalRecord(cfg, backend, settings.NewSettings(cfg.Runtime, nil))
+		assert.NotEmpty(t, routes)
+	})
+}
@@ -0,0 +1,17 @@
+package builder
+
+import (
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/router"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/indrasaputra/orvosi-api/usecase"
+)
+
+// BuildHealthChecker builds health check workflow
+// starting from handler down to repository.
+func BuildHealthChecker(cfg *config.Config, backend *repository.Backend, state usecase.ShutdownState) []*router.Route {
+	uc := usecase.NewHealthChecker(backend.Pinger, state)
+	hdr := handler.NewHealthChecker(uc)
+	return router.HealthChecker(hdr)
+}
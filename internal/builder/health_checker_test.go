@@ -0,0 +1,23 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/builder"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/lifecycle"
+	"github.com/indrasaputra/orvosi-api/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildHealthChecker(t *testing.T) {
+	t.Run("successfully build health checker", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+
+		backend := memory.NewBackend()
+
+		routes := builder.BuildHealthChecker(cfg, backend, lifecycle.NewManager())
+		assert.NotEmpty(t, routes)
+	})
+}
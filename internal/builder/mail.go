@@ -0,0 +1,32 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/mail"
+	"github.com/indrasaputra/orvosi-api/usecase"
+)
+
+const (
+	// MailSenderLog only writes the recipient and subject of the emails to the log.
+	MailSenderLog = "log"
+	// MailSenderSMTP sends the emails through the SMTP server.
+	MailSenderSMTP = "smtp"
+)
+
+// BuildMailSender builds the sender of the emails to users from given config.
+func BuildMailSender(cfg *config.Config) (usecase.SendEmail, error) {
+	switch cfg.Mail.Sender {
+	case MailSenderLog:
+		return mail.NewLog(cfg.Mail.From), nil
+	case MailSenderSMTP:
+		return mail.NewSMTP(mail.SMTPConfig{
+			Addr:     cfg.Mail.SMTPAddr,
+			From:     cfg.Mail.From,
+			Username: cfg.Mail.SMTPUsername,
+			Password: cfg.Mail.SMTPPassword,
+		}), nil
+	}
+	return nil, fmt.Errorf("unknown mail sender: %s", cfg.Mail.Sender)
+}
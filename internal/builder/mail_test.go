@@ -0,0 +1,56 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/builder"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/mail"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildMailSender(t *testing.T) {
+	t.Run("unknown sender", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+		cfg.Mail.Sender = "unknown"
+
+		sender, err := builder.BuildMailSender(cfg)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, sender)
+	})
+
+	t.Run("sender is log by default", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+
+		sender, err := builder.BuildMailSender(cfg)
+
+		assert.Nil(t, err)
+		assert.IsType(t, &mail.Log{}, sender)
+	})
+
+	t.Run("successfully build log sender", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+		cfg.Mail.Sender = "log"
+
+		sender, err := builder.BuildMailSender(cfg)
+
+		assert.Nil(t, err)
+		assert.IsType(t, &mail.Log{}, sender)
+	})
+
+	t.Run("successfully build smtp sender", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+		cfg.Mail.Sender = "smtp"
+		cfg.Mail.SMTPAddr = "localhost:25"
+
+		sender, err := builder.BuildMailSender(cfg)
+
+		assert.Nil(t, err)
+		assert.IsType(t, &mail.SMTP{}, sender)
+	})
+}
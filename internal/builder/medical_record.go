@@ -1,38 +1,52 @@
 package builder
 
 import (
-	"database/sql"
-
 	"github.com/indrasaputra/orvosi-api/internal/config"
 	"github.com/indrasaputra/orvosi-api/internal/http/handler"
 	"github.com/indrasaputra/orvosi-api/internal/http/router"
 	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/indrasaputra/orvosi-api/internal/tool"
 	"github.com/indrasaputra/orvosi-api/usecase"
 )
 
 // BuildMedicalRecordCreator builds medical record creation workflow
 // starting from handler down to repository.
-func BuildMedicalRecordCreator(cfg *config.Config, db *sql.DB) []*router.Route {
-	ins := repository.NewMedicalRecordInserter(db)
-	uc := usecase.NewMedicalRecordCreator(ins)
+func BuildMedicalRecordCreator(cfg *config.Config, backend *repository.Backend) []*router.Route {
+	uc := usecase.NewMedicalRecordCreator(backend.MedicalRecordInserter)
 	hdr := handler.NewMedicalRecordCreator(uc)
 	return router.MedicalRecordCreator(hdr)
 }
 
 // BuildMedicalRecordFinder builds medical record find workflow
 // starting from handler down to repository.
-func BuildMedicalRecordFinder(cfg *config.Config, db *sql.DB) []*router.Route {
-	sel := repository.NewMedicalRecordSelector(db)
-	uc := usecase.NewMedicalRecordFinder(sel)
+func BuildMedicalRecordFinder(cfg *config.Config, backend *repository.Backend, rt usecase.RuntimeSettings) []*router.Route {
+	uc := usecase.NewMedicalRecordFinder(backend.MedicalRecordSelector, rt)
 	hdr := handler.NewMedicalRecordFinder(uc)
-	return router.MedicalRecordFinder(hdr)
+	rdr := tool.NewMedicalRecordPDFRenderer(cfg.Clinic.PDFHeader, cfg.Clinic.PDFFooter)
+	prt := handler.NewMedicalRecordPrinter(uc, rdr)
+	return router.MedicalRecordFinder(hdr, prt)
 }
 
 // BuildMedicalRecordUpdater builds medical record update workflow
 // starting from handler down to repository.
-func BuildMedicalRecordUpdater(cfg *config.Config, db *sql.DB) []*router.Route {
-	up := repository.NewMedicalRecordUpdater(db)
-	uc := usecase.NewMedicalRecordUpdater(up)
+func BuildMedicalRecordUpdater(cfg *config.Config, backend *repository.Backend) []*router.Route {
+	uc := usecase.NewMedicalRecordUpdater(backend.MedicalRecordUpdater)
 	hdr := handler.NewMedicalRecordUpdater(uc)
 	return router.MedicalRecordUpdater(hdr)
 }
+
+// BuildMedicalRecordImporter builds medical record import workflow
+// starting from handler down to repository.
+func BuildMedicalRecordImporter(cfg *config.Config, backend *repository.Backend) []*router.Route {
+	uc := usecase.NewMedicalRecordImporter(backend.MedicalRecordInserter, backend.Transactor)
+	hdr := handler.NewMedicalRecordImporter(uc, cfg.HTTP.ImportBodyLimit)
+	return withBodyLimit(router.MedicalRecordImporter(hdr), cfg.HTTP.ImportBodyLimit)
+}
+
+// withBodyLimit sets the body limit of the routes which need more than the default limit of the server.
+func withBodyLimit(routes []*router.Route, limit int64) []*router.Route {
+	for _, route := range routes {
+		route.BodyLimit = limit
+	}
+	return routes
+}
@@ -1,11 +1,12 @@
 package builder_test
 
 import (
-	"database/sql"
 	"testing"
 
 	"github.com/indrasaputra/orvosi-api/internal/builder"
 	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/repository/memory"
+	"github.com/indrasaputra/orvosi-api/internal/settings"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -14,9 +15,9 @@ func TestBuildMedicalRecordCreator(t *testing.T) {
 		cfg, err := config.NewConfig("../../test/fixture/env.valid")
 		assert.Nil(t, err)
 
-		db := &sql.DB{}
+		backend := memory.NewBackend()
 
-		routes := builder.BuildMedicalRecordCreator(cfg, db)
+		routes := builder.BuildMedicalRecordCreator(cfg, backend)
 		assert.NotEmpty(t, routes)
 	})
 }
@@ -26,9 +27,9 @@ func TestBuildMedicalRecordFinder(t *testing.T) {
 		cfg, err := config.NewConfig("../../test/fixture/env.valid")
 		assert.Nil(t, err)
 
-		db := &sql.DB{}
+		backend := memory.NewBackend()
 
-		routes := builder.BuildMedicalRecordFinder(cfg, db)
+		routes := builder.BuildMedicalRecordFinder(cfg, backend, settings.NewSettings(cfg.Runtime, nil))
 		assert.NotEmpty(t, routes)
 	})
 }
@@ -38,9 +39,24 @@ func TestBuildMedicalRecordUpdater(t *testing.T) {
 		cfg, err := config.NewConfig("../../test/fixture/env.valid")
 		assert.Nil(t, err)
 
-		db := &sql.DB{}
+		backend := memory.NewBackend()
 
-		routes := builder.BuildMedicalRecordUpdater(cfg, db)
+		routes := builder.BuildMedicalRecordUpdater(cfg, backend)
 		assert.NotEmpty(t, routes)
 	})
 }
+
+func TestBuildMedicalRecordImporter(t *testing.T) {
+	t.Run("successfully build medical record importer", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+
+		backend := memory.NewBackend()
+
+		routes := builder.BuildMedicalRecordImporter(cfg, backend)
+		assert.NotEmpty(t, routes)
+		for _, route := range routes {
+			assert.Equal(t, cfg.HTTP.ImportBodyLimit, route.BodyLimit)
+		}
+	})
+}
@@ -1,8 +1,6 @@
 package builder
 
 import (
-	"database/sql"
-
 	"github.com/indrasaputra/orvosi-api/internal/config"
 	"github.com/indrasaputra/orvosi-api/internal/http/handler"
 	"github.com/indrasaputra/orvosi-api/internal/http/router"
@@ -10,11 +8,15 @@ import (
 	"github.com/indrasaputra/orvosi-api/usecase"
 )
 
+// BuildSignIn builds the usecase of sign-in.
+// It is shared by the sign-in route and the middleware which resolves the stored user of every request.
+func BuildSignIn(cfg *config.Config, backend *repository.Backend) *usecase.Signer {
+	return usecase.NewSigner(backend.UserInserter, backend.IdentityLinker, backend.Transactor)
+}
+
 // BuildSigner builds sign-in workflow
-// starting from handler down to repository.
-func BuildSigner(cfg *config.Config, db *sql.DB) []*router.Route {
-	ins := repository.NewUserInserter(db)
-	uc := usecase.NewSigner(ins)
-	hdr := handler.NewSigner(uc)
+// starting from handler down to the given usecase.
+func BuildSigner(cfg *config.Config, signer usecase.SignIn) []*router.Route {
+	hdr := handler.NewSigner(signer)
 	return router.Signer(hdr)
 }
@@ -1,11 +1,11 @@
 package builder_test
 
 import (
-	"database/sql"
 	"testing"
 
 	"github.com/indrasaputra/orvosi-api/internal/builder"
 	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/repository/memory"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -14,9 +14,12 @@ func TestBuildrSigner(t *testing.T) {
 		cfg, err := config.NewConfig("../../test/fixture/env.valid")
 		assert.Nil(t, err)
 
-		db := &sql.DB{}
+		backend := memory.NewBackend()
 
-		routes := builder.BuildSigner(cfg, db)
+		signer := builder.BuildSignIn(cfg, backend)
+		assert.NotNil(t, signer)
+
+		routes := builder.BuildSigner(cfg, signer)
 		assert.NotEmpty(t, routes)
 	})
 }
@@ -0,0 +1,59 @@
+package builder
+
+import (
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/router"
+	"github.com/indrasaputra/orvosi-api/internal/i18n"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/indrasaputra/orvosi-api/usecase"
+)
+
+// BuildUserProfile builds user profile workflow
+// starting from handler down to repository.
+// The preferred language must be one of the languages of the error messages.
+func BuildUserProfile(cfg *config.Config, backend *repository.Backend) []*router.Route {
+	finder := usecase.NewUserFinder(backend.UserSelector)
+	updater := usecase.NewProfileUpdater(backend.ProfileUpdater, backend.UserSelector, backend.Transactor, i18n.Languages())
+	hdr := handler.NewUserProfile(finder, updater)
+	return router.UserProfile(hdr)
+}
+
+// BuildEraseUser builds the usecase of user erasure.
+// It is shared by the HTTP routes and the worker which erases the users whose grace period has passed.
+func BuildEraseUser(cfg *config.Config, backend *repository.Backend, store usecase.AttachmentStorage) usecase.EraseUser {
+	return usecase.NewUserEraser(backend.UserEraser, backend.StorageDeleter, store, backend.Transactor, cfg.Erasure.GracePeriod)
+}
+
+// BuildUserEraser builds user erasure workflow
+// starting from handler down to the given usecase.
+func BuildUserEraser(cfg *config.Config, eraser usecase.EraseUser) []*router.Route {
+	hdr := handler.NewUserEraser(eraser)
+	return router.UserEraser(hdr)
+}
+
+// BuildUserDataExporter builds user data export workflow
+// starting from handler down to repository and storage.
+func BuildUserDataExporter(cfg *config.Config, backend *repository.Backend, store usecase.AttachmentStorage) []*router.Route {
+	exporter := usecase.NewUserDataExporter(backend.UserSelector, backend.MedicalRecordSelector, backend.AttachmentSelector, backend.UserEraser, backend.IdentityLinker, backend.EmailChanger)
+	finder := usecase.NewAttachmentFinder(backend.AttachmentSelector, store)
+	hdr := handler.NewUserDataExporter(exporter, finder)
+	return router.UserDataExporter(hdr)
+}
+
+// BuildUserEmail builds email change workflow
+// starting from handler down to repository and the mail sender.
+func BuildUserEmail(cfg *config.Config, backend *repository.Backend, emails usecase.ValidateEmail, sender usecase.SendEmail) []*router.Route {
+	changer := usecase.NewEmailChanger(backend.EmailChanger, backend.UserSelector, emails, sender, backend.Transactor, cfg.Email.ChangeTTL, cfg.Email.VerificationLink)
+	hdr := handler.NewUserEmail(changer)
+	return router.UserEmail(hdr)
+}
+
+// BuildUserIdentity builds identity linking workflow
+// starting from handler down to repository.
+// The decoder verifies the ID token of the identity being linked.
+func BuildUserIdentity(cfg *config.Config, backend *repository.Backend, decoder usecase.DecodeIDToken) []*router.Route {
+	linker := usecase.NewIdentityLinker(backend.IdentityLinker, decoder, backend.Transactor)
+	hdr := handler.NewUserIdentity(linker)
+	return router.UserIdentity(hdr)
+}
@@ -0,0 +1,41 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/builder"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/mail"
+	"github.com/indrasaputra/orvosi-api/internal/repository/memory"
+	"github.com/indrasaputra/orvosi-api/internal/storage"
+	"github.com/indrasaputra/orvosi-api/internal/tool"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildUser(t *testing.T) {
+	t.Run("successfully build user profile, eraser, and data exporter", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+
+		backend := memory.NewBackend()
+		store := storage.NewFilesystem(t.TempDir())
+
+		assert.NotEmpty(t, builder.BuildUserProfile(cfg, backend))
+
+		eraser := builder.BuildEraseUser(cfg, backend, store)
+		assert.NotNil(t, eraser)
+		assert.NotEmpty(t, builder.BuildUserEraser(cfg, eraser))
+		assert.NotEmpty(t, builder.BuildUserDataExporter(cfg, backend, store))
+	})
+
+	t.Run("successfully build user email and identity", func(t *testing.T) {
+		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		assert.Nil(t, err)
+
+		backend := memory.NewBackend()
+
+		assert.NotEmpty(t, builder.BuildUserEmail(cfg, backend, usecase.NewEmailSyntaxValidator(), mail.NewLog(cfg.Mail.From)))
+		assert.NotEmpty(t, builder.BuildUserIdentity(cfg, backend, tool.NewIDTokenDecoder(cfg.Google.Audience)))
+	})
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// CheckConfigError exits without stack trace, since the error already tells what to fix.
+// Asking for help exits successfully.
+func CheckConfigError(err error) {
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
+
+// CheckError panics if err is not nil.
+func CheckError(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
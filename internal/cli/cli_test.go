@@ -0,0 +1,25 @@
+package cli_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckConfigError(t *testing.T) {
+	t.Run("nil error is ignored", func(t *testing.T) {
+		assert.NotPanics(t, func() { cli.CheckConfigError(nil) })
+	})
+}
+
+func TestCheckError(t *testing.T) {
+	t.Run("nil error is ignored", func(t *testing.T) {
+		assert.NotPanics(t, func() { cli.CheckError(nil) })
+	})
+
+	t.Run("error panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "fail", func() { cli.CheckError(errors.New("fail")) })
+	})
+}
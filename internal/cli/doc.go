@@ -0,0 +1,2 @@
+// Package cli holds what the commands in app share, such as how they exit on error.
+package cli
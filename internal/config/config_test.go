@@ -1,24 +1,315 @@
 package config_test
 
 import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/indrasaputra/orvosi-api/internal/config"
 	"github.com/stretchr/testify/assert"
 )
 
+const fixture = "../../test/fixture/"
+
 func TestNewConfig(t *testing.T) {
+	t.Run("fail to create an instance of Config due to missing env file", func(t *testing.T) {
+		cfg, err := config.NewConfig(fixture + "env.missing")
+
+		assert.NotNil(t, err)
+		assert.Nil(t, cfg)
+	})
+
 	t.Run("fail to create an instance of Config due to incomplete env", func(t *testing.T) {
-		cfg, err := config.NewConfig("test/fixture/env.incomplete")
+		cfg, err := config.NewConfig(fixture + "env.incomplete")
 
 		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "GOOGLE_AUDIENCE is required")
+		assert.Contains(t, err.Error(), "HASHID_SALT is required")
+		assert.Contains(t, err.Error(), "HASHID_MIN_LENGTH is required")
 		assert.Nil(t, cfg)
 	})
 
 	t.Run("successfully create an instance of Config", func(t *testing.T) {
-		cfg, err := config.NewConfig("../../test/fixture/env.valid")
+		cfg, err := config.NewConfig(fixture + "env.valid")
 
 		assert.Nil(t, err)
 		assert.NotNil(t, cfg)
+		assert.Equal(t, "1234", cfg.Port)
+		assert.Equal(t, 10, cfg.Database.MaxOpenConns)
+		assert.Equal(t, 30*time.Minute, cfg.Database.ConnMaxLifetime)
+	})
+}
+
+func TestParseCommandLine(t *testing.T) {
+	t.Run("unknown flag", func(t *testing.T) {
+		var out bytes.Buffer
+		cmd, err := config.ParseCommandLine("api", []string{"--unknown"}, &out)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, cmd)
+		assert.Contains(t, out.String(), "-database-max-open-conns")
+	})
+
+	t.Run("help is asked", func(t *testing.T) {
+		_, err := config.ParseCommandLine("api", []string{"-h"}, &bytes.Buffer{})
+
+		assert.Equal(t, flag.ErrHelp, err)
+	})
+
+	t.Run("default env file may be missing", func(t *testing.T) {
+		cmd, err := config.ParseCommandLine("api", nil, &bytes.Buffer{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, config.DefaultEnvFile, cmd.EnvFile)
+		assert.True(t, cmd.EnvFileOptional)
+	})
+
+	t.Run("flags are parsed until the first argument", func(t *testing.T) {
+		args := []string{"--config", "config.yaml", "--env-file", "prod.env", "--print-config", "--port", "8080", "--database-max-open-conns=3", "migrate", "up", "--port", "1"}
+		cmd, err := config.ParseCommandLine("api", args, &bytes.Buffer{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "config.yaml", cmd.ConfigFile)
+		assert.Equal(t, "prod.env", cmd.EnvFile)
+		assert.False(t, cmd.EnvFileOptional)
+		assert.True(t, cmd.PrintConfig)
+		assert.Equal(t, map[string]string{"PORT": "8080", "DATABASE_MAX_OPEN_CONNS": "3"}, cmd.Flags)
+		assert.Equal(t, []string{"migrate", "up", "--port", "1"}, cmd.Args)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("yaml config file is flattened into env keys", func(t *testing.T) {
+		cfg, err := config.Load(&config.CommandLine{ConfigFile: fixture + "config/config.yaml"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "7000", cfg.Port)
+		assert.Equal(t, "yaml-host", cfg.Database.Host)
+		assert.Equal(t, 8, cfg.Database.MaxOpenConns)
+		assert.Equal(t, "replica-1:5432,replica-2:5432", cfg.Database.ReplicaHosts)
+		assert.Equal(t, time.Hour, cfg.Database.ConnMaxLifetime)
+		assert.Equal(t, uint(4), cfg.Hashid.MinLength)
+	})
+
+	t.Run("toml config file is flattened into env keys", func(t *testing.T) {
+		cfg, err := config.Load(&config.CommandLine{ConfigFile: fixture + "config/config.toml"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "7001", cfg.Port)
+		assert.Equal(t, "toml-host", cfg.Database.Host)
+		assert.Equal(t, 9, cfg.Database.MaxOpenConns)
+	})
+
+	t.Run("config file with unknown key, extension, or missing", func(t *testing.T) {
+		_, err := config.Load(&config.CommandLine{ConfigFile: fixture + "config/unknown.yaml"})
+		assert.Contains(t, err.Error(), "DATABASE_HOTS: unknown key in config file")
+
+		_, err = config.Load(&config.CommandLine{ConfigFile: fixture + "env.valid"})
+		assert.Contains(t, err.Error(), "must be .yaml, .yml, or .toml")
+
+		_, err = config.Load(&config.CommandLine{ConfigFile: fixture + "config/missing.yaml"})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("env file, env, and flag override the config file in order", func(t *testing.T) {
+		setenv(t, "DATABASE_MAX_OPEN_CONNS", "20")
+		setenv(t, "DATABASE_HOST", "env-host")
+
+		cmd := &config.CommandLine{
+			ConfigFile: fixture + "config/config.yaml",
+			EnvFile:    fixture + "env.valid",
+			Flags:      map[string]string{"DATABASE_HOST": "flag-host"},
+		}
+		cfg, err := config.Load(cmd)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "1234", cfg.Port)
+		assert.Equal(t, 20, cfg.Database.MaxOpenConns)
+		assert.Equal(t, "flag-host", cfg.Database.Host)
+		assert.Equal(t, "replica-1:5432,replica-2:5432", cfg.Database.ReplicaHosts)
+	})
+
+	t.Run("default of a list keeps its commas", func(t *testing.T) {
+		cfg, err := config.NewConfig(fixture + "env.valid")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "GET,HEAD,POST,PUT,PATCH,DELETE", cfg.HTTP.CORSAllowMethods)
+		assert.Equal(t, "'none'", cfg.HTTP.FrameAncestors)
+		assert.Empty(t, cfg.Runtime.CORSAllowOrigins)
+	})
+
+	t.Run("missing env file is allowed if it is optional", func(t *testing.T) {
+		cmd := &config.CommandLine{ConfigFile: fixture + "config/config.yaml", EnvFile: fixture + "env.missing", EnvFileOptional: true}
+		cfg, err := config.Load(cmd)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, cfg)
+	})
+
+	t.Run("secret is read from the file set by key with _FILE suffix", func(t *testing.T) {
+		setenv(t, "DATABASE_PASSWORD_FILE", fixture+"config/password.txt")
+
+		cfg, err := config.Load(&config.CommandLine{EnvFile: fixture + "env.valid"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "file-password", cfg.Database.Password)
+	})
+
+	t.Run("key and its _FILE can't be set in the same source", func(t *testing.T) {
+		setenv(t, "DATABASE_PASSWORD", "password")
+		setenv(t, "DATABASE_PASSWORD_FILE", fixture+"config/password.txt")
+
+		_, err := config.Load(&config.CommandLine{EnvFile: fixture + "env.valid"})
+
+		assert.Contains(t, err.Error(), "DATABASE_PASSWORD and DATABASE_PASSWORD_FILE are both set in env")
+	})
+
+	t.Run("file set by key with _FILE suffix is missing", func(t *testing.T) {
+		flags := map[string]string{"HASHID_SALT_FILE": fixture + "config/missing.txt"}
+
+		_, err := config.Load(&config.CommandLine{EnvFile: fixture + "env.valid", Flags: flags})
+
+		assert.Contains(t, err.Error(), "HASHID_SALT_FILE:")
+	})
+
+	t.Run("value can't be decoded", func(t *testing.T) {
+		flags := map[string]string{"DATABASE_MAX_OPEN_CONNS": "many", "DATABASE_CONNECT_BACKOFF": "soon", "DATABASE_REQUIRE_LATEST_SCHEMA": "maybe"}
+
+		_, err := config.Load(&config.CommandLine{EnvFile: fixture + "env.valid", Flags: flags})
+
+		assert.Contains(t, err.Error(), `DATABASE_MAX_OPEN_CONNS: invalid value "many"`)
+		assert.Contains(t, err.Error(), `DATABASE_CONNECT_BACKOFF: invalid value "soon"`)
+		assert.Contains(t, err.Error(), `DATABASE_REQUIRE_LATEST_SCHEMA: invalid value "maybe"`)
+	})
+
+	t.Run("every invalid value is reported at once", func(t *testing.T) {
+		flags := map[string]string{
+			"PORT":                     "70000",
+			"DATABASE_MAX_OPEN_CONNS":  "2",
+			"DATABASE_MAX_IDLE_CONNS":  "3",
+			"DATABASE_CONNECT_RETRIES": "-1",
+			"DATABASE_SSL_MODE":        "prefer",
+			"HASHID_MIN_LENGTH":        "0",
+			"ATTACHMENT_MAX_SIZE":      "0",
+			"HTTP_REFERRER_POLICY":     "never",
+			"HTTP_BODY_LIMIT":          "0",
+			"HTTP_TRUSTED_PROXIES":     "10.0.0.1",
+			"TLS_CERT_FILE":            "server.crt",
+			"TLS_CLIENT_AUTH":          "require",
+			"TLS_CLIENT_IDENTITIES":    "billing",
+			"HTTP_H2C":                 "true",
+			"ERASURE_GRACE_PERIOD":     "-1h",
+			"EMAIL_CHANGE_TTL":         "0s",
+			"MAIL_SENDER":              "smtp",
+		}
+
+		_, err := config.Load(&config.CommandLine{EnvFile: fixture + "env.valid", Flags: flags})
+
+		cerr, ok := err.(*config.Error)
+		assert.True(t, ok)
+		assert.Equal(t, []string{
+			`PORT: must be between 1 and 65535, got "70000"`,
+			`DATABASE_SSL_MODE: must be one of disable, require, verify-ca, verify-full, got "prefer"`,
+			"DATABASE_MAX_IDLE_CONNS: must not be greater than DATABASE_MAX_OPEN_CONNS (2), got 3",
+			"DATABASE_CONNECT_RETRIES: must not be negative, got -1",
+			"HASHID_MIN_LENGTH: must be at least 1",
+			"ATTACHMENT_MAX_SIZE: must be positive, got 0",
+			"EMAIL_CHANGE_TTL: must be positive, got 0s",
+			`HTTP_REFERRER_POLICY: must be one of no-referrer, no-referrer-when-downgrade, origin, origin-when-cross-origin, same-origin, strict-origin, strict-origin-when-cross-origin, unsafe-url, got "never"`,
+			"HTTP_BODY_LIMIT: must be positive, got 0",
+			`HTTP_TRUSTED_PROXIES: must be in CIDR notation, got "10.0.0.1"`,
+			"TLS_CERT_FILE: must be set together with TLS_KEY_FILE",
+			`TLS_CLIENT_AUTH: needs TLS_CERT_FILE and TLS_CLIENT_CA_FILE, got "require"`,
+			`TLS_CLIENT_IDENTITIES: must be in form of key=value, got "billing"`,
+			"HTTP_H2C: can't be used with TLS, since HTTP/2 is already served over TLS",
+			"ERASURE_GRACE_PERIOD: must not be negative, got -1h0m0s",
+			"MAIL_SMTP_ADDR: is required by smtp sender",
+		}, cerr.Problems)
+	})
+}
+
+func TestParsePairs(t *testing.T) {
+	t.Run("pairs are parsed", func(t *testing.T) {
+		pairs, err := config.ParsePairs(" billing.internal = billing, ,reports.internal=reports")
+
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"billing.internal": "billing", "reports.internal": "reports"}, pairs)
+	})
+
+	t.Run("pair without value", func(t *testing.T) {
+		_, err := config.ParsePairs("billing.internal=")
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestParseCIDRs(t *testing.T) {
+	t.Run("ranges are parsed", func(t *testing.T) {
+		ranges, err := config.ParseCIDRs(" 10.0.0.0/8, ,192.168.1.1/32")
+
+		assert.Nil(t, err)
+		assert.Len(t, ranges, 2)
+		assert.Equal(t, "10.0.0.0/8", ranges[0].String())
+		assert.Equal(t, "192.168.1.1/32", ranges[1].String())
+	})
+
+	t.Run("address without prefix length", func(t *testing.T) {
+		_, err := config.ParseCIDRs("10.0.0.1")
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestFileVersion(t *testing.T) {
+	t.Run("version changes when the file changes", func(t *testing.T) {
+		file := t.TempDir() + "/settings.yaml"
+		assert.Nil(t, ioutil.WriteFile(file, []byte("page_size: 10"), 0600))
+		before := config.FileVersion(file)
+
+		assert.Nil(t, ioutil.WriteFile(file, []byte("page_size: 100"), 0600))
+
+		assert.NotEmpty(t, before)
+		assert.NotEqual(t, before, config.FileVersion(file))
+	})
+
+	t.Run("file which is not set or doesn't exist has no version", func(t *testing.T) {
+		assert.Empty(t, config.FileVersion(""))
+		assert.Empty(t, config.FileVersion(t.TempDir()+"/missing.yaml"))
+	})
+}
+
+func TestConfig_Print(t *testing.T) {
+	t.Run("secrets are masked and sources are written", func(t *testing.T) {
+		cfg, err := config.Load(&config.CommandLine{EnvFile: fixture + "env.valid", Flags: map[string]string{"PORT": "8080"}})
+		assert.Nil(t, err)
+
+		var out bytes.Buffer
+		cfg.Print(&out)
+
+		assert.Contains(t, out.String(), `PORT="8080" # flag`+"\n")
+		assert.Contains(t, out.String(), `DATABASE_HOST="localhost" # env file`+"\n")
+		assert.Contains(t, out.String(), `DATABASE_BACKEND="postgres" # default`+"\n")
+		assert.Contains(t, out.String(), `DATABASE_CONN_MAX_LIFETIME="30m0s" # default`+"\n")
+		assert.Contains(t, out.String(), `MAIL_SENDER="log" # default`+"\n")
+		assert.Contains(t, out.String(), `DATABASE_PASSWORD="******" # env file`+"\n")
+		assert.Contains(t, out.String(), `HASHID_SALT="******" # env file`+"\n")
+		assert.Contains(t, out.String(), `DATABASE_URL="" # unset`+"\n")
+		assert.NotContains(t, out.String(), "password\"")
+	})
+}
+
+// setenv sets the environment variable until the test finishes.
+func setenv(t *testing.T, key, value string) {
+	old, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, old)
+			return
+		}
+		os.Unsetenv(key)
 	})
 }
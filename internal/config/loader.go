@@ -0,0 +1,392 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// SourceDefault marks the value taken from the default in `env` tag.
+	SourceDefault = "default"
+	// SourceConfigFile marks the value taken from the YAML or TOML config file.
+	SourceConfigFile = "config file"
+	// SourceEnvFile marks the value taken from the env file.
+	SourceEnvFile = "env file"
+	// SourceEnv marks the value taken from the environment variables.
+	SourceEnv = "env"
+	// SourceFlag marks the value taken from the command line flags.
+	SourceFlag = "flag"
+
+	// EnvConfigFile is the environment variable holding the path of config file
+	// if it isn't set by --config flag.
+	EnvConfigFile = "CONFIG_FILE"
+	// DefaultEnvFile is the env file read if it isn't set by --env-file flag.
+	// Unlike the file set by the flag, it may be missing.
+	DefaultEnvFile = ".env"
+
+	// fileSuffix marks the key whose value is read from the file it points to, e.g. DATABASE_PASSWORD_FILE.
+	fileSuffix  = "_FILE"
+	maskedValue = "******"
+)
+
+// CommandLine holds the sources of config set in the command line.
+type CommandLine struct {
+	// ConfigFile is the YAML or TOML file, chosen by its extension.
+	ConfigFile string
+	// EnvFile is the dotenv file.
+	EnvFile string
+	// EnvFileOptional allows EnvFile to be missing.
+	EnvFileOptional bool
+	// PrintConfig asks to print the config, with its secrets masked, instead of running.
+	PrintConfig bool
+	// Flags maps env key onto the value set by its flag.
+	Flags map[string]string
+	// Args are the arguments left after the flags, such as a subcommand.
+	Args []string
+}
+
+// ParseCommandLine parses the flags in args.
+// Besides --config, --env-file, and --print-config, every field of Config has its own flag
+// named after its env key, e.g. DATABASE_MAX_OPEN_CONNS is set by --database-max-open-conns.
+// The parsing stops at the first non-flag argument, which is left in Args.
+// The usage is written to output if the flags are invalid or -h is asked.
+func ParseCommandLine(name string, args []string, output io.Writer) (*CommandLine, error) {
+	cmd := &CommandLine{Flags: make(map[string]string)}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&cmd.ConfigFile, "config", os.Getenv(EnvConfigFile), "YAML or TOML config `file`")
+	fs.StringVar(&cmd.EnvFile, "env-file", DefaultEnvFile, "dotenv `file`")
+	fs.BoolVar(&cmd.PrintConfig, "print-config", false, "print the config with secrets masked, then exit")
+	for _, f := range fields(&Config{}) {
+		key := f.key
+		fs.Func(flagName(key), fmt.Sprintf("set %s", key), func(val string) error {
+			cmd.Flags[key] = val
+			return nil
+		})
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	cmd.EnvFileOptional = true
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "env-file" {
+			cmd.EnvFileOptional = false
+		}
+	})
+	cmd.Args = fs.Args()
+	return cmd, nil
+}
+
+// Load creates an instance of Config from the sources in the command line and the environment variables.
+// A key ending with _FILE, e.g. DATABASE_PASSWORD_FILE, sets the key without the suffix
+// to the content of the file, so secrets can be mounted as files.
+// All problems are reported at once, including those found by Validate.
+func Load(cmd *CommandLine) (*Config, error) {
+	cfg := &Config{sources: make(map[string]string)}
+	fs := fields(cfg)
+	known := make(map[string]bool, len(fs))
+	for _, f := range fs {
+		known[f.key] = true
+	}
+
+	var layers []layer
+	if cmd.ConfigFile != "" {
+		values, err := readConfigFile(cmd.ConfigFile)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer{SourceConfigFile, values, true})
+	}
+	if cmd.EnvFile != "" {
+		values, err := godotenv.Read(cmd.EnvFile)
+		if err != nil && !(cmd.EnvFileOptional && errors.Is(err, os.ErrNotExist)) {
+			return nil, fmt.Errorf("read env file %s: %w", cmd.EnvFile, err)
+		}
+		layers = append(layers, layer{SourceEnvFile, values, false})
+	}
+	layers = append(layers, layer{SourceEnv, environ(), false})
+	layers = append(layers, layer{SourceFlag, cmd.Flags, true})
+
+	values := make(map[string]string)
+	var problems []string
+	for _, l := range layers {
+		resolved, errs := l.resolve(known)
+		problems = append(problems, errs...)
+		for key, val := range resolved {
+			values[key] = val
+			cfg.sources[key] = l.source
+		}
+	}
+
+	for _, f := range fs {
+		val, ok := values[f.key]
+		switch {
+		case ok:
+		case f.hasDefault:
+			val = f.defaultValue
+			cfg.sources[f.key] = SourceDefault
+		case f.required:
+			problems = append(problems, fmt.Sprintf("%s is required", f.key))
+			continue
+		default:
+			continue
+		}
+		if err := setValue(f.value, val); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: invalid value %q: %v", f.key, val, err))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, newConfigError(problems)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Print writes every key and its value in env file format, followed by where the value is taken from.
+// The value of secret is masked.
+func (c *Config) Print(w io.Writer) {
+	for _, f := range fields(c) {
+		val := formatValue(f.value)
+		if f.secret && val != "" {
+			val = maskedValue
+		}
+		source := c.sources[f.key]
+		if source == "" {
+			source = "unset"
+		}
+		fmt.Fprintf(w, "%s=%s # %s\n", f.key, strconv.Quote(val), source)
+	}
+}
+
+// FileVersion tells a modification of the file by its modification time and size.
+// It is empty if the file is not set or can't be read, so the watchers of a file compare the versions to reload it.
+func FileVersion(file string) string {
+	if file == "" {
+		return ""
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%d", info.ModTime(), info.Size())
+}
+
+// layer is the values from one source.
+// If strict, a key which isn't known is a problem, since the source is only meant for this config.
+type layer struct {
+	source string
+	values map[string]string
+	strict bool
+}
+
+// resolve keeps only the known keys and replaces the keys ending with _FILE with the content of the file.
+func (l layer) resolve(known map[string]bool) (map[string]string, []string) {
+	res := make(map[string]string)
+	var problems []string
+	for key, val := range l.values {
+		if known[key] {
+			res[key] = val
+			continue
+		}
+		target := strings.TrimSuffix(key, fileSuffix)
+		if target == key || !known[target] {
+			if l.strict {
+				problems = append(problems, fmt.Sprintf("%s: unknown key in %s", key, l.source))
+			}
+			continue
+		}
+		if _, ok := l.values[target]; ok {
+			problems = append(problems, fmt.Sprintf("%s and %s are both set in %s", target, key, l.source))
+			continue
+		}
+		content, err := ioutil.ReadFile(val)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		res[target] = strings.TrimRight(string(content), "\r\n")
+	}
+	sort.Strings(problems)
+	return res, problems
+}
+
+// readConfigFile reads YAML or TOML file and flattens it into env keys.
+// Nested keys are joined by underscore, so `database: {max_open_conns: 5}` sets DATABASE_MAX_OPEN_CONNS.
+// A list is joined by comma.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file %s: %w", path, err)
+	}
+
+	var tree map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".toml":
+		err = toml.Unmarshal(data, &tree)
+	default:
+		return nil, fmt.Errorf("config file %s must be .yaml, .yml, or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode config file %s: %w", path, err)
+	}
+
+	res := make(map[string]string)
+	flatten("", tree, res)
+	return res, nil
+}
+
+func flatten(prefix string, tree map[string]interface{}, res map[string]string) {
+	for key, val := range tree {
+		key = strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		switch v := val.(type) {
+		case map[string]interface{}:
+			flatten(key, v, res)
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			res[key] = strings.Join(items, ",")
+		case nil:
+			res[key] = ""
+		default:
+			res[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+func environ() map[string]string {
+	res := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if idx := strings.Index(kv, "="); idx > 0 {
+			res[kv[:idx]] = kv[idx+1:]
+		}
+	}
+	return res
+}
+
+// field is a leaf of Config set by its `env` tag.
+type field struct {
+	key          string
+	defaultValue string
+	hasDefault   bool
+	required     bool
+	secret       bool
+	value        reflect.Value
+}
+
+// fields lists the fields of Config in the order they are declared.
+func fields(cfg *Config) []field {
+	var res []field
+	collectFields(reflect.ValueOf(cfg).Elem(), &res)
+	return res
+}
+
+func collectFields(val reflect.Value, res *[]field) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		tag, ok := sf.Tag.Lookup("env")
+		if !ok {
+			if val.Field(i).Kind() == reflect.Struct {
+				collectFields(val.Field(i), res)
+			}
+			continue
+		}
+
+		// default must be the last option, since the default of a list contains commas.
+		parts := strings.Split(tag, ",")
+		f := field{key: parts[0], secret: sf.Tag.Get("secret") == "true", value: val.Field(i)}
+		for j, opt := range parts[1:] {
+			if opt == "required" {
+				f.required = true
+			}
+			if strings.HasPrefix(opt, "default=") {
+				f.defaultValue, f.hasDefault = strings.TrimPrefix(strings.Join(parts[j+1:], ","), "default="), true
+				break
+			}
+		}
+		*res = append(*res, f)
+	}
+}
+
+func setValue(val reflect.Value, str string) error {
+	if val.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return err
+		}
+		val.SetInt(int64(d))
+		return nil
+	}
+
+	switch val.Kind() {
+	case reflect.String:
+		val.SetString(str)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return err
+		}
+		val.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(str, 10, val.Type().Bits())
+		if err != nil {
+			return err
+		}
+		val.SetInt(n)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return err
+		}
+		val.SetFloat(n)
+	case reflect.Uint, reflect.Uint64:
+		n, err := strconv.ParseUint(str, 10, val.Type().Bits())
+		if err != nil {
+			return err
+		}
+		val.SetUint(n)
+	default:
+		return fmt.Errorf("unsupported type %s", val.Type())
+	}
+	return nil
+}
+
+func formatValue(val reflect.Value) string {
+	if d, ok := val.Interface().(time.Duration); ok {
+		return d.String()
+	}
+	return fmt.Sprint(val.Interface())
+}
+
+func flagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
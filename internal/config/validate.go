@@ -0,0 +1,224 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	minPort     = 1
+	maxPort     = 65535
+	minPageSize = 1
+	maxPageSize = 100
+)
+
+// Error lists every problem found in the config.
+type Error struct {
+	Problems []string
+}
+
+func newConfigError(problems []string) *Error {
+	return &Error{Problems: problems}
+}
+
+// Error returns the problems, one per line.
+func (e *Error) Error() string {
+	return "invalid config:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// Validate checks the values which can be decoded but make no sense, such as port out of range.
+// Each problem is named after the env key, so it is clear what to fix.
+func (c *Config) Validate() error {
+	var v validator
+
+	port, err := strconv.Atoi(c.Port)
+	if err != nil || port < minPort || port > maxPort {
+		v.addf("PORT", "must be between %d and %d, got %q", minPort, maxPort, c.Port)
+	}
+
+	v.oneOf("DATABASE_BACKEND", c.Database.Backend, "postgres", "sqlite", "memory")
+	v.oneOf("DATABASE_SSL_MODE", c.Database.SSLMode, "disable", "require", "verify-ca", "verify-full")
+	v.nonNegative("DATABASE_MAX_OPEN_CONNS", c.Database.MaxOpenConns)
+	v.nonNegative("DATABASE_MAX_IDLE_CONNS", c.Database.MaxIdleConns)
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		v.addf("DATABASE_MAX_IDLE_CONNS", "must not be greater than DATABASE_MAX_OPEN_CONNS (%d), got %d", c.Database.MaxOpenConns, c.Database.MaxIdleConns)
+	}
+	v.nonNegative("DATABASE_STATEMENT_CACHE_CAPACITY", c.Database.StatementCacheCapacity)
+	v.nonNegative("DATABASE_CONNECT_RETRIES", c.Database.ConnectRetries)
+	v.nonNegative("DATABASE_TX_MAX_RETRIES", c.Database.TxMaxRetries)
+	v.nonNegativeDuration("DATABASE_CONN_MAX_LIFETIME", c.Database.ConnMaxLifetime)
+	v.nonNegativeDuration("DATABASE_CONN_MAX_IDLE_TIME", c.Database.ConnMaxIdleTime)
+	v.nonNegativeDuration("DATABASE_STATEMENT_TIMEOUT", c.Database.StatementTimeout)
+	v.nonNegativeDuration("DATABASE_CONNECT_BACKOFF", c.Database.ConnectBackoff)
+	v.nonNegativeDuration("DATABASE_READ_YOUR_WRITES_WINDOW", c.Database.ReadYourWritesWindow)
+	v.nonNegativeDuration("DATABASE_REPLICA_CHECK_INTERVAL", c.Database.ReplicaCheckInterval)
+
+	if c.Hashid.MinLength == 0 {
+		v.addf("HASHID_MIN_LENGTH", "must be at least 1")
+	}
+
+	if c.Attachment.MaxSize <= 0 {
+		v.addf("ATTACHMENT_MAX_SIZE", "must be positive, got %d", c.Attachment.MaxSize)
+	}
+	v.oneOf("ATTACHMENT_STORAGE", c.Attachment.Storage, "filesystem", "s3")
+
+	v.oneOf("EMAIL_VALIDATION", c.Email.Validation, "syntax", "mx")
+	v.nonNegativeDuration("EMAIL_MX_CACHE_TTL", c.Email.MXCacheTTL)
+	v.nonNegativeDuration("EMAIL_MX_NEGATIVE_CACHE_TTL", c.Email.MXNegativeCacheTTL)
+	if c.Email.ChangeTTL <= 0 {
+		v.addf("EMAIL_CHANGE_TTL", "must be positive, got %s", c.Email.ChangeTTL)
+	}
+
+	v.nonNegativeDuration("HTTP_CORS_MAX_AGE", c.HTTP.CORSMaxAge)
+	v.nonNegativeDuration("HTTP_HSTS_MAX_AGE", c.HTTP.HSTSMaxAge)
+	v.oneOf("HTTP_REFERRER_POLICY", c.HTTP.ReferrerPolicy, "no-referrer", "no-referrer-when-downgrade", "origin", "origin-when-cross-origin",
+		"same-origin", "strict-origin", "strict-origin-when-cross-origin", "unsafe-url")
+	if c.HTTP.BodyLimit <= 0 {
+		v.addf("HTTP_BODY_LIMIT", "must be positive, got %d", c.HTTP.BodyLimit)
+	}
+	if c.HTTP.ImportBodyLimit <= 0 {
+		v.addf("HTTP_IMPORT_BODY_LIMIT", "must be positive, got %d", c.HTTP.ImportBodyLimit)
+	}
+	if _, err := ParseCIDRs(c.HTTP.TrustedProxies); err != nil {
+		v.addf("HTTP_TRUSTED_PROXIES", "%v", err)
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		v.addf("TLS_CERT_FILE", "must be set together with TLS_KEY_FILE")
+	}
+	v.nonNegativeDuration("TLS_RELOAD_INTERVAL", c.TLS.ReloadInterval)
+	v.oneOf("TLS_MIN_VERSION", c.TLS.MinVersion, "1.2", "1.3")
+	v.oneOf("TLS_CLIENT_AUTH", c.TLS.ClientAuth, "none", "optional", "require")
+	if c.TLS.ClientAuth != "none" && (c.TLS.CertFile == "" || c.TLS.ClientCAFile == "") {
+		v.addf("TLS_CLIENT_AUTH", "needs TLS_CERT_FILE and TLS_CLIENT_CA_FILE, got %q", c.TLS.ClientAuth)
+	}
+	if _, err := ParsePairs(c.TLS.ClientIdentities); err != nil {
+		v.addf("TLS_CLIENT_IDENTITIES", "%v", err)
+	}
+	if c.HTTP.H2C && c.TLS.CertFile != "" {
+		v.addf("HTTP_H2C", "can't be used with TLS, since HTTP/2 is already served over TLS")
+	}
+
+	v.nonNegativeDuration("SHUTDOWN_DRAIN_PERIOD", c.Shutdown.DrainPeriod)
+	if c.Shutdown.Timeout <= 0 {
+		v.addf("SHUTDOWN_TIMEOUT", "must be positive, got %s", c.Shutdown.Timeout)
+	}
+
+	v.nonNegativeDuration("ERASURE_GRACE_PERIOD", c.Erasure.GracePeriod)
+	v.nonNegativeDuration("ERASURE_CHECK_INTERVAL", c.Erasure.CheckInterval)
+	if c.Erasure.BatchSize == 0 {
+		v.addf("ERASURE_BATCH_SIZE", "must be positive, got 0")
+	}
+
+	v.oneOf("MAIL_SENDER", c.Mail.Sender, "log", "smtp")
+	if c.Mail.Sender == "smtp" && c.Mail.SMTPAddr == "" {
+		v.addf("MAIL_SMTP_ADDR", "is required by smtp sender")
+	}
+
+	v.nonNegativeDuration("CONFIG_WATCH_INTERVAL", c.ConfigWatchInterval)
+	v.problems = append(v.problems, c.Runtime.problems()...)
+
+	if len(v.problems) > 0 {
+		return newConfigError(v.problems)
+	}
+	return nil
+}
+
+// Validate checks the runtime configuration alone, so a reloaded one can be checked before it is applied.
+func (r *Runtime) Validate() error {
+	if problems := r.problems(); len(problems) > 0 {
+		return newConfigError(problems)
+	}
+	return nil
+}
+
+func (r *Runtime) problems() []string {
+	var v validator
+
+	if r.PageSize < minPageSize || r.PageSize > maxPageSize {
+		v.addf("RUNTIME_PAGE_SIZE", "must be between %d and %d, got %d", minPageSize, maxPageSize, r.PageSize)
+	}
+	if r.RateLimit < 0 {
+		v.addf("RUNTIME_RATE_LIMIT", "must not be negative, got %g", r.RateLimit)
+	}
+	if r.RateLimit > 0 && r.RateBurst < 1 {
+		v.addf("RUNTIME_RATE_BURST", "must be at least 1 if RUNTIME_RATE_LIMIT is set, got %d", r.RateBurst)
+	}
+	v.oneOf("RUNTIME_LOG_LEVEL", r.LogLevel, "debug", "info", "warn", "error", "off")
+	for _, origin := range SplitList(r.CORSAllowOrigins) {
+		if u, err := url.Parse(origin); origin != "*" && (err != nil || u.Scheme == "" || u.Host == "" || u.Path != "") {
+			v.addf("RUNTIME_CORS_ALLOW_ORIGINS", "must be * or origins such as https://example.com, got %q", origin)
+		}
+	}
+	return v.problems
+}
+
+// SplitList splits the comma-separated value, trimming spaces and dropping empty items.
+func SplitList(value string) []string {
+	var res []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// ParsePairs parses the comma-separated `key=value` pairs, trimming spaces and dropping empty items.
+func ParsePairs(value string) (map[string]string, error) {
+	res := make(map[string]string)
+	for _, item := range SplitList(value) {
+		idx := strings.Index(item, "=")
+		if idx <= 0 || idx == len(item)-1 {
+			return nil, fmt.Errorf("must be in form of key=value, got %q", item)
+		}
+		res[strings.TrimSpace(item[:idx])] = strings.TrimSpace(item[idx+1:])
+	}
+	return res, nil
+}
+
+// ParseCIDRs parses the comma-separated IP ranges in CIDR notation, trimming spaces and dropping empty items.
+func ParseCIDRs(value string) ([]*net.IPNet, error) {
+	var res []*net.IPNet
+	for _, item := range SplitList(value) {
+		_, ipNet, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, fmt.Errorf("must be in CIDR notation, got %q", item)
+		}
+		res = append(res, ipNet)
+	}
+	return res, nil
+}
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(key, format string, args ...interface{}) {
+	v.problems = append(v.problems, key+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) oneOf(key, val string, options ...string) {
+	for _, opt := range options {
+		if val == opt {
+			return
+		}
+	}
+	v.addf(key, "must be one of %s, got %q", strings.Join(options, ", "), val)
+}
+
+func (v *validator) nonNegative(key string, val int) {
+	if val < 0 {
+		v.addf(key, "must not be negative, got %d", val)
+	}
+}
+
+func (v *validator) nonNegativeDuration(key string, val time.Duration) {
+	if val < 0 {
+		v.addf(key, "must not be negative, got %s", val)
+	}
+}
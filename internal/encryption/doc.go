@@ -0,0 +1,4 @@
+// Package encryption provides envelope encryption for data at rest.
+// Every value is encrypted using its own data key,
+// and the data key is wrapped (encrypted) by a versioned master key kept in a key manager.
+package encryption
@@ -0,0 +1,127 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// reservedPrefix starts every value written by the ciphers which isn't kept as is.
+	reservedPrefix = "orvosi:"
+	// envelopePrefix marks an encrypted value.
+	// Value without the prefix is treated as plaintext written before encryption was enabled.
+	envelopePrefix = reservedPrefix + "enc:v1:"
+	// plaintextPrefix marks a plaintext which starts with reservedPrefix itself,
+	// so a plaintext such as `orvosi:enc:v1:...` isn't mistaken for an encrypted value.
+	plaintextPrefix = reservedPrefix + "plain:"
+)
+
+// Envelope encrypts a value using a fresh AES-256-GCM data key
+// and stores the data key wrapped by the key manager next to the ciphertext.
+// The encrypted value is a string in form of
+// `orvosi:enc:v1:<master key version>:<base64 wrapped data key>:<base64 ciphertext>`,
+// so it fits the existing TEXT columns.
+type Envelope struct {
+	kms KeyManager
+}
+
+// NewEnvelope creates an instance of Envelope.
+func NewEnvelope(kms KeyManager) *Envelope {
+	return &Envelope{kms: kms}
+}
+
+// Encrypt encrypts the plaintext of the field held by the row, such as `medical_records:42`.
+// The field name and the row are authenticated along with the ciphertext,
+// so a value can't be copied to another field or another row without being detected.
+func (e *Envelope) Encrypt(field, row, plaintext string) (string, error) {
+	dataKey := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(aead, []byte(plaintext), additionalData(field, row))
+	if err != nil {
+		return "", err
+	}
+
+	version, wrapped, err := e.kms.Wrap(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	return envelopePrefix + version + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt decrypts the value of the field held by the row.
+// Stale is true if the value is plaintext or encrypted by a master key other than the active one,
+// which means the value should be encrypted again.
+func (e *Envelope) Decrypt(field, row, value string) (string, bool, error) {
+	if !strings.HasPrefix(value, envelopePrefix) {
+		return strings.TrimPrefix(value, plaintextPrefix), true, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, envelopePrefix), ":")
+	if len(parts) != 3 {
+		return "", false, fmt.Errorf("encrypted value of %s is malformed", field)
+	}
+	version := parts[0]
+
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", false, fmt.Errorf("wrapped key of %s is malformed: %v", field, err)
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", false, fmt.Errorf("ciphertext of %s is malformed: %v", field, err)
+	}
+
+	dataKey, err := e.kms.Unwrap(version, wrapped)
+	if err != nil {
+		return "", false, fmt.Errorf("unwrap key of %s: %v", field, err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", false, err
+	}
+	plaintext, err := open(aead, ciphertext, additionalData(field, row))
+	if err != nil {
+		return "", false, fmt.Errorf("decrypt %s: %v", field, err)
+	}
+	return string(plaintext), version != e.kms.ActiveVersion(), nil
+}
+
+// additionalData returns the data authenticated along with the ciphertext.
+func additionalData(field, row string) []byte {
+	return []byte(field + ":" + row)
+}
+
+// Plaintext keeps the value as is.
+// It is used when no master key is configured.
+type Plaintext struct{}
+
+// Encrypt returns the plaintext as is.
+// Plaintext which starts with `orvosi:` is marked, so it can't be mistaken for an encrypted value.
+func (Plaintext) Encrypt(field, row, plaintext string) (string, error) {
+	if strings.HasPrefix(plaintext, reservedPrefix) {
+		return plaintextPrefix + plaintext, nil
+	}
+	return plaintext, nil
+}
+
+// Decrypt returns the value as it was given to Encrypt and never reports it as stale.
+// Value which was encrypted can't be read without the master key, so it is rejected.
+func (Plaintext) Decrypt(field, row, value string) (string, bool, error) {
+	if strings.HasPrefix(value, envelopePrefix) {
+		return "", false, fmt.Errorf("%s is encrypted but no master key is configured", field)
+	}
+	return strings.TrimPrefix(value, plaintextPrefix), false, nil
+}
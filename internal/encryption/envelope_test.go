@@ -0,0 +1,117 @@
+package encryption_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/encryption"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvelope(t *testing.T) {
+	oldKMS, _ := encryption.NewLocalKMS(map[string][]byte{"v1": createKey(1)}, "v1")
+	newKMS, _ := encryption.NewLocalKMS(map[string][]byte{"v1": createKey(1), "v2": createKey(2)}, "v2")
+
+	t.Run("successfully encrypt and decrypt using active key", func(t *testing.T) {
+		env := encryption.NewEnvelope(newKMS)
+
+		value, err := env.Encrypt("symptom", "medical_records:1", "Fever")
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(value, "orvosi:enc:v1:v2:"))
+		assert.NotContains(t, value, "Fever")
+
+		plaintext, stale, err := env.Decrypt("symptom", "medical_records:1", value)
+		assert.Nil(t, err)
+		assert.False(t, stale)
+		assert.Equal(t, "Fever", plaintext)
+	})
+
+	t.Run("same plaintext is encrypted differently", func(t *testing.T) {
+		env := encryption.NewEnvelope(newKMS)
+
+		first, _ := env.Encrypt("symptom", "medical_records:1", "Fever")
+		second, _ := env.Encrypt("symptom", "medical_records:1", "Fever")
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("value encrypted by old key is stale", func(t *testing.T) {
+		value, _ := encryption.NewEnvelope(oldKMS).Encrypt("diagnosis", "medical_records:1", "Flu")
+
+		plaintext, stale, err := encryption.NewEnvelope(newKMS).Decrypt("diagnosis", "medical_records:1", value)
+		assert.Nil(t, err)
+		assert.True(t, stale)
+		assert.Equal(t, "Flu", plaintext)
+	})
+
+	t.Run("plaintext value is stale", func(t *testing.T) {
+		plaintext, stale, err := encryption.NewEnvelope(newKMS).Decrypt("therapy", "medical_records:1", "Rest")
+		assert.Nil(t, err)
+		assert.True(t, stale)
+		assert.Equal(t, "Rest", plaintext)
+	})
+
+	t.Run("marked plaintext value is stale", func(t *testing.T) {
+		value, _ := encryption.Plaintext{}.Encrypt("therapy", "medical_records:1", "orvosi:enc:v1:rest")
+
+		plaintext, stale, err := encryption.NewEnvelope(newKMS).Decrypt("therapy", "medical_records:1", value)
+		assert.Nil(t, err)
+		assert.True(t, stale)
+		assert.Equal(t, "orvosi:enc:v1:rest", plaintext)
+	})
+
+	t.Run("value moved to other row can't be decrypted", func(t *testing.T) {
+		env := encryption.NewEnvelope(newKMS)
+		value, _ := env.Encrypt("symptom", "medical_records:1", "Fever")
+
+		_, _, err := env.Decrypt("symptom", "medical_records:2", value)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("value moved to other field or tampered can't be decrypted", func(t *testing.T) {
+		env := encryption.NewEnvelope(newKMS)
+		value, _ := env.Encrypt("symptom", "medical_records:1", "Fever")
+
+		_, _, err := env.Decrypt("diagnosis", "medical_records:1", value)
+		assert.NotNil(t, err)
+
+		tampered := value[:len(value)-2] + "AA"
+		_, _, err = env.Decrypt("symptom", "medical_records:1", tampered)
+		assert.NotNil(t, err)
+
+		_, _, err = env.Decrypt("symptom", "medical_records:1", "orvosi:enc:v1:v2:malformed")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestPlaintext(t *testing.T) {
+	t.Run("value is kept as is", func(t *testing.T) {
+		value, err := encryption.Plaintext{}.Encrypt("symptom", "medical_records:1", "Fever")
+		assert.Nil(t, err)
+		assert.Equal(t, "Fever", value)
+
+		plaintext, stale, err := encryption.Plaintext{}.Decrypt("symptom", "medical_records:1", value)
+		assert.Nil(t, err)
+		assert.False(t, stale)
+		assert.Equal(t, "Fever", plaintext)
+	})
+
+	t.Run("value which looks encrypted is kept as is", func(t *testing.T) {
+		for _, text := range []string{"orvosi:enc:v1:v1:a:b", "orvosi:plain:Fever", "orvosi:"} {
+			value, err := encryption.Plaintext{}.Encrypt("symptom", "medical_records:1", text)
+			assert.Nil(t, err)
+
+			plaintext, stale, err := encryption.Plaintext{}.Decrypt("symptom", "medical_records:1", value)
+			assert.Nil(t, err)
+			assert.False(t, stale)
+			assert.Equal(t, text, plaintext)
+		}
+	})
+
+	t.Run("encrypted value can't be read", func(t *testing.T) {
+		kms, _ := encryption.NewLocalKMS(map[string][]byte{"v1": createKey(1)}, "v1")
+		value, _ := encryption.NewEnvelope(kms).Encrypt("symptom", "medical_records:1", "Fever")
+
+		_, _, err := encryption.Plaintext{}.Decrypt("symptom", "medical_records:1", value)
+		assert.NotNil(t, err)
+	})
+}
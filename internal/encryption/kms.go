@@ -0,0 +1,154 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// KeySize is the size of master key and data key in bytes (AES-256).
+const KeySize = 32
+
+// KeyManager defines the contract of a key management service
+// which keeps the master keys and wraps the data keys.
+type KeyManager interface {
+	// ActiveVersion returns the version of the master key used to wrap new data keys.
+	ActiveVersion() string
+	// Wrap encrypts the data key using the active master key.
+	Wrap(dataKey []byte) (version string, wrapped []byte, err error)
+	// Unwrap decrypts the data key using the master key of the version.
+	Unwrap(version string, wrapped []byte) ([]byte, error)
+}
+
+// LocalKMS is a key manager which keeps the master keys in memory.
+// It stands in for a real KMS, so the master keys are either taken from config or a local key file.
+type LocalKMS struct {
+	keys   map[string]cipher.AEAD
+	active string
+}
+
+// localKMSFile is the format of local KMS key file.
+type localKMSFile struct {
+	Active string            `json:"active"`
+	Keys   map[string]string `json:"keys"`
+}
+
+// NewLocalKMS creates an instance of LocalKMS.
+// Every key must be 32 bytes and the active version must be one of the keys.
+func NewLocalKMS(keys map[string][]byte, active string) (*LocalKMS, error) {
+	if _, ok := keys[active]; !ok {
+		return nil, fmt.Errorf("active key version %q doesn't exist", active)
+	}
+
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for version, key := range keys {
+		if version == "" || strings.ContainsAny(version, ":,") {
+			return nil, fmt.Errorf("key version %q is invalid", version)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("key version %q: %v", version, err)
+		}
+		aeads[version] = aead
+	}
+	return &LocalKMS{keys: aeads, active: active}, nil
+}
+
+// ParseKeys parses master keys in form of `version:base64,version:base64`.
+func ParseKeys(spec string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		idx := strings.Index(item, ":")
+		if idx <= 0 {
+			return nil, fmt.Errorf("key %q must be in form of version:base64", item)
+		}
+		key, err := base64.StdEncoding.DecodeString(item[idx+1:])
+		if err != nil {
+			return nil, fmt.Errorf("key version %q is not base64: %v", item[:idx], err)
+		}
+		keys[item[:idx]] = key
+	}
+	return keys, nil
+}
+
+// LoadLocalKMS creates an instance of LocalKMS from a JSON key file
+// in form of `{"active": "v2", "keys": {"v1": "base64", "v2": "base64"}}`.
+func LoadLocalKMS(path string) (*LocalKMS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file localKMSFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("key file %s is invalid: %v", path, err)
+	}
+
+	keys := make(map[string][]byte, len(file.Keys))
+	for version, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key version %q is not base64: %v", version, err)
+		}
+		keys[version] = key
+	}
+	return NewLocalKMS(keys, file.Active)
+}
+
+// ActiveVersion returns the version of the active master key.
+func (lk *LocalKMS) ActiveVersion() string {
+	return lk.active
+}
+
+// Wrap encrypts the data key using AES-GCM with the active master key.
+func (lk *LocalKMS) Wrap(dataKey []byte) (string, []byte, error) {
+	wrapped, err := seal(lk.keys[lk.active], dataKey, []byte(lk.active))
+	return lk.active, wrapped, err
+}
+
+// Unwrap decrypts the data key using the master key of the version.
+func (lk *LocalKMS) Unwrap(version string, wrapped []byte) ([]byte, error) {
+	aead, ok := lk.keys[version]
+	if !ok {
+		return nil, fmt.Errorf("key version %q doesn't exist", version)
+	}
+	return open(aead, wrapped, []byte(version))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes", KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts the plaintext and puts the random nonce in front of the ciphertext.
+func seal(aead cipher.AEAD, plaintext, additional []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+func open(aead cipher.AEAD, sealed, additional []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additional)
+}
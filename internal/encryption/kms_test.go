@@ -0,0 +1,111 @@
+package encryption_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/encryption"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLocalKMS(t *testing.T) {
+	t.Run("active version or key is invalid", func(t *testing.T) {
+		tables := []struct {
+			keys   map[string][]byte
+			active string
+		}{
+			{map[string][]byte{"v1": createKey(1)}, "v2"},
+			{map[string][]byte{"v1": []byte("short")}, "v1"},
+			{map[string][]byte{"v1": createKey(1), "v:2": createKey(2)}, "v1"},
+		}
+
+		for _, table := range tables {
+			kms, err := encryption.NewLocalKMS(table.keys, table.active)
+			assert.NotNil(t, err)
+			assert.Nil(t, kms)
+		}
+	})
+
+	t.Run("successfully create an instance of LocalKMS", func(t *testing.T) {
+		kms, err := encryption.NewLocalKMS(map[string][]byte{"v1": createKey(1)}, "v1")
+		assert.Nil(t, err)
+		assert.Equal(t, "v1", kms.ActiveVersion())
+	})
+}
+
+func TestParseKeys(t *testing.T) {
+	t.Run("spec is invalid", func(t *testing.T) {
+		for _, spec := range []string{"no-version", ":" + encodeKey(1), "v1:not base64!"} {
+			keys, err := encryption.ParseKeys(spec)
+			assert.NotNil(t, err)
+			assert.Nil(t, keys)
+		}
+	})
+
+	t.Run("successfully parse keys", func(t *testing.T) {
+		keys, err := encryption.ParseKeys("v1:" + encodeKey(1) + ", v2:" + encodeKey(2))
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(keys))
+		assert.Equal(t, createKey(2), keys["v2"])
+	})
+}
+
+func TestLoadLocalKMS(t *testing.T) {
+	t.Run("file doesn't exist or is invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		invalid := filepath.Join(dir, "invalid.json")
+		os.WriteFile(invalid, []byte("{"), 0o600)
+
+		for _, path := range []string{filepath.Join(dir, "missing.json"), invalid} {
+			kms, err := encryption.LoadLocalKMS(path)
+			assert.NotNil(t, err)
+			assert.Nil(t, kms)
+		}
+	})
+
+	t.Run("successfully load key file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "kms.json")
+		os.WriteFile(path, []byte(`{"active":"v2","keys":{"v1":"`+encodeKey(1)+`","v2":"`+encodeKey(2)+`"}}`), 0o600)
+
+		kms, err := encryption.LoadLocalKMS(path)
+		assert.Nil(t, err)
+		assert.Equal(t, "v2", kms.ActiveVersion())
+	})
+}
+
+func TestLocalKMS_WrapUnwrap(t *testing.T) {
+	kms, _ := encryption.NewLocalKMS(map[string][]byte{"v1": createKey(1), "v2": createKey(2)}, "v2")
+
+	t.Run("data key is wrapped by the active key", func(t *testing.T) {
+		version, wrapped, err := kms.Wrap(createKey(9))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "v2", version)
+		assert.False(t, bytes.Contains(wrapped, createKey(9)))
+
+		key, err := kms.Unwrap(version, wrapped)
+		assert.Nil(t, err)
+		assert.Equal(t, createKey(9), key)
+	})
+
+	t.Run("data key can't be unwrapped by other version", func(t *testing.T) {
+		_, wrapped, _ := kms.Wrap(createKey(9))
+
+		for _, version := range []string{"v1", "v3"} {
+			key, err := kms.Unwrap(version, wrapped)
+			assert.NotNil(t, err)
+			assert.Nil(t, key)
+		}
+	})
+}
+
+func createKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, encryption.KeySize)
+}
+
+func encodeKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(createKey(b))
+}
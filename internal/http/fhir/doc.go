@@ -0,0 +1,3 @@
+// Package fhir provides HL7 FHIR R4 representation of the domain.
+// It only contains the subset of resources and elements needed by the API.
+package fhir
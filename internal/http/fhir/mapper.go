@@ -0,0 +1,230 @@
+package fhir
+
+import (
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+const (
+	// EmailIdentifierSystem is the identifier system used to refer a user by email.
+	EmailIdentifierSystem = "urn:ietf:rfc:3986"
+
+	loincSystem             = "http://loinc.org"
+	loincSymptomCode        = "75325-1"
+	actCodeSystem           = "http://terminology.hl7.org/CodeSystem/v3-ActCode"
+	conditionCategorySystem = "http://terminology.hl7.org/CodeSystem/condition-category"
+	conditionVerStatSystem  = "http://terminology.hl7.org/CodeSystem/condition-ver-status"
+)
+
+// ResourceTypes lists all resource types that are derived from a medical record.
+var ResourceTypes = []string{
+	ResourceTypeEncounter,
+	ResourceTypeObservation,
+	ResourceTypeCondition,
+	ResourceTypeCarePlan,
+}
+
+// IsResourceTypeSupported tells whether the resource type can be derived from a medical record.
+func IsResourceTypeSupported(resourceType string) bool {
+	for _, rt := range ResourceTypes {
+		if rt == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+// NewResource maps a medical record into a resource of certain type.
+// All resources derived from the same medical record share the same id.
+// It returns nil if the resource type is not supported.
+func NewResource(resourceType string, mr *entity.MedicalRecord, email string) interface{} {
+	switch resourceType {
+	case ResourceTypeEncounter:
+		return NewEncounter(mr, email)
+	case ResourceTypeObservation:
+		return NewObservation(mr, email)
+	case ResourceTypeCondition:
+		return NewCondition(mr, email)
+	case ResourceTypeCarePlan:
+		return NewCarePlan(mr, email)
+	}
+	return nil
+}
+
+// NewEncounter maps a medical record into an Encounter.
+// A medical record is a single ambulatory visit.
+func NewEncounter(mr *entity.MedicalRecord, email string) *Encounter {
+	id := resourceID(mr.ID)
+	return &Encounter{
+		ResourceType: ResourceTypeEncounter,
+		ID:           id,
+		Meta:         newMeta(mr),
+		Status:       "finished",
+		Class:        Coding{System: actCodeSystem, Code: "AMB", Display: "ambulatory"},
+		Subject:      newSubject(email),
+		Period:       &Period{Start: formatTime(mr.CreatedAt)},
+		Diagnosis:    []EncounterDiagnosis{{Condition: Reference{Reference: ResourceTypeCondition + "/" + id}}},
+	}
+}
+
+// NewObservation maps the symptom of a medical record into an Observation.
+func NewObservation(mr *entity.MedicalRecord, email string) *Observation {
+	id := resourceID(mr.ID)
+	return &Observation{
+		ResourceType: ResourceTypeObservation,
+		ID:           id,
+		Meta:         newMeta(mr),
+		Status:       "final",
+		Code: CodeableConcept{
+			Coding: []Coding{{System: loincSystem, Code: loincSymptomCode, Display: "Symptom"}},
+			Text:   "Symptom",
+		},
+		Subject:           newSubject(email),
+		Encounter:         &Reference{Reference: ResourceTypeEncounter + "/" + id},
+		EffectiveDateTime: formatTime(mr.CreatedAt),
+		Issued:            formatTime(mr.CreatedAt),
+		ValueString:       mr.Symptom,
+	}
+}
+
+// NewCondition maps the diagnosis of a medical record into a Condition.
+func NewCondition(mr *entity.MedicalRecord, email string) *Condition {
+	id := resourceID(mr.ID)
+	return &Condition{
+		ResourceType: ResourceTypeCondition,
+		ID:           id,
+		Meta:         newMeta(mr),
+		VerificationStatus: &CodeableConcept{
+			Coding: []Coding{{System: conditionVerStatSystem, Code: "confirmed"}},
+		},
+		Category: []CodeableConcept{{
+			Coding: []Coding{{System: conditionCategorySystem, Code: "encounter-diagnosis", Display: "Encounter Diagnosis"}},
+		}},
+		Code:         CodeableConcept{Text: mr.Diagnosis},
+		Subject:      *newSubject(email),
+		Encounter:    &Reference{Reference: ResourceTypeEncounter + "/" + id},
+		RecordedDate: formatTime(mr.CreatedAt),
+	}
+}
+
+// NewCarePlan maps the therapy of a medical record into a CarePlan.
+// The result of the therapy, if any, is put as note.
+func NewCarePlan(mr *entity.MedicalRecord, email string) *CarePlan {
+	id := resourceID(mr.ID)
+	cp := &CarePlan{
+		ResourceType: ResourceTypeCarePlan,
+		ID:           id,
+		Meta:         newMeta(mr),
+		Status:       "active",
+		Intent:       "plan",
+		Description:  mr.Therapy,
+		Subject:      *newSubject(email),
+		Encounter:    &Reference{Reference: ResourceTypeEncounter + "/" + id},
+		Created:      formatTime(mr.CreatedAt),
+		Addresses:    []Reference{{Reference: ResourceTypeCondition + "/" + id}},
+	}
+	if mr.Result != "" {
+		cp.Status = "completed"
+		cp.Note = []Annotation{{Time: formatTime(mr.UpdatedAt), Text: mr.Result}}
+	}
+	return cp
+}
+
+// NewSearchSetBundle creates a Bundle of type searchset.
+// All resources are considered as the matches of the search.
+// The total is left out, since the resources are only a page of the matches.
+func NewSearchSetBundle(baseURL string, resources []interface{}, links []BundleLink) *Bundle {
+	entries := make([]BundleEntry, 0, len(resources))
+	for _, res := range resources {
+		entries = append(entries, BundleEntry{
+			FullURL:  baseURL + "/" + fullURLPath(res),
+			Resource: res,
+			Search:   &BundleEntrySearch{Mode: "match"},
+		})
+	}
+
+	return &Bundle{
+		ResourceType: ResourceTypeBundle,
+		Meta:         &Meta{LastUpdated: formatTime(time.Now())},
+		Type:         "searchset",
+		Link:         links,
+		Entry:        entries,
+	}
+}
+
+// NewOperationOutcome creates an OperationOutcome from an error.
+// The issue code follows https://hl7.org/fhir/R4/valueset-issue-type.html.
+func NewOperationOutcome(issueCode string, err *entity.Error) *OperationOutcome {
+	return &OperationOutcome{
+		ResourceType: ResourceTypeOperationOutcome,
+		Issue: []OperationOutcomeIssue{{
+			Severity: "error",
+			Code:     issueCode,
+			Details: &CodeableConcept{
+				Coding: []Coding{{Code: err.Code}},
+				Text:   err.Message,
+			},
+		}},
+	}
+}
+
+// NewCapabilityStatement creates the CapabilityStatement of the API.
+func NewCapabilityStatement() *CapabilityStatement {
+	resources := make([]CapabilityStatementResource, 0, len(ResourceTypes))
+	for _, rt := range ResourceTypes {
+		resources = append(resources, CapabilityStatementResource{
+			Type:        rt,
+			Interaction: []CapabilityInteraction{{Code: "read"}, {Code: "search-type"}},
+			SearchParam: []CapabilitySearchParam{{Name: "patient", Type: "reference"}, {Name: "date", Type: "date"}},
+		})
+	}
+
+	return &CapabilityStatement{
+		ResourceType: ResourceTypeCapabilityStatement,
+		Status:       "active",
+		Date:         "2021-03-20",
+		Kind:         "instance",
+		FHIRVersion:  Version,
+		Format:       []string{"json"},
+		Rest:         []CapabilityStatementRest{{Mode: "server", Resource: resources}},
+	}
+}
+
+func fullURLPath(res interface{}) string {
+	switch r := res.(type) {
+	case *Encounter:
+		return r.ResourceType + "/" + r.ID
+	case *Observation:
+		return r.ResourceType + "/" + r.ID
+	case *Condition:
+		return r.ResourceType + "/" + r.ID
+	case *CarePlan:
+		return r.ResourceType + "/" + r.ID
+	}
+	return ""
+}
+
+func resourceID(id hashids.ID) string {
+	hash, _ := hashids.EncodeID(id)
+	return string(hash)
+}
+
+func newMeta(mr *entity.MedicalRecord) *Meta {
+	return &Meta{LastUpdated: formatTime(mr.UpdatedAt)}
+}
+
+func newSubject(email string) *Reference {
+	return &Reference{
+		Identifier: &Identifier{System: EmailIdentifierSystem, Value: "mailto:" + email},
+		Display:    email,
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
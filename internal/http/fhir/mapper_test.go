@@ -0,0 +1,129 @@
+package fhir_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/fhir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsResourceTypeSupported(t *testing.T) {
+	t.Run("resource types derived from medical record are supported", func(t *testing.T) {
+		for _, rt := range []string{"Encounter", "Observation", "Condition", "CarePlan"} {
+			assert.True(t, fhir.IsResourceTypeSupported(rt))
+		}
+	})
+
+	t.Run("other resource types are not supported", func(t *testing.T) {
+		for _, rt := range []string{"Patient", "encounter", ""} {
+			assert.False(t, fhir.IsResourceTypeSupported(rt))
+		}
+	})
+}
+
+func TestNewResource(t *testing.T) {
+	t.Run("unsupported resource type returns nil", func(t *testing.T) {
+		res := fhir.NewResource("Patient", createMedicalRecord(), "user@dummy.com")
+		assert.Nil(t, res)
+	})
+
+	t.Run("symptom is mapped to observation", func(t *testing.T) {
+		res := fhir.NewResource(fhir.ResourceTypeObservation, createMedicalRecord(), "user@dummy.com")
+
+		obs, ok := res.(*fhir.Observation)
+		assert.True(t, ok)
+		assert.Equal(t, "oWx0b8DZ1a", obs.ID)
+		assert.Equal(t, "Symptom", obs.ValueString)
+		assert.Equal(t, "Encounter/oWx0b8DZ1a", obs.Encounter.Reference)
+		assert.Equal(t, "mailto:user@dummy.com", obs.Subject.Identifier.Value)
+	})
+
+	t.Run("diagnosis is mapped to condition", func(t *testing.T) {
+		res := fhir.NewResource(fhir.ResourceTypeCondition, createMedicalRecord(), "user@dummy.com")
+
+		cond, ok := res.(*fhir.Condition)
+		assert.True(t, ok)
+		assert.Equal(t, "Diagnosis", cond.Code.Text)
+		assert.Equal(t, "2021-01-28T15:00:00Z", cond.RecordedDate)
+	})
+
+	t.Run("therapy is mapped to care plan and its result becomes note", func(t *testing.T) {
+		res := fhir.NewResource(fhir.ResourceTypeCarePlan, createMedicalRecord(), "user@dummy.com")
+
+		cp, ok := res.(*fhir.CarePlan)
+		assert.True(t, ok)
+		assert.Equal(t, "Therapy", cp.Description)
+		assert.Equal(t, "completed", cp.Status)
+		assert.Equal(t, "Result", cp.Note[0].Text)
+	})
+
+	t.Run("care plan without result is still active", func(t *testing.T) {
+		mr := createMedicalRecord()
+		mr.Result = ""
+
+		cp := fhir.NewCarePlan(mr, "user@dummy.com")
+		assert.Equal(t, "active", cp.Status)
+		assert.Empty(t, cp.Note)
+	})
+
+	t.Run("medical record is mapped to encounter", func(t *testing.T) {
+		res := fhir.NewResource(fhir.ResourceTypeEncounter, createMedicalRecord(), "user@dummy.com")
+
+		enc, ok := res.(*fhir.Encounter)
+		assert.True(t, ok)
+		assert.Equal(t, "finished", enc.Status)
+		assert.Equal(t, "Condition/oWx0b8DZ1a", enc.Diagnosis[0].Condition.Reference)
+	})
+}
+
+func TestNewSearchSetBundle(t *testing.T) {
+	t.Run("all resources are put as match entries", func(t *testing.T) {
+		mr := createMedicalRecord()
+		res := []interface{}{fhir.NewEncounter(mr, "user@dummy.com")}
+
+		bundle := fhir.NewSearchSetBundle("http://localhost/fhir/r4", res, nil)
+
+		assert.Equal(t, "searchset", bundle.Type)
+		assert.Nil(t, bundle.Total)
+		assert.Equal(t, "http://localhost/fhir/r4/Encounter/oWx0b8DZ1a", bundle.Entry[0].FullURL)
+		assert.Equal(t, "match", bundle.Entry[0].Search.Mode)
+	})
+}
+
+func TestNewOperationOutcome(t *testing.T) {
+	t.Run("error is put as issue details", func(t *testing.T) {
+		oo := fhir.NewOperationOutcome("not-found", entity.ErrMedicalRecordNotFound)
+
+		assert.Equal(t, "OperationOutcome", oo.ResourceType)
+		assert.Equal(t, "not-found", oo.Issue[0].Code)
+		assert.Equal(t, entity.ErrMedicalRecordNotFound.Code, oo.Issue[0].Details.Coding[0].Code)
+	})
+}
+
+func TestNewCapabilityStatement(t *testing.T) {
+	t.Run("all supported resource types are listed", func(t *testing.T) {
+		cs := fhir.NewCapabilityStatement()
+
+		assert.Equal(t, fhir.Version, cs.FHIRVersion)
+		assert.Equal(t, len(fhir.ResourceTypes), len(cs.Rest[0].Resource))
+	})
+}
+
+func createMedicalRecord() *entity.MedicalRecord {
+	return &entity.MedicalRecord{
+		ID:        hashids.ID(1),
+		Symptom:   "Symptom",
+		Diagnosis: "Diagnosis",
+		Therapy:   "Therapy",
+		Result:    "Result",
+		Auditable: entity.Auditable{
+			CreatedBy: "user@dummy.com",
+			CreatedAt: time.Date(2021, time.January, 28, 15, 00, 00, 00, time.UTC),
+			UpdatedBy: "user@dummy.com",
+			UpdatedAt: time.Date(2021, time.January, 28, 15, 00, 00, 00, time.UTC),
+		},
+	}
+}
@@ -0,0 +1,204 @@
+package fhir
+
+const (
+	// MIMEApplicationFHIRJSON is the media type of FHIR JSON representation.
+	MIMEApplicationFHIRJSON = "application/fhir+json"
+	// MIMEApplicationFHIRJSONR4 is the media type of FHIR JSON representation pinned to version R4.
+	MIMEApplicationFHIRJSONR4 = MIMEApplicationFHIRJSON + "; fhirVersion=4.0"
+	// Version is the FHIR version implemented by the API.
+	Version = "4.0.1"
+
+	// ResourceTypeEncounter is the type of Encounter resource.
+	ResourceTypeEncounter = "Encounter"
+	// ResourceTypeObservation is the type of Observation resource.
+	ResourceTypeObservation = "Observation"
+	// ResourceTypeCondition is the type of Condition resource.
+	ResourceTypeCondition = "Condition"
+	// ResourceTypeCarePlan is the type of CarePlan resource.
+	ResourceTypeCarePlan = "CarePlan"
+	// ResourceTypeBundle is the type of Bundle resource.
+	ResourceTypeBundle = "Bundle"
+	// ResourceTypeOperationOutcome is the type of OperationOutcome resource.
+	ResourceTypeOperationOutcome = "OperationOutcome"
+	// ResourceTypeCapabilityStatement is the type of CapabilityStatement resource.
+	ResourceTypeCapabilityStatement = "CapabilityStatement"
+)
+
+// Meta represents FHIR Meta element.
+type Meta struct {
+	LastUpdated string `json:"lastUpdated,omitempty"`
+}
+
+// Coding represents FHIR Coding element.
+type Coding struct {
+	System  string `json:"system,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+// CodeableConcept represents FHIR CodeableConcept element.
+type CodeableConcept struct {
+	Coding []Coding `json:"coding,omitempty"`
+	Text   string   `json:"text,omitempty"`
+}
+
+// Identifier represents FHIR Identifier element.
+type Identifier struct {
+	System string `json:"system,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+// Reference represents FHIR Reference element.
+type Reference struct {
+	Reference  string      `json:"reference,omitempty"`
+	Identifier *Identifier `json:"identifier,omitempty"`
+	Display    string      `json:"display,omitempty"`
+}
+
+// Period represents FHIR Period element.
+type Period struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// Annotation represents FHIR Annotation element.
+type Annotation struct {
+	Time string `json:"time,omitempty"`
+	Text string `json:"text"`
+}
+
+// EncounterDiagnosis represents the diagnosis element of Encounter.
+type EncounterDiagnosis struct {
+	Condition Reference `json:"condition"`
+}
+
+// Encounter represents FHIR Encounter resource.
+type Encounter struct {
+	ResourceType string               `json:"resourceType"`
+	ID           string               `json:"id"`
+	Meta         *Meta                `json:"meta,omitempty"`
+	Status       string               `json:"status"`
+	Class        Coding               `json:"class"`
+	Subject      *Reference           `json:"subject,omitempty"`
+	Period       *Period              `json:"period,omitempty"`
+	Diagnosis    []EncounterDiagnosis `json:"diagnosis,omitempty"`
+}
+
+// Observation represents FHIR Observation resource.
+type Observation struct {
+	ResourceType      string          `json:"resourceType"`
+	ID                string          `json:"id"`
+	Meta              *Meta           `json:"meta,omitempty"`
+	Status            string          `json:"status"`
+	Code              CodeableConcept `json:"code"`
+	Subject           *Reference      `json:"subject,omitempty"`
+	Encounter         *Reference      `json:"encounter,omitempty"`
+	EffectiveDateTime string          `json:"effectiveDateTime,omitempty"`
+	Issued            string          `json:"issued,omitempty"`
+	ValueString       string          `json:"valueString"`
+}
+
+// Condition represents FHIR Condition resource.
+type Condition struct {
+	ResourceType       string            `json:"resourceType"`
+	ID                 string            `json:"id"`
+	Meta               *Meta             `json:"meta,omitempty"`
+	VerificationStatus *CodeableConcept  `json:"verificationStatus,omitempty"`
+	Category           []CodeableConcept `json:"category,omitempty"`
+	Code               CodeableConcept   `json:"code"`
+	Subject            Reference         `json:"subject"`
+	Encounter          *Reference        `json:"encounter,omitempty"`
+	RecordedDate       string            `json:"recordedDate,omitempty"`
+}
+
+// CarePlan represents FHIR CarePlan resource.
+type CarePlan struct {
+	ResourceType string       `json:"resourceType"`
+	ID           string       `json:"id"`
+	Meta         *Meta        `json:"meta,omitempty"`
+	Status       string       `json:"status"`
+	Intent       string       `json:"intent"`
+	Description  string       `json:"description"`
+	Subject      Reference    `json:"subject"`
+	Encounter    *Reference   `json:"encounter,omitempty"`
+	Created      string       `json:"created,omitempty"`
+	Addresses    []Reference  `json:"addresses,omitempty"`
+	Note         []Annotation `json:"note,omitempty"`
+}
+
+// BundleLink represents the link element of Bundle.
+type BundleLink struct {
+	Relation string `json:"relation"`
+	URL      string `json:"url"`
+}
+
+// BundleEntrySearch represents the search element of Bundle entry.
+type BundleEntrySearch struct {
+	Mode string `json:"mode"`
+}
+
+// BundleEntry represents the entry element of Bundle.
+type BundleEntry struct {
+	FullURL  string             `json:"fullUrl,omitempty"`
+	Resource interface{}        `json:"resource"`
+	Search   *BundleEntrySearch `json:"search,omitempty"`
+}
+
+// Bundle represents FHIR Bundle resource.
+type Bundle struct {
+	ResourceType string        `json:"resourceType"`
+	Meta         *Meta         `json:"meta,omitempty"`
+	Type         string        `json:"type"`
+	Total        *int          `json:"total,omitempty"`
+	Link         []BundleLink  `json:"link,omitempty"`
+	Entry        []BundleEntry `json:"entry,omitempty"`
+}
+
+// OperationOutcomeIssue represents the issue element of OperationOutcome.
+type OperationOutcomeIssue struct {
+	Severity    string           `json:"severity"`
+	Code        string           `json:"code"`
+	Details     *CodeableConcept `json:"details,omitempty"`
+	Diagnostics string           `json:"diagnostics,omitempty"`
+}
+
+// OperationOutcome represents FHIR OperationOutcome resource.
+type OperationOutcome struct {
+	ResourceType string                  `json:"resourceType"`
+	Issue        []OperationOutcomeIssue `json:"issue"`
+}
+
+// CapabilityStatementResource represents the resource element in CapabilityStatement rest.
+type CapabilityStatementResource struct {
+	Type        string                  `json:"type"`
+	Interaction []CapabilityInteraction `json:"interaction"`
+	SearchParam []CapabilitySearchParam `json:"searchParam,omitempty"`
+}
+
+// CapabilityInteraction represents the interaction element in CapabilityStatement.
+type CapabilityInteraction struct {
+	Code string `json:"code"`
+}
+
+// CapabilitySearchParam represents the searchParam element in CapabilityStatement.
+type CapabilitySearchParam struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// CapabilityStatementRest represents the rest element in CapabilityStatement.
+type CapabilityStatementRest struct {
+	Mode     string                        `json:"mode"`
+	Resource []CapabilityStatementResource `json:"resource"`
+}
+
+// CapabilityStatement represents FHIR CapabilityStatement resource.
+type CapabilityStatement struct {
+	ResourceType string                    `json:"resourceType"`
+	Status       string                    `json:"status"`
+	Date         string                    `json:"date"`
+	Kind         string                    `json:"kind"`
+	FHIRVersion  string                    `json:"fhirVersion"`
+	Format       []string                  `json:"format"`
+	Rest         []CapabilityStatementRest `json:"rest"`
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/middleware"
+	"github.com/indrasaputra/orvosi-api/internal/http/response"
+	"github.com/indrasaputra/orvosi-api/internal/settings"
+	"github.com/labstack/echo/v4"
+)
+
+// CurrentSettings gives the snapshot of runtime settings in use.
+type CurrentSettings interface {
+	// Current returns the snapshot in use.
+	Current() *settings.Snapshot
+}
+
+// AdminConfig handles HTTP request and response
+// for the runtime settings shown to admins.
+type AdminConfig struct {
+	settings CurrentSettings
+}
+
+// RuntimeConfig is the runtime settings shown to admins.
+// The admin emails are left out.
+type RuntimeConfig struct {
+	Version          uint64    `json:"version"`
+	LoadedAt         time.Time `json:"loaded_at"`
+	PageSize         uint      `json:"page_size"`
+	RateLimit        float64   `json:"rate_limit"`
+	RateBurst        int       `json:"rate_burst"`
+	LogLevel         string    `json:"log_level"`
+	CORSAllowOrigins []string  `json:"cors_allow_origins"`
+}
+
+// NewAdminConfig creates an instance of AdminConfig.
+func NewAdminConfig(settings CurrentSettings) *AdminConfig {
+	return &AdminConfig{
+		settings: settings,
+	}
+}
+
+// Show handles `GET /admin/config` endpoint.
+// Only internal services and the users whose email is in RUNTIME_ADMIN_EMAILS may see it.
+func (ac *AdminConfig) Show(ctx echo.Context) error {
+	snap := ac.settings.Current()
+	if _, ok := middleware.ServiceIdentity(ctx.Request().Context()); !ok {
+		user, err := extractUserFromRequestContext(ctx.Request().Context())
+		if err != nil {
+			return err
+		}
+		if !snap.IsAdmin(user.Email) {
+			return entity.ErrForbidden
+		}
+	}
+
+	ctx.JSON(http.StatusOK, response.NewSuccess(newRuntimeConfig(snap), response.EmptyMeta{}))
+	return nil
+}
+
+func newRuntimeConfig(snap *settings.Snapshot) *RuntimeConfig {
+	return &RuntimeConfig{
+		Version:          snap.Version,
+		LoadedAt:         snap.LoadedAt,
+		PageSize:         snap.PageSize,
+		RateLimit:        snap.RateLimit,
+		RateBurst:        snap.RateBurst,
+		LogLevel:         snap.LogLevel,
+		CORSAllowOrigins: snap.Origins,
+	}
+}
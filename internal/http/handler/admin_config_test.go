@@ -0,0 +1,95 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/middleware"
+	"github.com/indrasaputra/orvosi-api/internal/settings"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAdminConfig(t *testing.T) {
+	t.Run("successfully create an instance of AdminConfig", func(t *testing.T) {
+		h := handler.NewAdminConfig(createRuntimeSettings())
+		assert.NotNil(t, h)
+	})
+}
+
+func TestAdminConfig_Show(t *testing.T) {
+	t.Run("user is not set in the context", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/config", nil)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		h := handler.NewAdminConfig(createRuntimeSettings())
+		serve(ctx, h.Show)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("user is not an admin", func(t *testing.T) {
+		req := createAdminConfigRequest("dummy@orvosi.com")
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		h := handler.NewAdminConfig(createRuntimeSettings())
+		serve(ctx, h.Show)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-012","message":"Access to the resource is forbidden"}],"meta":null}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+
+	t.Run("internal service sees the settings without a user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/config", nil)
+		req = req.WithContext(context.WithValue(context.Background(), middleware.ContextKeyService, "monitoring"))
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		h := handler.NewAdminConfig(createRuntimeSettings())
+		serve(ctx, h.Show)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"version":1,`)
+	})
+
+	t.Run("admin sees the active version without admin emails", func(t *testing.T) {
+		req := createAdminConfigRequest("Admin@Orvosi.com")
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		h := handler.NewAdminConfig(createRuntimeSettings())
+		serve(ctx, h.Show)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"version":1,`)
+		assert.Contains(t, rec.Body.String(), `"page_size":25,"rate_limit":5,"rate_burst":10,"log_level":"warn","cors_allow_origins":["https://orvosi.com"]}`)
+		assert.NotContains(t, rec.Body.String(), "admin@orvosi.com")
+	})
+}
+
+func createAdminConfigRequest(email string) *http.Request {
+	user := &entity.User{Email: email}
+	req := httptest.NewRequest(http.MethodGet, "/admin/config", nil)
+	return req.WithContext(context.WithValue(context.Background(), middleware.ContextKeyUser, user))
+}
+
+func createRuntimeSettings() *settings.Settings {
+	runtime := config.Runtime{
+		PageSize:         25,
+		RateLimit:        5,
+		RateBurst:        10,
+		LogLevel:         "warn",
+		CORSAllowOrigins: "https://orvosi.com",
+		AdminEmails:      "admin@orvosi.com",
+	}
+	return settings.NewSettings(runtime, nil)
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// AttachmentDeleter handles HTTP request and response
+// for delete attachment.
+type AttachmentDeleter struct {
+	deleter usecase.DeleteAttachment
+}
+
+// NewAttachmentDeleter creates an instance of AttachmentDeleter.
+func NewAttachmentDeleter(deleter usecase.DeleteAttachment) *AttachmentDeleter {
+	return &AttachmentDeleter{
+		deleter: deleter,
+	}
+}
+
+// Delete handles `DELETE /medical-records/:id/attachments/:attachment_id` endpoint.
+func (ad *AttachmentDeleter) Delete(ctx echo.Context) error {
+	recordID, id, perr := extractAttachmentParams(ctx)
+	if perr != nil {
+		return perr
+	}
+
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	if err := ad.deleter.Delete(ctx.Request().Context(), uint64(user.ID), recordID, id); err != nil {
+		return err
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
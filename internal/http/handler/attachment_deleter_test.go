@@ -0,0 +1,81 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type AttachmentDeleterExecutor struct {
+	handler *handler.AttachmentDeleter
+	usecase *mock_usecase.MockDeleteAttachment
+}
+
+func TestNewAttachmentDeleter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of AttachmentDeleter", func(t *testing.T) {
+		exec := createAttachmentDeleterExecutor(ctrl)
+		assert.NotNil(t, exec.handler)
+	})
+}
+
+func TestAttachmentDeleter_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("medical record id is not hashids.ID", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodDelete, nil, "", createUserInformation(), "1234", "oWx0b8DZ1a")
+
+		exec := createAttachmentDeleterExecutor(ctrl)
+		serve(ctx, exec.handler.Delete)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodDelete, nil, "", nil, "oWx0b8DZ1a", "oWx0b8DZ1a")
+
+		exec := createAttachmentDeleterExecutor(ctrl)
+		serve(ctx, exec.handler.Delete)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("attachment is not found", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodDelete, nil, "", user, "oWx0b8DZ1a", "oWx0b8DZ1a")
+
+		exec := createAttachmentDeleterExecutor(ctrl)
+		exec.usecase.EXPECT().Delete(ctx.Request().Context(), uint64(user.ID), uint64(1), uint64(1)).Return(entity.ErrAttachmentNotFound)
+		serve(ctx, exec.handler.Delete)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("successfully delete attachment", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodDelete, nil, "", user, "oWx0b8DZ1a", "oWx0b8DZ1a")
+
+		exec := createAttachmentDeleterExecutor(ctrl)
+		exec.usecase.EXPECT().Delete(ctx.Request().Context(), uint64(user.ID), uint64(1), uint64(1)).Return(nil)
+		serve(ctx, exec.handler.Delete)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+}
+
+func createAttachmentDeleterExecutor(ctrl *gomock.Controller) *AttachmentDeleterExecutor {
+	u := mock_usecase.NewMockDeleteAttachment(ctrl)
+	h := handler.NewAttachmentDeleter(u)
+	return &AttachmentDeleterExecutor{
+		handler: h,
+		usecase: u,
+	}
+}
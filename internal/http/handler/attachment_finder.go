@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"mime"
+	"net/http"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/response"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// AttachmentFinder handles HTTP request and response
+// for find and download attachment.
+type AttachmentFinder struct {
+	finder usecase.FindAttachment
+}
+
+// NewAttachmentFinder creates an instance of AttachmentFinder.
+func NewAttachmentFinder(finder usecase.FindAttachment) *AttachmentFinder {
+	return &AttachmentFinder{
+		finder: finder,
+	}
+}
+
+// FindByMedicalRecordID handles `GET /medical-records/:id/attachments` endpoint.
+func (af *AttachmentFinder) FindByMedicalRecordID(ctx echo.Context) error {
+	recordID, herr := hashids.DecodeHash([]byte(ctx.Param("id")))
+	if herr != nil {
+		return entity.WrapError(entity.ErrInvalidID, herr.Error())
+	}
+
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	attachments, ferr := af.finder.FindByMedicalRecordID(ctx.Request().Context(), uint64(user.ID), uint64(recordID))
+	if ferr != nil {
+		return ferr
+	}
+
+	ctx.JSON(http.StatusOK, response.NewSuccess(createAttachmentResponses(attachments), response.EmptyMeta{}))
+	return nil
+}
+
+// Download handles `GET /medical-records/:id/attachments/:attachment_id` endpoint.
+// The content is sent as is with header `Digest` containing its SHA-256.
+func (af *AttachmentFinder) Download(ctx echo.Context) error {
+	recordID, id, perr := extractAttachmentParams(ctx)
+	if perr != nil {
+		return perr
+	}
+
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	attachment, data, ferr := af.finder.Download(ctx.Request().Context(), uint64(user.ID), recordID, id)
+	if ferr != nil {
+		return ferr
+	}
+
+	header := ctx.Response().Header()
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	header.Set(echo.HeaderXContentTypeOptions, "nosniff")
+	header.Set("ETag", `"`+attachment.Checksum+`"`)
+	if sum, err := hex.DecodeString(attachment.Checksum); err == nil {
+		header.Set("Digest", "sha-256="+base64.StdEncoding.EncodeToString(sum))
+	}
+	return ctx.Blob(http.StatusOK, attachment.ContentType, data)
+}
+
+func extractAttachmentParams(ctx echo.Context) (uint64, uint64, *entity.Error) {
+	recordID, err := hashids.DecodeHash([]byte(ctx.Param("id")))
+	if err != nil {
+		return 0, 0, entity.ErrInvalidID
+	}
+	id, err := hashids.DecodeHash([]byte(ctx.Param("attachment_id")))
+	if err != nil {
+		return 0, 0, entity.ErrInvalidID
+	}
+	return uint64(recordID), uint64(id), nil
+}
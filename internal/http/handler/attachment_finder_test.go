@@ -0,0 +1,126 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type AttachmentFinderExecutor struct {
+	handler *handler.AttachmentFinder
+	usecase *mock_usecase.MockFindAttachment
+}
+
+func TestNewAttachmentFinder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of AttachmentFinder", func(t *testing.T) {
+		exec := createAttachmentFinderExecutor(ctrl)
+		assert.NotNil(t, exec.handler)
+	})
+}
+
+func TestAttachmentFinder_FindByMedicalRecordID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("medical record id is not hashids.ID", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", createUserInformation(), "1234")
+
+		exec := createAttachmentFinderExecutor(ctrl)
+		serve(ctx, exec.handler.FindByMedicalRecordID)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("medical record is not found", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", user, "oWx0b8DZ1a")
+
+		exec := createAttachmentFinderExecutor(ctrl)
+		exec.usecase.EXPECT().FindByMedicalRecordID(ctx.Request().Context(), uint64(user.ID), uint64(1)).Return(nil, entity.ErrMedicalRecordNotFound)
+		serve(ctx, exec.handler.FindByMedicalRecordID)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("successfully find attachments", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", user, "oWx0b8DZ1a")
+
+		exec := createAttachmentFinderExecutor(ctrl)
+		exec.usecase.EXPECT().FindByMedicalRecordID(ctx.Request().Context(), uint64(user.ID), uint64(1)).Return(createAttachments(), nil)
+		serve(ctx, exec.handler.FindByMedicalRecordID)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"content_type":"application/pdf"`)
+	})
+}
+
+func TestAttachmentFinder_Download(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("attachment id is not hashids.ID", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", createUserInformation(), "oWx0b8DZ1a", "1234")
+
+		exec := createAttachmentFinderExecutor(ctrl)
+		serve(ctx, exec.handler.Download)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("finder returns error", func(t *testing.T) {
+		tables := []struct {
+			err    *entity.Error
+			status int
+		}{
+			{entity.ErrAttachmentNotFound, http.StatusNotFound},
+			{entity.ErrInternalServer, http.StatusInternalServerError},
+		}
+
+		for _, table := range tables {
+			user := createUserInformation()
+			ctx, rec := createAttachmentContext(http.MethodGet, nil, "", user, "oWx0b8DZ1a", "oWx0b8DZ1a")
+
+			exec := createAttachmentFinderExecutor(ctrl)
+			exec.usecase.EXPECT().Download(ctx.Request().Context(), uint64(user.ID), uint64(1), uint64(1)).Return(nil, nil, table.err)
+			serve(ctx, exec.handler.Download)
+
+			assert.Equal(t, table.status, rec.Code)
+		}
+	})
+
+	t.Run("successfully download attachment", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", user, "oWx0b8DZ1a", "oWx0b8DZ1a")
+		attachment := createAttachments()[0]
+
+		exec := createAttachmentFinderExecutor(ctrl)
+		exec.usecase.EXPECT().Download(ctx.Request().Context(), uint64(user.ID), uint64(1), uint64(1)).Return(attachment, []byte("%PDF-1.4"), nil)
+		serve(ctx, exec.handler.Download)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/pdf", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "attachment; filename=lab.pdf", rec.Header().Get(echo.HeaderContentDisposition))
+		assert.Equal(t, `"`+attachment.Checksum+`"`, rec.Header().Get("ETag"))
+		assert.NotEmpty(t, rec.Header().Get("Digest"))
+		assert.Equal(t, "%PDF-1.4", rec.Body.String())
+	})
+}
+
+func createAttachmentFinderExecutor(ctrl *gomock.Controller) *AttachmentFinderExecutor {
+	u := mock_usecase.NewMockFindAttachment(ctrl)
+	h := handler.NewAttachmentFinder(u)
+	return &AttachmentFinderExecutor{
+		handler: h,
+		usecase: u,
+	}
+}
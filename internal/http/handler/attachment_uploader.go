@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/response"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	attachmentFileField     = "file"
+	attachmentChecksumField = "checksum"
+	// attachmentChecksumLength is the length of hex encoded SHA-256.
+	attachmentChecksumLength = 64
+)
+
+// AttachmentResponse defines the JSON response of attachment.
+type AttachmentResponse struct {
+	ID              hashids.ID `json:"id"`
+	MedicalRecordID hashids.ID `json:"medical_record_id"`
+	Filename        string     `json:"filename"`
+	ContentType     string     `json:"content_type"`
+	Size            int64      `json:"size"`
+	Checksum        string     `json:"checksum"`
+	CreatedBy       string     `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedBy       string     `json:"updated_by"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// AttachmentUploader handles HTTP request and response
+// for upload attachment.
+type AttachmentUploader struct {
+	uploader usecase.UploadAttachment
+}
+
+// NewAttachmentUploader creates an instance of AttachmentUploader.
+func NewAttachmentUploader(uploader usecase.UploadAttachment) *AttachmentUploader {
+	return &AttachmentUploader{
+		uploader: uploader,
+	}
+}
+
+// Upload handles `POST /medical-records/:id/attachments` endpoint.
+// It expects a multipart form with the file in field `file`.
+// Field `checksum` (hex encoded SHA-256) is optional and must be sent before the file.
+func (au *AttachmentUploader) Upload(ctx echo.Context) error {
+	recordID, herr := hashids.DecodeHash([]byte(ctx.Param("id")))
+	if herr != nil {
+		return entity.WrapError(entity.ErrInvalidID, herr.Error())
+	}
+
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	part, checksum, perr := findAttachmentFilePart(ctx.Request())
+	if perr != nil {
+		return perr
+	}
+	defer part.Close()
+
+	attachment := &entity.Attachment{
+		Filename: part.FileName(),
+		Checksum: checksum,
+	}
+	result, uerr := au.uploader.Upload(ctx.Request().Context(), user, uint64(recordID), attachment, part)
+	if uerr != nil {
+		return uerr
+	}
+
+	ctx.JSON(http.StatusCreated, response.NewSuccess(createAttachmentResponse(result), response.EmptyMeta{}))
+	return nil
+}
+
+// findAttachmentFilePart walks through the multipart body until it finds the file part.
+// The checksum is taken from field `checksum` if it is sent before the file.
+func findAttachmentFilePart(req *http.Request) (*multipart.Part, string, *entity.Error) {
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return nil, "", entity.WrapError(entity.ErrInvalidAttachmentRequest, err.Error())
+	}
+
+	checksum := ""
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, "", entity.WrapError(entity.ErrInvalidAttachmentRequest, "file part is missing")
+		}
+		if err != nil {
+			return nil, "", entity.WrapError(entity.ErrInvalidAttachmentRequest, err.Error())
+		}
+
+		switch part.FormName() {
+		case attachmentChecksumField:
+			val, _ := io.ReadAll(io.LimitReader(part, attachmentChecksumLength+1))
+			checksum = strings.TrimSpace(string(val))
+			part.Close()
+		case attachmentFileField:
+			return part, checksum, nil
+		default:
+			part.Close()
+		}
+	}
+}
+
+func createAttachmentResponses(attachments []*entity.Attachment) []*AttachmentResponse {
+	res := make([]*AttachmentResponse, len(attachments))
+	for i, attachment := range attachments {
+		res[i] = createAttachmentResponse(attachment)
+	}
+	return res
+}
+
+func createAttachmentResponse(attachment *entity.Attachment) *AttachmentResponse {
+	return &AttachmentResponse{
+		ID:              attachment.ID,
+		MedicalRecordID: attachment.MedicalRecordID,
+		Filename:        attachment.Filename,
+		ContentType:     attachment.ContentType,
+		Size:            attachment.Size,
+		Checksum:        attachment.Checksum,
+		CreatedBy:       attachment.CreatedBy,
+		CreatedAt:       attachment.CreatedAt,
+		UpdatedBy:       attachment.UpdatedBy,
+		UpdatedAt:       attachment.UpdatedAt,
+	}
+}
@@ -0,0 +1,188 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/middleware"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type AttachmentUploaderExecutor struct {
+	handler *handler.AttachmentUploader
+	usecase *mock_usecase.MockUploadAttachment
+}
+
+func TestNewAttachmentUploader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of AttachmentUploader", func(t *testing.T) {
+		exec := createAttachmentUploaderExecutor(ctrl)
+		assert.NotNil(t, exec.handler)
+	})
+}
+
+func TestAttachmentUploader_Upload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("medical record id is not hashids.ID", func(t *testing.T) {
+		body, contentType := createAttachmentMultipartBody("", "lab.pdf", "%PDF-1.4")
+		ctx, rec := createAttachmentContext(http.MethodPost, body, contentType, createUserInformation(), "1234")
+
+		exec := createAttachmentUploaderExecutor(ctrl)
+		serve(ctx, exec.handler.Upload)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		body, contentType := createAttachmentMultipartBody("", "lab.pdf", "%PDF-1.4")
+		ctx, rec := createAttachmentContext(http.MethodPost, body, contentType, nil, "oWx0b8DZ1a")
+
+		exec := createAttachmentUploaderExecutor(ctrl)
+		serve(ctx, exec.handler.Upload)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("request is not multipart", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodPost, strings.NewReader(`{}`), echo.MIMEApplicationJSON, createUserInformation(), "oWx0b8DZ1a")
+
+		exec := createAttachmentUploaderExecutor(ctrl)
+		serve(ctx, exec.handler.Upload)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), entity.ErrInvalidAttachmentRequest.Code)
+	})
+
+	t.Run("file part is missing", func(t *testing.T) {
+		body, contentType := createAttachmentMultipartBody("ABCDEF", "", "")
+		ctx, rec := createAttachmentContext(http.MethodPost, body, contentType, createUserInformation(), "oWx0b8DZ1a")
+
+		exec := createAttachmentUploaderExecutor(ctrl)
+		serve(ctx, exec.handler.Upload)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), entity.ErrInvalidAttachmentRequest.Code)
+	})
+
+	t.Run("uploader returns error", func(t *testing.T) {
+		tables := []struct {
+			err    *entity.Error
+			status int
+		}{
+			{entity.ErrMedicalRecordNotFound, http.StatusNotFound},
+			{entity.ErrAttachmentTooLarge, http.StatusRequestEntityTooLarge},
+			{entity.ErrUnsupportedAttachmentType, http.StatusUnsupportedMediaType},
+			{entity.ErrAttachmentChecksumMismatch, http.StatusBadRequest},
+			{entity.ErrInternalServer, http.StatusInternalServerError},
+		}
+
+		for _, table := range tables {
+			body, contentType := createAttachmentMultipartBody("", "lab.pdf", "%PDF-1.4")
+			ctx, rec := createAttachmentContext(http.MethodPost, body, contentType, createUserInformation(), "oWx0b8DZ1a")
+
+			exec := createAttachmentUploaderExecutor(ctrl)
+			exec.usecase.EXPECT().Upload(ctx.Request().Context(), gomock.Any(), uint64(1), gomock.Any(), gomock.Any()).Return(nil, table.err)
+			serve(ctx, exec.handler.Upload)
+
+			assert.Equal(t, table.status, rec.Code)
+		}
+	})
+
+	t.Run("successfully upload attachment", func(t *testing.T) {
+		body, contentType := createAttachmentMultipartBody("ABCDEF", "lab.pdf", "%PDF-1.4")
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodPost, body, contentType, user, "oWx0b8DZ1a")
+
+		exec := createAttachmentUploaderExecutor(ctrl)
+		exec.usecase.EXPECT().Upload(ctx.Request().Context(), user, uint64(1), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *entity.User, _ uint64, attachment *entity.Attachment, content io.Reader) (*entity.Attachment, *entity.Error) {
+				data, _ := io.ReadAll(content)
+				assert.Equal(t, "%PDF-1.4", string(data))
+				assert.Equal(t, "lab.pdf", attachment.Filename)
+				assert.Equal(t, "ABCDEF", attachment.Checksum)
+				return createAttachments()[0], nil
+			})
+		serve(ctx, exec.handler.Upload)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"filename":"lab.pdf"`)
+		assert.Contains(t, rec.Body.String(), `"medical_record_id":"oWx0b8DZ1a"`)
+	})
+}
+
+func createAttachments() []*entity.Attachment {
+	return []*entity.Attachment{
+		{
+			ID:              hashids.ID(2),
+			MedicalRecordID: hashids.ID(1),
+			Filename:        "lab.pdf",
+			ContentType:     "application/pdf",
+			Size:            8,
+			Checksum:        "8e58fd1ea3bb4ce4f6bc1b4a2fa4f1fce95f5be7c1b4f4c4c5d1ed2d5f1d0c1a",
+			StorageKey:      "medical-records/1/key",
+			Auditable: entity.Auditable{
+				CreatedBy: "user@dummy.com",
+				CreatedAt: time.Date(2021, time.January, 28, 15, 00, 00, 00, time.UTC),
+				UpdatedBy: "user@dummy.com",
+				UpdatedAt: time.Date(2021, time.January, 28, 15, 00, 00, 00, time.UTC),
+			},
+		},
+	}
+}
+
+func createAttachmentMultipartBody(checksum, filename, content string) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if checksum != "" {
+		writer.WriteField("checksum", checksum)
+	}
+	if filename != "" {
+		part, _ := writer.CreateFormFile("file", filename)
+		part.Write([]byte(content))
+	}
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func createAttachmentContext(method string, body io.Reader, contentType string, user *entity.User, params ...string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, "/", body)
+	if contentType != "" {
+		req.Header.Set(echo.HeaderContentType, contentType)
+	}
+	if user != nil {
+		req = req.WithContext(context.WithValue(context.Background(), middleware.ContextKeyUser, user))
+	}
+
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(req, rec)
+	names := []string{"id", "attachment_id"}
+	ctx.SetParamNames(names[:len(params)]...)
+	ctx.SetParamValues(params...)
+	return ctx, rec
+}
+
+func createAttachmentUploaderExecutor(ctrl *gomock.Controller) *AttachmentUploaderExecutor {
+	u := mock_usecase.NewMockUploadAttachment(ctrl)
+	h := handler.NewAttachmentUploader(u)
+	return &AttachmentUploaderExecutor{
+		handler: h,
+		usecase: u,
+	}
+}
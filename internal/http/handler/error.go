@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/middleware"
+	"github.com/indrasaputra/orvosi-api/internal/http/response"
+	"github.com/indrasaputra/orvosi-api/internal/i18n"
+	"github.com/labstack/echo/v4"
+)
+
+// HTTPErrorHandler writes the error returned by the handlers and middlewares.
+// The status is decided by the kind of *entity.Error.
+// Errors of echo itself, such as unknown route, are mapped onto the *entity.Error of their status.
+// Any other error is hidden behind ErrInternalServer.
+// Nothing is written if the response has been written by the handler.
+// The public message is translated into the language negotiated from `Accept-Language` header.
+// The error is written as problem details (RFC 7807) if the client prefers it in `Accept` header.
+func HTTPErrorHandler(err error, ctx echo.Context) {
+	if ctx.Response().Committed {
+		return
+	}
+
+	var eerr *entity.Error
+	var herr *echo.HTTPError
+	switch {
+	case errors.As(err, &eerr):
+	case errors.As(err, &herr):
+		eerr = entity.WrapError(echoError(herr.Code), herr.Error())
+	default:
+		eerr = entity.ErrInternalServer
+	}
+
+	status := statusOf(eerr)
+	eerr = i18n.Localize(requestLanguage(ctx), eerr)
+	if ctx.Request().Method == http.MethodHead {
+		ctx.NoContent(status)
+		return
+	}
+	if prefersProblem(ctx.Request()) {
+		writeProblem(ctx, status, response.NewProblem(eerr, status, ctx.Request().URL.Path))
+		return
+	}
+	ctx.JSON(status, response.NewError(eerr))
+}
+
+// requestLanguage returns the language negotiated by middleware.WithLanguage.
+// The default language is returned if the middleware isn't used.
+func requestLanguage(ctx echo.Context) string {
+	if lang, ok := ctx.Request().Context().Value(middleware.ContextKeyLanguage).(string); ok {
+		return lang
+	}
+	return i18n.DefaultLanguage
+}
+
+// prefersProblem checks whether `Accept` header asks for problem details
+// at least as much as any other representation.
+// Clients that don't mention problem details keep receiving the error envelope.
+func prefersProblem(req *http.Request) bool {
+	problemQ, maxQ := 0.0, 0.0
+	for _, part := range strings.Split(req.Header.Get(echo.HeaderAccept), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if val, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(val, 64); err != nil {
+				continue
+			}
+		}
+		if mediaType == response.MIMEApplicationProblemJSON && q > problemQ {
+			problemQ = q
+		}
+		if q > maxQ {
+			maxQ = q
+		}
+	}
+	return problemQ > 0 && problemQ >= maxQ
+}
+
+func writeProblem(ctx echo.Context, status int, problem *response.Problem) {
+	ctx.Response().Header().Set(echo.HeaderContentType, response.MIMEApplicationProblemJSON)
+	ctx.JSON(status, problem)
+}
+
+// echoError maps the status of the error returned by echo itself onto *entity.Error.
+func echoError(status int) *entity.Error {
+	switch status {
+	case http.StatusBadRequest:
+		return entity.ErrBadRequest
+	case http.StatusUnauthorized:
+		return entity.ErrUnauthorized
+	case http.StatusForbidden:
+		return entity.ErrForbidden
+	case http.StatusNotFound:
+		return entity.ErrRouteNotFound
+	case http.StatusMethodNotAllowed:
+		return entity.ErrMethodNotAllowed
+	case http.StatusRequestEntityTooLarge:
+		return entity.ErrRequestTooLarge
+	case http.StatusUnsupportedMediaType:
+		return entity.ErrWrongContentType
+	case http.StatusTooManyRequests:
+		return entity.ErrTooManyRequests
+	case http.StatusServiceUnavailable:
+		return entity.ErrServiceUnavailable
+	}
+	return entity.ErrInternalServer
+}
+
+// statusOf maps the kind of error to HTTP status.
+func statusOf(err *entity.Error) int {
+	switch err.Kind {
+	case entity.KindValidation:
+		return http.StatusBadRequest
+	case entity.KindNotFound:
+		return http.StatusNotFound
+	case entity.KindUnauthorized:
+		return http.StatusUnauthorized
+	case entity.KindForbidden:
+		return http.StatusForbidden
+	case entity.KindConflict:
+		return http.StatusConflict
+	case entity.KindUnavailable:
+		return http.StatusServiceUnavailable
+	case entity.KindTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case entity.KindUnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
+	case entity.KindNotAcceptable:
+		return http.StatusNotAcceptable
+	case entity.KindTooManyRequests:
+		return http.StatusTooManyRequests
+	case entity.KindMethodNotAllowed:
+		return http.StatusMethodNotAllowed
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,209 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPErrorHandler(t *testing.T) {
+	t.Run("status is decided by the kind of error", func(t *testing.T) {
+		pairs := []struct {
+			err    *entity.Error
+			status int
+		}{
+			{entity.ErrInternalServer, http.StatusInternalServerError},
+			{entity.ErrInvalidMedicalRecordAttribute, http.StatusBadRequest},
+			{entity.ErrInvalidID, http.StatusBadRequest},
+			{entity.ErrMedicalRecordNotFound, http.StatusNotFound},
+			{entity.ErrUnauthorized, http.StatusUnauthorized},
+			{entity.ErrForbidden, http.StatusForbidden},
+			{entity.ErrAlreadyExists, http.StatusConflict},
+			{entity.ErrConcurrentUpdate, http.StatusConflict},
+			{entity.ErrTimeout, http.StatusServiceUnavailable},
+			{entity.ErrServiceUnavailable, http.StatusServiceUnavailable},
+			{entity.ErrAttachmentTooLarge, http.StatusRequestEntityTooLarge},
+			{entity.ErrWrongContentType, http.StatusUnsupportedMediaType},
+			{entity.ErrNotAcceptable, http.StatusNotAcceptable},
+			{entity.ErrTooManyRequests, http.StatusTooManyRequests},
+			{entity.ErrRequestTooLarge, http.StatusRequestEntityTooLarge},
+			{entity.WrapError(entity.ErrMedicalRecordNotFound, "wrapped"), http.StatusNotFound},
+		}
+
+		for _, pair := range pairs {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := echo.New().NewContext(req, rec)
+
+			handler.HTTPErrorHandler(pair.err, ctx)
+
+			assert.Equal(t, pair.status, rec.Code, pair.err.Code)
+			str := fmt.Sprintf(`{"errors":[{"code":"%s","message":"%s"}],"meta":null}`+"\n", pair.err.Code, pair.err.Message)
+			assert.Equal(t, str, rec.Body.String())
+		}
+	})
+
+	t.Run("unknown error is hidden as internal server error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		handler.HTTPErrorHandler(errors.New("connection refused"), ctx)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-001","message":"Internal server error"}],"meta":null}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+
+	t.Run("echo error is mapped onto the error of its status", func(t *testing.T) {
+		pairs := []struct {
+			err    *echo.HTTPError
+			status int
+			code   string
+		}{
+			{echo.ErrNotFound, http.StatusNotFound, entity.ErrRouteNotFound.Code},
+			{echo.ErrMethodNotAllowed, http.StatusMethodNotAllowed, entity.ErrMethodNotAllowed.Code},
+			{echo.ErrStatusRequestEntityTooLarge, http.StatusRequestEntityTooLarge, entity.ErrRequestTooLarge.Code},
+			{echo.ErrBadRequest, http.StatusBadRequest, entity.ErrBadRequest.Code},
+			{echo.ErrUnsupportedMediaType, http.StatusUnsupportedMediaType, entity.ErrWrongContentType.Code},
+			{echo.NewHTTPError(http.StatusTeapot), http.StatusInternalServerError, entity.ErrInternalServer.Code},
+		}
+
+		for _, pair := range pairs {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := echo.New().NewContext(req, rec)
+
+			handler.HTTPErrorHandler(pair.err, ctx)
+
+			assert.Equal(t, pair.status, rec.Code, pair.code)
+			assert.Contains(t, rec.Body.String(), `"code":"`+pair.code+`"`)
+		}
+	})
+
+	t.Run("echo error is localized and written as problem details", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/unknown", nil)
+		req.Header.Set(echo.HeaderAccept, "application/problem+json")
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyLanguage, "hu"))
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		handler.HTTPErrorHandler(echo.ErrNotFound, ctx)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "application/problem+json", rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Body.String(), "Az útvonal nem található")
+	})
+
+	t.Run("head request has no body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodHead, "/", nil)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		handler.HTTPErrorHandler(entity.ErrMedicalRecordNotFound, ctx)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("written response is left as is", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+		ctx.String(http.StatusTeapot, "written")
+
+		handler.HTTPErrorHandler(entity.ErrInternalServer, ctx)
+
+		assert.Equal(t, http.StatusTeapot, rec.Code)
+		assert.Equal(t, "written", rec.Body.String())
+	})
+
+	t.Run("message is translated into the negotiated language", func(t *testing.T) {
+		pairs := []struct {
+			lang    string
+			message string
+		}{
+			{"en", "Medical record not found"},
+			{"id", "Rekam medis tidak ditemukan"},
+			{"hu", "Az orvosi lelet nem található"},
+		}
+
+		for _, pair := range pairs {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyLanguage, pair.lang))
+			rec := httptest.NewRecorder()
+			ctx := echo.New().NewContext(req, rec)
+
+			handler.HTTPErrorHandler(entity.ErrMedicalRecordNotFound, ctx)
+
+			assert.Equal(t, http.StatusNotFound, rec.Code, pair.lang)
+			str := fmt.Sprintf(`{"errors":[{"code":"02-005","message":"%s"}],"meta":null}`+"\n", pair.message)
+			assert.Equal(t, str, rec.Body.String(), pair.lang)
+		}
+	})
+
+	t.Run("problem details is written if the client prefers it", func(t *testing.T) {
+		accepts := []string{
+			"application/problem+json",
+			"application/json, application/problem+json",
+			"application/problem+json;q=0.9, application/json;q=0.5",
+		}
+
+		for _, accept := range accepts {
+			req := httptest.NewRequest(http.MethodPost, "/medical-records", nil)
+			req.Header.Set(echo.HeaderAccept, accept)
+			rec := httptest.NewRecorder()
+			ctx := echo.New().NewContext(req, rec)
+			params := []entity.InvalidParam{{Name: "symptom", Rule: "required", Reason: "must not be blank"}}
+
+			handler.HTTPErrorHandler(entity.WithInvalidParams(entity.ErrInvalidMedicalRecordAttribute, params), ctx)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, accept)
+			assert.Equal(t, "application/problem+json", rec.Header().Get(echo.HeaderContentType), accept)
+			str := `{"type":"urn:orvosi:error:02-002","title":"Medical record's attributes are invalid. Please, check all attributes","status":400,"detail":"symptom must not be blank","instance":"/medical-records","code":"02-002","invalid_params":[{"name":"symptom","rule":"required","reason":"must not be blank"}]}` + "\n"
+			assert.Equal(t, str, rec.Body.String(), accept)
+		}
+	})
+
+	t.Run("error envelope is written if the client doesn't prefer problem details", func(t *testing.T) {
+		accepts := []string{
+			"",
+			"application/json",
+			"*/*",
+			"application/problem+json;q=0.5, application/json",
+			"application/problem+json;q=0",
+		}
+
+		for _, accept := range accepts {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAccept, accept)
+			rec := httptest.NewRecorder()
+			ctx := echo.New().NewContext(req, rec)
+
+			handler.HTTPErrorHandler(entity.ErrMedicalRecordNotFound, ctx)
+
+			assert.Equal(t, http.StatusNotFound, rec.Code, accept)
+			assert.Equal(t, echo.MIMEApplicationJSONCharsetUTF8, rec.Header().Get(echo.HeaderContentType), accept)
+			str := fmt.Sprintf("%s\n", `{"errors":[{"code":"02-005","message":"Medical record not found"}],"meta":null}`)
+			assert.Equal(t, str, rec.Body.String(), accept)
+		}
+	})
+}
+
+// serve runs the handler and writes its error the same way the server does.
+func serve(ctx echo.Context, h echo.HandlerFunc) error {
+	err := h(ctx)
+	if err != nil {
+		handler.HTTPErrorHandler(err, ctx)
+	}
+	return err
+}
@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/fhir"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// FHIRBasePath is the base path of FHIR R4 endpoints.
+	FHIRBasePath = "/fhir/r4"
+
+	fhirDateDay   = "2006-01-02"
+	fhirDateMonth = "2006-01"
+	fhirDateYear  = "2006"
+)
+
+var (
+	// fhirMinDate and fhirMaxDate bound the date search when one of its side is not specified.
+	fhirMinDate = time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	fhirMaxDate = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+)
+
+// FHIRMedicalRecord handles HTTP request and response
+// for medical record represented as HL7 FHIR R4 resources.
+// Each medical record is represented as an Encounter (the visit),
+// an Observation (the symptom), a Condition (the diagnosis), and a CarePlan (the therapy and its result).
+// All of them share the same id as the medical record.
+type FHIRMedicalRecord struct {
+	finder usecase.FindMedicalRecord
+}
+
+// NewFHIRMedicalRecord creates an instance of FHIRMedicalRecord.
+func NewFHIRMedicalRecord(finder usecase.FindMedicalRecord) *FHIRMedicalRecord {
+	return &FHIRMedicalRecord{
+		finder: finder,
+	}
+}
+
+// Metadata handles `GET /fhir/r4/metadata` endpoint.
+// It returns the CapabilityStatement of the server.
+func (fm *FHIRMedicalRecord) Metadata(ctx echo.Context) error {
+	if !isFHIRAcceptable(ctx.Request()) {
+		return writeFHIRError(ctx, http.StatusNotAcceptable, "not-supported", entity.ErrNotAcceptable)
+	}
+	return writeFHIR(ctx, http.StatusOK, fhir.NewCapabilityStatement())
+}
+
+// Read handles `GET /fhir/r4/:type/:id` endpoint.
+// Only resource derived from medical record that is owned by the user can be read.
+func (fm *FHIRMedicalRecord) Read(ctx echo.Context) error {
+	if !isFHIRAcceptable(ctx.Request()) {
+		return writeFHIRError(ctx, http.StatusNotAcceptable, "not-supported", entity.ErrNotAcceptable)
+	}
+
+	resourceType := ctx.Param("type")
+	if !fhir.IsResourceTypeSupported(resourceType) {
+		return writeFHIRError(ctx, http.StatusNotFound, "not-supported", entity.ErrUnsupportedResourceType)
+	}
+
+	id, herr := hashids.DecodeHash([]byte(ctx.Param("id")))
+	if herr != nil || id == 0 {
+		return writeFHIRError(ctx, http.StatusNotFound, "not-found", entity.ErrInvalidID)
+	}
+
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return writeFHIRError(ctx, http.StatusInternalServerError, "exception", entity.ErrInternalServer)
+	}
+
+	record, ferr := fm.finder.FindByID(ctx.Request().Context(), uint64(id), uint64(user.ID))
+	if ferr != nil {
+		return writeFHIRError(ctx, statusOf(ferr), fhirIssueCode(ferr), ferr)
+	}
+
+	return writeFHIR(ctx, http.StatusOK, fhir.NewResource(resourceType, record, user.Email))
+}
+
+// Search handles `GET /fhir/r4/:type` endpoint.
+// It supports search param `patient` (the user's email) and `date` (the date of the visit).
+// Param `date` can be repeated and prefixed by eq, ge, gt, le, or lt.
+// The result is a searchset Bundle and the next page, if any, is linked using param `from`.
+func (fm *FHIRMedicalRecord) Search(ctx echo.Context) error {
+	if !isFHIRAcceptable(ctx.Request()) {
+		return writeFHIRError(ctx, http.StatusNotAcceptable, "not-supported", entity.ErrNotAcceptable)
+	}
+
+	resourceType := ctx.Param("type")
+	if !fhir.IsResourceTypeSupported(resourceType) {
+		return writeFHIRError(ctx, http.StatusNotFound, "not-supported", entity.ErrUnsupportedResourceType)
+	}
+
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return writeFHIRError(ctx, http.StatusInternalServerError, "exception", entity.ErrInternalServer)
+	}
+
+	params := ctx.QueryParams()
+	if patient := params.Get("patient"); patient != "" && !isSamePatient(patient, user.Email) {
+		return writeFHIRError(ctx, http.StatusForbidden, "forbidden", entity.ErrForbidden)
+	}
+
+	since, until, perr := parseFHIRDateParams(params["date"])
+	if perr != nil {
+		return writeFHIRError(ctx, http.StatusBadRequest, "invalid", perr)
+	}
+
+	from, qerr := extractQueryParam(params.Get("from"))
+	if qerr != nil {
+		return writeFHIRError(ctx, http.StatusBadRequest, "invalid", qerr)
+	}
+
+	records, more, ferr := fm.finder.FindByUserIDWithinPeriod(ctx.Request().Context(), uint64(user.ID), since, until, from)
+	if ferr != nil {
+		return writeFHIRError(ctx, statusOf(ferr), fhirIssueCode(ferr), ferr)
+	}
+
+	resources := make([]interface{}, len(records))
+	for i, record := range records {
+		resources[i] = fhir.NewResource(resourceType, record, user.Email)
+	}
+
+	base := requestBaseURL(ctx.Request())
+	bundle := fhir.NewSearchSetBundle(base+FHIRBasePath, resources, createFHIRSearchLinks(base, ctx.Request().URL, records, more))
+	return writeFHIR(ctx, http.StatusOK, bundle)
+}
+
+// isFHIRAcceptable checks `_format` param and `Accept` header.
+// Only JSON representation is supported.
+func isFHIRAcceptable(req *http.Request) bool {
+	if format := req.URL.Query().Get("_format"); format != "" {
+		return format == "json" || isFHIRJSONMediaType(format)
+	}
+
+	accept := req.Header.Get(echo.HeaderAccept)
+	if accept == "" {
+		return true
+	}
+	for _, part := range strings.Split(accept, ",") {
+		if isFHIRJSONMediaType(part) {
+			return true
+		}
+	}
+	return false
+}
+
+func isFHIRJSONMediaType(value string) bool {
+	mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(value))
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case fhir.MIMEApplicationFHIRJSON, echo.MIMEApplicationJSON, "application/*", "*/*":
+		return true
+	}
+	return false
+}
+
+func isSamePatient(patient, email string) bool {
+	patient = strings.TrimPrefix(patient, fhir.EmailIdentifierSystem+"|")
+	patient = strings.TrimPrefix(patient, "mailto:")
+	return strings.EqualFold(patient, email)
+}
+
+// parseFHIRDateParams converts FHIR date search params into [since, until).
+func parseFHIRDateParams(values []string) (time.Time, time.Time, *entity.Error) {
+	since, until := fhirMinDate, fhirMaxDate
+	for _, value := range values {
+		prefix := "eq"
+		if len(value) > 2 && value[0] >= 'a' && value[0] <= 'z' {
+			prefix, value = value[:2], value[2:]
+		}
+
+		start, end, ok := parseFHIRDate(value)
+		if !ok {
+			return since, until, entity.ErrInvalidParam
+		}
+
+		switch prefix {
+		case "eq":
+			since, until = latest(since, start), earliest(until, end)
+		case "ge":
+			since = latest(since, start)
+		case "gt":
+			since = latest(since, end)
+		case "le":
+			until = earliest(until, end)
+		case "lt":
+			until = earliest(until, start)
+		default:
+			return since, until, entity.ErrInvalidParam
+		}
+	}
+	return since, until, nil
+}
+
+// parseFHIRDate parses FHIR date (year, month, day, or full date time)
+// and returns the range covered by its precision.
+func parseFHIRDate(value string) (time.Time, time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, t.Add(time.Second), true
+	}
+	if t, err := time.Parse(fhirDateDay, value); err == nil {
+		return t, t.AddDate(0, 0, 1), true
+	}
+	if t, err := time.Parse(fhirDateMonth, value); err == nil {
+		return t, t.AddDate(0, 1, 0), true
+	}
+	if t, err := time.Parse(fhirDateYear, value); err == nil {
+		return t, t.AddDate(1, 0, 0), true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func createFHIRSearchLinks(base string, u *url.URL, records []*entity.MedicalRecord, more bool) []fhir.BundleLink {
+	links := []fhir.BundleLink{{Relation: "self", URL: base + u.RequestURI()}}
+	if !more || len(records) == 0 {
+		return links
+	}
+
+	hash, err := hashids.EncodeID(records[len(records)-1].ID)
+	if err != nil {
+		return links
+	}
+	query := u.Query()
+	query.Set("from", string(hash))
+	next := *u
+	next.RawQuery = query.Encode()
+	return append(links, fhir.BundleLink{Relation: "next", URL: base + next.RequestURI()})
+}
+
+func requestBaseURL(req *http.Request) string {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + req.Host
+}
+
+func writeFHIR(ctx echo.Context, status int, resource interface{}) error {
+	ctx.Response().Header().Set(echo.HeaderContentType, fhir.MIMEApplicationFHIRJSONR4)
+	return ctx.JSON(status, resource)
+}
+
+func writeFHIRError(ctx echo.Context, status int, issueCode string, err *entity.Error) error {
+	writeFHIR(ctx, status, fhir.NewOperationOutcome(issueCode, err))
+	return err
+}
+
+// fhirIssueCode maps the kind of error to the code of OperationOutcome's issue.
+func fhirIssueCode(err *entity.Error) string {
+	switch err.Kind {
+	case entity.KindValidation:
+		return "invalid"
+	case entity.KindNotFound:
+		return "not-found"
+	case entity.KindUnauthorized:
+		return "login"
+	case entity.KindForbidden:
+		return "forbidden"
+	case entity.KindConflict:
+		return "conflict"
+	case entity.KindUnavailable:
+		return "transient"
+	}
+	return "exception"
+}
@@ -0,0 +1,239 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/middleware"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type FHIRMedicalRecordExecutor struct {
+	handler *handler.FHIRMedicalRecord
+	usecase *mock_usecase.MockFindMedicalRecord
+}
+
+func TestNewFHIRMedicalRecord(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of FHIRMedicalRecord", func(t *testing.T) {
+		exec := createFHIRMedicalRecordExecutor(ctrl)
+		assert.NotNil(t, exec.handler)
+	})
+}
+
+func TestFHIRMedicalRecord_Metadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("xml representation is not acceptable", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/fhir/r4/metadata", nil)
+		req.Header.Set(echo.HeaderAccept, "application/fhir+xml")
+
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		exec := createFHIRMedicalRecordExecutor(ctrl)
+		serve(ctx, exec.handler.Metadata)
+
+		assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+		assert.Equal(t, "OperationOutcome", decodeFHIRResource(rec)["resourceType"])
+	})
+
+	t.Run("successfully get capability statement", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/fhir/r4/metadata", nil)
+		req.Header.Set(echo.HeaderAccept, "application/fhir+json")
+
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		exec := createFHIRMedicalRecordExecutor(ctrl)
+		serve(ctx, exec.handler.Metadata)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/fhir+json; fhirVersion=4.0", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "CapabilityStatement", decodeFHIRResource(rec)["resourceType"])
+	})
+}
+
+func TestFHIRMedicalRecord_Read(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("resource type is not supported", func(t *testing.T) {
+		ctx, rec := createFHIRContext("/fhir/r4/Patient/oWx0b8DZ1a", "Patient", "oWx0b8DZ1a", createUserInformation())
+
+		exec := createFHIRMedicalRecordExecutor(ctrl)
+		serve(ctx, exec.handler.Read)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "OperationOutcome", decodeFHIRResource(rec)["resourceType"])
+	})
+
+	t.Run("resource id is not hashids.ID", func(t *testing.T) {
+		ctx, rec := createFHIRContext("/fhir/r4/Encounter/1234", "Encounter", "1234", createUserInformation())
+
+		exec := createFHIRMedicalRecordExecutor(ctrl)
+		serve(ctx, exec.handler.Read)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		ctx, rec := createFHIRContext("/fhir/r4/Encounter/oWx0b8DZ1a", "Encounter", "oWx0b8DZ1a", nil)
+
+		exec := createFHIRMedicalRecordExecutor(ctrl)
+		serve(ctx, exec.handler.Read)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("medical record is not owned by the user", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createFHIRContext("/fhir/r4/Encounter/oWx0b8DZ1a", "Encounter", "oWx0b8DZ1a", user)
+
+		exec := createFHIRMedicalRecordExecutor(ctrl)
+		exec.usecase.EXPECT().FindByID(ctx.Request().Context(), uint64(1), uint64(user.ID)).Return(nil, entity.ErrForbidden)
+		serve(ctx, exec.handler.Read)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("successfully read condition", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createFHIRContext("/fhir/r4/Condition/oWx0b8DZ1a", "Condition", "oWx0b8DZ1a", user)
+
+		exec := createFHIRMedicalRecordExecutor(ctrl)
+		exec.usecase.EXPECT().FindByID(ctx.Request().Context(), uint64(1), uint64(user.ID)).Return(createMedicalRecords()[0], nil)
+		serve(ctx, exec.handler.Read)
+
+		res := decodeFHIRResource(rec)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Condition", res["resourceType"])
+		assert.Equal(t, "oWx0b8DZ1a", res["id"])
+		assert.Equal(t, "Diagnosis", res["code"].(map[string]interface{})["text"])
+	})
+}
+
+func TestFHIRMedicalRecord_Search(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("patient is not the user", func(t *testing.T) {
+		ctx, rec := createFHIRContext("/fhir/r4/Encounter?patient=other@email.com", "Encounter", "", createUserInformation())
+
+		exec := createFHIRMedicalRecordExecutor(ctrl)
+		serve(ctx, exec.handler.Search)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("date param is invalid", func(t *testing.T) {
+		paths := []string{"/fhir/r4/Encounter?date=yesterday", "/fhir/r4/Encounter?date=ne2021-01-01"}
+		for _, path := range paths {
+			ctx, rec := createFHIRContext(path, "Encounter", "", createUserInformation())
+
+			exec := createFHIRMedicalRecordExecutor(ctrl)
+			serve(ctx, exec.handler.Search)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("finder service returns 5xx error", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createFHIRContext("/fhir/r4/Encounter", "Encounter", "", user)
+
+		exec := createFHIRMedicalRecordExecutor(ctrl)
+		exec.usecase.EXPECT().FindByUserIDWithinPeriod(ctx.Request().Context(), uint64(user.ID), gomock.Any(), gomock.Any(), uint64(maxUint64)).Return(nil, false, entity.ErrInternalServer)
+		serve(ctx, exec.handler.Search)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("successfully search observations by patient and date", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createFHIRContext("/fhir/r4/Observation?patient=mailto:user@email.com&date=ge2021-01&date=lt2021-01-29", "Observation", "", user)
+
+		since := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+		until := time.Date(2021, time.January, 29, 0, 0, 0, 0, time.UTC)
+
+		exec := createFHIRMedicalRecordExecutor(ctrl)
+		exec.usecase.EXPECT().FindByUserIDWithinPeriod(ctx.Request().Context(), uint64(user.ID), since, until, uint64(maxUint64)).Return(createMedicalRecords(), true, nil)
+		serve(ctx, exec.handler.Search)
+
+		res := decodeFHIRResource(rec)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/fhir+json; fhirVersion=4.0", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "Bundle", res["resourceType"])
+		assert.NotContains(t, res, "total")
+
+		entry := res["entry"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "Observation", entry["resource"].(map[string]interface{})["resourceType"])
+
+		links := res["link"].([]interface{})
+		assert.Equal(t, 2, len(links))
+		assert.Contains(t, links[1].(map[string]interface{})["url"], "from=oWx0b8DZ1a")
+	})
+
+	t.Run("last page has no next link", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createFHIRContext("/fhir/r4/Encounter", "Encounter", "", user)
+
+		exec := createFHIRMedicalRecordExecutor(ctrl)
+		exec.usecase.EXPECT().FindByUserIDWithinPeriod(ctx.Request().Context(), uint64(user.ID), gomock.Any(), gomock.Any(), uint64(maxUint64)).Return(createMedicalRecords(), false, nil)
+		serve(ctx, exec.handler.Search)
+
+		res := decodeFHIRResource(rec)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		links := res["link"].([]interface{})
+		assert.Equal(t, 1, len(links))
+		assert.Equal(t, "self", links[0].(map[string]interface{})["relation"])
+	})
+}
+
+func createFHIRContext(path, resourceType, id string, user *entity.User) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if user != nil {
+		req = req.WithContext(context.WithValue(context.Background(), middleware.ContextKeyUser, user))
+	}
+
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(req, rec)
+	if id == "" {
+		ctx.SetPath("/fhir/r4/:type")
+		ctx.SetParamNames("type")
+		ctx.SetParamValues(resourceType)
+	} else {
+		ctx.SetPath("/fhir/r4/:type/:id")
+		ctx.SetParamNames("type", "id")
+		ctx.SetParamValues(resourceType, id)
+	}
+	return ctx, rec
+}
+
+func decodeFHIRResource(rec *httptest.ResponseRecorder) map[string]interface{} {
+	var res map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &res)
+	return res
+}
+
+func createFHIRMedicalRecordExecutor(ctrl *gomock.Controller) *FHIRMedicalRecordExecutor {
+	u := mock_usecase.NewMockFindMedicalRecord(ctrl)
+	h := handler.NewFHIRMedicalRecord(u)
+	return &FHIRMedicalRecordExecutor{
+		handler: h,
+		usecase: u,
+	}
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/indrasaputra/orvosi-api/internal/http/response"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// HealthChecker handles HTTP request and response
+// for health check.
+type HealthChecker struct {
+	checker usecase.CheckHealth
+}
+
+// NewHealthChecker creates an instance of HealthChecker.
+func NewHealthChecker(checker usecase.CheckHealth) *HealthChecker {
+	return &HealthChecker{
+		checker: checker,
+	}
+}
+
+// Check handles `GET /health` endpoint.
+func (hc *HealthChecker) Check(ctx echo.Context) error {
+	if err := hc.checker.Check(ctx.Request().Context()); err != nil {
+		return err
+	}
+
+	ctx.JSON(http.StatusOK, response.NewSuccess(nil, response.EmptyMeta{}))
+	return nil
+}
+
+// Ready handles `GET /ready` endpoint.
+func (hc *HealthChecker) Ready(ctx echo.Context) error {
+	if err := hc.checker.Ready(ctx.Request().Context()); err != nil {
+		return err
+	}
+
+	ctx.JSON(http.StatusOK, response.NewSuccess(nil, response.EmptyMeta{}))
+	return nil
+}
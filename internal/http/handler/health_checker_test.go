@@ -0,0 +1,107 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type HealthCheckerExecutor struct {
+	handler *handler.HealthChecker
+	usecase *mock_usecase.MockCheckHealth
+}
+
+func TestNewHealthChecker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of HealthChecker", func(t *testing.T) {
+		exec := createHealthCheckerExecutor(ctrl)
+		assert.NotNil(t, exec.handler)
+	})
+}
+
+func TestHealthChecker_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("database is unreachable", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		exec := createHealthCheckerExecutor(ctrl)
+		exec.usecase.EXPECT().Check(req.Context()).Return(entity.ErrServiceUnavailable)
+
+		serve(ctx, exec.handler.Check)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-008","message":"Service is unavailable"}],"meta":null}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+
+	t.Run("system is healthy", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		exec := createHealthCheckerExecutor(ctrl)
+		exec.usecase.EXPECT().Check(req.Context()).Return(nil)
+
+		serve(ctx, exec.handler.Check)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"data":null,"meta":{}}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+}
+
+func TestHealthChecker_Ready(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("system is shutting down", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/ready", nil)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		exec := createHealthCheckerExecutor(ctrl)
+		exec.usecase.EXPECT().Ready(req.Context()).Return(entity.ErrShuttingDown)
+
+		serve(ctx, exec.handler.Ready)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-015","message":"Service is shutting down"}],"meta":null}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+
+	t.Run("system is ready", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/ready", nil)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		exec := createHealthCheckerExecutor(ctrl)
+		exec.usecase.EXPECT().Ready(req.Context()).Return(nil)
+
+		serve(ctx, exec.handler.Ready)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func createHealthCheckerExecutor(ctrl *gomock.Controller) *HealthCheckerExecutor {
+	u := mock_usecase.NewMockCheckHealth(ctrl)
+	h := handler.NewHealthChecker(u)
+	return &HealthCheckerExecutor{
+		handler: h,
+		usecase: u,
+	}
+}
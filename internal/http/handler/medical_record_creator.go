@@ -7,15 +7,17 @@ import (
 	"github.com/indrasaputra/orvosi-api/entity"
 	"github.com/indrasaputra/orvosi-api/internal/http/middleware"
 	"github.com/indrasaputra/orvosi-api/internal/http/response"
+	"github.com/indrasaputra/orvosi-api/internal/validation"
 	"github.com/indrasaputra/orvosi-api/usecase"
 	"github.com/labstack/echo/v4"
 )
 
 // CreateMedicalRecordRequest represents medical record request.
+// The fields are validated declaratively, see package validation.
 type CreateMedicalRecordRequest struct {
-	Symptom   string `json:"symptom"`
-	Diagnosis string `json:"diagnosis"`
-	Therapy   string `json:"therapy"`
+	Symptom   string `json:"symptom" validate:"required"`
+	Diagnosis string `json:"diagnosis" validate:"required"`
+	Therapy   string `json:"therapy" validate:"required"`
 }
 
 // MedicalRecordCreator handles HTTP request and response
@@ -35,26 +37,19 @@ func NewMedicalRecordCreator(creator usecase.CreateMedicalRecord) *MedicalRecord
 func (mrc *MedicalRecordCreator) Create(ctx echo.Context) error {
 	var request CreateMedicalRecordRequest
 	if err := ctx.Bind(&request); err != nil {
-		res := response.NewError(entity.ErrInvalidMedicalRecordRequest)
-		ctx.JSON(http.StatusBadRequest, res)
-		return err
+		return entity.WrapError(entity.ErrInvalidMedicalRecordRequest, err.Error())
+	}
+	if params := validation.Validate(&request); len(params) > 0 {
+		return entity.WithInvalidParams(entity.ErrInvalidMedicalRecordAttribute, params)
 	}
 
 	user, err := extractUserFromRequestContext(ctx.Request().Context())
 	if err != nil {
-		res := response.NewError(err)
-		ctx.JSON(http.StatusInternalServerError, res)
 		return err
 	}
 
 	record := createMedicalRecordFromCreateRequest(&request, user)
 	if err := mrc.creator.Create(ctx.Request().Context(), record); err != nil {
-		res := response.NewError(err)
-		status := http.StatusInternalServerError
-		if err.Code != entity.ErrInternalServer.Code {
-			status = http.StatusBadRequest
-		}
-		ctx.JSON(status, res)
 		return err
 	}
 
@@ -62,7 +57,7 @@ func (mrc *MedicalRecordCreator) Create(ctx echo.Context) error {
 	return nil
 }
 
-func extractUserFromRequestContext(ctx context.Context) (*entity.User, error) {
+func extractUserFromRequestContext(ctx context.Context) (*entity.User, *entity.Error) {
 	val := ctx.Value(middleware.ContextKeyUser)
 	user, ok := val.(*entity.User)
 	if !ok {
@@ -48,13 +48,48 @@ func TestMedicalRecordCreator_Create(t *testing.T) {
 		ctx := e.NewContext(req, rec)
 
 		exec := createMedicalRecordCreatorExecutor(ctrl)
-		exec.handler.Create(ctx)
+		serve(ctx, exec.handler.Create)
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"02-003","message":"Medical record request is invalid. Please, check the JSON request"}],"meta":null}`)
 		assert.Equal(t, str, rec.Body.String())
 	})
 
+	t.Run("blank attributes are listed as invalid params", func(t *testing.T) {
+		body, _ := json.Marshal(&handler.CreateMedicalRecordRequest{Symptom: "symptom", Diagnosis: " "})
+		req := httptest.NewRequest(http.MethodPost, "/medical-records", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", echo.MIMEApplicationJSON)
+		req.Header.Set("Accept", "application/problem+json")
+
+		rec := httptest.NewRecorder()
+		e := echo.New()
+		ctx := e.NewContext(req, rec)
+
+		exec := createMedicalRecordCreatorExecutor(ctrl)
+		serve(ctx, exec.handler.Create)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"type":"urn:orvosi:error:02-002","title":"Medical record's attributes are invalid. Please, check all attributes","status":400,"detail":"diagnosis must not be blank; therapy must not be blank","instance":"/medical-records","code":"02-002","invalid_params":[{"name":"diagnosis","rule":"required","reason":"must not be blank"},{"name":"therapy","rule":"required","reason":"must not be blank"}]}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+
+	t.Run("blank attributes keep the error envelope for clients expecting JSON", func(t *testing.T) {
+		body, _ := json.Marshal(&handler.CreateMedicalRecordRequest{})
+		req := httptest.NewRequest(http.MethodPost, "/medical-records", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		e := echo.New()
+		ctx := e.NewContext(req, rec)
+
+		exec := createMedicalRecordCreatorExecutor(ctrl)
+		serve(ctx, exec.handler.Create)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"02-002","message":"Medical record's attributes are invalid. Please, check all attributes"}],"meta":null}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+
 	t.Run("can't extract user information from request context", func(t *testing.T) {
 		mr := createValidCreateMedicalRecordRequest()
 		body, _ := json.Marshal(mr)
@@ -66,7 +101,7 @@ func TestMedicalRecordCreator_Create(t *testing.T) {
 		ctx := e.NewContext(req, rec)
 
 		exec := createMedicalRecordCreatorExecutor(ctrl)
-		exec.handler.Create(ctx)
+		serve(ctx, exec.handler.Create)
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-001","message":"Internal server error"}],"meta":null}`)
@@ -87,7 +122,7 @@ func TestMedicalRecordCreator_Create(t *testing.T) {
 
 		exec := createMedicalRecordCreatorExecutor(ctrl)
 		exec.usecase.EXPECT().Create(ctx.Request().Context(), createMedicalRecordFromCreateRequest(mr, user)).Return(entity.ErrInvalidMedicalRecordAttribute)
-		exec.handler.Create(ctx)
+		serve(ctx, exec.handler.Create)
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"02-002","message":"Medical record's attributes are invalid. Please, check all attributes"}],"meta":null}`)
@@ -108,7 +143,7 @@ func TestMedicalRecordCreator_Create(t *testing.T) {
 
 		exec := createMedicalRecordCreatorExecutor(ctrl)
 		exec.usecase.EXPECT().Create(ctx.Request().Context(), createMedicalRecordFromCreateRequest(mr, user)).Return(entity.ErrInternalServer)
-		exec.handler.Create(ctx)
+		serve(ctx, exec.handler.Create)
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-001","message":"Internal server error"}],"meta":null}`)
@@ -129,7 +164,7 @@ func TestMedicalRecordCreator_Create(t *testing.T) {
 
 		exec := createMedicalRecordCreatorExecutor(ctrl)
 		exec.usecase.EXPECT().Create(ctx.Request().Context(), createMedicalRecordFromCreateRequest(mr, user)).Return(nil)
-		exec.handler.Create(ctx)
+		serve(ctx, exec.handler.Create)
 
 		assert.Equal(t, http.StatusCreated, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"data":null,"meta":{}}`)
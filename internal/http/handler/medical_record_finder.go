@@ -50,26 +50,16 @@ func (mf *MedicalRecordFinder) FindByID(ctx echo.Context) error {
 	str := ctx.Param("id")
 	id, herr := hashids.DecodeHash([]byte(str))
 	if herr != nil {
-		res := response.NewError(entity.ErrInvalidID)
-		ctx.JSON(http.StatusInternalServerError, res)
-		return herr
+		return entity.WrapError(entity.ErrInvalidID, herr.Error())
 	}
 
 	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
 	if cerr != nil {
-		res := response.NewError(cerr)
-		ctx.JSON(http.StatusInternalServerError, res)
 		return cerr
 	}
 
-	record, ferr := mf.finder.FindByID(ctx.Request().Context(), uint64(id), user.Email)
+	record, ferr := mf.finder.FindByID(ctx.Request().Context(), uint64(id), uint64(user.ID))
 	if ferr != nil {
-		res := response.NewError(ferr)
-		status := http.StatusInternalServerError
-		if ferr.Code == entity.ErrUnauthorized.Code {
-			status = http.StatusUnauthorized
-		}
-		ctx.JSON(status, res)
 		return ferr
 	}
 
@@ -78,32 +68,22 @@ func (mf *MedicalRecordFinder) FindByID(ctx echo.Context) error {
 	return nil
 }
 
-// FindByEmail handles `GET /medical-records` endpoint.
-// It extracts the user's email from bearer token
+// FindByUser handles `GET /medical-records` endpoint.
+// It extracts the user from the request context
 // then finds all medical records bounded to the user.
-func (mf *MedicalRecordFinder) FindByEmail(ctx echo.Context) error {
+func (mf *MedicalRecordFinder) FindByUser(ctx echo.Context) error {
 	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
 	if cerr != nil {
-		res := response.NewError(cerr)
-		ctx.JSON(http.StatusInternalServerError, res)
 		return cerr
 	}
 
 	from, qerr := extractQueryParam(ctx.QueryParam("from"))
 	if qerr != nil {
-		res := response.NewError(qerr)
-		ctx.JSON(http.StatusBadRequest, res)
 		return qerr
 	}
 
-	records, ferr := mf.finder.FindByEmail(ctx.Request().Context(), user.Email, from)
+	records, ferr := mf.finder.FindByUserID(ctx.Request().Context(), uint64(user.ID), from)
 	if ferr != nil {
-		res := response.NewError(ferr)
-		status := http.StatusInternalServerError
-		if ferr.Code != entity.ErrInternalServer.Code {
-			status = http.StatusBadRequest
-		}
-		ctx.JSON(status, res)
 		return ferr
 	}
 
@@ -52,9 +52,9 @@ func TestMedicalRecordFinder_FindByID(t *testing.T) {
 		ctx.SetParamValues("1234")
 
 		exec := createMedicalRecordFinderExecutor(ctrl)
-		exec.handler.FindByID(ctx)
+		serve(ctx, exec.handler.FindByID)
 
-		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-004","message":"Entity ID is invalid"}],"meta":null}`)
 		assert.Equal(t, str, rec.Body.String())
 	})
@@ -70,7 +70,7 @@ func TestMedicalRecordFinder_FindByID(t *testing.T) {
 		ctx.SetParamValues("oWx0b8DZ1a")
 
 		exec := createMedicalRecordFinderExecutor(ctrl)
-		exec.handler.FindByID(ctx)
+		serve(ctx, exec.handler.FindByID)
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-001","message":"Internal server error"}],"meta":null}`)
@@ -90,11 +90,11 @@ func TestMedicalRecordFinder_FindByID(t *testing.T) {
 		ctx.SetParamValues("oWx0b8DZ1a")
 
 		exec := createMedicalRecordFinderExecutor(ctrl)
-		exec.usecase.EXPECT().FindByID(ctx.Request().Context(), uint64(1), user.Email).Return(nil, entity.ErrUnauthorized)
-		exec.handler.FindByID(ctx)
+		exec.usecase.EXPECT().FindByID(ctx.Request().Context(), uint64(1), uint64(user.ID)).Return(nil, entity.ErrForbidden)
+		serve(ctx, exec.handler.FindByID)
 
-		assert.Equal(t, http.StatusUnauthorized, rec.Code)
-		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-002","message":"Request is unauthorized"}],"meta":null}`)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-012","message":"Access to the resource is forbidden"}],"meta":null}`)
 		assert.Equal(t, str, rec.Body.String())
 	})
 
@@ -111,8 +111,8 @@ func TestMedicalRecordFinder_FindByID(t *testing.T) {
 		ctx.SetParamValues("oWx0b8DZ1a")
 
 		exec := createMedicalRecordFinderExecutor(ctrl)
-		exec.usecase.EXPECT().FindByID(ctx.Request().Context(), uint64(1), user.Email).Return(nil, entity.ErrInternalServer)
-		exec.handler.FindByID(ctx)
+		exec.usecase.EXPECT().FindByID(ctx.Request().Context(), uint64(1), uint64(user.ID)).Return(nil, entity.ErrInternalServer)
+		serve(ctx, exec.handler.FindByID)
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-001","message":"Internal server error"}],"meta":null}`)
@@ -132,8 +132,8 @@ func TestMedicalRecordFinder_FindByID(t *testing.T) {
 		ctx.SetParamValues("oWx0b8DZ1a")
 
 		exec := createMedicalRecordFinderExecutor(ctrl)
-		exec.usecase.EXPECT().FindByID(ctx.Request().Context(), uint64(1), user.Email).Return(createMedicalRecords()[0], nil)
-		exec.handler.FindByID(ctx)
+		exec.usecase.EXPECT().FindByID(ctx.Request().Context(), uint64(1), uint64(user.ID)).Return(createMedicalRecords()[0], nil)
+		serve(ctx, exec.handler.FindByID)
 
 		assert.Equal(t, http.StatusOK, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"data":{"id":"oWx0b8DZ1a","symptom":"Symptom","diagnosis":"Diagnosis","therapy":"Therapy","result":"Result","created_by":"user@dummy.com","created_at":"2021-01-28T15:00:00Z","updated_by":"user@dummy.com","updated_at":"2021-01-28T15:00:00Z"},"meta":{}}`)
@@ -141,7 +141,7 @@ func TestMedicalRecordFinder_FindByID(t *testing.T) {
 	})
 }
 
-func TestMedicalRecordFinder_FindByEmail(t *testing.T) {
+func TestMedicalRecordFinder_FindByUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -153,7 +153,7 @@ func TestMedicalRecordFinder_FindByEmail(t *testing.T) {
 		ctx := e.NewContext(req, rec)
 
 		exec := createMedicalRecordFinderExecutor(ctrl)
-		exec.handler.FindByEmail(ctx)
+		serve(ctx, exec.handler.FindByUser)
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-001","message":"Internal server error"}],"meta":null}`)
@@ -172,7 +172,7 @@ func TestMedicalRecordFinder_FindByEmail(t *testing.T) {
 			ctx := e.NewContext(req, rec)
 
 			exec := createMedicalRecordFinderExecutor(ctrl)
-			exec.handler.FindByEmail(ctx)
+			serve(ctx, exec.handler.FindByUser)
 
 			assert.Equal(t, http.StatusBadRequest, rec.Code)
 			str := fmt.Sprintf("%s\n", `{"errors":[{"code":"02-006","message":"Query param(s) is invalid"}],"meta":null}`)
@@ -190,8 +190,8 @@ func TestMedicalRecordFinder_FindByEmail(t *testing.T) {
 		ctx := e.NewContext(req, rec)
 
 		exec := createMedicalRecordFinderExecutor(ctrl)
-		exec.usecase.EXPECT().FindByEmail(ctx.Request().Context(), user.Email, uint64(1)).Return([]*entity.MedicalRecord{}, entity.ErrInvalidEmail)
-		exec.handler.FindByEmail(ctx)
+		exec.usecase.EXPECT().FindByUserID(ctx.Request().Context(), uint64(user.ID), uint64(1)).Return([]*entity.MedicalRecord{}, entity.ErrInvalidEmail)
+		serve(ctx, exec.handler.FindByUser)
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"02-004","message":"Email is invalid. Please, check the email"}],"meta":null}`)
@@ -208,8 +208,8 @@ func TestMedicalRecordFinder_FindByEmail(t *testing.T) {
 		ctx := e.NewContext(req, rec)
 
 		exec := createMedicalRecordFinderExecutor(ctrl)
-		exec.usecase.EXPECT().FindByEmail(ctx.Request().Context(), user.Email, uint64(1)).Return([]*entity.MedicalRecord{}, entity.ErrInternalServer)
-		exec.handler.FindByEmail(ctx)
+		exec.usecase.EXPECT().FindByUserID(ctx.Request().Context(), uint64(user.ID), uint64(1)).Return([]*entity.MedicalRecord{}, entity.ErrInternalServer)
+		serve(ctx, exec.handler.FindByUser)
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-001","message":"Internal server error"}],"meta":null}`)
@@ -237,8 +237,8 @@ func TestMedicalRecordFinder_FindByEmail(t *testing.T) {
 
 			exec := createMedicalRecordFinderExecutor(ctrl)
 			mrs := createMedicalRecords()
-			exec.usecase.EXPECT().FindByEmail(ctx.Request().Context(), user.Email, table.param).Return(mrs, nil)
-			exec.handler.FindByEmail(ctx)
+			exec.usecase.EXPECT().FindByUserID(ctx.Request().Context(), uint64(user.ID), table.param).Return(mrs, nil)
+			serve(ctx, exec.handler.FindByUser)
 
 			assert.Equal(t, http.StatusOK, rec.Code)
 			str := fmt.Sprintf("%s\n", `{"data":[{"id":"oWx0b8DZ1a","symptom":"Symptom","diagnosis":"Diagnosis","therapy":"Therapy","result":"Result","created_by":"user@dummy.com","created_at":"2021-01-28T15:00:00Z","updated_by":"user@dummy.com","updated_at":"2021-01-28T15:00:00Z"}],"meta":{}}`)
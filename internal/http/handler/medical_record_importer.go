@@ -0,0 +1,388 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/response"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	importFormatCSV    = "csv"
+	importFormatNDJSON = "ndjson"
+
+	importFileField   = "file"
+	importFormatField = "format"
+)
+
+// ImportMedicalRecordRow represents a single medical record row in NDJSON import.
+type ImportMedicalRecordRow struct {
+	Symptom   string `json:"symptom"`
+	Diagnosis string `json:"diagnosis"`
+	Therapy   string `json:"therapy"`
+	Result    string `json:"result"`
+}
+
+// ImportRowErrorResponse defines the JSON response of a row that can't be imported.
+type ImportRowErrorResponse struct {
+	Row     int    `json:"row"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ImportReportResponse defines the JSON response of medical record import.
+type ImportReportResponse struct {
+	DryRun        bool                      `json:"dry_run"`
+	Total         int                       `json:"total"`
+	Valid         int                       `json:"valid"`
+	Imported      int                       `json:"imported"`
+	Errors        []*ImportRowErrorResponse `json:"errors"`
+	OmittedErrors int                       `json:"omitted_errors"`
+}
+
+// MedicalRecordImporter handles HTTP request and response
+// for import medical records.
+type MedicalRecordImporter struct {
+	importer  usecase.ImportMedicalRecord
+	bodyLimit int64
+}
+
+// NewMedicalRecordImporter creates an instance of MedicalRecordImporter.
+// The body limit is the maximum size of the request body,
+// so a single NDJSON line can be as long as the body.
+func NewMedicalRecordImporter(importer usecase.ImportMedicalRecord, bodyLimit int64) *MedicalRecordImporter {
+	return &MedicalRecordImporter{
+		importer:  importer,
+		bodyLimit: bodyLimit,
+	}
+}
+
+// Import handles `POST /medical-records/import` endpoint.
+// It expects a multipart form with the file in field `file`.
+// The file is copied into a temporary file, so it is never fully buffered in memory
+// and it can be read again if the import is run again.
+// Set query param `dry_run=true` to only validate the file.
+func (mi *MedicalRecordImporter) Import(ctx echo.Context) error {
+	dryRun, qerr := extractDryRunParam(ctx.QueryParam("dry_run"))
+	if qerr != nil {
+		return qerr
+	}
+
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	part, format, perr := findImportFilePart(ctx.Request())
+	if perr != nil {
+		return perr
+	}
+	defer part.Close()
+
+	file, ferr := spoolImportFile(part)
+	if ferr != nil {
+		return ferr
+	}
+	defer func() {
+		file.Close()
+		os.Remove(file.Name())
+	}()
+
+	source, serr := newMedicalRecordSource(format, file, mi.bodyLimit)
+	if serr != nil {
+		return serr
+	}
+
+	report, ierr := mi.importer.Import(ctx.Request().Context(), user, source, dryRun)
+	if ierr != nil {
+		return ierr
+	}
+
+	status := http.StatusCreated
+	if dryRun {
+		status = http.StatusOK
+	}
+	ctx.JSON(status, response.NewSuccess(createImportReportResponse(report), response.EmptyMeta{}))
+	return nil
+}
+
+func extractDryRunParam(param string) (bool, *entity.Error) {
+	if param == "" {
+		return false, nil
+	}
+
+	dryRun, err := strconv.ParseBool(param)
+	if err != nil {
+		return false, entity.ErrInvalidParam
+	}
+	return dryRun, nil
+}
+
+// findImportFilePart walks through the multipart body until it finds the file part.
+// The format is taken from field `format` if it is sent before the file,
+// otherwise it is detected from the file name or the part's content type.
+func findImportFilePart(req *http.Request) (*multipart.Part, string, *entity.Error) {
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return nil, "", entity.WrapError(entity.ErrInvalidImportFile, err.Error())
+	}
+
+	format := ""
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, "", entity.WrapError(entity.ErrInvalidImportFile, "file part is missing")
+		}
+		if err != nil {
+			return nil, "", entity.WrapError(entity.ErrInvalidImportFile, err.Error())
+		}
+
+		switch part.FormName() {
+		case importFormatField:
+			val, _ := io.ReadAll(io.LimitReader(part, int64(len(importFormatNDJSON))))
+			format = strings.ToLower(strings.TrimSpace(string(val)))
+			part.Close()
+		case importFileField:
+			if format == "" {
+				format = detectImportFormat(part.FileName(), part.Header.Get(echo.HeaderContentType))
+			}
+			return part, format, nil
+		default:
+			part.Close()
+		}
+	}
+}
+
+func detectImportFormat(filename, contentType string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return importFormatCSV
+	case ".ndjson", ".jsonl":
+		return importFormatNDJSON
+	}
+
+	switch {
+	case strings.HasPrefix(contentType, "text/csv"):
+		return importFormatCSV
+	case strings.HasPrefix(contentType, "application/x-ndjson"):
+		return importFormatNDJSON
+	}
+	return ""
+}
+
+// spoolImportFile copies the file part into a temporary file and rewinds it.
+// The caller must close and remove the file.
+func spoolImportFile(part io.Reader) (*os.File, *entity.Error) {
+	file, err := ioutil.TempFile("", "orvosi-import-*")
+	if err != nil {
+		return nil, entity.WrapError(entity.ErrInternalServer, "[MedicalRecordImporter-Import] create temporary file: "+err.Error())
+	}
+	if _, err = io.Copy(file, part); err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, entity.WrapError(entity.ErrInvalidImportFile, err.Error())
+	}
+	return file, nil
+}
+
+// medicalRecordReader reads medical records from a single pass of the file.
+type medicalRecordReader interface {
+	Next() (*entity.MedicalRecord, error)
+}
+
+// medicalRecordSource reads medical records from the file in the format,
+// and starts over from the beginning of the file when it is rewound.
+type medicalRecordSource struct {
+	file      io.ReadSeeker
+	format    string
+	bodyLimit int64
+	reader    medicalRecordReader
+}
+
+func newMedicalRecordSource(format string, file io.ReadSeeker, bodyLimit int64) (*medicalRecordSource, *entity.Error) {
+	source := &medicalRecordSource{
+		file:      file,
+		format:    format,
+		bodyLimit: bodyLimit,
+	}
+	if err := source.open(); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+// Next returns the next medical record in the file.
+func (ms *medicalRecordSource) Next() (*entity.MedicalRecord, error) {
+	return ms.reader.Next()
+}
+
+// Rewind restarts reading from the first medical record in the file.
+func (ms *medicalRecordSource) Rewind() error {
+	if _, err := ms.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := ms.open(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ms *medicalRecordSource) open() *entity.Error {
+	switch ms.format {
+	case importFormatCSV:
+		reader, err := newCSVMedicalRecordSource(ms.file)
+		if err != nil {
+			return err
+		}
+		ms.reader = reader
+		return nil
+	case importFormatNDJSON:
+		ms.reader = newNDJSONMedicalRecordSource(ms.file, ms.bodyLimit)
+		return nil
+	}
+	return entity.WrapError(entity.ErrInvalidImportFile, "unknown format: "+ms.format)
+}
+
+// csvMedicalRecordSource reads medical records from CSV.
+// The first line must be the header. Columns symptom, diagnosis, and therapy are mandatory,
+// column result is optional, and the order doesn't matter.
+type csvMedicalRecordSource struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVMedicalRecordSource(r io.Reader) (*csvMedicalRecordSource, *entity.Error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, entity.WrapError(entity.ErrInvalidImportFile, "read csv header: "+err.Error())
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, col := range header {
+		columns[strings.ToLower(strings.TrimSpace(col))] = i
+	}
+	for _, col := range []string{"symptom", "diagnosis", "therapy"} {
+		if _, ok := columns[col]; !ok {
+			return nil, entity.WrapError(entity.ErrInvalidImportFile, "csv header doesn't contain column "+col)
+		}
+	}
+
+	return &csvMedicalRecordSource{
+		reader:  reader,
+		columns: columns,
+	}, nil
+}
+
+// Next returns the next medical record in CSV.
+func (cs *csvMedicalRecordSource) Next() (*entity.MedicalRecord, error) {
+	row, err := cs.reader.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if perr, ok := err.(*csv.ParseError); ok {
+		return nil, entity.WrapError(entity.ErrInvalidImportRow, perr.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.MedicalRecord{
+		Symptom:   cs.column(row, "symptom"),
+		Diagnosis: cs.column(row, "diagnosis"),
+		Therapy:   cs.column(row, "therapy"),
+		Result:    cs.column(row, "result"),
+	}, nil
+}
+
+func (cs *csvMedicalRecordSource) column(row []string, name string) string {
+	i, ok := cs.columns[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return row[i]
+}
+
+// ndjsonMedicalRecordSource reads medical records from newline delimited JSON.
+// Each non-blank line must be a JSON object of ImportMedicalRecordRow.
+type ndjsonMedicalRecordSource struct {
+	scanner *bufio.Scanner
+}
+
+// newNDJSONMedicalRecordSource creates an instance of ndjsonMedicalRecordSource.
+// The buffer only grows up to the body limit when a line needs it.
+func newNDJSONMedicalRecordSource(r io.Reader, bodyLimit int64) *ndjsonMedicalRecordSource {
+	scanner := bufio.NewScanner(r)
+	if bodyLimit > bufio.MaxScanTokenSize {
+		scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), int(bodyLimit))
+	}
+	return &ndjsonMedicalRecordSource{
+		scanner: scanner,
+	}
+}
+
+// Next returns the next medical record in NDJSON.
+func (ns *ndjsonMedicalRecordSource) Next() (*entity.MedicalRecord, error) {
+	for ns.scanner.Scan() {
+		line := bytes.TrimSpace(ns.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var row ImportMedicalRecordRow
+		if err := json.Unmarshal(line, &row); err != nil {
+			return nil, entity.WrapError(entity.ErrInvalidImportRow, err.Error())
+		}
+		return &entity.MedicalRecord{
+			Symptom:   row.Symptom,
+			Diagnosis: row.Diagnosis,
+			Therapy:   row.Therapy,
+			Result:    row.Result,
+		}, nil
+	}
+
+	if err := ns.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func createImportReportResponse(report *entity.ImportReport) *ImportReportResponse {
+	errs := make([]*ImportRowErrorResponse, len(report.Errors))
+	for i, e := range report.Errors {
+		errs[i] = &ImportRowErrorResponse{
+			Row:     e.Row,
+			Code:    e.Err.Code,
+			Message: e.Err.Message,
+		}
+	}
+
+	return &ImportReportResponse{
+		DryRun:        report.DryRun,
+		Total:         report.Total,
+		Valid:         report.Valid,
+		Imported:      report.Imported,
+		Errors:        errs,
+		OmittedErrors: report.OmittedErrors,
+	}
+}
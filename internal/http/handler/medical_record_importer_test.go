@@ -0,0 +1,278 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/middleware"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type MedicalRecordImporterExecutor struct {
+	handler *handler.MedicalRecordImporter
+	usecase *mock_usecase.MockImportMedicalRecord
+}
+
+func TestNewMedicalRecordImporter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of MedicalRecordImporter", func(t *testing.T) {
+		exec := createMedicalRecordImporterExecutor(ctrl)
+		assert.NotNil(t, exec.handler)
+	})
+}
+
+func TestMedicalRecordImporter_Import(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("query param 'dry_run' is invalid", func(t *testing.T) {
+		body, contentType := createImportMultipartBody("", "records.csv", "symptom,diagnosis,therapy\n")
+		req := httptest.NewRequest(http.MethodPost, "/medical-records/import?dry_run=maybe", body)
+		req.Header.Set(echo.HeaderContentType, contentType)
+		req = req.WithContext(context.WithValue(context.Background(), middleware.ContextKeyUser, createUserInformation()))
+
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		exec := createMedicalRecordImporterExecutor(ctrl)
+		serve(ctx, exec.handler.Import)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"02-006","message":"Query param(s) is invalid"}],"meta":null}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		body, contentType := createImportMultipartBody("", "records.csv", "symptom,diagnosis,therapy\n")
+		req := httptest.NewRequest(http.MethodPost, "/medical-records/import", body)
+		req.Header.Set(echo.HeaderContentType, contentType)
+
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		exec := createMedicalRecordImporterExecutor(ctrl)
+		serve(ctx, exec.handler.Import)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-001","message":"Internal server error"}],"meta":null}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+
+	t.Run("request is not multipart", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/medical-records/import", bytes.NewBufferString("symptom,diagnosis,therapy\n"))
+		req.Header.Set(echo.HeaderContentType, "text/csv")
+		req = req.WithContext(context.WithValue(context.Background(), middleware.ContextKeyUser, createUserInformation()))
+
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		exec := createMedicalRecordImporterExecutor(ctrl)
+		serve(ctx, exec.handler.Import)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"02-007","message":"Import file is invalid. Please, check the file and its format"}],"meta":null}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+
+	t.Run("file format is unknown", func(t *testing.T) {
+		body, contentType := createImportMultipartBody("", "records.txt", "symptom,diagnosis,therapy\n")
+		req := httptest.NewRequest(http.MethodPost, "/medical-records/import", body)
+		req.Header.Set(echo.HeaderContentType, contentType)
+		req = req.WithContext(context.WithValue(context.Background(), middleware.ContextKeyUser, createUserInformation()))
+
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		exec := createMedicalRecordImporterExecutor(ctrl)
+		serve(ctx, exec.handler.Import)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"02-007","message":"Import file is invalid. Please, check the file and its format"}],"meta":null}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+
+	t.Run("csv header misses mandatory column", func(t *testing.T) {
+		body, contentType := createImportMultipartBody("", "records.csv", "symptom,therapy\nfever,rest\n")
+		req := httptest.NewRequest(http.MethodPost, "/medical-records/import", body)
+		req.Header.Set(echo.HeaderContentType, contentType)
+		req = req.WithContext(context.WithValue(context.Background(), middleware.ContextKeyUser, createUserInformation()))
+
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		exec := createMedicalRecordImporterExecutor(ctrl)
+		serve(ctx, exec.handler.Import)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"02-007","message":"Import file is invalid. Please, check the file and its format"}],"meta":null}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+
+	t.Run("importer service returns 5xx error", func(t *testing.T) {
+		body, contentType := createImportMultipartBody("", "records.csv", "symptom,diagnosis,therapy\nfever,flu,rest\n")
+		req := httptest.NewRequest(http.MethodPost, "/medical-records/import", body)
+		req.Header.Set(echo.HeaderContentType, contentType)
+		user := createUserInformation()
+		req = req.WithContext(context.WithValue(context.Background(), middleware.ContextKeyUser, user))
+
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		exec := createMedicalRecordImporterExecutor(ctrl)
+		exec.usecase.EXPECT().Import(ctx.Request().Context(), user, gomock.Any(), false).Return(nil, entity.ErrInternalServer)
+		serve(ctx, exec.handler.Import)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-001","message":"Internal server error"}],"meta":null}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+
+	t.Run("successfully dry run csv import", func(t *testing.T) {
+		content := "diagnosis,symptom,therapy,result\nflu,fever,rest,healed\n\"broken,row\n"
+		body, contentType := createImportMultipartBody("", "records.csv", content)
+		req := httptest.NewRequest(http.MethodPost, "/medical-records/import?dry_run=true", body)
+		req.Header.Set(echo.HeaderContentType, contentType)
+		user := createUserInformation()
+		req = req.WithContext(context.WithValue(context.Background(), middleware.ContextKeyUser, user))
+
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		exec := createMedicalRecordImporterExecutor(ctrl)
+		exec.usecase.EXPECT().Import(ctx.Request().Context(), user, gomock.Any(), true).
+			DoAndReturn(func(_ context.Context, _ *entity.User, source usecase.MedicalRecordSource, dryRun bool) (*entity.ImportReport, *entity.Error) {
+				record, err := source.Next()
+				assert.Nil(t, err)
+				assert.Equal(t, &entity.MedicalRecord{Symptom: "fever", Diagnosis: "flu", Therapy: "rest", Result: "healed"}, record)
+
+				_, err = source.Next()
+				assert.NotNil(t, err)
+				assert.Equal(t, entity.ErrInvalidImportRow.Code, err.(*entity.Error).Code)
+
+				_, err = source.Next()
+				assert.Equal(t, io.EOF, err)
+
+				return &entity.ImportReport{
+					DryRun: dryRun,
+					Total:  2,
+					Valid:  1,
+					Errors: []*entity.ImportRowError{{Row: 2, Err: entity.ErrInvalidImportRow}},
+				}, nil
+			})
+		serve(ctx, exec.handler.Import)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"data":{"dry_run":true,"total":2,"valid":1,"imported":0,"errors":[{"row":2,"code":"02-008","message":"Import row can't be parsed"}],"omitted_errors":0},"meta":{}}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+
+	t.Run("successfully import ndjson", func(t *testing.T) {
+		content := "{\"symptom\":\"fever\",\"diagnosis\":\"flu\",\"therapy\":\"rest\"}\n\nnot-json\n"
+		body, contentType := createImportMultipartBody("ndjson", "records", content)
+		req := httptest.NewRequest(http.MethodPost, "/medical-records/import", body)
+		req.Header.Set(echo.HeaderContentType, contentType)
+		user := createUserInformation()
+		req = req.WithContext(context.WithValue(context.Background(), middleware.ContextKeyUser, user))
+
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		exec := createMedicalRecordImporterExecutor(ctrl)
+		exec.usecase.EXPECT().Import(ctx.Request().Context(), user, gomock.Any(), false).
+			DoAndReturn(func(_ context.Context, _ *entity.User, source usecase.MedicalRecordSource, dryRun bool) (*entity.ImportReport, *entity.Error) {
+				record, err := source.Next()
+				assert.Nil(t, err)
+				assert.Equal(t, &entity.MedicalRecord{Symptom: "fever", Diagnosis: "flu", Therapy: "rest"}, record)
+
+				_, err = source.Next()
+				assert.NotNil(t, err)
+				assert.Equal(t, entity.ErrInvalidImportRow.Code, err.(*entity.Error).Code)
+
+				_, err = source.Next()
+				assert.Equal(t, io.EOF, err)
+
+				assert.Nil(t, source.Rewind())
+				record, err = source.Next()
+				assert.Nil(t, err)
+				assert.Equal(t, &entity.MedicalRecord{Symptom: "fever", Diagnosis: "flu", Therapy: "rest"}, record)
+
+				return &entity.ImportReport{
+					DryRun:   dryRun,
+					Total:    2,
+					Valid:    1,
+					Imported: 1,
+					Errors:   []*entity.ImportRowError{{Row: 2, Err: entity.ErrInvalidImportRow}},
+				}, nil
+			})
+		serve(ctx, exec.handler.Import)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"data":{"dry_run":false,"total":2,"valid":1,"imported":1,"errors":[{"row":2,"code":"02-008","message":"Import row can't be parsed"}],"omitted_errors":0},"meta":{}}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+
+	t.Run("ndjson line longer than the default buffer is read", func(t *testing.T) {
+		symptom := strings.Repeat("a", 100*1024)
+		content := "{\"symptom\":\"" + symptom + "\",\"diagnosis\":\"flu\",\"therapy\":\"rest\"}\n"
+		body, contentType := createImportMultipartBody("ndjson", "records", content)
+		req := httptest.NewRequest(http.MethodPost, "/medical-records/import", body)
+		req.Header.Set(echo.HeaderContentType, contentType)
+		user := createUserInformation()
+		req = req.WithContext(context.WithValue(context.Background(), middleware.ContextKeyUser, user))
+
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		exec := createMedicalRecordImporterExecutor(ctrl)
+		exec.usecase.EXPECT().Import(ctx.Request().Context(), user, gomock.Any(), false).
+			DoAndReturn(func(_ context.Context, _ *entity.User, source usecase.MedicalRecordSource, dryRun bool) (*entity.ImportReport, *entity.Error) {
+				record, err := source.Next()
+				assert.Nil(t, err)
+				assert.Equal(t, symptom, record.Symptom)
+
+				_, err = source.Next()
+				assert.Equal(t, io.EOF, err)
+
+				return &entity.ImportReport{Total: 1, Valid: 1, Imported: 1}, nil
+			})
+		serve(ctx, exec.handler.Import)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+}
+
+func createImportMultipartBody(format, filename, content string) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if format != "" {
+		writer.WriteField("format", format)
+	}
+	part, _ := writer.CreateFormFile("file", filename)
+	part.Write([]byte(content))
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func createMedicalRecordImporterExecutor(ctrl *gomock.Controller) *MedicalRecordImporterExecutor {
+	u := mock_usecase.NewMockImportMedicalRecord(ctrl)
+	h := handler.NewMedicalRecordImporter(u, 1<<20)
+	return &MedicalRecordImporterExecutor{
+		handler: h,
+		usecase: u,
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// MIMEApplicationPDF is the media type of PDF document.
+const MIMEApplicationPDF = "application/pdf"
+
+// MedicalRecordRenderer defines the contract to render a medical record into a document.
+type MedicalRecordRenderer interface {
+	// Render renders the medical record.
+	Render(record *entity.MedicalRecord) ([]byte, error)
+}
+
+// MedicalRecordPrinter handles HTTP request and response
+// for printable medical record summary.
+type MedicalRecordPrinter struct {
+	finder   usecase.FindMedicalRecord
+	renderer MedicalRecordRenderer
+}
+
+// NewMedicalRecordPrinter creates an instance of MedicalRecordPrinter.
+func NewMedicalRecordPrinter(finder usecase.FindMedicalRecord, renderer MedicalRecordRenderer) *MedicalRecordPrinter {
+	return &MedicalRecordPrinter{
+		finder:   finder,
+		renderer: renderer,
+	}
+}
+
+// Print handles `GET /medical-records/:id.pdf` endpoint
+// or `GET /medical-records/:id` with header `Accept: application/pdf`.
+// It is authorized the same way as FindByID.
+func (mp *MedicalRecordPrinter) Print(ctx echo.Context) error {
+	str := ctx.Param("id")
+	id, herr := hashids.DecodeHash([]byte(str))
+	if herr != nil {
+		return entity.WrapError(entity.ErrInvalidID, herr.Error())
+	}
+
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	record, ferr := mp.finder.FindByID(ctx.Request().Context(), uint64(id), uint64(user.ID))
+	if ferr != nil {
+		return ferr
+	}
+
+	doc, rerr := mp.renderer.Render(record)
+	if rerr != nil {
+		return entity.WrapError(entity.ErrInternalServer, "[MedicalRecordPrinter-Print] render: "+rerr.Error())
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=\"medical-record-%s.pdf\"", str))
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+	return ctx.Blob(http.StatusOK, MIMEApplicationPDF, doc)
+}
@@ -0,0 +1,132 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/middleware"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type MedicalRecordPrinterExecutor struct {
+	handler  *handler.MedicalRecordPrinter
+	usecase  *mock_usecase.MockFindMedicalRecord
+	renderer *fakeMedicalRecordRenderer
+}
+
+type fakeMedicalRecordRenderer struct {
+	doc []byte
+	err error
+}
+
+func (f *fakeMedicalRecordRenderer) Render(record *entity.MedicalRecord) ([]byte, error) {
+	return f.doc, f.err
+}
+
+func TestNewMedicalRecordPrinter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of MedicalRecordPrinter", func(t *testing.T) {
+		exec := createMedicalRecordPrinterExecutor(ctrl)
+		assert.NotNil(t, exec.handler)
+	})
+}
+
+func TestMedicalRecordPrinter_Print(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("entity id is not hashids.ID", func(t *testing.T) {
+		ctx, rec := createMedicalRecordPrinterContext("1234", createUserInformation())
+
+		exec := createMedicalRecordPrinterExecutor(ctrl)
+		err := serve(ctx, exec.handler.Print)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		ctx, rec := createMedicalRecordPrinterContext("oWx0b8DZ1a", nil)
+
+		exec := createMedicalRecordPrinterExecutor(ctrl)
+		err := serve(ctx, exec.handler.Print)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("medical record is not owned by given email", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createMedicalRecordPrinterContext("oWx0b8DZ1a", user)
+
+		exec := createMedicalRecordPrinterExecutor(ctrl)
+		exec.usecase.EXPECT().FindByID(ctx.Request().Context(), uint64(1), uint64(user.ID)).Return(nil, entity.ErrForbidden)
+		err := serve(ctx, exec.handler.Print)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("renderer fails to render the medical record", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createMedicalRecordPrinterContext("oWx0b8DZ1a", user)
+
+		exec := createMedicalRecordPrinterExecutor(ctrl)
+		exec.renderer.err = errors.New("render error")
+		exec.usecase.EXPECT().FindByID(ctx.Request().Context(), uint64(1), uint64(user.ID)).Return(createMedicalRecords()[0], nil)
+		err := serve(ctx, exec.handler.Print)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("successfully print medical record owned by certain email", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createMedicalRecordPrinterContext("oWx0b8DZ1a", user)
+
+		exec := createMedicalRecordPrinterExecutor(ctrl)
+		exec.usecase.EXPECT().FindByID(ctx.Request().Context(), uint64(1), uint64(user.ID)).Return(createMedicalRecords()[0], nil)
+		err := serve(ctx, exec.handler.Print)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, handler.MIMEApplicationPDF, rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, `inline; filename="medical-record-oWx0b8DZ1a.pdf"`, rec.Header().Get(echo.HeaderContentDisposition))
+		assert.Equal(t, "%PDF-1.4", rec.Body.String())
+	})
+}
+
+func createMedicalRecordPrinterContext(id string, user *entity.User) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if user != nil {
+		req = req.WithContext(context.WithValue(context.Background(), middleware.ContextKeyUser, user))
+	}
+
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(req, rec)
+	ctx.SetPath("/medical-records/:id")
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id)
+	return ctx, rec
+}
+
+func createMedicalRecordPrinterExecutor(ctrl *gomock.Controller) *MedicalRecordPrinterExecutor {
+	u := mock_usecase.NewMockFindMedicalRecord(ctrl)
+	r := &fakeMedicalRecordRenderer{doc: []byte("%PDF-1.4")}
+	h := handler.NewMedicalRecordPrinter(u, r)
+	return &MedicalRecordPrinterExecutor{
+		handler:  h,
+		usecase:  u,
+		renderer: r,
+	}
+}
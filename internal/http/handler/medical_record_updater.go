@@ -36,33 +36,21 @@ func (mru *MedicalRecordUpdater) Update(ctx echo.Context) error {
 	str := ctx.Param("id")
 	id, herr := hashids.DecodeHash([]byte(str))
 	if herr != nil {
-		res := response.NewError(entity.ErrInvalidID)
-		ctx.JSON(http.StatusInternalServerError, res)
-		return herr
+		return entity.WrapError(entity.ErrInvalidID, herr.Error())
 	}
 
 	var request UpdateMedicalRecordRequest
 	if err := ctx.Bind(&request); err != nil {
-		res := response.NewError(entity.ErrInvalidMedicalRecordRequest)
-		ctx.JSON(http.StatusBadRequest, res)
-		return err
+		return entity.WrapError(entity.ErrInvalidMedicalRecordRequest, err.Error())
 	}
 
 	user, err := extractUserFromRequestContext(ctx.Request().Context())
 	if err != nil {
-		res := response.NewError(err)
-		ctx.JSON(http.StatusInternalServerError, res)
 		return err
 	}
 
 	record := createMedicalRecordFromUpdateRequest(&request, user)
-	if err := mru.updater.Update(ctx.Request().Context(), user.Email, uint64(id), record); err != nil {
-		res := response.NewError(err)
-		status := http.StatusNotFound
-		if err.Code == entity.ErrInternalServer.Code {
-			status = http.StatusInternalServerError
-		}
-		ctx.JSON(status, res)
+	if err := mru.updater.Update(ctx.Request().Context(), uint64(id), uint64(user.ID), record); err != nil {
 		return err
 	}
 
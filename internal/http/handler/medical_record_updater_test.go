@@ -48,9 +48,9 @@ func TestMedicalRecordUpdater_Update(t *testing.T) {
 		ctx.SetParamValues("1234")
 
 		exec := createMedicalRecordUpdaterExecutor(ctrl)
-		exec.handler.Update(ctx)
+		serve(ctx, exec.handler.Update)
 
-		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-004","message":"Entity ID is invalid"}],"meta":null}`)
 		assert.Equal(t, str, rec.Body.String())
 	})
@@ -68,7 +68,7 @@ func TestMedicalRecordUpdater_Update(t *testing.T) {
 		ctx.SetParamValues("oWx0b8DZ1a")
 
 		exec := createMedicalRecordUpdaterExecutor(ctrl)
-		exec.handler.Update(ctx)
+		serve(ctx, exec.handler.Update)
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"02-003","message":"Medical record request is invalid. Please, check the JSON request"}],"meta":null}`)
@@ -89,7 +89,7 @@ func TestMedicalRecordUpdater_Update(t *testing.T) {
 		ctx.SetParamValues("oWx0b8DZ1a")
 
 		exec := createMedicalRecordUpdaterExecutor(ctrl)
-		exec.handler.Update(ctx)
+		serve(ctx, exec.handler.Update)
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-001","message":"Internal server error"}],"meta":null}`)
@@ -112,8 +112,8 @@ func TestMedicalRecordUpdater_Update(t *testing.T) {
 		ctx.SetParamValues("oWx0b8DZ1a")
 
 		exec := createMedicalRecordUpdaterExecutor(ctrl)
-		exec.usecase.EXPECT().Update(ctx.Request().Context(), user.Email, uint64(1), createMedicalRecordFromUpdateRequest(mr, user)).Return(entity.ErrMedicalRecordNotFound)
-		exec.handler.Update(ctx)
+		exec.usecase.EXPECT().Update(ctx.Request().Context(), uint64(1), uint64(user.ID), createMedicalRecordFromUpdateRequest(mr, user)).Return(entity.ErrMedicalRecordNotFound)
+		serve(ctx, exec.handler.Update)
 
 		assert.Equal(t, http.StatusNotFound, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"02-005","message":"Medical record not found"}],"meta":null}`)
@@ -136,8 +136,8 @@ func TestMedicalRecordUpdater_Update(t *testing.T) {
 		ctx.SetParamValues("oWx0b8DZ1a")
 
 		exec := createMedicalRecordUpdaterExecutor(ctrl)
-		exec.usecase.EXPECT().Update(ctx.Request().Context(), user.Email, uint64(1), createMedicalRecordFromUpdateRequest(mr, user)).Return(entity.ErrInternalServer)
-		exec.handler.Update(ctx)
+		exec.usecase.EXPECT().Update(ctx.Request().Context(), uint64(1), uint64(user.ID), createMedicalRecordFromUpdateRequest(mr, user)).Return(entity.ErrInternalServer)
+		serve(ctx, exec.handler.Update)
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-001","message":"Internal server error"}],"meta":null}`)
@@ -160,8 +160,8 @@ func TestMedicalRecordUpdater_Update(t *testing.T) {
 		ctx.SetParamValues("oWx0b8DZ1a")
 
 		exec := createMedicalRecordUpdaterExecutor(ctrl)
-		exec.usecase.EXPECT().Update(ctx.Request().Context(), user.Email, uint64(1), createMedicalRecordFromUpdateRequest(mr, user)).Return(nil)
-		exec.handler.Update(ctx)
+		exec.usecase.EXPECT().Update(ctx.Request().Context(), uint64(1), uint64(user.ID), createMedicalRecordFromUpdateRequest(mr, user)).Return(nil)
+		serve(ctx, exec.handler.Update)
 
 		assert.Equal(t, http.StatusOK, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"data":null,"meta":{}}`)
@@ -3,7 +3,6 @@ package handler
 import (
 	"net/http"
 
-	"github.com/indrasaputra/orvosi-api/entity"
 	"github.com/indrasaputra/orvosi-api/internal/http/response"
 	"github.com/indrasaputra/orvosi-api/usecase"
 	"github.com/labstack/echo/v4"
@@ -23,24 +22,18 @@ func NewSigner(signin usecase.SignIn) *Signer {
 }
 
 // SignIn handles `POST /sign-in` endpoint.
+// It responds with the signed-in user, including the profile.
 func (s *Signer) SignIn(ctx echo.Context) error {
 	user, err := extractUserFromRequestContext(ctx.Request().Context())
 	if err != nil {
-		res := response.NewError(err)
-		ctx.JSON(http.StatusInternalServerError, res)
 		return err
 	}
 
-	if err := s.signin.SignIn(ctx.Request().Context(), user); err != nil {
-		res := response.NewError(err)
-		status := http.StatusInternalServerError
-		if err.Code == entity.ErrEmptyUser.Code {
-			status = http.StatusBadRequest
-		}
-		ctx.JSON(status, res)
-		return err
+	stored, serr := s.signin.SignIn(ctx.Request().Context(), user)
+	if serr != nil {
+		return serr
 	}
 
-	ctx.JSON(http.StatusCreated, response.NewSuccess(nil, response.EmptyMeta{}))
+	ctx.JSON(http.StatusCreated, response.NewSuccess(createUserResponse(stored), response.EmptyMeta{}))
 	return nil
 }
@@ -43,7 +43,7 @@ func TestSigner_SignIn(t *testing.T) {
 		ctx := e.NewContext(req, rec)
 
 		exec := createSignerExecutor(ctrl)
-		exec.handler.SignIn(ctx)
+		serve(ctx, exec.handler.SignIn)
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-001","message":"Internal server error"}],"meta":null}`)
@@ -60,8 +60,8 @@ func TestSigner_SignIn(t *testing.T) {
 		ctx := e.NewContext(req, rec)
 
 		exec := createSignerExecutor(ctrl)
-		exec.usecase.EXPECT().SignIn(ctx.Request().Context(), user).Return(entity.ErrEmptyUser)
-		exec.handler.SignIn(ctx)
+		exec.usecase.EXPECT().SignIn(ctx.Request().Context(), user).Return(nil, entity.ErrEmptyUser)
+		serve(ctx, exec.handler.SignIn)
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"03-001","message":"User is empty"}],"meta":null}`)
@@ -78,8 +78,8 @@ func TestSigner_SignIn(t *testing.T) {
 		ctx := e.NewContext(req, rec)
 
 		exec := createSignerExecutor(ctrl)
-		exec.usecase.EXPECT().SignIn(ctx.Request().Context(), user).Return(entity.ErrInternalServer)
-		exec.handler.SignIn(ctx)
+		exec.usecase.EXPECT().SignIn(ctx.Request().Context(), user).Return(nil, entity.ErrInternalServer)
+		serve(ctx, exec.handler.SignIn)
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"01-001","message":"Internal server error"}],"meta":null}`)
@@ -96,11 +96,15 @@ func TestSigner_SignIn(t *testing.T) {
 		ctx := e.NewContext(req, rec)
 
 		exec := createSignerExecutor(ctrl)
-		exec.usecase.EXPECT().SignIn(ctx.Request().Context(), user).Return(nil)
-		exec.handler.SignIn(ctx)
+		stored := *user
+		stored.Profile.Specialty = "Immunology"
+		exec.usecase.EXPECT().SignIn(ctx.Request().Context(), user).Return(&stored, nil)
+		serve(ctx, exec.handler.SignIn)
 
 		assert.Equal(t, http.StatusCreated, rec.Code)
-		str := fmt.Sprintf("%s\n", `{"data":null,"meta":{}}`)
+		str := fmt.Sprintf("%s\n", `{"data":{"id":"oWx0b8DZ1a","email":"user@email.com","name":"Edward Jenner","google_id":"12345678901234567890",`+
+			`"display_name":"","specialty":"Immunology","license_number":"","timezone":"","preferred_language":"",`+
+			`"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"},"meta":{}}`)
 		assert.Equal(t, str, rec.Body.String())
 	})
 }
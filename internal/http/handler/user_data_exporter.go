@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// MIMEApplicationZip is the media type of ZIP archive.
+const MIMEApplicationZip = "application/zip"
+
+// UserDataResponse defines the content of `data.json` in the archive of user data.
+type UserDataResponse struct {
+	ExportedAt     time.Time                `json:"exported_at"`
+	User           *UserResponse            `json:"user"`
+	MedicalRecords []*MedicalRecordResponse `json:"medical_records"`
+	Attachments    []*AttachmentResponse    `json:"attachments"`
+	Erasure        *ErasureResponse         `json:"erasure"`
+	Identities     []*IdentityResponse      `json:"identities"`
+	EmailChange    *EmailChangeResponse     `json:"email_change"`
+}
+
+// UserDataExporter handles HTTP request and response
+// for user data export.
+type UserDataExporter struct {
+	exporter usecase.ExportUserData
+	finder   usecase.FindAttachment
+}
+
+// NewUserDataExporter creates an instance of UserDataExporter.
+func NewUserDataExporter(exporter usecase.ExportUserData, finder usecase.FindAttachment) *UserDataExporter {
+	return &UserDataExporter{
+		exporter: exporter,
+		finder:   finder,
+	}
+}
+
+// Export handles `GET /me/data` endpoint.
+// It responds with a ZIP archive containing `data.json`, which holds everything stored about the user,
+// and the content of each attachment as `attachments/<attachment id>/<filename>`.
+// The archive is streamed one attachment at a time, so the status is sent before the attachments are read.
+// If an attachment fails to be read, the archive is cut short without its central directory,
+// which makes it unreadable instead of silently incomplete.
+func (ue *UserDataExporter) Export(ctx echo.Context) error {
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	data, err := ue.exporter.Export(ctx.Request().Context(), user.Email)
+	if err != nil {
+		return err
+	}
+
+	doc, merr := json.MarshalIndent(NewUserDataResponse(data, time.Now().UTC()), "", "  ")
+	if merr != nil {
+		return entity.WrapError(entity.ErrInternalServer, "[UserDataExporter-Export] marshal: "+merr.Error())
+	}
+	names, nerr := attachmentNames(data.Attachments)
+	if nerr != nil {
+		return nerr
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentType, MIMEApplicationZip)
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="orvosi-data.zip"`)
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+	ctx.Response().WriteHeader(http.StatusOK)
+
+	zw := zip.NewWriter(ctx.Response())
+	if err := writeZipFile(zw, "data.json", doc); err != nil {
+		return err
+	}
+	for i, attachment := range data.Attachments {
+		_, content, ferr := ue.finder.Download(ctx.Request().Context(), uint64(user.ID), uint64(attachment.MedicalRecordID), uint64(attachment.ID))
+		if ferr != nil {
+			return ferr
+		}
+		if err := writeZipFile(zw, names[i], content); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return entity.WrapError(entity.ErrInternalServer, "[UserDataExporter-Export] close: "+err.Error())
+	}
+	return nil
+}
+
+// attachmentNames returns the name of each attachment in the archive.
+// They are decided before the response is sent, so a failure can still be responded with an error.
+func attachmentNames(attachments []*entity.Attachment) ([]string, *entity.Error) {
+	names := make([]string, len(attachments))
+	for i, attachment := range attachments {
+		hash, err := hashids.EncodeID(attachment.ID)
+		if err != nil {
+			return nil, entity.WrapError(entity.ErrInternalServer, "[UserDataExporter-Export] encode: "+err.Error())
+		}
+		names[i] = fmt.Sprintf("attachments/%s/%s", hash, path.Base("/"+attachment.Filename))
+	}
+	return names, nil
+}
+
+func writeZipFile(zw *zip.Writer, name string, content []byte) *entity.Error {
+	w, err := zw.Create(name)
+	if err == nil {
+		_, err = w.Write(content)
+	}
+	if err != nil {
+		return entity.WrapError(entity.ErrInternalServer, "[UserDataExporter-Export] write "+name+": "+err.Error())
+	}
+	return nil
+}
+
+// NewUserDataResponse creates the content of `data.json` from the data of a user.
+// It is shared with the export of orvosi-admin, so both exports look the same.
+func NewUserDataResponse(data *entity.UserData, exportedAt time.Time) *UserDataResponse {
+	res := &UserDataResponse{
+		ExportedAt:     exportedAt,
+		User:           createUserResponse(data.User),
+		MedicalRecords: createMedicalRecordResponses(data.MedicalRecords),
+		Attachments:    createAttachmentResponses(data.Attachments),
+		Identities:     make([]*IdentityResponse, len(data.Identities)),
+	}
+	if data.Erasure != nil {
+		res.Erasure = createErasureResponse(data.Erasure)
+	}
+	for i, identity := range data.Identities {
+		res.Identities[i] = createIdentityResponse(identity)
+	}
+	if data.EmailChange != nil {
+		res.EmailChange = createEmailChangeResponse(data.EmailChange)
+	}
+	return res
+}
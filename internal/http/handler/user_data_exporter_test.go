@@ -0,0 +1,131 @@
+package handler_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type UserDataExporterExecutor struct {
+	handler  *handler.UserDataExporter
+	exporter *mock_usecase.MockExportUserData
+	finder   *mock_usecase.MockFindAttachment
+}
+
+func TestNewUserDataExporter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of UserDataExporter", func(t *testing.T) {
+		exec := createUserDataExporterExecutor(ctrl)
+		assert.NotNil(t, exec.handler)
+	})
+}
+
+func TestUserDataExporter_Export(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", nil)
+
+		exec := createUserDataExporterExecutor(ctrl)
+		serve(ctx, exec.handler.Export)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("user is not found", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", user)
+
+		exec := createUserDataExporterExecutor(ctrl)
+		exec.exporter.EXPECT().Export(ctx.Request().Context(), user.Email).Return(nil, entity.ErrUserNotFound)
+		serve(ctx, exec.handler.Export)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("attachment can't be downloaded after the archive is sent", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", user)
+
+		exec := createUserDataExporterExecutor(ctrl)
+		exec.exporter.EXPECT().Export(ctx.Request().Context(), user.Email).Return(createUserData(user), nil)
+		exec.finder.EXPECT().Download(ctx.Request().Context(), uint64(user.ID), uint64(2), uint64(3)).Return(nil, nil, entity.ErrInternalServer)
+		serve(ctx, exec.handler.Export)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		_, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+		assert.NotNil(t, err, "the archive must be unreadable")
+	})
+
+	t.Run("successfully export user data", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", user)
+		data := createUserData(user)
+
+		exec := createUserDataExporterExecutor(ctrl)
+		exec.exporter.EXPECT().Export(ctx.Request().Context(), user.Email).Return(data, nil)
+		exec.finder.EXPECT().Download(ctx.Request().Context(), uint64(user.ID), uint64(2), uint64(3)).Return(data.Attachments[0], []byte("lab result"), nil)
+		serve(ctx, exec.handler.Export)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, handler.MIMEApplicationZip, rec.Header().Get("Content-Type"))
+
+		zr, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+		assert.Nil(t, err)
+		files := map[string][]byte{}
+		for _, file := range zr.File {
+			r, err := file.Open()
+			assert.Nil(t, err)
+			content, _ := io.ReadAll(r)
+			files[file.Name] = content
+		}
+
+		var res handler.UserDataResponse
+		assert.Nil(t, json.Unmarshal(files["data.json"], &res))
+		assert.Equal(t, user.Email, res.User.Email)
+		assert.Len(t, res.MedicalRecords, 1)
+		assert.Len(t, res.Attachments, 1)
+		assert.NotNil(t, res.Erasure)
+		if assert.Len(t, res.Identities, 1) {
+			assert.Equal(t, "google-id", res.Identities[0].Subject)
+		}
+		assert.Equal(t, "b@orvosi.com", res.EmailChange.Email)
+		hash, _ := hashids.EncodeID(3)
+		assert.Equal(t, []byte("lab result"), files["attachments/"+string(hash)+"/lab.pdf"])
+	})
+}
+
+func createUserData(user *entity.User) *entity.UserData {
+	return &entity.UserData{
+		User:           user,
+		MedicalRecords: []*entity.MedicalRecord{{ID: 2, Symptom: "symptom"}},
+		Attachments:    []*entity.Attachment{{ID: 3, MedicalRecordID: 2, Filename: "../lab.pdf"}},
+		Erasure:        createErasure(),
+		Identities:     []*entity.Identity{{ID: 4, UserID: user.ID, Provider: entity.ProviderGoogle, Subject: "google-id"}},
+		EmailChange:    &entity.EmailChange{UserID: user.ID, Email: "b@orvosi.com"},
+	}
+}
+
+func createUserDataExporterExecutor(ctrl *gomock.Controller) *UserDataExporterExecutor {
+	e := mock_usecase.NewMockExportUserData(ctrl)
+	f := mock_usecase.NewMockFindAttachment(ctrl)
+	h := handler.NewUserDataExporter(e, f)
+	return &UserDataExporterExecutor{
+		handler:  h,
+		exporter: e,
+		finder:   f,
+	}
+}
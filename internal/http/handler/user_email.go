@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/response"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// EmailChangeRequest represents email change request.
+type EmailChangeRequest struct {
+	Email string `json:"email"`
+}
+
+// VerifyEmailRequest represents the request to verify the new email.
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// EmailChangeResponse defines the JSON response of a requested email change.
+// The token is never responded, since it must be read from the new email.
+type EmailChangeResponse struct {
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UserEmail handles HTTP request and response
+// for the email change of the signed-in user.
+type UserEmail struct {
+	changer usecase.ChangeEmail
+}
+
+// NewUserEmail creates an instance of UserEmail.
+func NewUserEmail(changer usecase.ChangeEmail) *UserEmail {
+	return &UserEmail{
+		changer: changer,
+	}
+}
+
+// Request handles `POST /me/email` endpoint.
+// The email is changed only after the token sent to the new email is verified, so it responds with 202 Accepted.
+func (ue *UserEmail) Request(ctx echo.Context) error {
+	var request EmailChangeRequest
+	if err := ctx.Bind(&request); err != nil {
+		return entity.WrapError(entity.ErrInvalidEmailChangeRequest, err.Error())
+	}
+
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	change, err := ue.changer.Request(ctx.Request().Context(), user, request.Email)
+	if err != nil {
+		return err
+	}
+
+	ctx.JSON(http.StatusAccepted, response.NewSuccess(createEmailChangeResponse(change), response.EmptyMeta{}))
+	return nil
+}
+
+// Verify handles `POST /me/email/verify` endpoint.
+// It responds with the user having the new email.
+func (ue *UserEmail) Verify(ctx echo.Context) error {
+	var request VerifyEmailRequest
+	if err := ctx.Bind(&request); err != nil {
+		return entity.WrapError(entity.ErrInvalidEmailChangeRequest, err.Error())
+	}
+
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	changed, err := ue.changer.Verify(ctx.Request().Context(), user, request.Token)
+	if err != nil {
+		return err
+	}
+
+	ctx.JSON(http.StatusOK, response.NewSuccess(createUserResponse(changed), response.EmptyMeta{}))
+	return nil
+}
+
+func createEmailChangeResponse(change *entity.EmailChange) *EmailChangeResponse {
+	return &EmailChangeResponse{
+		Email:     change.Email,
+		ExpiresAt: change.ExpiresAt,
+	}
+}
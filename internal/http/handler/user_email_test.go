@@ -0,0 +1,144 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type UserEmailExecutor struct {
+	handler *handler.UserEmail
+	usecase *mock_usecase.MockChangeEmail
+}
+
+func TestNewUserEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of UserEmail", func(t *testing.T) {
+		exec := createUserEmailExecutor(ctrl)
+		assert.NotNil(t, exec.handler)
+	})
+}
+
+func TestUserEmail_Request(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("request body is invalid", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodPost, strings.NewReader(`{"email": 1}`), echo.MIMEApplicationJSON, user)
+
+		exec := createUserEmailExecutor(ctrl)
+		serve(ctx, exec.handler.Request)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodPost, strings.NewReader(`{"email": "new@email.com"}`), echo.MIMEApplicationJSON, nil)
+
+		exec := createUserEmailExecutor(ctrl)
+		serve(ctx, exec.handler.Request)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("email is already used", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodPost, strings.NewReader(`{"email": "new@email.com"}`), echo.MIMEApplicationJSON, user)
+
+		exec := createUserEmailExecutor(ctrl)
+		exec.usecase.EXPECT().Request(ctx.Request().Context(), user, "new@email.com").Return(nil, entity.ErrEmailAlreadyUsed)
+		serve(ctx, exec.handler.Request)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("successfully request email change", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodPost, strings.NewReader(`{"email": "new@email.com"}`), echo.MIMEApplicationJSON, user)
+		change := &entity.EmailChange{
+			UserID:    user.ID,
+			Email:     "new@email.com",
+			TokenHash: "hash",
+			ExpiresAt: time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC),
+		}
+
+		exec := createUserEmailExecutor(ctrl)
+		exec.usecase.EXPECT().Request(ctx.Request().Context(), user, "new@email.com").Return(change, nil)
+		serve(ctx, exec.handler.Request)
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"data":{"email":"new@email.com","expires_at":"2026-01-02T00:00:00Z"},"meta":{}}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+}
+
+func TestUserEmail_Verify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("request body is invalid", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodPost, strings.NewReader(`{"token": 1}`), echo.MIMEApplicationJSON, user)
+
+		exec := createUserEmailExecutor(ctrl)
+		serve(ctx, exec.handler.Verify)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodPost, strings.NewReader(`{"token": "token"}`), echo.MIMEApplicationJSON, nil)
+
+		exec := createUserEmailExecutor(ctrl)
+		serve(ctx, exec.handler.Verify)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("token is invalid", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodPost, strings.NewReader(`{"token": "token"}`), echo.MIMEApplicationJSON, user)
+
+		exec := createUserEmailExecutor(ctrl)
+		exec.usecase.EXPECT().Verify(ctx.Request().Context(), user, "token").Return(nil, entity.ErrInvalidEmailChangeToken)
+		serve(ctx, exec.handler.Verify)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("successfully verify new email", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodPost, strings.NewReader(`{"token": "token"}`), echo.MIMEApplicationJSON, user)
+		changed := *user
+		changed.Email = "new@email.com"
+
+		exec := createUserEmailExecutor(ctrl)
+		exec.usecase.EXPECT().Verify(ctx.Request().Context(), user, "token").Return(&changed, nil)
+		serve(ctx, exec.handler.Verify)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		res := decodeUserResponse(t, rec.Body.Bytes())
+		assert.Equal(t, "new@email.com", res.Email)
+	})
+}
+
+func createUserEmailExecutor(ctrl *gomock.Controller) *UserEmailExecutor {
+	u := mock_usecase.NewMockChangeEmail(ctrl)
+	h := handler.NewUserEmail(u)
+	return &UserEmailExecutor{
+		handler: h,
+		usecase: u,
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/response"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// ErasureResponse defines the JSON response of a scheduled erasure.
+type ErasureResponse struct {
+	RequestedAt time.Time `json:"requested_at"`
+	EraseAfter  time.Time `json:"erase_after"`
+}
+
+// UserEraser handles HTTP request and response
+// for user erasure.
+type UserEraser struct {
+	eraser usecase.EraseUser
+}
+
+// NewUserEraser creates an instance of UserEraser.
+func NewUserEraser(eraser usecase.EraseUser) *UserEraser {
+	return &UserEraser{
+		eraser: eraser,
+	}
+}
+
+// Schedule handles `DELETE /me` endpoint.
+// The data is erased after the grace period, so it responds with 202 Accepted.
+func (ue *UserEraser) Schedule(ctx echo.Context) error {
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	erasure, err := ue.eraser.Schedule(ctx.Request().Context(), uint64(user.ID))
+	if err != nil {
+		return err
+	}
+
+	ctx.JSON(http.StatusAccepted, response.NewSuccess(createErasureResponse(erasure), response.EmptyMeta{}))
+	return nil
+}
+
+// Find handles `GET /me/erasure` endpoint.
+func (ue *UserEraser) Find(ctx echo.Context) error {
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	erasure, err := ue.eraser.Find(ctx.Request().Context(), uint64(user.ID))
+	if err != nil {
+		return err
+	}
+
+	ctx.JSON(http.StatusOK, response.NewSuccess(createErasureResponse(erasure), response.EmptyMeta{}))
+	return nil
+}
+
+// Cancel handles `DELETE /me/erasure` endpoint.
+func (ue *UserEraser) Cancel(ctx echo.Context) error {
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	if err := ue.eraser.Cancel(ctx.Request().Context(), uint64(user.ID)); err != nil {
+		return err
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func createErasureResponse(erasure *entity.Erasure) *ErasureResponse {
+	return &ErasureResponse{
+		RequestedAt: erasure.RequestedAt,
+		EraseAfter:  erasure.EraseAfter,
+	}
+}
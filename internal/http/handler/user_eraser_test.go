@@ -0,0 +1,161 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type UserEraserExecutor struct {
+	handler *handler.UserEraser
+	usecase *mock_usecase.MockEraseUser
+}
+
+func TestNewUserEraser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of UserEraser", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+		assert.NotNil(t, exec.handler)
+	})
+}
+
+func TestUserEraser_Schedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodDelete, nil, "", nil)
+
+		exec := createUserEraserExecutor(ctrl)
+		serve(ctx, exec.handler.Schedule)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("user is not found", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodDelete, nil, "", user)
+
+		exec := createUserEraserExecutor(ctrl)
+		exec.usecase.EXPECT().Schedule(ctx.Request().Context(), uint64(user.ID)).Return(nil, entity.ErrUserNotFound)
+		serve(ctx, exec.handler.Schedule)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("successfully schedule erasure", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodDelete, nil, "", user)
+		erasure := createErasure()
+
+		exec := createUserEraserExecutor(ctrl)
+		exec.usecase.EXPECT().Schedule(ctx.Request().Context(), uint64(user.ID)).Return(erasure, nil)
+		serve(ctx, exec.handler.Schedule)
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"data":{"requested_at":"2026-01-01T00:00:00Z","erase_after":"2026-01-31T00:00:00Z"},"meta":{}}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+}
+
+func TestUserEraser_Find(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", nil)
+
+		exec := createUserEraserExecutor(ctrl)
+		serve(ctx, exec.handler.Find)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("erasure is not scheduled", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", user)
+
+		exec := createUserEraserExecutor(ctrl)
+		exec.usecase.EXPECT().Find(ctx.Request().Context(), uint64(user.ID)).Return(nil, entity.ErrErasureNotScheduled)
+		serve(ctx, exec.handler.Find)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"errors":[{"code":"03-003","message":"Erasure is not scheduled"}],"meta":null}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+
+	t.Run("successfully find erasure", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", user)
+
+		exec := createUserEraserExecutor(ctrl)
+		exec.usecase.EXPECT().Find(ctx.Request().Context(), uint64(user.ID)).Return(createErasure(), nil)
+		serve(ctx, exec.handler.Find)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestUserEraser_Cancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodDelete, nil, "", nil)
+
+		exec := createUserEraserExecutor(ctrl)
+		serve(ctx, exec.handler.Cancel)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("erasure is not scheduled", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodDelete, nil, "", user)
+
+		exec := createUserEraserExecutor(ctrl)
+		exec.usecase.EXPECT().Cancel(ctx.Request().Context(), uint64(user.ID)).Return(entity.ErrErasureNotScheduled)
+		serve(ctx, exec.handler.Cancel)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("successfully cancel erasure", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodDelete, nil, "", user)
+
+		exec := createUserEraserExecutor(ctrl)
+		exec.usecase.EXPECT().Cancel(ctx.Request().Context(), uint64(user.ID)).Return(nil)
+		serve(ctx, exec.handler.Cancel)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+}
+
+func createErasure() *entity.Erasure {
+	requestedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &entity.Erasure{
+		UserID:      1,
+		Email:       "user@email.com",
+		RequestedAt: requestedAt,
+		EraseAfter:  requestedAt.Add(30 * 24 * time.Hour),
+	}
+}
+
+func createUserEraserExecutor(ctrl *gomock.Controller) *UserEraserExecutor {
+	u := mock_usecase.NewMockEraseUser(ctrl)
+	h := handler.NewUserEraser(u)
+	return &UserEraserExecutor{
+		handler: h,
+		usecase: u,
+	}
+}
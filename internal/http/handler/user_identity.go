@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/response"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// LinkIdentityRequest represents identity linking request.
+// The ID token proves that the user owns the identity.
+type LinkIdentityRequest struct {
+	IDToken string `json:"id_token"`
+}
+
+// IdentityResponse defines the JSON response of an identity.
+type IdentityResponse struct {
+	ID        hashids.ID `json:"id"`
+	Provider  string     `json:"provider"`
+	Subject   string     `json:"subject"`
+	Email     string     `json:"email"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// UserIdentity handles HTTP request and response
+// for the identities of the signed-in user.
+type UserIdentity struct {
+	linker usecase.LinkIdentity
+}
+
+// NewUserIdentity creates an instance of UserIdentity.
+func NewUserIdentity(linker usecase.LinkIdentity) *UserIdentity {
+	return &UserIdentity{
+		linker: linker,
+	}
+}
+
+// FindAll handles `GET /me/identities` endpoint.
+func (ui *UserIdentity) FindAll(ctx echo.Context) error {
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	identities, err := ui.linker.FindAll(ctx.Request().Context(), uint64(user.ID))
+	if err != nil {
+		return err
+	}
+
+	res := make([]*IdentityResponse, len(identities))
+	for i, identity := range identities {
+		res[i] = createIdentityResponse(identity)
+	}
+	ctx.JSON(http.StatusOK, response.NewSuccess(res, response.EmptyMeta{}))
+	return nil
+}
+
+// Link handles `POST /me/identities` endpoint.
+func (ui *UserIdentity) Link(ctx echo.Context) error {
+	var request LinkIdentityRequest
+	if err := ctx.Bind(&request); err != nil {
+		return entity.WrapError(entity.ErrInvalidIdentityRequest, err.Error())
+	}
+
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	identity, err := ui.linker.Link(ctx.Request().Context(), uint64(user.ID), request.IDToken)
+	if err != nil {
+		return err
+	}
+
+	ctx.JSON(http.StatusCreated, response.NewSuccess(createIdentityResponse(identity), response.EmptyMeta{}))
+	return nil
+}
+
+// Unlink handles `DELETE /me/identities/:id` endpoint.
+func (ui *UserIdentity) Unlink(ctx echo.Context) error {
+	id, herr := hashids.DecodeHash([]byte(ctx.Param("id")))
+	if herr != nil {
+		return entity.ErrInvalidID
+	}
+
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	if err := ui.linker.Unlink(ctx.Request().Context(), uint64(user.ID), uint64(id)); err != nil {
+		return err
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func createIdentityResponse(identity *entity.Identity) *IdentityResponse {
+	return &IdentityResponse{
+		ID:        identity.ID,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+}
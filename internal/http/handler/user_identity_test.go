@@ -0,0 +1,182 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type UserIdentityExecutor struct {
+	handler *handler.UserIdentity
+	usecase *mock_usecase.MockLinkIdentity
+}
+
+func TestNewUserIdentity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of UserIdentity", func(t *testing.T) {
+		exec := createUserIdentityExecutor(ctrl)
+		assert.NotNil(t, exec.handler)
+	})
+}
+
+func TestUserIdentity_FindAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", nil)
+
+		exec := createUserIdentityExecutor(ctrl)
+		serve(ctx, exec.handler.FindAll)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("usecase returns error", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", user)
+
+		exec := createUserIdentityExecutor(ctrl)
+		exec.usecase.EXPECT().FindAll(ctx.Request().Context(), uint64(user.ID)).Return(nil, entity.ErrInternalServer)
+		serve(ctx, exec.handler.FindAll)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("successfully find all identities", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", user)
+
+		exec := createUserIdentityExecutor(ctrl)
+		exec.usecase.EXPECT().FindAll(ctx.Request().Context(), uint64(user.ID)).Return([]*entity.Identity{createIdentity(user)}, nil)
+		serve(ctx, exec.handler.FindAll)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		str := fmt.Sprintf("%s\n", `{"data":[{"id":"oWx0b8DZ1a","provider":"google","subject":"12345678901234567890","email":"user@email.com","created_at":"2026-01-01T00:00:00Z"}],"meta":{}}`)
+		assert.Equal(t, str, rec.Body.String())
+	})
+}
+
+func TestUserIdentity_Link(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("request body is invalid", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodPost, strings.NewReader(`{"id_token": 1}`), echo.MIMEApplicationJSON, user)
+
+		exec := createUserIdentityExecutor(ctrl)
+		serve(ctx, exec.handler.Link)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodPost, strings.NewReader(`{"id_token": "token"}`), echo.MIMEApplicationJSON, nil)
+
+		exec := createUserIdentityExecutor(ctrl)
+		serve(ctx, exec.handler.Link)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("identity is linked to another user", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodPost, strings.NewReader(`{"id_token": "token"}`), echo.MIMEApplicationJSON, user)
+
+		exec := createUserIdentityExecutor(ctrl)
+		exec.usecase.EXPECT().Link(ctx.Request().Context(), uint64(user.ID), "token").Return(nil, entity.ErrIdentityAlreadyLinked)
+		serve(ctx, exec.handler.Link)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("successfully link identity", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodPost, strings.NewReader(`{"id_token": "token"}`), echo.MIMEApplicationJSON, user)
+
+		exec := createUserIdentityExecutor(ctrl)
+		exec.usecase.EXPECT().Link(ctx.Request().Context(), uint64(user.ID), "token").Return(createIdentity(user), nil)
+		serve(ctx, exec.handler.Link)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+}
+
+func TestUserIdentity_Unlink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("id is invalid", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodDelete, nil, "", user, "invalid")
+
+		exec := createUserIdentityExecutor(ctrl)
+		serve(ctx, exec.handler.Unlink)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodDelete, nil, "", nil, "oWx0b8DZ1a")
+
+		exec := createUserIdentityExecutor(ctrl)
+		serve(ctx, exec.handler.Unlink)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("identity is the last one", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodDelete, nil, "", user, "oWx0b8DZ1a")
+
+		exec := createUserIdentityExecutor(ctrl)
+		exec.usecase.EXPECT().Unlink(ctx.Request().Context(), uint64(user.ID), uint64(1)).Return(entity.ErrLastIdentity)
+		serve(ctx, exec.handler.Unlink)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("successfully unlink identity", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodDelete, nil, "", user, "oWx0b8DZ1a")
+
+		exec := createUserIdentityExecutor(ctrl)
+		exec.usecase.EXPECT().Unlink(ctx.Request().Context(), uint64(user.ID), uint64(1)).Return(nil)
+		serve(ctx, exec.handler.Unlink)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+}
+
+func createIdentity(user *entity.User) *entity.Identity {
+	return &entity.Identity{
+		ID:        hashids.ID(1),
+		UserID:    user.ID,
+		Provider:  entity.ProviderGoogle,
+		Subject:   user.GoogleID,
+		Email:     user.Email,
+		CreatedAt: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func createUserIdentityExecutor(ctrl *gomock.Controller) *UserIdentityExecutor {
+	u := mock_usecase.NewMockLinkIdentity(ctrl)
+	h := handler.NewUserIdentity(u)
+	return &UserIdentityExecutor{
+		handler: h,
+		usecase: u,
+	}
+}
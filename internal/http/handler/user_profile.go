@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/response"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// UserResponse defines the JSON response of a user, including the profile.
+type UserResponse struct {
+	ID                hashids.ID `json:"id"`
+	Email             string     `json:"email"`
+	Name              string     `json:"name"`
+	GoogleID          string     `json:"google_id"`
+	DisplayName       string     `json:"display_name"`
+	Specialty         string     `json:"specialty"`
+	LicenseNumber     string     `json:"license_number"`
+	Timezone          string     `json:"timezone"`
+	PreferredLanguage string     `json:"preferred_language"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// UpdateProfileRequest represents profile update request.
+// A field which is not sent is kept as is, while an empty one is cleared.
+type UpdateProfileRequest struct {
+	DisplayName       *string `json:"display_name"`
+	Specialty         *string `json:"specialty"`
+	LicenseNumber     *string `json:"license_number"`
+	Timezone          *string `json:"timezone"`
+	PreferredLanguage *string `json:"preferred_language"`
+}
+
+// UserProfile handles HTTP request and response
+// for the profile of the signed-in user.
+type UserProfile struct {
+	finder  usecase.FindUser
+	updater usecase.UpdateProfile
+}
+
+// NewUserProfile creates an instance of UserProfile.
+func NewUserProfile(finder usecase.FindUser, updater usecase.UpdateProfile) *UserProfile {
+	return &UserProfile{
+		finder:  finder,
+		updater: updater,
+	}
+}
+
+// Find handles `GET /me` endpoint.
+func (up *UserProfile) Find(ctx echo.Context) error {
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	stored, err := up.finder.FindByEmail(ctx.Request().Context(), user.Email)
+	if err != nil {
+		return err
+	}
+
+	ctx.JSON(http.StatusOK, response.NewSuccess(createUserResponse(stored), response.EmptyMeta{}))
+	return nil
+}
+
+// Update handles `PATCH /me` endpoint.
+func (up *UserProfile) Update(ctx echo.Context) error {
+	var request UpdateProfileRequest
+	if err := ctx.Bind(&request); err != nil {
+		return entity.WrapError(entity.ErrInvalidProfileRequest, err.Error())
+	}
+
+	user, cerr := extractUserFromRequestContext(ctx.Request().Context())
+	if cerr != nil {
+		return cerr
+	}
+
+	stored, err := up.updater.Update(ctx.Request().Context(), user.Email, createProfileUpdateFromRequest(&request))
+	if err != nil {
+		return err
+	}
+
+	ctx.JSON(http.StatusOK, response.NewSuccess(createUserResponse(stored), response.EmptyMeta{}))
+	return nil
+}
+
+func createProfileUpdateFromRequest(req *UpdateProfileRequest) *entity.ProfileUpdate {
+	return &entity.ProfileUpdate{
+		DisplayName:       req.DisplayName,
+		Specialty:         req.Specialty,
+		LicenseNumber:     req.LicenseNumber,
+		Timezone:          req.Timezone,
+		PreferredLanguage: req.PreferredLanguage,
+	}
+}
+
+func createUserResponse(user *entity.User) *UserResponse {
+	return &UserResponse{
+		ID:                user.ID,
+		Email:             user.Email,
+		Name:              user.Name,
+		GoogleID:          user.GoogleID,
+		DisplayName:       user.Profile.DisplayName,
+		Specialty:         user.Profile.Specialty,
+		LicenseNumber:     user.Profile.LicenseNumber,
+		Timezone:          user.Profile.Timezone,
+		PreferredLanguage: user.Profile.PreferredLanguage,
+		CreatedAt:         user.CreatedAt,
+		UpdatedAt:         user.UpdatedAt,
+	}
+}
@@ -0,0 +1,149 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type UserProfileExecutor struct {
+	handler *handler.UserProfile
+	finder  *mock_usecase.MockFindUser
+	updater *mock_usecase.MockUpdateProfile
+}
+
+func TestNewUserProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of UserProfile", func(t *testing.T) {
+		exec := createUserProfileExecutor(ctrl)
+		assert.NotNil(t, exec.handler)
+	})
+}
+
+func TestUserProfile_Find(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", nil)
+
+		exec := createUserProfileExecutor(ctrl)
+		serve(ctx, exec.handler.Find)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("user is not found", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", user)
+
+		exec := createUserProfileExecutor(ctrl)
+		exec.finder.EXPECT().FindByEmail(ctx.Request().Context(), user.Email).Return(nil, entity.ErrUserNotFound)
+		serve(ctx, exec.handler.Find)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("successfully find profile", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodGet, nil, "", user)
+		stored := *user
+		stored.Profile = entity.Profile{DisplayName: "dr. Jenner", Timezone: "Europe/London"}
+
+		exec := createUserProfileExecutor(ctrl)
+		exec.finder.EXPECT().FindByEmail(ctx.Request().Context(), user.Email).Return(&stored, nil)
+		serve(ctx, exec.handler.Find)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		res := decodeUserResponse(t, rec.Body.Bytes())
+		assert.Equal(t, "dr. Jenner", res.DisplayName)
+		assert.Equal(t, "Europe/London", res.Timezone)
+	})
+}
+
+func TestUserProfile_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("request body is invalid", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodPatch, strings.NewReader(`{"specialty": 1}`), echo.MIMEApplicationJSON, user)
+
+		exec := createUserProfileExecutor(ctrl)
+		serve(ctx, exec.handler.Update)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "03-004")
+	})
+
+	t.Run("can't extract user information from request context", func(t *testing.T) {
+		ctx, rec := createAttachmentContext(http.MethodPatch, strings.NewReader(`{}`), echo.MIMEApplicationJSON, nil)
+
+		exec := createUserProfileExecutor(ctrl)
+		serve(ctx, exec.handler.Update)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("profile is invalid", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodPatch, strings.NewReader(`{"timezone": "Mars/Olympus"}`), echo.MIMEApplicationJSON, user)
+		invalid := entity.WithInvalidParams(entity.ErrInvalidProfileAttribute, []entity.InvalidParam{{Name: "timezone", Rule: "timezone"}})
+
+		exec := createUserProfileExecutor(ctrl)
+		exec.updater.EXPECT().Update(ctx.Request().Context(), user.Email, gomock.Any()).Return(nil, invalid)
+		serve(ctx, exec.handler.Update)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "03-005")
+	})
+
+	t.Run("successfully update only the fields which are sent", func(t *testing.T) {
+		user := createUserInformation()
+		ctx, rec := createAttachmentContext(http.MethodPatch, strings.NewReader(`{"specialty": "Immunology", "license_number": ""}`), echo.MIMEApplicationJSON, user)
+		stored := *user
+		stored.Profile.Specialty = "Immunology"
+
+		exec := createUserProfileExecutor(ctrl)
+		exec.updater.EXPECT().Update(ctx.Request().Context(), user.Email, gomock.Any()).
+			DoAndReturn(func(_ interface{}, _ string, update *entity.ProfileUpdate) (*entity.User, *entity.Error) {
+				assert.Nil(t, update.DisplayName)
+				assert.Equal(t, "Immunology", *update.Specialty)
+				assert.Equal(t, "", *update.LicenseNumber)
+				return &stored, nil
+			})
+		serve(ctx, exec.handler.Update)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Immunology", decodeUserResponse(t, rec.Body.Bytes()).Specialty)
+	})
+}
+
+func decodeUserResponse(t *testing.T, body []byte) *handler.UserResponse {
+	var res struct {
+		Data *handler.UserResponse `json:"data"`
+	}
+	assert.Nil(t, json.Unmarshal(body, &res))
+	return res.Data
+}
+
+func createUserProfileExecutor(ctrl *gomock.Controller) *UserProfileExecutor {
+	f := mock_usecase.NewMockFindUser(ctrl)
+	u := mock_usecase.NewMockUpdateProfile(ctrl)
+	h := handler.NewUserProfile(f, u)
+	return &UserProfileExecutor{
+		handler: h,
+		finder:  f,
+		updater: u,
+	}
+}
@@ -2,12 +2,15 @@ package middleware
 
 import (
 	"context"
-	"net/http"
+	"io"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/indrasaputra/orvosi-api/entity"
-	"github.com/indrasaputra/orvosi-api/internal/http/response"
 	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
 )
 
 // ContextKey is just an alias for string to be used
@@ -20,8 +23,25 @@ const (
 	// ContextKeyUser is just a string "user" defined as a key
 	// to save a user information in context.
 	ContextKeyUser = ContextKey("user")
+	// ContextKeyService is just a string "service" defined as a key
+	// to save the identity of internal service authenticated by its client certificate in context.
+	ContextKeyService = ContextKey("service")
+	// ContextKeyLanguage is just a string "language" defined as a key
+	// to save the language negotiated from Accept-Language header in context.
+	ContextKeyLanguage = ContextKey("language")
 
 	authBearerKey = "Bearer"
+
+	// clientIdleTimeout is how long the rate limit of a client is kept after its last request.
+	clientIdleTimeout = 10 * time.Minute
+
+	headerCacheControl    = "Cache-Control"
+	headerPragma          = "Pragma"
+	headerAcceptLanguage  = "Accept-Language"
+	headerContentLanguage = "Content-Language"
+
+	mimeApplicationPDF = "application/pdf"
+	pdfExtension       = ".pdf"
 )
 
 // JWTDecoder defines the function contract to decode JWT.
@@ -36,16 +56,12 @@ func WithJWTDecoder(decoder JWTDecoder) echo.MiddlewareFunc {
 			val := ctx.Request().Header.Get(echo.HeaderAuthorization)
 			token := strings.Split(val, " ")
 			if len(token) != 2 || (len(token) == 2 && token[0] != authBearerKey) {
-				res := response.NewError(entity.ErrUnauthorized)
-				ctx.JSON(http.StatusUnauthorized, res)
 				return entity.ErrUnauthorized
 			}
 
 			user, err := decoder(token[1])
 			if err != nil {
-				res := response.NewError(entity.ErrUnauthorized)
-				ctx.JSON(http.StatusUnauthorized, res)
-				return err
+				return entity.WrapError(entity.ErrUnauthorized, err.Error())
 			}
 
 			reqCtx := context.WithValue(ctx.Request().Context(), ContextKeyUser, user)
@@ -57,6 +73,86 @@ func WithJWTDecoder(decoder JWTDecoder) echo.MiddlewareFunc {
 	}
 }
 
+// UserResolver defines the function contract to find the stored user of the user decoded from JWT.
+type UserResolver func(ctx context.Context, user *entity.User) (*entity.User, *entity.Error)
+
+// WithUserResolver replaces the user saved by WithJWTDecoder with the stored user,
+// so the handlers get the id of the user and their current email, which may differ from the one in the token.
+// It must run after WithJWTDecoder.
+func WithUserResolver(resolver UserResolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			reqCtx := ctx.Request().Context()
+			user, ok := reqCtx.Value(ContextKeyUser).(*entity.User)
+			if !ok || user == nil {
+				return entity.ErrUnauthorized
+			}
+
+			stored, err := resolver(reqCtx, user)
+			if err != nil {
+				return err
+			}
+
+			reqCtx = context.WithValue(reqCtx, ContextKeyUser, stored)
+			ctx.SetRequest(ctx.Request().WithContext(reqCtx))
+			return next(ctx)
+		}
+	}
+}
+
+// Chain combines the middlewares into one which runs them in the given order.
+func Chain(midds ...echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		for i := len(midds) - 1; i >= 0; i-- {
+			next = midds[i](next)
+		}
+		return next
+	}
+}
+
+// WithClientIdentity maps the common name of the verified client certificate onto the identity of internal service
+// and saves it in the request context, keyed by ContextKeyService.
+// The request with a client certificate whose common name isn't mapped is forbidden.
+// The request without a verified client certificate is passed as is.
+func WithClientIdentity(identities map[string]string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			state := ctx.Request().TLS
+			if state == nil || len(state.VerifiedChains) == 0 {
+				return next(ctx)
+			}
+
+			identity, ok := identities[state.VerifiedChains[0][0].Subject.CommonName]
+			if !ok {
+				return entity.ErrForbidden
+			}
+			reqCtx := context.WithValue(ctx.Request().Context(), ContextKeyService, identity)
+			ctx.SetRequest(ctx.Request().WithContext(reqCtx))
+			return next(ctx)
+		}
+	}
+}
+
+// ServiceIdentity returns the identity of internal service saved by WithClientIdentity.
+func ServiceIdentity(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(ContextKeyService).(string)
+	return identity, ok
+}
+
+// WithServiceIdentityOr passes the request of internal service identified by WithClientIdentity as is
+// and authorizes any other request using auth, such as WithJWTDecoder.
+func WithServiceIdentityOr(auth echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		authorized := auth(next)
+		return func(ctx echo.Context) error {
+			if _, ok := ServiceIdentity(ctx.Request().Context()); ok {
+				return next(ctx)
+			}
+			return authorized(ctx)
+		}
+	}
+}
+
 // WithContentType checks if the request contains header with key Content-Type
 // and value that is expected. The expected value is set from contentType parameter.
 func WithContentType(contentType string) echo.MiddlewareFunc {
@@ -64,11 +160,213 @@ func WithContentType(contentType string) echo.MiddlewareFunc {
 		return func(ctx echo.Context) error {
 			content := ctx.Request().Header.Get(echo.HeaderContentType)
 			if content != contentType {
-				res := response.NewError(entity.ErrWrongContentType)
-				ctx.JSON(http.StatusBadRequest, res)
 				return entity.ErrWrongContentType
 			}
 			return next(ctx)
 		}
 	}
 }
+
+// WithBodyLimit rejects the request whose body is larger than limit bytes with ErrRequestTooLarge.
+// The body without Content-Length is counted while it is read,
+// and the error of the handler is replaced if the limit is exceeded.
+func WithBodyLimit(limit int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			if req.ContentLength > limit {
+				return entity.ErrRequestTooLarge
+			}
+			body := &limitedBody{ReadCloser: req.Body, remaining: limit}
+			req.Body = body
+
+			err := next(ctx)
+			if body.exceeded && !ctx.Response().Committed {
+				return entity.ErrRequestTooLarge
+			}
+			return err
+		}
+	}
+}
+
+// limitedBody fails the read after more than remaining bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	exceeded  bool
+}
+
+func (lb *limitedBody) Read(p []byte) (int, error) {
+	if lb.exceeded {
+		return 0, entity.ErrRequestTooLarge
+	}
+	if int64(len(p)) > lb.remaining+1 {
+		p = p[:lb.remaining+1]
+	}
+	n, err := lb.ReadCloser.Read(p)
+	if int64(n) > lb.remaining {
+		n, lb.remaining, lb.exceeded = int(lb.remaining), 0, true
+		return n, entity.ErrRequestTooLarge
+	}
+	lb.remaining -= int64(n)
+	return n, err
+}
+
+// WithNoStore forbids the clients and proxies to keep the response,
+// since it contains personal health information.
+func WithNoStore() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			header := ctx.Response().Header()
+			header.Set(headerCacheControl, "no-store")
+			header.Set(headerPragma, "no-cache")
+			return next(ctx)
+		}
+	}
+}
+
+// WithPDFNegotiation routes the request to pdfHandler instead of the next handler
+// if the client asks for PDF representation.
+// The client asks for PDF by appending ".pdf" to the last path param (e.g. `/medical-records/:id.pdf`)
+// or by putting "application/pdf" as the first media type in Accept header.
+// The ".pdf" suffix is removed from the param before pdfHandler is called.
+func WithPDFNegotiation(pdfHandler echo.HandlerFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			values := ctx.ParamValues()
+			if n := len(values); n > 0 && strings.HasSuffix(values[n-1], pdfExtension) {
+				stripped := append([]string{}, values...)
+				stripped[n-1] = strings.TrimSuffix(stripped[n-1], pdfExtension)
+				ctx.SetParamValues(stripped...)
+				return pdfHandler(ctx)
+			}
+
+			accept := strings.Split(ctx.Request().Header.Get(echo.HeaderAccept), ",")[0]
+			if strings.TrimSpace(strings.Split(accept, ";")[0]) == mimeApplicationPDF {
+				return pdfHandler(ctx)
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// WithLanguage negotiates the language of the response from Accept-Language header.
+// The language with the highest quality among the supported ones is chosen,
+// matched by its primary subtag, so "id-ID" is served as "id".
+// The fallback is chosen if none of them is asked.
+// The language is passed through the request context and written in Content-Language header.
+func WithLanguage(fallback string, supported ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			lang := negotiateLanguage(ctx.Request().Header.Get(headerAcceptLanguage), fallback, supported)
+
+			ctx.Response().Header().Set(headerContentLanguage, lang)
+			ctx.Response().Header().Add(echo.HeaderVary, headerAcceptLanguage)
+
+			reqCtx := context.WithValue(ctx.Request().Context(), ContextKeyLanguage, lang)
+			ctx.SetRequest(ctx.Request().WithContext(reqCtx))
+
+			return next(ctx)
+		}
+	}
+}
+
+func negotiateLanguage(header, fallback string, supported []string) string {
+	lang, best := fallback, 0.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		primary := strings.Split(tag, "-")[0]
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				val, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err != nil {
+					val = 0
+				}
+				q = val
+			}
+		}
+		if q <= best {
+			continue
+		}
+
+		for _, candidate := range supported {
+			if primary == candidate {
+				lang, best = candidate, q
+				break
+			}
+		}
+	}
+	return lang
+}
+
+// RateLimit returns the number of requests per second and the burst allowed for each client.
+// It is called on every request, so the limit can change while the application runs.
+// Zero rate disables the limit.
+type RateLimit func() (rate float64, burst int)
+
+// WithRateLimit limits the requests of each client, identified by its IP address, using token bucket.
+// The request above the limit returns ErrTooManyRequests.
+func WithRateLimit(limit RateLimit) echo.MiddlewareFunc {
+	limiters := newClientLimiters()
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			r, burst := limit()
+			if r <= 0 {
+				return next(ctx)
+			}
+			if !limiters.allow(ctx.RealIP(), rate.Limit(r), burst, time.Now()) {
+				return entity.ErrTooManyRequests
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// clientLimiters keeps a token bucket per client.
+// The bucket of a client which hasn't sent any request for clientIdleTimeout is removed.
+type clientLimiters struct {
+	mu        sync.Mutex
+	clients   map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newClientLimiters() *clientLimiters {
+	return &clientLimiters{clients: make(map[string]*clientLimiter)}
+}
+
+func (cl *clientLimiters) allow(client string, limit rate.Limit, burst int, now time.Time) bool {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if now.Sub(cl.lastSweep) >= clientIdleTimeout {
+		for key, c := range cl.clients {
+			if now.Sub(c.lastSeen) >= clientIdleTimeout {
+				delete(cl.clients, key)
+			}
+		}
+		cl.lastSweep = now
+	}
+
+	c, ok := cl.clients[client]
+	if !ok {
+		c = &clientLimiter{limiter: rate.NewLimiter(limit, burst)}
+		cl.clients[client] = c
+	}
+	if c.limiter.Limit() != limit {
+		c.limiter.SetLimitAt(now, limit)
+	}
+	if c.limiter.Burst() != burst {
+		c.limiter.SetBurstAt(now, burst)
+	}
+	c.lastSeen = now
+	return c.limiter.AllowN(now, 1)
+}
@@ -1,10 +1,17 @@
 package middleware_test
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/indrasaputra/hashids"
 	"github.com/indrasaputra/orvosi-api/entity"
 	"github.com/indrasaputra/orvosi-api/internal/http/middleware"
 	"github.com/labstack/echo/v4"
@@ -26,7 +33,6 @@ func TestWithJWTDecoder(t *testing.T) {
 		err := hdr(ctx)
 
 		assert.NotNil(t, err)
-		assert.Equal(t, http.StatusUnauthorized, rec.Code)
 		assert.Equal(t, entity.ErrUnauthorized, err)
 	})
 
@@ -45,7 +51,6 @@ func TestWithJWTDecoder(t *testing.T) {
 		err := hdr(ctx)
 
 		assert.NotNil(t, err)
-		assert.Equal(t, http.StatusUnauthorized, rec.Code)
 		assert.Equal(t, entity.ErrUnauthorized, err)
 	})
 
@@ -64,7 +69,6 @@ func TestWithJWTDecoder(t *testing.T) {
 		err := hdr(ctx)
 
 		assert.NotNil(t, err)
-		assert.Equal(t, http.StatusUnauthorized, rec.Code)
 		assert.Equal(t, entity.ErrUnauthorized, err)
 	})
 
@@ -82,9 +86,9 @@ func TestWithJWTDecoder(t *testing.T) {
 
 		err := hdr(ctx)
 
-		assert.NotNil(t, err)
-		assert.Equal(t, http.StatusUnauthorized, rec.Code)
-		assert.Equal(t, entity.ErrUnauthorized, err)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, entity.ErrUnauthorized.Code, err.(*entity.Error).Code)
+		}
 	})
 
 	t.Run("successfully decode jwt", func(t *testing.T) {
@@ -112,6 +116,149 @@ func TestWithJWTDecoder(t *testing.T) {
 	})
 }
 
+func TestWithUserResolver(t *testing.T) {
+	withUser := func(user *entity.User) echo.Context {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if user != nil {
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyUser, user))
+		}
+		return echo.New().NewContext(req, httptest.NewRecorder())
+	}
+
+	t.Run("request doesn't contain user", func(t *testing.T) {
+		ctx := withUser(nil)
+		resolver := func(ctx context.Context, user *entity.User) (*entity.User, *entity.Error) {
+			return user, nil
+		}
+
+		err := middleware.WithUserResolver(resolver)(createHandler())(ctx)
+
+		assert.Equal(t, entity.ErrUnauthorized, err)
+	})
+
+	t.Run("resolver returns error", func(t *testing.T) {
+		ctx := withUser(&entity.User{Email: "dummy@jwtmiddleware.com"})
+		resolver := func(ctx context.Context, user *entity.User) (*entity.User, *entity.Error) {
+			return nil, entity.ErrInternalServer
+		}
+
+		err := middleware.WithUserResolver(resolver)(createHandler())(ctx)
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+	})
+
+	t.Run("successfully replace user with the stored one", func(t *testing.T) {
+		ctx := withUser(&entity.User{Email: "dummy@jwtmiddleware.com", GoogleID: "google"})
+		resolver := func(ctx context.Context, user *entity.User) (*entity.User, *entity.Error) {
+			return &entity.User{ID: 1, Email: "new@jwtmiddleware.com", GoogleID: user.GoogleID}, nil
+		}
+
+		var user *entity.User
+		err := middleware.WithUserResolver(resolver)(func(c echo.Context) error {
+			user, _ = c.Request().Context().Value(middleware.ContextKeyUser).(*entity.User)
+			return nil
+		})(ctx)
+
+		assert.Nil(t, err)
+		if assert.NotNil(t, user) {
+			assert.Equal(t, hashids.ID(1), user.ID)
+			assert.Equal(t, "new@jwtmiddleware.com", user.Email)
+		}
+	})
+}
+
+func TestChain(t *testing.T) {
+	t.Run("middlewares run in the given order", func(t *testing.T) {
+		var order []string
+		mark := func(name string) echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(ctx echo.Context) error {
+					order = append(order, name)
+					return next(ctx)
+				}
+			}
+		}
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+		err := middleware.Chain(mark("first"), mark("second"))(createHandler())(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"first", "second"}, order)
+	})
+}
+
+func TestWithClientIdentity(t *testing.T) {
+	identities := map[string]string{"billing.internal": "billing"}
+	withPeer := func(commonName string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+		req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		return req
+	}
+
+	t.Run("request without client certificate is passed as is", func(t *testing.T) {
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+		var ok bool
+		err := middleware.WithClientIdentity(identities)(func(c echo.Context) error {
+			_, ok = middleware.ServiceIdentity(c.Request().Context())
+			return nil
+		})(ctx)
+
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("client certificate isn't mapped", func(t *testing.T) {
+		ctx := echo.New().NewContext(withPeer("unknown.internal"), httptest.NewRecorder())
+
+		err := middleware.WithClientIdentity(identities)(createHandler())(ctx)
+
+		assert.Equal(t, entity.ErrForbidden, err)
+	})
+
+	t.Run("client certificate is mapped onto service identity", func(t *testing.T) {
+		ctx := echo.New().NewContext(withPeer("billing.internal"), httptest.NewRecorder())
+
+		var identity string
+		err := middleware.WithClientIdentity(identities)(func(c echo.Context) error {
+			identity, _ = middleware.ServiceIdentity(c.Request().Context())
+			return nil
+		})(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "billing", identity)
+	})
+}
+
+func TestWithServiceIdentityOr(t *testing.T) {
+	auth := middleware.WithServiceIdentityOr(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			return entity.ErrUnauthorized
+		}
+	})
+
+	t.Run("request of internal service skips authorization", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyService, "billing"))
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		err := auth(createHandler())(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("other request is authorized", func(t *testing.T) {
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+		err := auth(createHandler())(ctx)
+
+		assert.Equal(t, entity.ErrUnauthorized, err)
+	})
+}
+
 func TestWithContentType(t *testing.T) {
 	t.Run("request can't be continued due to wrong content type", func(t *testing.T) {
 		tables := []struct {
@@ -139,7 +286,6 @@ func TestWithContentType(t *testing.T) {
 
 			assert.NotNil(t, err)
 			assert.Equal(t, entity.ErrWrongContentType, err)
-			assert.Equal(t, http.StatusBadRequest, rec.Code)
 		}
 	})
 
@@ -161,6 +307,193 @@ func TestWithContentType(t *testing.T) {
 	})
 }
 
+func TestWithBodyLimit(t *testing.T) {
+	readBody := func(c echo.Context) error {
+		if _, err := io.ReadAll(c.Request().Body); err != nil {
+			return entity.ErrInvalidMedicalRecordRequest
+		}
+		return c.String(http.StatusOK, "test")
+	}
+
+	t.Run("content length is larger than the limit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789"))
+		ctx := echo.New().NewContext(req, httptest.NewRecorder())
+
+		err := middleware.WithBodyLimit(5)(readBody)(ctx)
+
+		assert.Equal(t, entity.ErrRequestTooLarge, err)
+	})
+
+	t.Run("body without content length is larger than the limit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789"))
+		req.ContentLength = -1
+		ctx := echo.New().NewContext(req, httptest.NewRecorder())
+
+		err := middleware.WithBodyLimit(5)(readBody)(ctx)
+
+		assert.Equal(t, entity.ErrRequestTooLarge, err)
+	})
+
+	t.Run("body fits the limit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789"))
+		req.ContentLength = -1
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		err := middleware.WithBodyLimit(10)(readBody)(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestWithNoStore(t *testing.T) {
+	t.Run("response must not be stored", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		err := middleware.WithNoStore()(createHandler())(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+		assert.Equal(t, "no-cache", rec.Header().Get("Pragma"))
+	})
+}
+
+func TestWithPDFNegotiation(t *testing.T) {
+	t.Run("request without pdf extension or accept header goes to the next handler", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationJSON+", application/pdf")
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("oWx0b8DZ1a")
+
+		hdr := createHandler()
+		hdr = middleware.WithPDFNegotiation(createPDFHandler())(hdr)
+
+		err := hdr(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test", rec.Body.String())
+	})
+
+	t.Run("pdf extension is removed and the request goes to pdf handler", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("oWx0b8DZ1a.pdf")
+
+		hdr := createHandler()
+		hdr = middleware.WithPDFNegotiation(createPDFHandler())(hdr)
+
+		err := hdr(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "pdf oWx0b8DZ1a", rec.Body.String())
+	})
+
+	t.Run("accept header prefers pdf and the request goes to pdf handler", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAccept, "application/pdf;q=1.0, application/json")
+		rec := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("oWx0b8DZ1a")
+
+		hdr := createHandler()
+		hdr = middleware.WithPDFNegotiation(createPDFHandler())(hdr)
+
+		err := hdr(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "pdf oWx0b8DZ1a", rec.Body.String())
+	})
+}
+
+func TestWithLanguage(t *testing.T) {
+	t.Run("language is negotiated from accept-language header", func(t *testing.T) {
+		pairs := []struct {
+			header string
+			lang   string
+		}{
+			{"", "en"},
+			{"id", "id"},
+			{"id-ID", "id"},
+			{"HU-hu", "hu"},
+			{"fr-FR, hu;q=0.8, id;q=0.5", "hu"},
+			{"en;q=0.1, id;q=0.9", "id"},
+			{"id;q=0, hu;q=0.2", "hu"},
+			{"fr, de", "en"},
+			{"*", "en"},
+		}
+
+		for _, pair := range pairs {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Language", pair.header)
+			rec := httptest.NewRecorder()
+			ctx := echo.New().NewContext(req, rec)
+
+			var lang interface{}
+			hdr := middleware.WithLanguage("en", "en", "hu", "id")(func(c echo.Context) error {
+				lang = c.Request().Context().Value(middleware.ContextKeyLanguage)
+				return nil
+			})
+
+			err := hdr(ctx)
+
+			assert.Nil(t, err)
+			assert.Equal(t, pair.lang, lang, pair.header)
+			assert.Equal(t, pair.lang, rec.Header().Get("Content-Language"), pair.header)
+			assert.Equal(t, "Accept-Language", rec.Header().Get("Vary"), pair.header)
+		}
+	})
+}
+
+func TestWithRateLimit(t *testing.T) {
+	call := func(hdr echo.HandlerFunc, ip string) error {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderXRealIP, ip)
+		return hdr(echo.New().NewContext(req, httptest.NewRecorder()))
+	}
+
+	t.Run("zero rate disables the limit", func(t *testing.T) {
+		hdr := middleware.WithRateLimit(func() (float64, int) { return 0, 0 })(createHandler())
+
+		for i := 0; i < 5; i++ {
+			assert.Nil(t, call(hdr, "10.0.0.1"))
+		}
+	})
+
+	t.Run("each client has its own limit", func(t *testing.T) {
+		hdr := middleware.WithRateLimit(func() (float64, int) { return 1, 2 })(createHandler())
+
+		assert.Nil(t, call(hdr, "10.0.0.1"))
+		assert.Nil(t, call(hdr, "10.0.0.1"))
+		assert.Equal(t, entity.ErrTooManyRequests, call(hdr, "10.0.0.1"))
+		assert.Nil(t, call(hdr, "10.0.0.2"))
+	})
+
+	t.Run("limit is read on every request", func(t *testing.T) {
+		limit := 0.001
+		hdr := middleware.WithRateLimit(func() (float64, int) { return limit, 1 })(createHandler())
+
+		assert.Nil(t, call(hdr, "10.0.0.1"))
+		assert.Equal(t, entity.ErrTooManyRequests, call(hdr, "10.0.0.1"))
+
+		limit = 0
+		assert.Nil(t, call(hdr, "10.0.0.1"))
+	})
+}
+
 func createErrorDecoder() middleware.JWTDecoder {
 	return func(token string) (*entity.User, *entity.Error) {
 		return nil, entity.ErrUnauthorized
@@ -178,3 +511,9 @@ func createHandler() echo.HandlerFunc {
 		return c.String(http.StatusOK, "test")
 	}
 }
+
+func createPDFHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.String(http.StatusOK, "pdf "+c.Param("id"))
+	}
+}
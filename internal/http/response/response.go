@@ -1,5 +1,18 @@
 package response
 
+import (
+	"strings"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+const (
+	// MIMEApplicationProblemJSON is media type of Problem response.
+	MIMEApplicationProblemJSON = "application/problem+json"
+	// ProblemTypePrefix prefixes the error code to build the type of Problem.
+	ProblemTypePrefix = "urn:orvosi:error:"
+)
+
 // Success represents success response.
 type Success struct {
 	// Data represents any primary data that is visible in response.
@@ -16,6 +29,24 @@ type Error struct {
 	Meta interface{} `json:"meta"`
 }
 
+// Problem represents problem details response as defined in RFC 7807.
+type Problem struct {
+	// Type is URI reference identifying the problem type.
+	Type string `json:"type"`
+	// Title is short summary of the problem type.
+	Title string `json:"title"`
+	// Status is HTTP status code of the response.
+	Status int `json:"status"`
+	// Detail explains this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+	// Instance is URI reference identifying this occurrence of the problem.
+	Instance string `json:"instance,omitempty"`
+	// Code is error code, the same as in Error response.
+	Code string `json:"code"`
+	// InvalidParams lists the fields of the request that fail the validation.
+	InvalidParams []entity.InvalidParam `json:"invalid_params,omitempty"`
+}
+
 // EmptyMeta represents an empty struct.
 type EmptyMeta struct{}
 
@@ -34,3 +65,23 @@ func NewError(errors ...error) *Error {
 		Meta:   nil,
 	}
 }
+
+// NewProblem creates an instance of Problem response from the error.
+// The type is built from the error code, so each code is a distinct problem type.
+// The detail joins the reason of every invalid param, if any.
+func NewProblem(err *entity.Error, status int, instance string) *Problem {
+	reasons := make([]string, 0, len(err.InvalidParams))
+	for _, param := range err.InvalidParams {
+		reasons = append(reasons, param.Name+" "+param.Reason)
+	}
+
+	return &Problem{
+		Type:          ProblemTypePrefix + err.Code,
+		Title:         err.Message,
+		Status:        status,
+		Detail:        strings.Join(reasons, "; "),
+		Instance:      instance,
+		Code:          err.Code,
+		InvalidParams: err.InvalidParams,
+	}
+}
@@ -1,8 +1,10 @@
 package response_test
 
 import (
+	"net/http"
 	"testing"
 
+	"github.com/indrasaputra/orvosi-api/entity"
 	"github.com/indrasaputra/orvosi-api/internal/http/response"
 	"github.com/stretchr/testify/assert"
 )
@@ -76,6 +78,33 @@ func TestNewError(t *testing.T) {
 	})
 }
 
+func TestNewProblem(t *testing.T) {
+	t.Run("error without invalid params has no detail", func(t *testing.T) {
+		res := response.NewProblem(entity.ErrMedicalRecordNotFound, http.StatusNotFound, "/medical-records/abc")
+
+		assert.Equal(t, "urn:orvosi:error:02-005", res.Type)
+		assert.Equal(t, entity.ErrMedicalRecordNotFound.Message, res.Title)
+		assert.Equal(t, http.StatusNotFound, res.Status)
+		assert.Empty(t, res.Detail)
+		assert.Equal(t, "/medical-records/abc", res.Instance)
+		assert.Equal(t, "02-005", res.Code)
+		assert.Empty(t, res.InvalidParams)
+	})
+
+	t.Run("detail joins the reason of invalid params", func(t *testing.T) {
+		params := []entity.InvalidParam{
+			{Name: "symptom", Rule: "required", Reason: "must not be blank"},
+			{Name: "diagnosis", Rule: "required", Reason: "must not be blank"},
+		}
+		err := entity.WithInvalidParams(entity.ErrInvalidMedicalRecordAttribute, params)
+
+		res := response.NewProblem(err, http.StatusBadRequest, "/medical-records")
+
+		assert.Equal(t, "symptom must not be blank; diagnosis must not be blank", res.Detail)
+		assert.Equal(t, params, res.InvalidParams)
+	})
+}
+
 func buildListOfError(errs []*Error) []error {
 	res := make([]error, len(errs))
 	for i, err := range errs {
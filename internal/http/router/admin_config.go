@@ -0,0 +1,24 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+)
+
+// AdminConfig creates routes for the runtime settings shown to admins.
+// Internal services can see them as well.
+func AdminConfig(h *handler.AdminConfig) []*Route {
+	var routes []*Route
+
+	r := &Route{
+		Method:  http.MethodGet,
+		Path:    "/admin/config",
+		Handler: h.Show,
+		// the settings hold nothing of a user, so internal services can read them for monitoring.
+		AllowService: true,
+	}
+
+	routes = append(routes, r)
+	return routes
+}
@@ -0,0 +1,28 @@
+package router_test
+
+import (
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/router"
+	"github.com/indrasaputra/orvosi-api/internal/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminConfigRoutes(t *testing.T) {
+	t.Run("all desired admin config routes are registered and not public", func(t *testing.T) {
+		desired := map[string]string{
+			"/admin/config": "GET",
+		}
+
+		h := handler.NewAdminConfig(settings.NewSettings(config.Runtime{}, nil))
+		routes := router.AdminConfig(h)
+
+		assert.Len(t, routes, len(desired))
+		for _, route := range routes {
+			assert.Equal(t, desired[route.Path], route.Method)
+			assert.False(t, route.Public)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+)
+
+// AttachmentUploader creates routes for attachment uploader.
+func AttachmentUploader(h *handler.AttachmentUploader) []*Route {
+	var routes []*Route
+
+	r := &Route{
+		Method:  http.MethodPost,
+		Path:    "/medical-records/:id/attachments",
+		Handler: h.Upload,
+	}
+
+	routes = append(routes, r)
+	return routes
+}
+
+// AttachmentFinder creates routes for attachment finder.
+func AttachmentFinder(h *handler.AttachmentFinder) []*Route {
+	var routes []*Route
+
+	fbr := &Route{
+		Method:  http.MethodGet,
+		Path:    "/medical-records/:id/attachments",
+		Handler: h.FindByMedicalRecordID,
+	}
+
+	dl := &Route{
+		Method:  http.MethodGet,
+		Path:    "/medical-records/:id/attachments/:attachment_id",
+		Handler: h.Download,
+	}
+
+	routes = append(routes, fbr, dl)
+	return routes
+}
+
+// AttachmentDeleter creates routes for attachment deleter.
+func AttachmentDeleter(h *handler.AttachmentDeleter) []*Route {
+	var routes []*Route
+
+	r := &Route{
+		Method:  http.MethodDelete,
+		Path:    "/medical-records/:id/attachments/:attachment_id",
+		Handler: h.Delete,
+	}
+
+	routes = append(routes, r)
+	return routes
+}
@@ -0,0 +1,69 @@
+package router_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/router"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachmentUploaderRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("all desired attachment uploader routes are registered", func(t *testing.T) {
+		desired := map[string]string{
+			"/medical-records/:id/attachments": "POST",
+		}
+
+		h := handler.NewAttachmentUploader(mock_usecase.NewMockUploadAttachment(ctrl))
+		routes := router.AttachmentUploader(h)
+
+		assert.Equal(t, len(desired), len(routes))
+		for _, route := range routes {
+			assert.Equal(t, desired[route.Path], route.Method)
+		}
+	})
+}
+
+func TestAttachmentFinderRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("all desired attachment finder routes are registered", func(t *testing.T) {
+		desired := map[string]string{
+			"/medical-records/:id/attachments":                "GET",
+			"/medical-records/:id/attachments/:attachment_id": "GET",
+		}
+
+		h := handler.NewAttachmentFinder(mock_usecase.NewMockFindAttachment(ctrl))
+		routes := router.AttachmentFinder(h)
+
+		assert.Equal(t, len(desired), len(routes))
+		for _, route := range routes {
+			assert.Equal(t, desired[route.Path], route.Method)
+		}
+	})
+}
+
+func TestAttachmentDeleterRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("all desired attachment deleter routes are registered", func(t *testing.T) {
+		desired := map[string]string{
+			"/medical-records/:id/attachments/:attachment_id": "DELETE",
+		}
+
+		h := handler.NewAttachmentDeleter(mock_usecase.NewMockDeleteAttachment(ctrl))
+		routes := router.AttachmentDeleter(h)
+
+		assert.Equal(t, len(desired), len(routes))
+		for _, route := range routes {
+			assert.Equal(t, desired[route.Path], route.Method)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+)
+
+// FHIRMedicalRecord creates routes for medical record represented in FHIR R4.
+func FHIRMedicalRecord(h *handler.FHIRMedicalRecord) []*Route {
+	var routes []*Route
+
+	meta := &Route{
+		Method:  http.MethodGet,
+		Path:    handler.FHIRBasePath + "/metadata",
+		Handler: h.Metadata,
+	}
+
+	search := &Route{
+		Method:  http.MethodGet,
+		Path:    handler.FHIRBasePath + "/:type",
+		Handler: h.Search,
+	}
+
+	read := &Route{
+		Method:  http.MethodGet,
+		Path:    handler.FHIRBasePath + "/:type/:id",
+		Handler: h.Read,
+	}
+
+	routes = append(routes, meta, search, read)
+	return routes
+}
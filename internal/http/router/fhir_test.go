@@ -0,0 +1,38 @@
+package router_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/router"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFHIRMedicalRecordRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("all desired fhir routes are registered", func(t *testing.T) {
+		desired := map[string]string{
+			"/fhir/r4/metadata":  "GET",
+			"/fhir/r4/:type":     "GET",
+			"/fhir/r4/:type/:id": "GET",
+		}
+
+		h := createFHIRMedicalRecord(ctrl)
+		routes := router.FHIRMedicalRecord(h)
+
+		assert.Equal(t, len(desired), len(routes))
+		for _, route := range routes {
+			assert.Equal(t, desired[route.Path], route.Method)
+			assert.Empty(t, route.Middlewares)
+		}
+	})
+}
+
+func createFHIRMedicalRecord(ctrl *gomock.Controller) *handler.FHIRMedicalRecord {
+	m := mock_usecase.NewMockFindMedicalRecord(ctrl)
+	return handler.NewFHIRMedicalRecord(m)
+}
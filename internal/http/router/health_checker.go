@@ -0,0 +1,31 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+)
+
+// HealthChecker creates routes for health check.
+// The routes are public so load balancers and orchestrators can call them.
+// `/health` tells whether the application works, while `/ready` also fails once it is shutting down.
+func HealthChecker(h *handler.HealthChecker) []*Route {
+	var routes []*Route
+
+	health := &Route{
+		Method:  http.MethodGet,
+		Path:    "/health",
+		Handler: h.Check,
+		Public:  true,
+	}
+
+	ready := &Route{
+		Method:  http.MethodGet,
+		Path:    "/ready",
+		Handler: h.Ready,
+		Public:  true,
+	}
+
+	routes = append(routes, health, ready)
+	return routes
+}
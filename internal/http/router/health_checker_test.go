@@ -0,0 +1,37 @@
+package router_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/router"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCheckerRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("all desired health checker routes are registered and public", func(t *testing.T) {
+		desired := map[string]string{
+			"/health": "GET",
+			"/ready":  "GET",
+		}
+
+		h := createHealthChecker(ctrl)
+		routes := router.HealthChecker(h)
+		assert.Len(t, routes, len(desired))
+
+		for _, route := range routes {
+			assert.Equal(t, desired[route.Path], route.Method)
+			assert.True(t, route.Public)
+		}
+	})
+}
+
+func createHealthChecker(ctrl *gomock.Controller) *handler.HealthChecker {
+	m := mock_usecase.NewMockCheckHealth(ctrl)
+	return handler.NewHealthChecker(m)
+}
@@ -24,19 +24,21 @@ func MedicalRecordCreator(h *handler.MedicalRecordCreator) []*Route {
 }
 
 // MedicalRecordFinder creates routes for medical record finder.
-func MedicalRecordFinder(h *handler.MedicalRecordFinder) []*Route {
+// A single medical record can also be printed as PDF using the printer.
+func MedicalRecordFinder(h *handler.MedicalRecordFinder, p *handler.MedicalRecordPrinter) []*Route {
 	var routes []*Route
 
 	fbe := &Route{
 		Method:  http.MethodGet,
 		Path:    "/medical-records",
-		Handler: h.FindByEmail,
+		Handler: h.FindByUser,
 	}
 
 	fbi := &Route{
-		Method:  http.MethodGet,
-		Path:    "/medical-records/:id",
-		Handler: h.FindByID,
+		Method:      http.MethodGet,
+		Path:        "/medical-records/:id",
+		Handler:     h.FindByID,
+		Middlewares: []echo.MiddlewareFunc{middleware.WithPDFNegotiation(p.Print)},
 	}
 
 	routes = append(routes, fbe, fbi)
@@ -57,3 +59,17 @@ func MedicalRecordUpdater(h *handler.MedicalRecordUpdater) []*Route {
 	routes = append(routes, r)
 	return routes
 }
+
+// MedicalRecordImporter creates routes for medical record importer.
+func MedicalRecordImporter(h *handler.MedicalRecordImporter) []*Route {
+	var routes []*Route
+
+	r := &Route{
+		Method:  http.MethodPost,
+		Path:    "/medical-records/import",
+		Handler: h.Import,
+	}
+
+	routes = append(routes, r)
+	return routes
+}
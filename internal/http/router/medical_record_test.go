@@ -6,6 +6,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/indrasaputra/orvosi-api/internal/http/handler"
 	"github.com/indrasaputra/orvosi-api/internal/http/router"
+	"github.com/indrasaputra/orvosi-api/internal/tool"
 	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
 	"github.com/stretchr/testify/assert"
 )
@@ -40,11 +41,25 @@ func TestMedicalRecordFinderRoutes(t *testing.T) {
 		}
 
 		h := createMedicalRecordFinder(ctrl)
-		routes := router.MedicalRecordFinder(h)
+		p := createMedicalRecordPrinter(ctrl)
+		routes := router.MedicalRecordFinder(h, p)
 
 		for _, route := range routes {
 			assert.Equal(t, desired[route.Path], route.Method)
-			assert.Empty(t, route.Middlewares)
+		}
+	})
+
+	t.Run("find by id route negotiates pdf", func(t *testing.T) {
+		h := createMedicalRecordFinder(ctrl)
+		p := createMedicalRecordPrinter(ctrl)
+		routes := router.MedicalRecordFinder(h, p)
+
+		for _, route := range routes {
+			if route.Path == "/medical-records/:id" {
+				assert.NotEmpty(t, route.Middlewares)
+			} else {
+				assert.Empty(t, route.Middlewares)
+			}
 		}
 	})
 }
@@ -68,6 +83,25 @@ func TestMedicalRecordUpdaterRoutes(t *testing.T) {
 	})
 }
 
+func TestMedicalRecordImporterRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("all desired medical record importer routes are registered", func(t *testing.T) {
+		desired := map[string]string{
+			"/medical-records/import": "POST",
+		}
+
+		h := createMedicalRecordImporter(ctrl)
+		routes := router.MedicalRecordImporter(h)
+
+		for _, route := range routes {
+			assert.Equal(t, desired[route.Path], route.Method)
+			assert.Empty(t, route.Middlewares)
+		}
+	})
+}
+
 func createMedicalRecordCreator(ctrl *gomock.Controller) *handler.MedicalRecordCreator {
 	m := mock_usecase.NewMockCreateMedicalRecord(ctrl)
 	return handler.NewMedicalRecordCreator(m)
@@ -78,7 +112,17 @@ func createMedicalRecordFinder(ctrl *gomock.Controller) *handler.MedicalRecordFi
 	return handler.NewMedicalRecordFinder(m)
 }
 
+func createMedicalRecordPrinter(ctrl *gomock.Controller) *handler.MedicalRecordPrinter {
+	m := mock_usecase.NewMockFindMedicalRecord(ctrl)
+	return handler.NewMedicalRecordPrinter(m, tool.NewMedicalRecordPDFRenderer("header", "footer"))
+}
+
 func createMedicalRecordUpdater(ctrl *gomock.Controller) *handler.MedicalRecordUpdater {
 	m := mock_usecase.NewMockUpdateMedicalRecord(ctrl)
 	return handler.NewMedicalRecordUpdater(m)
 }
+
+func createMedicalRecordImporter(ctrl *gomock.Controller) *handler.MedicalRecordImporter {
+	m := mock_usecase.NewMockImportMedicalRecord(ctrl)
+	return handler.NewMedicalRecordImporter(m, 1<<20)
+}
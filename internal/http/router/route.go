@@ -12,4 +12,12 @@ type Route struct {
 	Handler echo.HandlerFunc
 	// Middlewares defines the list of middleware used for the route.
 	Middlewares []echo.MiddlewareFunc
+	// Public defines whether the route can be called without authorization.
+	// The response of a route which needs authorization is never cached, since it contains personal health information.
+	Public bool
+	// AllowService defines whether internal service identified by its client certificate can call the route without a user.
+	// The other callers still need authorization.
+	AllowService bool
+	// BodyLimit defines the maximum size of the request body in bytes. Zero means the default limit of the server.
+	BodyLimit int64
 }
@@ -0,0 +1,113 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+)
+
+// UserProfile creates routes for the profile of the signed-in user.
+func UserProfile(h *handler.UserProfile) []*Route {
+	var routes []*Route
+
+	fnd := &Route{
+		Method:  http.MethodGet,
+		Path:    "/me",
+		Handler: h.Find,
+	}
+
+	upd := &Route{
+		Method:  http.MethodPatch,
+		Path:    "/me",
+		Handler: h.Update,
+	}
+
+	routes = append(routes, fnd, upd)
+	return routes
+}
+
+// UserEraser creates routes for user eraser.
+func UserEraser(h *handler.UserEraser) []*Route {
+	var routes []*Route
+
+	sch := &Route{
+		Method:  http.MethodDelete,
+		Path:    "/me",
+		Handler: h.Schedule,
+	}
+
+	fnd := &Route{
+		Method:  http.MethodGet,
+		Path:    "/me/erasure",
+		Handler: h.Find,
+	}
+
+	cnc := &Route{
+		Method:  http.MethodDelete,
+		Path:    "/me/erasure",
+		Handler: h.Cancel,
+	}
+
+	routes = append(routes, sch, fnd, cnc)
+	return routes
+}
+
+// UserDataExporter creates routes for user data exporter.
+func UserDataExporter(h *handler.UserDataExporter) []*Route {
+	var routes []*Route
+
+	r := &Route{
+		Method:  http.MethodGet,
+		Path:    "/me/data",
+		Handler: h.Export,
+	}
+
+	routes = append(routes, r)
+	return routes
+}
+
+// UserEmail creates routes for the email change of the signed-in user.
+func UserEmail(h *handler.UserEmail) []*Route {
+	var routes []*Route
+
+	req := &Route{
+		Method:  http.MethodPost,
+		Path:    "/me/email",
+		Handler: h.Request,
+	}
+
+	vrf := &Route{
+		Method:  http.MethodPost,
+		Path:    "/me/email/verify",
+		Handler: h.Verify,
+	}
+
+	routes = append(routes, req, vrf)
+	return routes
+}
+
+// UserIdentity creates routes for the identities of the signed-in user.
+func UserIdentity(h *handler.UserIdentity) []*Route {
+	var routes []*Route
+
+	fnd := &Route{
+		Method:  http.MethodGet,
+		Path:    "/me/identities",
+		Handler: h.FindAll,
+	}
+
+	lnk := &Route{
+		Method:  http.MethodPost,
+		Path:    "/me/identities",
+		Handler: h.Link,
+	}
+
+	unl := &Route{
+		Method:  http.MethodDelete,
+		Path:    "/me/identities/:id",
+		Handler: h.Unlink,
+	}
+
+	routes = append(routes, fnd, lnk, unl)
+	return routes
+}
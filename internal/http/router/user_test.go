@@ -0,0 +1,112 @@
+package router_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/router"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserProfileRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("all desired user profile routes are registered", func(t *testing.T) {
+		desired := map[string]bool{
+			"GET /me":   true,
+			"PATCH /me": true,
+		}
+
+		h := handler.NewUserProfile(mock_usecase.NewMockFindUser(ctrl), mock_usecase.NewMockUpdateProfile(ctrl))
+		routes := router.UserProfile(h)
+
+		assert.Equal(t, len(desired), len(routes))
+		for _, route := range routes {
+			assert.True(t, desired[route.Method+" "+route.Path], route.Method+" "+route.Path)
+		}
+	})
+}
+
+func TestUserEraserRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("all desired user eraser routes are registered", func(t *testing.T) {
+		desired := map[string]bool{
+			"DELETE /me":         true,
+			"GET /me/erasure":    true,
+			"DELETE /me/erasure": true,
+		}
+
+		h := handler.NewUserEraser(mock_usecase.NewMockEraseUser(ctrl))
+		routes := router.UserEraser(h)
+
+		assert.Equal(t, len(desired), len(routes))
+		for _, route := range routes {
+			assert.True(t, desired[route.Method+" "+route.Path], route.Method+" "+route.Path)
+		}
+	})
+}
+
+func TestUserDataExporterRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("all desired user data exporter routes are registered", func(t *testing.T) {
+		desired := map[string]string{
+			"/me/data": "GET",
+		}
+
+		h := handler.NewUserDataExporter(mock_usecase.NewMockExportUserData(ctrl), mock_usecase.NewMockFindAttachment(ctrl))
+		routes := router.UserDataExporter(h)
+
+		assert.Equal(t, len(desired), len(routes))
+		for _, route := range routes {
+			assert.Equal(t, desired[route.Path], route.Method)
+		}
+	})
+}
+
+func TestUserEmailRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("all desired user email routes are registered", func(t *testing.T) {
+		desired := map[string]bool{
+			"POST /me/email":        true,
+			"POST /me/email/verify": true,
+		}
+
+		h := handler.NewUserEmail(mock_usecase.NewMockChangeEmail(ctrl))
+		routes := router.UserEmail(h)
+
+		assert.Equal(t, len(desired), len(routes))
+		for _, route := range routes {
+			assert.True(t, desired[route.Method+" "+route.Path], route.Method+" "+route.Path)
+		}
+	})
+}
+
+func TestUserIdentityRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("all desired user identity routes are registered", func(t *testing.T) {
+		desired := map[string]bool{
+			"GET /me/identities":        true,
+			"POST /me/identities":       true,
+			"DELETE /me/identities/:id": true,
+		}
+
+		h := handler.NewUserIdentity(mock_usecase.NewMockLinkIdentity(ctrl))
+		routes := router.UserIdentity(h)
+
+		assert.Equal(t, len(desired), len(routes))
+		for _, route := range routes {
+			assert.True(t, desired[route.Method+" "+route.Path], route.Method+" "+route.Path)
+		}
+	})
+}
@@ -1,9 +1,15 @@
 package server
 
 import (
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	apimiddleware "github.com/indrasaputra/orvosi-api/internal/http/middleware"
 	"github.com/indrasaputra/orvosi-api/internal/http/router"
+	"github.com/indrasaputra/orvosi-api/internal/i18n"
+	"github.com/indrasaputra/orvosi-api/internal/settings"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/labstack/gommon/log"
 )
 
 // Server acts as echo.Echo server.
@@ -12,19 +18,103 @@ type Server struct {
 }
 
 // NewServer creates an instance of Echo.
-func NewServer(jwtDecoder echo.MiddlewareFunc, routes []*router.Route) *Server {
+// The allowed origins, the rate limit, and the log level are read from the runtime settings,
+// so they follow the settings when they are reloaded.
+// The other CORS settings, the security headers, the default body limit, and the trusted proxies are taken from cfg.
+func NewServer(jwtDecoder echo.MiddlewareFunc, routes []*router.Route, rt *settings.Settings, cfg *config.HTTP) *Server {
 	e := echo.New()
+	e.HTTPErrorHandler = handler.HTTPErrorHandler
+	e.IPExtractor = ipExtractor(cfg)
+	e.Logger.SetLevel(logLevel(rt.Current().LogLevel))
+	rt.OnChange(func(snap *settings.Snapshot) {
+		e.Logger.SetLevel(logLevel(snap.LogLevel))
+	})
 
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
-	e.Use(middleware.CORS())
+	e.Use(middleware.SecureWithConfig(secureConfig(cfg)))
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOriginFunc: func(origin string) (bool, error) {
+			return rt.Current().IsOriginAllowed(origin), nil
+		},
+		AllowMethods:  config.SplitList(cfg.CORSAllowMethods),
+		AllowHeaders:  config.SplitList(cfg.CORSAllowHeaders),
+		ExposeHeaders: config.SplitList(cfg.CORSExposeHeaders),
+		MaxAge:        int(cfg.CORSMaxAge.Seconds()),
+	}))
+	e.Use(apimiddleware.WithLanguage(i18n.DefaultLanguage, i18n.Languages()...))
+	e.Use(apimiddleware.WithRateLimit(func() (float64, int) {
+		snap := rt.Current()
+		return snap.RateLimit, snap.RateBurst
+	}))
 
 	for _, route := range routes {
-		var midds []echo.MiddlewareFunc
-		midds = append(midds, jwtDecoder)
+		limit := route.BodyLimit
+		if limit == 0 {
+			limit = cfg.BodyLimit
+		}
+		midds := []echo.MiddlewareFunc{apimiddleware.WithBodyLimit(limit)}
+		if !route.Public {
+			auth := jwtDecoder
+			if route.AllowService {
+				auth = apimiddleware.WithServiceIdentityOr(jwtDecoder)
+			}
+			midds = append(midds, apimiddleware.WithNoStore(), auth)
+		}
 		midds = append(midds, route.Middlewares...)
 		e.Add(route.Method, route.Path, route.Handler, midds...)
 	}
 
 	return &Server{e}
 }
+
+// secureConfig sets the security headers.
+// The responses are data, not documents, so the content security policy allows nothing to be loaded.
+// HSTS is only sent over HTTPS.
+func secureConfig(cfg *config.HTTP) middleware.SecureConfig {
+	sc := middleware.SecureConfig{
+		ContentTypeNosniff:    "nosniff",
+		ContentSecurityPolicy: "default-src 'none'; frame-ancestors " + cfg.FrameAncestors,
+		HSTSMaxAge:            int(cfg.HSTSMaxAge.Seconds()),
+		HSTSExcludeSubdomains: !cfg.HSTSIncludeSubdomains,
+		ReferrerPolicy:        cfg.ReferrerPolicy,
+	}
+	switch cfg.FrameAncestors {
+	case "'none'":
+		sc.XFrameOptions = "DENY"
+	case "'self'":
+		sc.XFrameOptions = "SAMEORIGIN"
+	}
+	return sc
+}
+
+// ipExtractor takes the client IP, which the rate limit is keyed on, from the connection.
+// It is only taken from X-Forwarded-For if the request comes through the trusted proxies,
+// so a client can't get a fresh rate limit by sending a forged header.
+func ipExtractor(cfg *config.HTTP) echo.IPExtractor {
+	ranges, _ := config.ParseCIDRs(cfg.TrustedProxies)
+	if len(ranges) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, r := range ranges {
+		options = append(options, echo.TrustIPRange(r))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+func logLevel(level string) log.Lvl {
+	switch level {
+	case "debug":
+		return log.DEBUG
+	case "warn":
+		return log.WARN
+	case "error":
+		return log.ERROR
+	case "off":
+		return log.OFF
+	default:
+		return log.INFO
+	}
+}
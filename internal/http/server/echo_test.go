@@ -1,15 +1,26 @@
 package server_test
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/internal/config"
 	"github.com/indrasaputra/orvosi-api/internal/http/handler"
 	"github.com/indrasaputra/orvosi-api/internal/http/middleware"
 	"github.com/indrasaputra/orvosi-api/internal/http/router"
 	"github.com/indrasaputra/orvosi-api/internal/http/server"
+	"github.com/indrasaputra/orvosi-api/internal/settings"
 	"github.com/indrasaputra/orvosi-api/internal/tool"
 	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -23,15 +34,305 @@ func TestNewServer(t *testing.T) {
 	})
 }
 
+func TestServer_PublicRoute(t *testing.T) {
+	t.Run("public route skips authorization while the others don't", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		u := mock_usecase.NewMockCheckHealth(ctrl)
+		u.EXPECT().Check(gomock.Any()).Return(nil)
+		routes := append(router.HealthChecker(handler.NewHealthChecker(u)), router.MedicalRecordCreator(createMedicalRecordCreator(ctrl))...)
+		d := tool.NewIDTokenDecoder("audience")
+		srv := server.NewServer(middleware.WithJWTDecoder(d.Decode), routes, createSettings(config.Runtime{PageSize: 10, LogLevel: "info", CORSAllowOrigins: "*"}), createHTTPConfig())
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/medical-records", nil))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestServer_ServiceRoute(t *testing.T) {
+	t.Run("internal service calls the route which allows it without a user", func(t *testing.T) {
+		rt := createSettings(config.Runtime{PageSize: 10, LogLevel: "info", AdminEmails: "admin@orvosi.com"})
+		d := tool.NewIDTokenDecoder("audience")
+		srv := server.NewServer(middleware.WithJWTDecoder(d.Decode), router.AdminConfig(handler.NewAdminConfig(rt)), rt, createHTTPConfig())
+		srv.Use(middleware.WithClientIdentity(map[string]string{"monitoring.internal": "monitoring"}))
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/config", nil)
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: "monitoring.internal"}}
+		req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/config", nil))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestServer_HTTPErrorHandler(t *testing.T) {
+	t.Run("error returned by middleware is written by the error handler", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		srv := createServer(ctrl)
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/medical-records", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, `{"errors":[{"code":"01-002","message":"Request is unauthorized"}],"meta":null}`+"\n", rec.Body.String())
+	})
+
+	t.Run("error is written in the language asked by the client", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		srv := createServer(ctrl)
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/medical-records", nil)
+		req.Header.Set("Accept-Language", "id-ID, en;q=0.8")
+		srv.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "id", rec.Header().Get("Content-Language"))
+		assert.Equal(t, `{"errors":[{"code":"01-002","message":"Permintaan tidak terotorisasi"}],"meta":null}`+"\n", rec.Body.String())
+	})
+
+	t.Run("unknown route keeps the status of echo", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		srv := createServer(ctrl)
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestServer_RuntimeSettings(t *testing.T) {
+	t.Run("requests above the rate limit are rejected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		srv := createServerWithSettings(ctrl, createSettings(config.Runtime{PageSize: 10, LogLevel: "info", CORSAllowOrigins: "*", RateLimit: 1, RateBurst: 1}))
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/medical-records", nil))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		rec = httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/medical-records", nil))
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, `{"errors":[{"code":"01-013","message":"Too many requests. Please, try again later"}],"meta":null}`+"\n", rec.Body.String())
+	})
+
+	t.Run("forged forwarded header doesn't bypass the rate limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		srv := createServerWithSettings(ctrl, createSettings(config.Runtime{PageSize: 10, LogLevel: "info", CORSAllowOrigins: "*", RateLimit: 1, RateBurst: 1}))
+
+		for i, ip := range []string{"203.0.113.1", "203.0.113.2"} {
+			req := httptest.NewRequest(http.MethodPost, "/medical-records", nil)
+			req.Header.Set(echo.HeaderXForwardedFor, ip)
+			req.Header.Set(echo.HeaderXRealIP, ip)
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+			if i == 0 {
+				assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			} else {
+				assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+			}
+		}
+	})
+
+	t.Run("client behind the trusted proxy is limited by its forwarded ip", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := createHTTPConfig()
+		cfg.TrustedProxies = "192.0.2.0/24"
+		d := tool.NewIDTokenDecoder("audience")
+		rt := createSettings(config.Runtime{PageSize: 10, LogLevel: "info", CORSAllowOrigins: "*", RateLimit: 1, RateBurst: 1})
+		srv := server.NewServer(middleware.WithJWTDecoder(d.Decode), router.MedicalRecordCreator(createMedicalRecordCreator(ctrl)), rt, cfg)
+
+		for _, ip := range []string{"203.0.113.1", "203.0.113.2"} {
+			req := httptest.NewRequest(http.MethodPost, "/medical-records", nil)
+			req.Header.Set(echo.HeaderXForwardedFor, ip)
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		}
+	})
+
+	t.Run("allowed origins follow the reloaded settings", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		initial := config.Runtime{PageSize: 10, LogLevel: "info", CORSAllowOrigins: "https://orvosi.com"}
+		next := config.Runtime{PageSize: 10, LogLevel: "debug", CORSAllowOrigins: "https://orvosi.com, https://admin.orvosi.com"}
+		rt := createSettings(initial, next)
+		srv := createServerWithSettings(ctrl, rt)
+
+		preflight := func() string {
+			req := httptest.NewRequest(http.MethodOptions, "/medical-records", nil)
+			req.Header.Set("Origin", "https://admin.orvosi.com")
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+			return rec.Header().Get("Access-Control-Allow-Origin")
+		}
+
+		assert.Empty(t, preflight())
+		assert.Equal(t, log.INFO, srv.Logger.Level())
+
+		changed, err := rt.Reload()
+		assert.True(t, changed)
+		assert.Nil(t, err)
+
+		assert.Equal(t, "https://admin.orvosi.com", preflight())
+		assert.Equal(t, log.DEBUG, srv.Logger.Level())
+	})
+}
+
+func TestServer_SecurityHeaders(t *testing.T) {
+	t.Run("security headers are set and hsts is only sent over https", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		srv := createServer(ctrl)
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/medical-records", nil))
+
+		assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "DENY", rec.Header().Get("X-Frame-Options"))
+		assert.Equal(t, "default-src 'none'; frame-ancestors 'none'", rec.Header().Get("Content-Security-Policy"))
+		assert.Equal(t, "no-referrer", rec.Header().Get("Referrer-Policy"))
+		assert.Empty(t, rec.Header().Get("Strict-Transport-Security"))
+
+		rec = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/medical-records", nil)
+		req.Header.Set("X-Forwarded-Proto", "https")
+		srv.ServeHTTP(rec, req)
+
+		assert.Equal(t, "max-age=3600; includeSubdomains", rec.Header().Get("Strict-Transport-Security"))
+	})
+
+	t.Run("only the response of route which needs authorization is not stored", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		u := mock_usecase.NewMockCheckHealth(ctrl)
+		u.EXPECT().Check(gomock.Any()).Return(nil)
+		routes := append(router.HealthChecker(handler.NewHealthChecker(u)), router.MedicalRecordCreator(createMedicalRecordCreator(ctrl))...)
+		d := tool.NewIDTokenDecoder("audience")
+		srv := server.NewServer(middleware.WithJWTDecoder(d.Decode), routes, createSettings(config.Runtime{PageSize: 10, LogLevel: "info"}), createHTTPConfig())
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+		assert.Empty(t, rec.Header().Get("Cache-Control"))
+
+		rec = httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/medical-records", nil))
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	})
+
+	t.Run("preflight uses the configured methods and headers", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		srv := createServerWithSettings(ctrl, createSettings(config.Runtime{PageSize: 10, LogLevel: "info", CORSAllowOrigins: "https://orvosi.com"}))
+
+		req := httptest.NewRequest(http.MethodOptions, "/medical-records", nil)
+		req.Header.Set("Origin", "https://orvosi.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+
+		assert.Equal(t, "https://orvosi.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET,POST", rec.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Authorization,Content-Type", rec.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+	})
+}
+
+func TestServer_BodyLimit(t *testing.T) {
+	t.Run("body larger than the limit of the route is rejected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		srv := createServer(ctrl)
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/medical-records", strings.NewReader(`{"symptom":"a long symptom"}`)))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Equal(t, `{"errors":[{"code":"01-014","message":"Request body is too large"}],"meta":null}`+"\n", rec.Body.String())
+	})
+
+	t.Run("route has its own limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		routes := router.MedicalRecordCreator(createMedicalRecordCreator(ctrl))
+		routes[0].BodyLimit = 1024
+		d := tool.NewIDTokenDecoder("audience")
+		srv := server.NewServer(middleware.WithJWTDecoder(d.Decode), routes, createSettings(config.Runtime{PageSize: 10, LogLevel: "info"}), createHTTPConfig())
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/medical-records", strings.NewReader(`{"symptom":"a long symptom"}`)))
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
 func createMedicalRecordCreator(ctrl *gomock.Controller) *handler.MedicalRecordCreator {
 	m := mock_usecase.NewMockCreateMedicalRecord(ctrl)
 	return handler.NewMedicalRecordCreator(m)
 }
 
 func createServer(ctrl *gomock.Controller) *server.Server {
+	return createServerWithSettings(ctrl, createSettings(config.Runtime{PageSize: 10, LogLevel: "info", CORSAllowOrigins: "*"}))
+}
+
+func createServerWithSettings(ctrl *gomock.Controller, rt *settings.Settings) *server.Server {
 	c := createMedicalRecordCreator(ctrl)
 	r := router.MedicalRecordCreator(c)
 	d := tool.NewIDTokenDecoder("audience")
 	m := middleware.WithJWTDecoder(d.Decode)
-	return server.NewServer(m, r)
+	return server.NewServer(m, r, rt, createHTTPConfig())
+}
+
+func createHTTPConfig() *config.HTTP {
+	return &config.HTTP{
+		CORSAllowMethods:      "GET,POST",
+		CORSAllowHeaders:      "Authorization,Content-Type",
+		CORSExposeHeaders:     "Content-Language",
+		CORSMaxAge:            10 * time.Minute,
+		HSTSMaxAge:            time.Hour,
+		HSTSIncludeSubdomains: true,
+		FrameAncestors:        "'none'",
+		ReferrerPolicy:        "no-referrer",
+		BodyLimit:             16,
+	}
+}
+
+// createSettings creates settings which are reloaded to next, if it is set, on every reload.
+func createSettings(initial config.Runtime, next ...config.Runtime) *settings.Settings {
+	return settings.NewSettings(initial, func() (config.Runtime, error) {
+		if len(next) == 0 {
+			return initial, nil
+		}
+		return next[0], nil
+	})
 }
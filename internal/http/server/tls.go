@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"golang.org/x/net/http2"
+)
+
+var (
+	tlsVersions = map[string]uint16{
+		"1.2": tls.VersionTLS12,
+		"1.3": tls.VersionTLS13,
+	}
+	clientAuthTypes = map[string]tls.ClientAuthType{
+		"none":     tls.NoClientCert,
+		"optional": tls.VerifyClientCertIfGiven,
+		"require":  tls.RequireAndVerifyClientCert,
+	}
+)
+
+// CertificateReloader serves the certificate of the server
+// and loads it again when its files change, so a renewed certificate is used without restart.
+type CertificateReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	version string
+}
+
+// NewCertificateReloader creates an instance of CertificateReloader.
+// It fails if the certificate can't be loaded.
+func NewCertificateReloader(certFile, keyFile string) (*CertificateReloader, error) {
+	cr := &CertificateReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	if _, err := cr.Reload(); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+// GetCertificate returns the certificate in use. It is meant for tls.Config.
+func (cr *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	return cr.cert, nil
+}
+
+// Reload loads the certificate if its files have changed since the last load.
+// The current certificate is kept if the new one can't be loaded,
+// e.g. the certificate has been written but its key hasn't.
+// It returns whether a new certificate is applied.
+func (cr *CertificateReloader) Reload() (bool, error) {
+	version := config.FileVersion(cr.certFile) + "|" + config.FileVersion(cr.keyFile)
+
+	cr.mu.RLock()
+	unchanged := cr.cert != nil && version == cr.version
+	cr.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cr.certFile, cr.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("load certificate %s: %w", cr.certFile, err)
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.cert, cr.version = &cert, version
+	return true, nil
+}
+
+// Watch checks the files every interval and reloads the certificate when they change.
+// It runs until the context is done.
+func (cr *CertificateReloader) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			applied, err := cr.Reload()
+			switch {
+			case err != nil:
+				log.Printf("[TLS-Reload] keep the current certificate: %v", err)
+			case applied:
+				log.Printf("[TLS-Reload] apply %s", cr.certFile)
+			}
+		}
+	}
+}
+
+// NewTLSConfig creates the TLS config of the server using the certificate served by certs.
+// The client certificate is asked and verified against the client CA if the client authentication is enabled.
+func NewTLSConfig(cfg *config.TLS, certs *CertificateReloader) (*tls.Config, error) {
+	tc := &tls.Config{
+		MinVersion:     tlsVersions[cfg.MinVersion],
+		GetCertificate: certs.GetCertificate,
+		NextProtos:     []string{http2.NextProtoTLS, "http/1.1"},
+		ClientAuth:     clientAuthTypes[cfg.ClientAuth],
+	}
+	if tc.ClientAuth == tls.NoClientCert {
+		return tc, nil
+	}
+
+	pem, err := ioutil.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("read client CA %s: %w", cfg.ClientCAFile, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("client CA %s has no certificate", cfg.ClientCAFile)
+	}
+	tc.ClientCAs = pool
+	return tc, nil
+}
+
+// StartTLSConfig starts HTTPS server, which also serves HTTP/2, using the TLS config.
+func (s *Server) StartTLSConfig(address string, tc *tls.Config) error {
+	s.TLSServer.Addr = address
+	s.TLSServer.TLSConfig = tc
+	return s.StartServer(s.TLSServer)
+}
+
+// StartH2C starts HTTP server which also serves HTTP/2 without TLS.
+func (s *Server) StartH2C(address string) error {
+	return s.StartH2CServer(address, &http2.Server{})
+}
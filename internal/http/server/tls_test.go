@@ -0,0 +1,297 @@
+package server_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/http/handler"
+	"github.com/indrasaputra/orvosi-api/internal/http/middleware"
+	"github.com/indrasaputra/orvosi-api/internal/http/router"
+	"github.com/indrasaputra/orvosi-api/internal/http/server"
+	"github.com/indrasaputra/orvosi-api/internal/tool"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
+)
+
+func TestNewCertificateReloader(t *testing.T) {
+	t.Run("certificate files are missing", func(t *testing.T) {
+		dir := t.TempDir()
+
+		certs, err := server.NewCertificateReloader(filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key"))
+
+		assert.NotNil(t, err)
+		assert.Nil(t, certs)
+	})
+
+	t.Run("successfully load the certificate", func(t *testing.T) {
+		ca := createCA(t)
+		certFile, keyFile := ca.writePair(t, t.TempDir(), "server", "localhost")
+
+		certs, err := server.NewCertificateReloader(certFile, keyFile)
+		assert.Nil(t, err)
+
+		cert, err := certs.GetCertificate(nil)
+		assert.Nil(t, err)
+		assert.NotNil(t, cert)
+	})
+}
+
+func TestCertificateReloader_Reload(t *testing.T) {
+	t.Run("certificate is reloaded only when its files change", func(t *testing.T) {
+		ca := createCA(t)
+		dir := t.TempDir()
+		certFile, keyFile := ca.writePair(t, dir, "server", "localhost")
+		certs, err := server.NewCertificateReloader(certFile, keyFile)
+		assert.Nil(t, err)
+		old, _ := certs.GetCertificate(nil)
+
+		applied, err := certs.Reload()
+		assert.False(t, applied)
+		assert.Nil(t, err)
+
+		ca.writePair(t, dir, "server", "renewed.localhost")
+		touch(t, certFile, keyFile)
+
+		applied, err = certs.Reload()
+		assert.True(t, applied)
+		assert.Nil(t, err)
+		cert, _ := certs.GetCertificate(nil)
+		assert.NotEqual(t, old.Certificate[0], cert.Certificate[0])
+	})
+
+	t.Run("invalid certificate keeps the current one", func(t *testing.T) {
+		ca := createCA(t)
+		dir := t.TempDir()
+		certFile, keyFile := ca.writePair(t, dir, "server", "localhost")
+		certs, err := server.NewCertificateReloader(certFile, keyFile)
+		assert.Nil(t, err)
+		old, _ := certs.GetCertificate(nil)
+
+		assert.Nil(t, ioutil.WriteFile(keyFile, []byte("broken"), 0600))
+		touch(t, keyFile)
+
+		applied, err := certs.Reload()
+		assert.False(t, applied)
+		assert.NotNil(t, err)
+		cert, _ := certs.GetCertificate(nil)
+		assert.Equal(t, old, cert)
+	})
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	t.Run("client CA is missing", func(t *testing.T) {
+		ca := createCA(t)
+		certFile, keyFile := ca.writePair(t, t.TempDir(), "server", "localhost")
+		certs, _ := server.NewCertificateReloader(certFile, keyFile)
+
+		cfg := &config.TLS{MinVersion: "1.2", ClientAuth: "require", ClientCAFile: filepath.Join(t.TempDir(), "ca.crt")}
+		tc, err := server.NewTLSConfig(cfg, certs)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, tc)
+	})
+
+	t.Run("client certificate is required and verified", func(t *testing.T) {
+		ca := createCA(t)
+		dir := t.TempDir()
+		certFile, keyFile := ca.writePair(t, dir, "server", "localhost")
+		certs, _ := server.NewCertificateReloader(certFile, keyFile)
+
+		cfg := &config.TLS{MinVersion: "1.3", ClientAuth: "require", ClientCAFile: ca.writeCert(t, dir)}
+		tc, err := server.NewTLSConfig(cfg, certs)
+
+		assert.Nil(t, err)
+		assert.Equal(t, uint16(tls.VersionTLS13), tc.MinVersion)
+		assert.Equal(t, tls.RequireAndVerifyClientCert, tc.ClientAuth)
+		assert.NotNil(t, tc.ClientCAs)
+		assert.Contains(t, tc.NextProtos, "h2")
+	})
+}
+
+func TestServer_StartTLSConfig(t *testing.T) {
+	t.Run("internal caller is identified by its client certificate over http/2", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ca := createCA(t)
+		dir := t.TempDir()
+		certFile, keyFile := ca.writePair(t, dir, "server", "localhost")
+		certs, err := server.NewCertificateReloader(certFile, keyFile)
+		assert.Nil(t, err)
+		tc, err := server.NewTLSConfig(&config.TLS{MinVersion: "1.2", ClientAuth: "optional", ClientCAFile: ca.writeCert(t, dir)}, certs)
+		assert.Nil(t, err)
+
+		u := mock_usecase.NewMockCheckHealth(ctrl)
+		u.EXPECT().Check(gomock.Any()).Return(nil).AnyTimes()
+		srv := server.NewServer(middleware.WithJWTDecoder(tool.NewIDTokenDecoder("audience").Decode), router.HealthChecker(handler.NewHealthChecker(u)),
+			createSettings(config.Runtime{PageSize: 10, LogLevel: "off"}), createHTTPConfig())
+		srv.HideBanner, srv.HidePort = true, true
+		var identity string
+		srv.Use(middleware.WithClientIdentity(map[string]string{"billing.internal": "billing"}))
+		srv.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(ctx echo.Context) error {
+				identity, _ = middleware.ServiceIdentity(ctx.Request().Context())
+				return next(ctx)
+			}
+		})
+
+		addr := freeAddress(t)
+		go srv.StartTLSConfig(addr, tc)
+		defer srv.Shutdown(context.Background())
+
+		billing := ca.client(t, dir, "billing.internal")
+		res := waitForServer(t, billing, "https://"+addr+"/health")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, 2, res.ProtoMajor)
+		assert.Equal(t, "billing", identity)
+
+		res, err = ca.client(t, dir, "unknown.internal").Get("https://" + addr + "/health")
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+}
+
+func TestServer_StartH2C(t *testing.T) {
+	t.Run("http/2 is served without tls", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		u := mock_usecase.NewMockCheckHealth(ctrl)
+		u.EXPECT().Check(gomock.Any()).Return(nil).AnyTimes()
+		srv := server.NewServer(middleware.WithJWTDecoder(tool.NewIDTokenDecoder("audience").Decode), router.HealthChecker(handler.NewHealthChecker(u)),
+			createSettings(config.Runtime{PageSize: 10, LogLevel: "off"}), createHTTPConfig())
+		srv.HideBanner, srv.HidePort = true, true
+
+		addr := freeAddress(t)
+		go srv.StartH2C(addr)
+		defer srv.Shutdown(context.Background())
+
+		transport := &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+		}
+		res := waitForServer(t, &http.Client{Transport: transport, Timeout: 5 * time.Second}, "http://"+addr+"/health")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, 2, res.ProtoMajor)
+	})
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func createCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "orvosi test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+	return &testCA{cert: cert, key: key, der: der}
+}
+
+func (ca *testCA) writeCert(t *testing.T, dir string) string {
+	file := filepath.Join(dir, "ca.crt")
+	assert.Nil(t, ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.der}), 0600))
+	return file
+}
+
+// writePair writes the certificate signed by the CA and its key as name.crt and name.key.
+func (ca *testCA) writePair(t *testing.T, dir, name, commonName string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	assert.Nil(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	assert.Nil(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.Nil(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
+
+// client creates HTTP/2 client which trusts the CA and presents a client certificate with the common name.
+func (ca *testCA) client(t *testing.T, dir, commonName string) *http.Client {
+	certFile, keyFile := ca.writePair(t, dir, commonName, commonName)
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	assert.Nil(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	transport := &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: pool, Certificates: []tls.Certificate{cert}},
+		ForceAttemptHTTP2: true,
+	}
+	return &http.Client{Transport: transport, Timeout: 5 * time.Second}
+}
+
+// touch moves the modification time of the files forward, so the change is seen even within the resolution of the filesystem.
+func touch(t *testing.T, files ...string) {
+	later := time.Now().Add(time.Minute)
+	for _, file := range files {
+		assert.Nil(t, os.Chtimes(file, later, later))
+	}
+}
+
+func freeAddress(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func waitForServer(t *testing.T, client *http.Client, url string) *http.Response {
+	var lastErr error
+	for i := 0; i < 50; i++ {
+		res, err := client.Get(url)
+		if err == nil {
+			return res
+		}
+		lastErr = err
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("server isn't ready: %v", lastErr)
+	return nil
+}
@@ -0,0 +1,4 @@
+// Package i18n provides the translation of the messages exposed to the user.
+// The messages are kept in a catalog per language, keyed by error code.
+// The reasons of invalid params are kept apart per language, keyed by validation rule.
+package i18n
@@ -0,0 +1,130 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+const (
+	// DefaultLanguage is used when the user doesn't ask for any supported language.
+	DefaultLanguage = "en"
+
+	catalogDir = "catalog"
+	reasonDir  = "reasons"
+
+	// reasonParam is replaced by the parameter of the rule in the translated reason.
+	reasonParam = "{param}"
+)
+
+//go:embed catalog/*.json reasons/*.json
+var catalogFiles embed.FS
+
+var (
+	// catalogs maps language onto its catalog.
+	// Each catalog maps error code onto the translated message.
+	catalogs = mustLoadCatalogs(catalogDir)
+	// reasons maps language onto the reasons of invalid params.
+	// Each of them maps validation rule onto the translated reason.
+	reasons = mustLoadCatalogs(reasonDir)
+)
+
+// Languages returns the supported languages, sorted.
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Catalog returns a copy of the messages in the language, keyed by error code.
+// It returns nil if the language isn't supported.
+func Catalog(lang string) map[string]string {
+	catalog, ok := catalogs[lang]
+	if !ok {
+		return nil
+	}
+	res := make(map[string]string, len(catalog))
+	for code, message := range catalog {
+		res[code] = message
+	}
+	return res
+}
+
+// Reasons returns a copy of the reasons of invalid params in the language, keyed by validation rule.
+// It returns nil if the language isn't supported.
+func Reasons(lang string) map[string]string {
+	reason, ok := reasons[lang]
+	if !ok {
+		return nil
+	}
+	res := make(map[string]string, len(reason))
+	for rule, message := range reason {
+		res[rule] = message
+	}
+	return res
+}
+
+// Localize returns a copy of the error with its message and the reasons of its invalid params in the language.
+// The error is returned as it is if the language has no translation of the code nor of any rule.
+func Localize(lang string, err *entity.Error) *entity.Error {
+	message, ok := catalogs[lang][err.Code]
+	if !ok {
+		message = err.Message
+	}
+	params, translated := localizeParams(lang, err.InvalidParams)
+	if message == err.Message && !translated {
+		return err
+	}
+
+	res := entity.WithMessage(err, message)
+	res.InvalidParams = params
+	return res
+}
+
+// localizeParams returns a copy of the invalid params with their reasons in the language.
+// The reason whose rule has no translation is kept.
+func localizeParams(lang string, params []entity.InvalidParam) ([]entity.InvalidParam, bool) {
+	res := make([]entity.InvalidParam, 0, len(params))
+	translated := false
+	for _, param := range params {
+		if reason, ok := reasons[lang][param.Rule]; ok {
+			reason = strings.ReplaceAll(reason, reasonParam, param.Param)
+			translated = translated || reason != param.Reason
+			param.Reason = reason
+		}
+		res = append(res, param)
+	}
+	if !translated {
+		return params, false
+	}
+	return res, true
+}
+
+func mustLoadCatalogs(dir string) map[string]map[string]string {
+	entries, err := catalogFiles.ReadDir(dir)
+	if err != nil {
+		panic(fmt.Sprintf("i18n: read %s: %v", dir, err))
+	}
+
+	res := make(map[string]map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := catalogFiles.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: read catalog %s: %v", entry.Name(), err))
+		}
+		var catalog map[string]string
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: decode catalog %s: %v", entry.Name(), err))
+		}
+		res[strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))] = catalog
+	}
+	return res
+}
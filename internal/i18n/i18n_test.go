@@ -0,0 +1,142 @@
+package i18n_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguages(t *testing.T) {
+	t.Run("english, hungarian, and indonesian are supported", func(t *testing.T) {
+		assert.Equal(t, []string{"en", "hu", "id"}, i18n.Languages())
+	})
+}
+
+func TestCatalog(t *testing.T) {
+	codes := parseEntityErrors(t)
+
+	t.Run("every error code is translated in every catalog", func(t *testing.T) {
+		for _, lang := range i18n.Languages() {
+			catalog := i18n.Catalog(lang)
+			for code := range codes {
+				assert.NotEmpty(t, catalog[code], "%s has no translation of %s", lang, code)
+			}
+			for code := range catalog {
+				assert.Contains(t, codes, code, "%s translates unknown code %s", lang, code)
+			}
+		}
+	})
+
+	t.Run("english catalog matches the messages in entity", func(t *testing.T) {
+		assert.Equal(t, codes, i18n.Catalog(i18n.DefaultLanguage))
+	})
+
+	t.Run("unsupported language has no catalog", func(t *testing.T) {
+		assert.Nil(t, i18n.Catalog("xx"))
+	})
+}
+
+func TestReasons(t *testing.T) {
+	t.Run("every rule is translated in every language", func(t *testing.T) {
+		rules := i18n.Reasons(i18n.DefaultLanguage)
+		assert.NotEmpty(t, rules)
+		for _, lang := range i18n.Languages() {
+			reasons := i18n.Reasons(lang)
+			assert.Len(t, reasons, len(rules), lang)
+			for rule := range rules {
+				assert.NotEmpty(t, reasons[rule], "%s has no reason of %s", lang, rule)
+			}
+		}
+	})
+
+	t.Run("unsupported language has no reasons", func(t *testing.T) {
+		assert.Nil(t, i18n.Reasons("xx"))
+	})
+}
+
+func TestLocalize(t *testing.T) {
+	t.Run("message is translated and the rest is kept", func(t *testing.T) {
+		ori := entity.WrapError(entity.ErrMedicalRecordNotFound, "internal")
+
+		err := i18n.Localize("id", ori)
+		assert.Equal(t, "Rekam medis tidak ditemukan", err.Message)
+		assert.Equal(t, ori.Code, err.Code)
+		assert.Equal(t, ori.Kind, err.Kind)
+		assert.Equal(t, ori.Error(), err.Error())
+		assert.Equal(t, "Medical record not found", entity.ErrMedicalRecordNotFound.Message)
+	})
+
+	t.Run("reasons of invalid params are translated with their param", func(t *testing.T) {
+		ori := entity.WithInvalidParams(entity.ErrInvalidMedicalRecordAttribute, []entity.InvalidParam{
+			{Name: "symptom", Rule: "required", Reason: "must not be blank"},
+			{Name: "symptom", Rule: "max", Param: "10", Reason: "must be at most 10 characters"},
+			{Name: "symptom", Rule: "unknown", Reason: "is unknown"},
+		})
+
+		err := i18n.Localize("hu", ori)
+		assert.Equal(t, "nem lehet üres", err.InvalidParams[0].Reason)
+		assert.Equal(t, "legfeljebb 10 karakter hosszú lehet", err.InvalidParams[1].Reason)
+		assert.Equal(t, "is unknown", err.InvalidParams[2].Reason)
+		assert.Equal(t, "must not be blank", ori.InvalidParams[0].Reason)
+	})
+
+	t.Run("english reasons are the same as the reasons of the rules", func(t *testing.T) {
+		ori := entity.WithInvalidParams(entity.ErrInvalidMedicalRecordAttribute, []entity.InvalidParam{
+			{Name: "symptom", Rule: "required", Reason: "must not be blank"},
+			{Name: "symptom", Rule: "min", Param: "2", Reason: "must be at least 2 characters"},
+			{Name: "symptom", Rule: "oneof", Param: "male, female", Reason: "must be one of male, female"},
+		})
+
+		assert.Same(t, ori, i18n.Localize("en", ori))
+	})
+
+	t.Run("english returns the same error", func(t *testing.T) {
+		assert.Same(t, entity.ErrMedicalRecordNotFound, i18n.Localize("en", entity.ErrMedicalRecordNotFound))
+	})
+
+	t.Run("unsupported language or unknown code returns the same error", func(t *testing.T) {
+		unknown := entity.NewError(entity.KindInternal, "99-999", "unknown")
+
+		assert.Same(t, entity.ErrMedicalRecordNotFound, i18n.Localize("xx", entity.ErrMedicalRecordNotFound))
+		assert.Same(t, unknown, i18n.Localize("hu", unknown))
+	})
+}
+
+// parseEntityErrors reads every NewError call in entity/error.go and maps its code onto its message,
+// so a new error can't be added without its translations.
+func parseEntityErrors(t *testing.T) map[string]string {
+	file, err := parser.ParseFile(token.NewFileSet(), "../../entity/error.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parse entity errors: %v", err)
+	}
+
+	res := map[string]string{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) != 3 {
+			return true
+		}
+		if fn, ok := call.Fun.(*ast.Ident); !ok || fn.Name != "NewError" {
+			return true
+		}
+		code, cok := call.Args[1].(*ast.BasicLit)
+		message, mok := call.Args[2].(*ast.BasicLit)
+		if !cok || !mok {
+			return true
+		}
+		c, _ := strconv.Unquote(code.Value)
+		m, _ := strconv.Unquote(message.Value)
+		res[c] = m
+		return true
+	})
+	if len(res) == 0 {
+		t.Fatal("no error is found in entity")
+	}
+	return res
+}
@@ -0,0 +1,4 @@
+// Package lifecycle coordinates the shutdown of the application.
+// The components, such as HTTP server, background workers, and database pool, register how they stop,
+// and they are stopped in the reverse order of their registration.
+package lifecycle
@@ -0,0 +1,120 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// StopFunc stops a component. It must return once the context is done.
+type StopFunc func(ctx context.Context) error
+
+type component struct {
+	name string
+	stop StopFunc
+}
+
+// Manager stops the registered components when the application shuts down.
+type Manager struct {
+	mu         sync.Mutex
+	components []component
+	draining   int32
+}
+
+// NewManager creates an instance of Manager.
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Register adds a component which is stopped on shutdown.
+// The components are stopped in the reverse order of their registration,
+// so a component is stopped before the components it depends on.
+func (m *Manager) Register(name string, stop StopFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.components = append(m.components, component{name: name, stop: stop})
+}
+
+// Go runs the background worker until shutdown.
+// Its context is canceled when it is stopped, and the shutdown waits until it returns.
+func (m *Manager) Go(name string, run func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run(ctx)
+	}()
+
+	m.Register(name, func(stopCtx context.Context) error {
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-stopCtx.Done():
+			return stopCtx.Err()
+		}
+	})
+}
+
+// Draining tells whether the shutdown has started.
+// The application should report it isn't ready, so no new traffic is sent to it.
+func (m *Manager) Draining() bool {
+	return atomic.LoadInt32(&m.draining) == 1
+}
+
+// Shutdown marks the application as draining and waits for the drain period,
+// so the load balancer stops sending new traffic while the in-flight requests are still served.
+// Then, it stops the components in the reverse order of their registration.
+// Every component is stopped even if another one fails. All failures are returned at once.
+func (m *Manager) Shutdown(ctx context.Context, drain time.Duration) error {
+	atomic.StoreInt32(&m.draining, 1)
+	if drain > 0 {
+		log.Printf("[Lifecycle-Shutdown] draining for %s", drain)
+		timer := time.NewTimer(drain)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
+	m.mu.Lock()
+	components := append([]component{}, m.components...)
+	m.mu.Unlock()
+
+	var problems []string
+	for i := len(components) - 1; i >= 0; i-- {
+		c := components[i]
+		log.Printf("[Lifecycle-Shutdown] stopping %s", c.name)
+		if err := c.stop(ctx); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", c.name, err))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New("shutdown: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// WaitForSignal blocks until SIGINT or SIGTERM is received, then shuts down.
+// The components must stop within timeout after the drain period.
+func (m *Manager) WaitForSignal(drain, timeout time.Duration) error {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	sig := <-quit
+	log.Printf("[Lifecycle-Shutdown] received %s", sig)
+
+	ctx, cancel := context.WithTimeout(context.Background(), drain+timeout)
+	defer cancel()
+	return m.Shutdown(ctx, drain)
+}
@@ -0,0 +1,117 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/internal/lifecycle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewManager(t *testing.T) {
+	t.Run("successfully create an instance of Manager", func(t *testing.T) {
+		m := lifecycle.NewManager()
+
+		assert.NotNil(t, m)
+		assert.False(t, m.Draining())
+	})
+}
+
+func TestManager_Shutdown(t *testing.T) {
+	t.Run("components are stopped in the reverse order after draining", func(t *testing.T) {
+		m := lifecycle.NewManager()
+		var stopped []string
+		register := func(name string) {
+			m.Register(name, func(ctx context.Context) error {
+				assert.True(t, m.Draining())
+				stopped = append(stopped, name)
+				return nil
+			})
+		}
+		register("database")
+		register("worker")
+		register("http server")
+
+		err := m.Shutdown(context.Background(), time.Millisecond)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"http server", "worker", "database"}, stopped)
+	})
+
+	t.Run("every component is stopped even if one fails", func(t *testing.T) {
+		m := lifecycle.NewManager()
+		dbClosed := false
+		m.Register("database", func(ctx context.Context) error {
+			dbClosed = true
+			return nil
+		})
+		m.Register("http server", func(ctx context.Context) error {
+			return errors.New("in-flight requests remain")
+		})
+
+		err := m.Shutdown(context.Background(), 0)
+
+		assert.EqualError(t, err, "shutdown: http server: in-flight requests remain")
+		assert.True(t, dbClosed)
+	})
+
+	t.Run("background worker is canceled and waited", func(t *testing.T) {
+		m := lifecycle.NewManager()
+		finished := false
+		m.Go("worker", func(ctx context.Context) {
+			<-ctx.Done()
+			finished = true
+		})
+
+		err := m.Shutdown(context.Background(), 0)
+
+		assert.Nil(t, err)
+		assert.True(t, finished)
+	})
+
+	t.Run("background worker which doesn't stop in time", func(t *testing.T) {
+		m := lifecycle.NewManager()
+		block := make(chan struct{})
+		defer close(block)
+		m.Go("worker", func(ctx context.Context) {
+			<-block
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := m.Shutdown(ctx, time.Hour)
+
+		assert.EqualError(t, err, "shutdown: worker: context deadline exceeded")
+	})
+}
+
+func TestManager_WaitForSignal(t *testing.T) {
+	t.Run("SIGTERM shuts down the components", func(t *testing.T) {
+		m := lifecycle.NewManager()
+		stopped := make(chan struct{})
+		m.Register("http server", func(ctx context.Context) error {
+			close(stopped)
+			return nil
+		})
+
+		done := make(chan error)
+		go func() {
+			done <- m.WaitForSignal(0, time.Second)
+		}()
+
+		time.Sleep(20 * time.Millisecond)
+
+		assert.Nil(t, syscall.Kill(os.Getpid(), syscall.SIGTERM))
+		select {
+		case err := <-done:
+			assert.Nil(t, err)
+			<-stopped
+		case <-time.After(time.Second):
+			t.Fatal("manager doesn't shut down on SIGTERM")
+		}
+	})
+}
@@ -0,0 +1,3 @@
+// Package mail provides the senders of the emails the service sends to its users,
+// such as the token to verify a new email.
+package mail
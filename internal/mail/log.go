@@ -0,0 +1,26 @@
+package mail
+
+import (
+	"context"
+	"log"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// Log only writes the recipient and subject of the emails to the log instead of sending them.
+// The body is never written, since it carries the verification token.
+// It is meant for development, where there is no SMTP server.
+type Log struct {
+	from string
+}
+
+// NewLog creates an instance of Log.
+func NewLog(from string) *Log {
+	return &Log{from: from}
+}
+
+// Send writes the recipient and subject of the email to the log.
+func (l *Log) Send(ctx context.Context, to, subject, body string) *entity.Error {
+	log.Printf("[Mail] from: %s, to: %s, subject: %s, body: %d bytes", l.from, to, subject, len(body))
+	return nil
+}
@@ -0,0 +1,34 @@
+package mail_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/mail"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLog(t *testing.T) {
+	t.Run("successfully create an instance of Log", func(t *testing.T) {
+		sender := mail.NewLog("no-reply@orvosi.com")
+		assert.NotNil(t, sender)
+	})
+}
+
+func TestLog_Send(t *testing.T) {
+	t.Run("successfully write the email to the log without its body", func(t *testing.T) {
+		var buf bytes.Buffer
+		log.SetOutput(&buf)
+		defer log.SetOutput(os.Stderr)
+
+		sender := mail.NewLog("no-reply@orvosi.com")
+		err := sender.Send(context.Background(), "a@orvosi.com", "subject", "the token")
+
+		assert.Nil(t, err)
+		assert.Contains(t, buf.String(), "to: a@orvosi.com")
+		assert.NotContains(t, buf.String(), "the token")
+	})
+}
@@ -0,0 +1,63 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// SMTPConfig holds the configuration of SMTP.
+type SMTPConfig struct {
+	// Addr is the SMTP server in form of host:port.
+	Addr string
+	// From is the address which the emails are sent from.
+	From string
+	// Username and Password authenticate to the server using PLAIN auth. Leave them empty if it doesn't need authentication.
+	Username string
+	Password string
+}
+
+// SMTP sends the emails through an SMTP server.
+type SMTP struct {
+	cfg  SMTPConfig
+	auth smtp.Auth
+}
+
+// NewSMTP creates an instance of SMTP.
+func NewSMTP(cfg SMTPConfig) *SMTP {
+	s := &SMTP{cfg: cfg}
+	if cfg.Username != "" {
+		host, _, _ := net.SplitHostPort(cfg.Addr)
+		s.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, host)
+	}
+	return s
+}
+
+// Send sends the email as plain text.
+// The recipient and subject must not contain line breaks, so they can't inject another header.
+func (s *SMTP) Send(ctx context.Context, to, subject, body string) *entity.Error {
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return entity.WrapError(entity.ErrInternalServer, "[SMTP-Send] header contains line break")
+	}
+
+	if err := smtp.SendMail(s.cfg.Addr, s.auth, s.cfg.From, []string{to}, s.message(to, subject, body)); err != nil {
+		return entity.WrapError(entity.ErrInternalServer, "[SMTP-Send] send: "+err.Error())
+	}
+	return nil
+}
+
+func (s *SMTP) message(to, subject, body string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,99 @@
+package mail_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/mail"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSMTP(t *testing.T) {
+	t.Run("successfully create an instance of SMTP", func(t *testing.T) {
+		sender := mail.NewSMTP(mail.SMTPConfig{Addr: "localhost:25", Username: "user", Password: "pass"})
+		assert.NotNil(t, sender)
+	})
+}
+
+func TestSMTP_Send(t *testing.T) {
+	t.Run("header contains line break", func(t *testing.T) {
+		sender := mail.NewSMTP(mail.SMTPConfig{Addr: "localhost:25", From: "no-reply@orvosi.com"})
+
+		err := sender.Send(context.Background(), "a@orvosi.com\r\nBcc: b@orvosi.com", "subject", "body")
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+
+		err = sender.Send(context.Background(), "a@orvosi.com", "subject\nBcc: b@orvosi.com", "body")
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("server is unreachable", func(t *testing.T) {
+		ln, lerr := net.Listen("tcp", "127.0.0.1:0")
+		assert.Nil(t, lerr)
+		addr := ln.Addr().String()
+		ln.Close()
+
+		sender := mail.NewSMTP(mail.SMTPConfig{Addr: addr, From: "no-reply@orvosi.com"})
+		err := sender.Send(context.Background(), "a@orvosi.com", "subject", "body")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("successfully send the email", func(t *testing.T) {
+		ln, lerr := net.Listen("tcp", "127.0.0.1:0")
+		assert.Nil(t, lerr)
+		defer ln.Close()
+
+		received := make(chan string, 1)
+		go serveSMTP(ln, received)
+
+		sender := mail.NewSMTP(mail.SMTPConfig{Addr: ln.Addr().String(), From: "no-reply@orvosi.com"})
+		err := sender.Send(context.Background(), "a@orvosi.com", "subject", "line 1\nline 2")
+		assert.Nil(t, err)
+
+		data := <-received
+		assert.Contains(t, data, "From: no-reply@orvosi.com\r\n")
+		assert.Contains(t, data, "To: a@orvosi.com\r\n")
+		assert.Contains(t, data, "Subject: subject\r\n")
+		assert.Contains(t, data, "line 1\r\nline 2")
+	})
+}
+
+// serveSMTP accepts one connection and replies just enough of SMTP to receive one email.
+func serveSMTP(ln net.Listener, received chan<- string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250 localhost")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			data, _ := bufio.NewReader(tp.DotReader()).ReadString(0)
+			received <- strings.ReplaceAll(data, "\n", "\r\n")
+			tp.PrintfLine("250 ok")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("250 ok")
+		}
+	}
+}
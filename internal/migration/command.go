@@ -0,0 +1,87 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// Usage describes the migrate subcommand.
+const Usage = `usage: migrate <command>
+
+commands:
+  up                apply all pending migrations
+  down [steps]      roll back the last applied migrations (default 1)
+  status            list all migrations and their state
+  force <version>   mark the database as migrated up to the version without running any migration`
+
+// RunCommand runs the migrate subcommand using its args and writes the result to out.
+func RunCommand(ctx context.Context, m *Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(Usage)
+	}
+
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		n, err := m.Up(ctx)
+		fmt.Fprintf(out, "applied %d migration(s)\n", n)
+		return err
+
+	case args[0] == "down" && len(args) <= 2:
+		steps := 1
+		if len(args) == 2 {
+			var err error
+			if steps, err = strconv.Atoi(args[1]); err != nil {
+				return fmt.Errorf("steps %q is not a number", args[1])
+			}
+		}
+		n, err := m.Down(ctx, steps)
+		fmt.Fprintf(out, "rolled back %d migration(s)\n", n)
+		return err
+
+	case args[0] == "status" && len(args) == 1:
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		writeStatus(out, statuses)
+		return nil
+
+	case args[0] == "force" && len(args) == 2:
+		version, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("version %q is not a number", args[1])
+		}
+		if err := m.Force(ctx, version); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "forced version %d\n", version)
+		return nil
+	}
+	return errors.New(Usage)
+}
+
+func writeStatus(out io.Writer, statuses []*Status) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, st := range statuses {
+		state, appliedAt := "pending", "-"
+		if st.Applied {
+			state, appliedAt = "applied", st.AppliedAt.Format(time.RFC3339)
+		}
+		switch {
+		case st.Dirty:
+			state = "dirty"
+		case st.Applied && st.Name == "":
+			state = "missing file"
+		case st.Modified:
+			state = "modified"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", st.Version, st.Name, state, appliedAt)
+	}
+	w.Flush()
+}
@@ -0,0 +1,53 @@
+package migration_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/orvosi-api/internal/migration"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCommand(t *testing.T) {
+	t.Run("command or its argument is invalid", func(t *testing.T) {
+		tables := [][]string{{}, {"sideways"}, {"up", "1"}, {"down", "one"}, {"force"}, {"force", "-1"}}
+
+		for _, args := range tables {
+			exec := createMigratorExecutor()
+			var out bytes.Buffer
+
+			err := migration.RunCommand(context.Background(), exec.migrator, args, &out)
+			assert.NotNil(t, err)
+		}
+	})
+
+	t.Run("successfully print status", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		exec.expectLock(sqlmock.NewRows(historyColumns()).AddRow(1, exec.files[0].Checksum, false, time.Now()))
+		exec.expectUnlock()
+		var out bytes.Buffer
+
+		err := migration.RunCommand(context.Background(), exec.migrator, []string{"status"}, &out)
+
+		assert.Nil(t, err)
+		assert.Contains(t, out.String(), "create_user")
+		assert.Regexp(t, `2\s+create_medical_record\s+pending`, out.String())
+	})
+
+	t.Run("successfully apply pending migrations", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		exec.expectLock(sqlmock.NewRows(historyColumns()).
+			AddRow(1, exec.files[0].Checksum, false, time.Now()).
+			AddRow(2, exec.files[1].Checksum, false, time.Now()))
+		exec.expectUnlock()
+		var out bytes.Buffer
+
+		err := migration.RunCommand(context.Background(), exec.migrator, []string{"up"}, &out)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "applied 0 migration(s)\n", out.String())
+	})
+}
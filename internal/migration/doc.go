@@ -0,0 +1,6 @@
+// Package migration applies the embedded SQL migration files to the database.
+//
+// Applied versions are recorded in table schema_history along with the checksum of their up file,
+// so a migration file which is changed after being applied is detected.
+// All commands take a Postgres advisory lock, so only one runner migrates the database at a time.
+package migration
@@ -0,0 +1,60 @@
+package migration_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/indrasaputra/orvosi-api/db/migrations"
+	"github.com/indrasaputra/orvosi-api/internal/migration"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("file name or content is invalid", func(t *testing.T) {
+		tables := []fstest.MapFS{
+			{"create_user.up.sql": {Data: []byte("SELECT 1;")}},
+			{"000001_create_user.down.sql": {Data: []byte("SELECT 1;")}},
+			{
+				"000001_create_user.up.sql":  {Data: []byte("SELECT 1;")},
+				"000001_create_users.up.sql": {Data: []byte("SELECT 1;")},
+			},
+		}
+
+		for _, table := range tables {
+			res, err := migration.Load(table)
+			assert.NotNil(t, err)
+			assert.Nil(t, res)
+		}
+	})
+
+	t.Run("successfully load migrations sorted by version", func(t *testing.T) {
+		res, err := migration.Load(createMigrationFS())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+		assert.Equal(t, uint64(1), res[0].Version)
+		assert.Equal(t, "create_user", res[0].Name)
+		assert.Equal(t, "DROP TABLE users;", res[0].Down)
+		assert.Equal(t, 64, len(res[1].Checksum))
+		assert.Empty(t, res[1].Down)
+	})
+
+	t.Run("successfully load embedded migrations", func(t *testing.T) {
+		res, err := migration.Load(migrations.FS)
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, res)
+		for i, mig := range res {
+			assert.Equal(t, uint64(i+1), mig.Version)
+			assert.NotEmpty(t, mig.Down)
+		}
+	})
+}
+
+func createMigrationFS() fstest.MapFS {
+	return fstest.MapFS{
+		"000002_create_medical_record.up.sql": {Data: []byte("CREATE TABLE medical_records (id BIGSERIAL);")},
+		"000001_create_user.up.sql":           {Data: []byte("CREATE TABLE users (id BIGSERIAL);")},
+		"000001_create_user.down.sql":         {Data: []byte("DROP TABLE users;")},
+	}
+}
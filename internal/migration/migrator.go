@@ -0,0 +1,321 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+	"time"
+)
+
+// advisoryLockID is the key of Postgres advisory lock shared by all migration runners.
+const advisoryLockID int64 = 7308614093542756
+
+// Status is the state of a migration in the database.
+type Status struct {
+	Version   uint64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	// Dirty is true if the migration failed in the middle of being applied or rolled back.
+	Dirty bool
+	// Modified is true if the up file has changed since the migration was applied.
+	Modified bool
+}
+
+type record struct {
+	checksum  string
+	dirty     bool
+	appliedAt time.Time
+}
+
+// Migrator applies and rolls back migrations.
+// The migration files manage their own transaction,
+// so a migration is marked dirty before it runs and clean after it succeeds.
+type Migrator struct {
+	db         *sql.DB
+	migrations []*Migration
+}
+
+// NewMigrator creates an instance of Migrator using the migration files in fsys.
+func NewMigrator(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// Up applies all pending migrations in order of their version.
+// It returns the number of applied migrations.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	total := 0
+	err := m.withLock(ctx, func(conn *sql.Conn, records map[uint64]*record) error {
+		if err := m.check(records); err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := records[mig.Version]; ok {
+				continue
+			}
+			if _, err := conn.ExecContext(ctx, "INSERT INTO schema_history (version, name, checksum, dirty, applied_at) VALUES ($1, $2, $3, TRUE, $4)",
+				mig.Version, mig.Name, mig.Checksum, time.Now()); err != nil {
+				return fmt.Errorf("record migration %d: %v", mig.Version, err)
+			}
+			if _, err := conn.ExecContext(ctx, mig.Up); err != nil {
+				return fmt.Errorf("apply migration %d_%s: %v", mig.Version, mig.Name, err)
+			}
+			if _, err := conn.ExecContext(ctx, "UPDATE schema_history SET dirty = FALSE WHERE version = $1", mig.Version); err != nil {
+				return fmt.Errorf("record migration %d: %v", mig.Version, err)
+			}
+			total++
+		}
+		return nil
+	})
+	return total, err
+}
+
+// Down rolls back the last applied migrations as many as steps.
+// It returns the number of rolled back migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	if steps < 1 {
+		return 0, fmt.Errorf("steps must be at least 1")
+	}
+
+	total := 0
+	err := m.withLock(ctx, func(conn *sql.Conn, records map[uint64]*record) error {
+		if err := m.check(records); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && total < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := records[mig.Version]; !ok {
+				continue
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migration %d_%s doesn't have down file", mig.Version, mig.Name)
+			}
+			if _, err := conn.ExecContext(ctx, "UPDATE schema_history SET dirty = TRUE WHERE version = $1", mig.Version); err != nil {
+				return fmt.Errorf("record migration %d: %v", mig.Version, err)
+			}
+			if _, err := conn.ExecContext(ctx, mig.Down); err != nil {
+				return fmt.Errorf("roll back migration %d_%s: %v", mig.Version, mig.Name, err)
+			}
+			if _, err := conn.ExecContext(ctx, "DELETE FROM schema_history WHERE version = $1", mig.Version); err != nil {
+				return fmt.Errorf("record migration %d: %v", mig.Version, err)
+			}
+			total++
+		}
+		return nil
+	})
+	return total, err
+}
+
+// Force records that the database is exactly at the version without running any migration.
+// All migrations up to the version are marked as applied and clean using the checksum of the current files,
+// and the later ones are marked as pending.
+// It is used to recover after a dirty migration has been fixed manually.
+// Version 0 marks all migrations as pending.
+func (m *Migrator) Force(ctx context.Context, version uint64) error {
+	if version != 0 && m.find(version) == nil {
+		return fmt.Errorf("migration version %d doesn't exist", version)
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn, _ map[uint64]*record) error {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM schema_history WHERE version > $1", version); err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if mig.Version > version {
+				break
+			}
+			if _, err := tx.ExecContext(ctx, "INSERT INTO schema_history (version, name, checksum, dirty, applied_at) VALUES ($1, $2, $3, FALSE, $4) "+
+				"ON CONFLICT (version) DO UPDATE SET name = EXCLUDED.name, checksum = EXCLUDED.checksum, dirty = FALSE",
+				mig.Version, mig.Name, mig.Checksum, time.Now()); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+}
+
+// Status returns the state of all migrations ordered by version.
+// Applied version whose file doesn't exist anymore is also listed, without name.
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	var result []*Status
+	err := m.withLock(ctx, func(_ *sql.Conn, records map[uint64]*record) error {
+		result = m.status(records)
+		return nil
+	})
+	return result, err
+}
+
+// CheckLatest returns error if the database schema is not at the latest version,
+// or any applied migration is dirty or has been modified.
+func (m *Migrator) CheckLatest(ctx context.Context) error {
+	return m.withLock(ctx, func(_ *sql.Conn, records map[uint64]*record) error {
+		if err := m.check(records); err != nil {
+			return err
+		}
+		pending := 0
+		for _, st := range m.status(records) {
+			if !st.Applied {
+				pending++
+			}
+		}
+		if pending > 0 {
+			return fmt.Errorf("database schema is behind: %d pending migration(s)", pending)
+		}
+		return nil
+	})
+}
+
+func (m *Migrator) status(records map[uint64]*record) []*Status {
+	var result []*Status
+	for _, mig := range m.migrations {
+		st := &Status{Version: mig.Version, Name: mig.Name}
+		if rec, ok := records[mig.Version]; ok {
+			st.Applied = true
+			st.AppliedAt = rec.appliedAt
+			st.Dirty = rec.dirty
+			st.Modified = rec.checksum != mig.Checksum
+		}
+		result = append(result, st)
+	}
+	for version, rec := range records {
+		if m.find(version) == nil {
+			result = append(result, &Status{Version: version, Applied: true, AppliedAt: rec.appliedAt, Dirty: rec.dirty})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result
+}
+
+// check makes sure the applied migrations are clean and match the files.
+func (m *Migrator) check(records map[uint64]*record) error {
+	for _, st := range m.status(records) {
+		switch {
+		case st.Dirty:
+			return fmt.Errorf("migration version %d is dirty: fix the database manually and run `migrate force <version>`", st.Version)
+		case st.Applied && st.Name == "":
+			return fmt.Errorf("migration version %d is applied but its file doesn't exist", st.Version)
+		case st.Modified:
+			return fmt.Errorf("migration %d_%s has been modified since it was applied", st.Version, st.Name)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) find(version uint64) *Migration {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig
+		}
+	}
+	return nil
+}
+
+// withLock runs fn on a single connection which holds the advisory lock.
+// Session-level advisory lock belongs to a connection, so the lock, the migrations, and the unlock must share it.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn, records map[uint64]*record) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryLockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %v", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", advisoryLockID)
+
+	if err := m.prepare(ctx, conn); err != nil {
+		return err
+	}
+	records, err := selectRecords(ctx, conn)
+	if err != nil {
+		return err
+	}
+	return fn(conn, records)
+}
+
+// prepare creates the history table.
+// If the table is empty and the database has been migrated by golang-migrate CLI,
+// its version is taken over so the existing deployment doesn't run the migrations again.
+func (m *Migrator) prepare(ctx context.Context, conn *sql.Conn) error {
+	query := "CREATE TABLE IF NOT EXISTS schema_history (" +
+		"version BIGINT PRIMARY KEY, name TEXT NOT NULL, checksum TEXT NOT NULL, dirty BOOLEAN NOT NULL, applied_at TIMESTAMP NOT NULL)"
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("create schema_history: %v", err)
+	}
+
+	var count int
+	var legacy sql.NullString
+	row := conn.QueryRowContext(ctx, "SELECT (SELECT COUNT(*) FROM schema_history), to_regclass('schema_migrations')::TEXT")
+	if err := row.Scan(&count, &legacy); err != nil {
+		return fmt.Errorf("check schema_history: %v", err)
+	}
+	if count > 0 || !legacy.Valid {
+		return nil
+	}
+
+	var version uint64
+	var dirty bool
+	err := conn.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read schema_migrations: %v", err)
+	}
+	if dirty {
+		return fmt.Errorf("schema_migrations version %d is dirty: fix it using golang-migrate CLI first", version)
+	}
+
+	for _, mig := range m.migrations {
+		if mig.Version > version {
+			break
+		}
+		if _, err := conn.ExecContext(ctx, "INSERT INTO schema_history (version, name, checksum, dirty, applied_at) VALUES ($1, $2, $3, FALSE, $4)",
+			mig.Version, mig.Name, mig.Checksum, time.Now()); err != nil {
+			return fmt.Errorf("take over schema_migrations: %v", err)
+		}
+	}
+	return nil
+}
+
+func selectRecords(ctx context.Context, conn *sql.Conn) (map[uint64]*record, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, checksum, dirty, applied_at FROM schema_history ORDER BY version")
+	if err != nil {
+		return nil, fmt.Errorf("read schema_history: %v", err)
+	}
+	defer rows.Close()
+
+	records := make(map[uint64]*record)
+	for rows.Next() {
+		var version uint64
+		rec := &record{}
+		if err := rows.Scan(&version, &rec.checksum, &rec.dirty, &rec.appliedAt); err != nil {
+			return nil, fmt.Errorf("read schema_history: %v", err)
+		}
+		records[version] = rec
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("read schema_history: %v", rows.Err())
+	}
+	return records, nil
+}
@@ -0,0 +1,94 @@
+package migration_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/db/migrations"
+	"github.com/indrasaputra/orvosi-api/internal/migration"
+	"github.com/indrasaputra/orvosi-api/test/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+var tables = []string{"users", "medical_records", "attachments"}
+
+func TestMain(m *testing.M) {
+	postgres.Main(m)
+}
+
+func TestMigrator_Postgres(t *testing.T) {
+	t.Run("apply all migrations up, down, and up again", func(t *testing.T) {
+		db := postgres.NewEmptyDatabase(t)
+		migrator, err := migration.NewMigrator(db, migrations.FS)
+		assert.Nil(t, err)
+		all, err := migration.Load(migrations.FS)
+		assert.Nil(t, err)
+
+		n, err := migrator.Up(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, len(all), n)
+		assert.Nil(t, migrator.CheckLatest(context.Background()))
+		assertTablesExist(t, db, true)
+
+		n, err = migrator.Down(context.Background(), len(all))
+		assert.Nil(t, err)
+		assert.Equal(t, len(all), n)
+		assert.NotNil(t, migrator.CheckLatest(context.Background()))
+		assertTablesExist(t, db, false)
+
+		n, err = migrator.Up(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, len(all), n)
+		assertTablesExist(t, db, true)
+	})
+
+	t.Run("every migration can be rolled back and applied again", func(t *testing.T) {
+		db := postgres.NewEmptyDatabase(t)
+		migrator, err := migration.NewMigrator(db, migrations.FS)
+		assert.Nil(t, err)
+		all, err := migration.Load(migrations.FS)
+		assert.Nil(t, err)
+
+		_, err = migrator.Up(context.Background())
+		assert.Nil(t, err)
+
+		for i := 1; i <= len(all); i++ {
+			n, err := migrator.Down(context.Background(), i)
+			assert.Nil(t, err)
+			assert.Equal(t, i, n)
+
+			n, err = migrator.Up(context.Background())
+			assert.Nil(t, err)
+			assert.Equal(t, i, n)
+		}
+
+		statuses, err := migrator.Status(context.Background())
+		assert.Nil(t, err)
+		assert.Len(t, statuses, len(all))
+		for _, status := range statuses {
+			assert.True(t, status.Applied)
+			assert.False(t, status.Dirty)
+		}
+	})
+
+	t.Run("nothing to apply on a migrated database", func(t *testing.T) {
+		db := postgres.NewDatabase(t)
+		migrator, err := migration.NewMigrator(db, migrations.FS)
+		assert.Nil(t, err)
+
+		n, err := migrator.Up(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, n)
+	})
+}
+
+func assertTablesExist(t *testing.T, db *sql.DB, exist bool) {
+	for _, table := range tables {
+		var name sql.NullString
+		err := db.QueryRow("SELECT to_regclass($1)::TEXT", table).Scan(&name)
+		assert.Nil(t, err)
+		assert.Equal(t, exist, name.Valid, table)
+	}
+}
@@ -0,0 +1,287 @@
+package migration_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/orvosi-api/internal/migration"
+	"github.com/stretchr/testify/assert"
+)
+
+type MigratorExecutor struct {
+	migrator *migration.Migrator
+	sql      sqlmock.Sqlmock
+	files    []*migration.Migration
+}
+
+func TestNewMigrator(t *testing.T) {
+	t.Run("successfully create an instance of Migrator", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		assert.NotNil(t, exec.migrator)
+	})
+}
+
+func TestMigrator_Up(t *testing.T) {
+	t.Run("migration lock can't be acquired", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		exec.sql.ExpectExec(quote("SELECT pg_advisory_lock($1)")).WillReturnError(errors.New("connection refused"))
+
+		n, err := exec.migrator.Up(context.Background())
+
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("applied migration has been modified", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		exec.expectLock(sqlmock.NewRows(historyColumns()).AddRow(1, "modified", false, time.Now()))
+		exec.expectUnlock()
+
+		n, err := exec.migrator.Up(context.Background())
+
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "modified")
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("applied migration is dirty", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		exec.expectLock(sqlmock.NewRows(historyColumns()).AddRow(1, exec.files[0].Checksum, true, time.Now()))
+		exec.expectUnlock()
+
+		_, err := exec.migrator.Up(context.Background())
+
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "dirty")
+	})
+
+	t.Run("migration fails and is left dirty", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		exec.expectLock(sqlmock.NewRows(historyColumns()).AddRow(1, exec.files[0].Checksum, false, time.Now()))
+		exec.sql.ExpectExec(quote("INSERT INTO schema_history (version, name, checksum, dirty, applied_at) VALUES ($1, $2, $3, TRUE, $4)")).
+			WithArgs(2, "create_medical_record", exec.files[1].Checksum, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectExec(quote(exec.files[1].Up)).WillReturnError(errors.New("syntax error"))
+		exec.expectUnlock()
+
+		n, err := exec.migrator.Up(context.Background())
+
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, n)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+
+	t.Run("successfully apply pending migrations", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		exec.expectLock(sqlmock.NewRows(historyColumns()))
+		for _, mig := range exec.files {
+			exec.sql.ExpectExec(quote("INSERT INTO schema_history (version, name, checksum, dirty, applied_at) VALUES ($1, $2, $3, TRUE, $4)")).
+				WithArgs(mig.Version, mig.Name, mig.Checksum, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			exec.sql.ExpectExec(quote(mig.Up)).WillReturnResult(sqlmock.NewResult(0, 0))
+			exec.sql.ExpectExec(quote("UPDATE schema_history SET dirty = FALSE WHERE version = $1")).
+				WithArgs(mig.Version).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		exec.expectUnlock()
+
+		n, err := exec.migrator.Up(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, n)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+
+	t.Run("successfully take over version of golang-migrate", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		exec.sql.ExpectExec(quote("SELECT pg_advisory_lock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+		exec.sql.ExpectExec(quote("CREATE TABLE IF NOT EXISTS schema_history")).WillReturnResult(sqlmock.NewResult(0, 0))
+		exec.sql.ExpectQuery(quote("SELECT (SELECT COUNT(*) FROM schema_history), to_regclass('schema_migrations')::TEXT")).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "to_regclass"}).AddRow(0, "schema_migrations"))
+		exec.sql.ExpectQuery(quote("SELECT version, dirty FROM schema_migrations LIMIT 1")).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(2, false))
+		for _, mig := range exec.files {
+			exec.sql.ExpectExec(quote("INSERT INTO schema_history (version, name, checksum, dirty, applied_at) VALUES ($1, $2, $3, FALSE, $4)")).
+				WithArgs(mig.Version, mig.Name, mig.Checksum, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		exec.sql.ExpectQuery(quote("SELECT version, checksum, dirty, applied_at FROM schema_history ORDER BY version")).
+			WillReturnRows(sqlmock.NewRows(historyColumns()).
+				AddRow(1, exec.files[0].Checksum, false, time.Now()).
+				AddRow(2, exec.files[1].Checksum, false, time.Now()))
+		exec.expectUnlock()
+
+		n, err := exec.migrator.Up(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, n)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+}
+
+func TestMigrator_Down(t *testing.T) {
+	t.Run("steps is less than one", func(t *testing.T) {
+		exec := createMigratorExecutor()
+
+		n, err := exec.migrator.Down(context.Background(), 0)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("migration doesn't have down file", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		exec.expectLock(sqlmock.NewRows(historyColumns()).
+			AddRow(1, exec.files[0].Checksum, false, time.Now()).
+			AddRow(2, exec.files[1].Checksum, false, time.Now()))
+		exec.expectUnlock()
+
+		n, err := exec.migrator.Down(context.Background(), 1)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("successfully roll back the last migration", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		exec.expectLock(sqlmock.NewRows(historyColumns()).AddRow(1, exec.files[0].Checksum, false, time.Now()))
+		exec.sql.ExpectExec(quote("UPDATE schema_history SET dirty = TRUE WHERE version = $1")).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectExec(quote(exec.files[0].Down)).WillReturnResult(sqlmock.NewResult(0, 0))
+		exec.sql.ExpectExec(quote("DELETE FROM schema_history WHERE version = $1")).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.expectUnlock()
+
+		n, err := exec.migrator.Down(context.Background(), 5)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+}
+
+func TestMigrator_Force(t *testing.T) {
+	t.Run("version doesn't exist", func(t *testing.T) {
+		exec := createMigratorExecutor()
+
+		err := exec.migrator.Force(context.Background(), 3)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("successfully force dirty database to a version", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		exec.expectLock(sqlmock.NewRows(historyColumns()).
+			AddRow(1, exec.files[0].Checksum, false, time.Now()).
+			AddRow(2, exec.files[1].Checksum, true, time.Now()))
+		exec.sql.ExpectBegin()
+		exec.sql.ExpectExec(quote("DELETE FROM schema_history WHERE version > $1")).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectExec(quote("INSERT INTO schema_history (version, name, checksum, dirty, applied_at) VALUES ($1, $2, $3, FALSE, $4) ON CONFLICT (version)")).
+			WithArgs(1, "create_user", exec.files[0].Checksum, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectCommit()
+		exec.expectUnlock()
+
+		err := exec.migrator.Force(context.Background(), 1)
+
+		assert.Nil(t, err)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+}
+
+func TestMigrator_Status(t *testing.T) {
+	t.Run("history can't be read", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		exec.sql.ExpectExec(quote("SELECT pg_advisory_lock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+		exec.sql.ExpectExec(quote("CREATE TABLE IF NOT EXISTS schema_history")).WillReturnError(errors.New("permission denied"))
+		exec.expectUnlock()
+
+		res, err := exec.migrator.Status(context.Background())
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("successfully list applied, pending, and unknown migrations", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		exec.expectLock(sqlmock.NewRows(historyColumns()).
+			AddRow(1, "modified", false, time.Now()).
+			AddRow(9, "unknown", false, time.Now()))
+		exec.expectUnlock()
+
+		res, err := exec.migrator.Status(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(res))
+		assert.True(t, res[0].Applied)
+		assert.True(t, res[0].Modified)
+		assert.False(t, res[1].Applied)
+		assert.Equal(t, uint64(9), res[2].Version)
+		assert.Empty(t, res[2].Name)
+	})
+}
+
+func TestMigrator_CheckLatest(t *testing.T) {
+	t.Run("schema is behind", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		exec.expectLock(sqlmock.NewRows(historyColumns()).AddRow(1, exec.files[0].Checksum, false, time.Now()))
+		exec.expectUnlock()
+
+		err := exec.migrator.CheckLatest(context.Background())
+
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "1 pending")
+	})
+
+	t.Run("schema is at the latest version", func(t *testing.T) {
+		exec := createMigratorExecutor()
+		exec.expectLock(sqlmock.NewRows(historyColumns()).
+			AddRow(1, exec.files[0].Checksum, false, time.Now()).
+			AddRow(2, exec.files[1].Checksum, false, time.Now()))
+		exec.expectUnlock()
+
+		err := exec.migrator.CheckLatest(context.Background())
+
+		assert.Nil(t, err)
+	})
+}
+
+// expectLock expects the lock is acquired and the history table is prepared and read.
+func (e *MigratorExecutor) expectLock(history *sqlmock.Rows) {
+	e.sql.ExpectExec(quote("SELECT pg_advisory_lock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	e.sql.ExpectExec(quote("CREATE TABLE IF NOT EXISTS schema_history")).WillReturnResult(sqlmock.NewResult(0, 0))
+	e.sql.ExpectQuery(quote("SELECT (SELECT COUNT(*) FROM schema_history), to_regclass('schema_migrations')::TEXT")).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "to_regclass"}).AddRow(1, nil))
+	e.sql.ExpectQuery(quote("SELECT version, checksum, dirty, applied_at FROM schema_history ORDER BY version")).
+		WillReturnRows(history)
+}
+
+func (e *MigratorExecutor) expectUnlock() {
+	e.sql.ExpectExec(quote("SELECT pg_advisory_unlock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func historyColumns() []string {
+	return []string{"version", "checksum", "dirty", "applied_at"}
+}
+
+func quote(query string) string {
+	return regexp.QuoteMeta(query)
+}
+
+func createMigratorExecutor() *MigratorExecutor {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Panicf("[createMigratorExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	files, _ := migration.Load(createMigrationFS())
+	migrator, _ := migration.NewMigrator(db, createMigrationFS())
+	return &MigratorExecutor{
+		migrator: migrator,
+		sql:      mock,
+		files:    files,
+	}
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// AttachmentDeleter connects the database with attachment entity
+// and only responsible for deleting a data.
+type AttachmentDeleter struct {
+	db *sql.DB
+}
+
+// NewAttachmentDeleter creates an instance of AttachmentDeleter.
+func NewAttachmentDeleter(db *sql.DB) *AttachmentDeleter {
+	return &AttachmentDeleter{db: db}
+}
+
+// DoesRecordExist checks whether medical record which has certain id and is owned by the user who has the userID exists.
+func (ad *AttachmentDeleter) DoesRecordExist(ctx context.Context, id, userID uint64) (bool, *entity.Error) {
+	return doesMedicalRecordExist(ctx, ad.db, id, userID)
+}
+
+// Delete deletes an attachment of a medical record and returns its storage key.
+// The storage key is queued to be deleted from storage in the same transaction.
+func (ad *AttachmentDeleter) Delete(ctx context.Context, recordID, id uint64) (string, *entity.Error) {
+	query := "DELETE FROM attachments WHERE id = $1 AND medical_record_id = $2 RETURNING storage_key"
+	row := querierFromContext(ctx, ad.db).QueryRowContext(ctx, query, id, recordID)
+
+	var key string
+	err := row.Scan(&key)
+	if err == sql.ErrNoRows {
+		return "", entity.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return "", databaseError(err, "[AttachmentDeleter-Delete] exec delete query: ")
+	}
+	if err := queueStorageDeletions(ctx, ad.db, []string{key}, "[AttachmentDeleter-Delete]"); err != nil {
+		return "", err
+	}
+	return key, nil
+}
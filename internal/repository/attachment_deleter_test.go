@@ -0,0 +1,90 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type AttachmentDeleterExecutor struct {
+	repo *repository.AttachmentDeleter
+	sql  sqlmock.Sqlmock
+}
+
+func TestNewAttachmentDeleter(t *testing.T) {
+	t.Run("successfully create an instance of AttachmentDeleter", func(t *testing.T) {
+		exec := createAttachmentDeleterExecutor()
+		assert.NotNil(t, exec.repo)
+	})
+}
+
+func TestAttachmentDeleter_Delete(t *testing.T) {
+	t.Run("attachment is not found", func(t *testing.T) {
+		exec := createAttachmentDeleterExecutor()
+
+		exec.sql.ExpectQuery(`DELETE FROM attachments WHERE id = \$1 AND medical_record_id = \$2 RETURNING storage_key`).
+			WillReturnError(sql.ErrNoRows)
+		key, err := exec.repo.Delete(context.Background(), uint64(1), uint64(2))
+
+		assert.Empty(t, key)
+		assert.Equal(t, entity.ErrAttachmentNotFound, err)
+	})
+
+	t.Run("delete query returns error", func(t *testing.T) {
+		exec := createAttachmentDeleterExecutor()
+
+		exec.sql.ExpectQuery(`DELETE FROM attachments WHERE id = \$1 AND medical_record_id = \$2 RETURNING storage_key`).
+			WillReturnError(errors.New("fail to delete from database"))
+		key, err := exec.repo.Delete(context.Background(), uint64(1), uint64(2))
+
+		assert.Empty(t, key)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("queue storage deletion query returns error", func(t *testing.T) {
+		exec := createAttachmentDeleterExecutor()
+
+		exec.sql.ExpectQuery(`DELETE FROM attachments WHERE id = \$1 AND medical_record_id = \$2 RETURNING storage_key`).
+			WillReturnRows(sqlmock.NewRows([]string{"storage_key"}).AddRow("medical-records/1/key"))
+		exec.sql.ExpectExec(`INSERT INTO storage_deletions`).
+			WillReturnError(errors.New("fail to insert to database"))
+		key, err := exec.repo.Delete(context.Background(), uint64(1), uint64(2))
+
+		assert.Empty(t, key)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("successfully delete attachment and queue its storage key", func(t *testing.T) {
+		exec := createAttachmentDeleterExecutor()
+
+		exec.sql.ExpectQuery(`DELETE FROM attachments WHERE id = \$1 AND medical_record_id = \$2 RETURNING storage_key`).
+			WillReturnRows(sqlmock.NewRows([]string{"storage_key"}).AddRow("medical-records/1/key"))
+		exec.sql.ExpectExec(`INSERT INTO storage_deletions`).
+			WithArgs("medical-records/1/key", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		key, err := exec.repo.Delete(context.Background(), uint64(1), uint64(2))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "medical-records/1/key", key)
+	})
+}
+
+func createAttachmentDeleterExecutor() *AttachmentDeleterExecutor {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Panicf("[createAttachmentDeleterExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	repo := repository.NewAttachmentDeleter(db)
+	return &AttachmentDeleterExecutor{
+		repo: repo,
+		sql:  mock,
+	}
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// AttachmentInserter connects the database with attachment entity
+// and only responsible for inserting a new data.
+type AttachmentInserter struct {
+	db *sql.DB
+}
+
+// NewAttachmentInserter creates an instance of AttachmentInserter.
+func NewAttachmentInserter(db *sql.DB) *AttachmentInserter {
+	return &AttachmentInserter{db: db}
+}
+
+// DoesRecordExist checks whether medical record which has certain id and is owned by the user who has the userID exists.
+func (ai *AttachmentInserter) DoesRecordExist(ctx context.Context, id, userID uint64) (bool, *entity.Error) {
+	return doesMedicalRecordExist(ctx, ai.db, id, userID)
+}
+
+// Insert inserts a new attachment data into the database.
+func (ai *AttachmentInserter) Insert(ctx context.Context, attachment *entity.Attachment) *entity.Error {
+	if attachment == nil {
+		return entity.ErrEmptyAttachment
+	}
+
+	query := "INSERT INTO " +
+		"attachments (medical_record_id, filename, content_type, size, checksum, storage_key, created_at, updated_at, created_by, updated_by) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id"
+
+	now := time.Now().UTC()
+	row := querierFromContext(ctx, ai.db).QueryRowContext(ctx, query,
+		uint64(attachment.MedicalRecordID),
+		attachment.Filename,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.Checksum,
+		attachment.StorageKey,
+		now,
+		now,
+		attachment.CreatedBy,
+		attachment.UpdatedBy,
+	)
+
+	var id uint64
+	if err := row.Scan(&id); err != nil {
+		return databaseError(err, "[AttachmentInserter-Insert] exec insert query: ")
+	}
+
+	attachment.ID = hashids.ID(id)
+	attachment.CreatedAt = now
+	attachment.UpdatedAt = now
+	return nil
+}
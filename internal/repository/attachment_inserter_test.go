@@ -0,0 +1,100 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type AttachmentInserterExecutor struct {
+	repo *repository.AttachmentInserter
+	sql  sqlmock.Sqlmock
+}
+
+func TestNewAttachmentInserter(t *testing.T) {
+	t.Run("successfully create an instance of AttachmentInserter", func(t *testing.T) {
+		exec := createAttachmentInserterExecutor()
+		assert.NotNil(t, exec.repo)
+	})
+}
+
+func TestAttachmentInserter_DoesRecordExist(t *testing.T) {
+	t.Run("successfully found the record", func(t *testing.T) {
+		exec := createAttachmentInserterExecutor()
+
+		exec.sql.ExpectQuery(`SELECT id FROM medical_records WHERE id = \$1 AND user_id = \$2 LIMIT 1`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		found, err := exec.repo.DoesRecordExist(context.Background(), uint64(1), uint64(3))
+
+		assert.Nil(t, err)
+		assert.True(t, found)
+	})
+}
+
+func TestAttachmentInserter_Insert(t *testing.T) {
+	t.Run("attachment is nil", func(t *testing.T) {
+		exec := createAttachmentInserterExecutor()
+
+		err := exec.repo.Insert(context.Background(), nil)
+
+		assert.Equal(t, entity.ErrEmptyAttachment, err)
+	})
+
+	t.Run("insert query returns error", func(t *testing.T) {
+		exec := createAttachmentInserterExecutor()
+
+		exec.sql.ExpectQuery(`INSERT INTO attachments \(medical_record_id, filename, content_type, size, checksum, storage_key, created_at, updated_at, created_by, updated_by\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10\) RETURNING id`).
+			WillReturnError(errors.New("fail to insert to database"))
+		err := exec.repo.Insert(context.Background(), createValidAttachment())
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("successfully insert attachment", func(t *testing.T) {
+		exec := createAttachmentInserterExecutor()
+		attachment := createValidAttachment()
+
+		exec.sql.ExpectQuery(`INSERT INTO attachments \(medical_record_id, filename, content_type, size, checksum, storage_key, created_at, updated_at, created_by, updated_by\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10\) RETURNING id`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		err := exec.repo.Insert(context.Background(), attachment)
+
+		assert.Nil(t, err)
+		assert.Equal(t, hashids.ID(7), attachment.ID)
+	})
+}
+
+func createValidAttachment() *entity.Attachment {
+	return &entity.Attachment{
+		MedicalRecordID: hashids.ID(1),
+		Filename:        "lab.pdf",
+		ContentType:     "application/pdf",
+		Size:            1024,
+		Checksum:        "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		StorageKey:      "medical-records/1/key",
+		Auditable: entity.Auditable{
+			CreatedBy: "dummy@dummy.com",
+			UpdatedBy: "dummy@dummy.com",
+		},
+	}
+}
+
+func createAttachmentInserterExecutor() *AttachmentInserterExecutor {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Panicf("[createAttachmentInserterExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	repo := repository.NewAttachmentInserter(db)
+	return &AttachmentInserterExecutor{
+		repo: repo,
+		sql:  mock,
+	}
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+const attachmentColumns = "id, medical_record_id, filename, content_type, size, checksum, storage_key, created_at, created_by, updated_at, updated_by"
+
+// AttachmentSelector connects the database with attachment entity
+// and only responsible for retrieving attachment data.
+type AttachmentSelector struct {
+	db *sql.DB
+}
+
+// NewAttachmentSelector creates an instance of AttachmentSelector.
+func NewAttachmentSelector(db *sql.DB) *AttachmentSelector {
+	return &AttachmentSelector{db: db}
+}
+
+// DoesRecordExist checks whether medical record which has certain id and is owned by the user who has the userID exists.
+func (as *AttachmentSelector) DoesRecordExist(ctx context.Context, id, userID uint64) (bool, *entity.Error) {
+	return doesMedicalRecordExist(ctx, as.db, id, userID)
+}
+
+// doesMedicalRecordExist is shared by repositories that need to check the ownership of a medical record.
+func doesMedicalRecordExist(ctx context.Context, db *sql.DB, id, userID uint64) (bool, *entity.Error) {
+	query := "SELECT id FROM medical_records WHERE id = $1 AND user_id = $2 LIMIT 1"
+	row := querierFromContext(ctx, db).QueryRowContext(ctx, query, id, userID)
+
+	var tmp uint64
+	err := row.Scan(&tmp)
+	if err != nil && err != sql.ErrNoRows {
+		return false, databaseError(err, "[DoesRecordExist] exec select query: ")
+	} else if err != nil && err == sql.ErrNoRows {
+		return false, nil
+	}
+	return true, nil
+}
+
+// FindByMedicalRecordID finds all attachments of a medical record.
+func (as *AttachmentSelector) FindByMedicalRecordID(ctx context.Context, recordID uint64) ([]*entity.Attachment, *entity.Error) {
+	query := "SELECT " + attachmentColumns + " FROM attachments WHERE medical_record_id = $1 ORDER BY id ASC"
+	rows, err := querierFromContext(ctx, as.db).QueryContext(ctx, query, recordID)
+	if err != nil {
+		return []*entity.Attachment{}, databaseError(err, "[AttachmentSelector-FindByMedicalRecordID] exec select query: ")
+	}
+	defer rows.Close()
+
+	var result []*entity.Attachment
+	for rows.Next() {
+		var tmp entity.Attachment
+		if err := scanAttachment(rows, &tmp); err != nil {
+			log.Printf("[AttachmentSelector-FindByMedicalRecordID] scan rows error: %v", err)
+			continue
+		}
+		result = append(result, &tmp)
+	}
+	if rows.Err() != nil {
+		return []*entity.Attachment{}, databaseError(rows.Err(), "[AttachmentSelector-FindByMedicalRecordID] iterate rows: ")
+	}
+	return result, nil
+}
+
+// FindByID finds an attachment of a medical record by its id.
+func (as *AttachmentSelector) FindByID(ctx context.Context, recordID, id uint64) (*entity.Attachment, *entity.Error) {
+	query := "SELECT " + attachmentColumns + " FROM attachments WHERE id = $1 AND medical_record_id = $2 LIMIT 1"
+	row := querierFromContext(ctx, as.db).QueryRowContext(ctx, query, id, recordID)
+
+	var attachment entity.Attachment
+	err := scanAttachment(row, &attachment)
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, databaseError(err, "[AttachmentSelector-FindByID] exec select query: ")
+	}
+	return &attachment, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAttachment(row scanner, a *entity.Attachment) error {
+	return row.Scan(&a.ID, &a.MedicalRecordID, &a.Filename, &a.ContentType, &a.Size, &a.Checksum, &a.StorageKey, &a.CreatedAt, &a.CreatedBy, &a.UpdatedAt, &a.UpdatedBy)
+}
@@ -0,0 +1,109 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var attachmentRowColumns = []string{"id", "medical_record_id", "filename", "content_type", "size", "checksum", "storage_key", "created_at", "created_by", "updated_at", "updated_by"}
+
+type AttachmentSelectorExecutor struct {
+	repo *repository.AttachmentSelector
+	sql  sqlmock.Sqlmock
+}
+
+func TestNewAttachmentSelector(t *testing.T) {
+	t.Run("successfully create an instance of AttachmentSelector", func(t *testing.T) {
+		exec := createAttachmentSelectorExecutor()
+		assert.NotNil(t, exec.repo)
+	})
+}
+
+func TestAttachmentSelector_FindByMedicalRecordID(t *testing.T) {
+	t.Run("select query returns error", func(t *testing.T) {
+		exec := createAttachmentSelectorExecutor()
+
+		exec.sql.ExpectQuery(`SELECT (.+) FROM attachments WHERE medical_record_id = \$1 ORDER BY id ASC`).
+			WillReturnError(errors.New("fail to select from database"))
+		res, err := exec.repo.FindByMedicalRecordID(context.Background(), uint64(1))
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Empty(t, res)
+	})
+
+	t.Run("successfully retrieve attachments", func(t *testing.T) {
+		exec := createAttachmentSelectorExecutor()
+
+		exec.sql.ExpectQuery(`SELECT (.+) FROM attachments WHERE medical_record_id = \$1 ORDER BY id ASC`).
+			WillReturnRows(sqlmock.NewRows(attachmentRowColumns).
+				AddRow(1, 1, "lab.pdf", "application/pdf", 1024, "checksum", "key-1", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com").
+				AddRow(2, 1, "scan.png", "image/png", 2048, "checksum", "key-2", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com"),
+			)
+		res, err := exec.repo.FindByMedicalRecordID(context.Background(), uint64(1))
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+		assert.Equal(t, "scan.png", res[1].Filename)
+	})
+}
+
+func TestAttachmentSelector_FindByID(t *testing.T) {
+	t.Run("attachment is not found", func(t *testing.T) {
+		exec := createAttachmentSelectorExecutor()
+
+		exec.sql.ExpectQuery(`SELECT (.+) FROM attachments WHERE id = \$1 AND medical_record_id = \$2 LIMIT 1`).
+			WillReturnError(sql.ErrNoRows)
+		res, err := exec.repo.FindByID(context.Background(), uint64(1), uint64(2))
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrAttachmentNotFound, err)
+	})
+
+	t.Run("select query returns error", func(t *testing.T) {
+		exec := createAttachmentSelectorExecutor()
+
+		exec.sql.ExpectQuery(`SELECT (.+) FROM attachments WHERE id = \$1 AND medical_record_id = \$2 LIMIT 1`).
+			WillReturnError(errors.New("fail to select from database"))
+		res, err := exec.repo.FindByID(context.Background(), uint64(1), uint64(2))
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("successfully retrieve one attachment", func(t *testing.T) {
+		exec := createAttachmentSelectorExecutor()
+
+		exec.sql.ExpectQuery(`SELECT (.+) FROM attachments WHERE id = \$1 AND medical_record_id = \$2 LIMIT 1`).
+			WillReturnRows(sqlmock.NewRows(attachmentRowColumns).
+				AddRow(2, 1, "lab.pdf", "application/pdf", 1024, "checksum", "key-1", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com"),
+			)
+		res, err := exec.repo.FindByID(context.Background(), uint64(1), uint64(2))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "key-1", res.StorageKey)
+		assert.Equal(t, int64(1024), res.Size)
+	})
+}
+
+func createAttachmentSelectorExecutor() *AttachmentSelectorExecutor {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Panicf("[createAttachmentSelectorExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	repo := repository.NewAttachmentSelector(db)
+	return &AttachmentSelectorExecutor{
+		repo: repo,
+		sql:  mock,
+	}
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/indrasaputra/orvosi-api/usecase"
+)
+
+// Backend holds all repositories of a storage backend.
+// Every backend must pass the contract tests in package contract.
+type Backend struct {
+	MedicalRecordInserter interface {
+		usecase.InsertMedicalRecordRepository
+		usecase.BulkInsertMedicalRecordRepository
+	}
+	MedicalRecordSelector   usecase.FindMedicalRecordRepository
+	MedicalRecordUpdater    usecase.UpdateMedicalRecordRepository
+	MedicalRecordReassigner usecase.ReassignMedicalRecordRepository
+	UserInserter            usecase.SignInRepository
+	UserSelector            usecase.FindUserRepository
+	ProfileUpdater          usecase.UpdateProfileRepository
+	UserAnonymizer          usecase.AnonymizeUserRepository
+	UserEraser              usecase.EraseUserRepository
+	StorageDeleter          usecase.StorageDeletionRepository
+	IdentityLinker          usecase.LinkIdentityRepository
+	EmailChanger            usecase.ChangeEmailRepository
+	AttachmentInserter      usecase.UploadAttachmentRepository
+	AttachmentSelector      usecase.FindAttachmentRepository
+	AttachmentDeleter       usecase.DeleteAttachmentRepository
+	Transactor              usecase.Transactor
+	Pinger                  usecase.PingRepository
+}
+
+// NewSQLBackend creates a backend whose repositories connect to the SQL database.
+// The database is either PostgreSQL or SQLite.
+// The router is optional and only routes the reads of medical records.
+func NewSQLBackend(db *sql.DB, router *ReplicaRouter, cipher FieldCipher, txMaxRetries int) *Backend {
+	return &Backend{
+		MedicalRecordInserter:   NewMedicalRecordInserter(db, cipher, router, txMaxRetries),
+		MedicalRecordSelector:   NewMedicalRecordSelector(db, cipher, router),
+		MedicalRecordUpdater:    NewMedicalRecordUpdater(db, cipher, router),
+		MedicalRecordReassigner: NewMedicalRecordReassigner(db, router),
+		UserInserter:            NewUserInserter(db),
+		UserSelector:            NewUserSelector(db),
+		ProfileUpdater:          NewProfileUpdater(db),
+		UserAnonymizer:          NewUserAnonymizer(db),
+		UserEraser:              NewUserEraser(db),
+		StorageDeleter:          NewStorageDeleter(db),
+		IdentityLinker:          NewIdentityLinker(db),
+		EmailChanger:            NewEmailChanger(db),
+		AttachmentInserter:      NewAttachmentInserter(db),
+		AttachmentSelector:      NewAttachmentSelector(db),
+		AttachmentDeleter:       NewAttachmentDeleter(db),
+		Transactor:              NewTransactor(db, txMaxRetries),
+		Pinger:                  NewDatabasePinger(db),
+	}
+}
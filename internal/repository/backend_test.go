@@ -0,0 +1,43 @@
+//go:build sqlite
+// +build sqlite
+
+package repository_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/db/sqlite"
+	"github.com/indrasaputra/orvosi-api/internal/encryption"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/indrasaputra/orvosi-api/internal/repository/contract"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLBackend_SQLiteContract(t *testing.T) {
+	contract.Run(t, func(t *testing.T) *repository.Backend {
+		return repository.NewSQLBackend(openSQLite(t), nil, encryption.Plaintext{}, 0)
+	})
+}
+
+func TestSQLBackend_SQLiteContractWithReplica(t *testing.T) {
+	contract.Run(t, func(t *testing.T) *repository.Backend {
+		db := openSQLite(t)
+		router := repository.NewReplicaRouter(db, []*sql.DB{db}, time.Second)
+		return repository.NewSQLBackend(db, router, encryption.Plaintext{}, 0)
+	})
+}
+
+func openSQLite(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "orvosi.db")+"?_foreign_keys=on")
+	assert.Nil(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(sqlite.Schema)
+	assert.Nil(t, err)
+	return db
+}
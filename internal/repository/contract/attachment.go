@@ -0,0 +1,136 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func runAttachment(t *testing.T, newBackend NewBackend) {
+	t.Run("insert empty attachment", func(t *testing.T) {
+		backend := newBackend(t)
+
+		err := backend.AttachmentInserter.Insert(context.Background(), nil)
+
+		assert.Equal(t, entity.ErrEmptyAttachment, err)
+	})
+
+	t.Run("insert attachment of missing medical record", func(t *testing.T) {
+		backend := newBackend(t)
+
+		err := backend.AttachmentInserter.Insert(context.Background(), createAttachment(1, "key"))
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("storage key must be unique", func(t *testing.T) {
+		backend := newBackend(t)
+		recordID := uint64(insertMedicalRecords(t, backend, "a@orvosi.com", 1)[0].ID)
+
+		assert.Nil(t, backend.AttachmentInserter.Insert(context.Background(), createAttachment(recordID, "key")))
+		err := backend.AttachmentInserter.Insert(context.Background(), createAttachment(recordID, "key"))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, entity.ErrAlreadyExists.Code, err.Code)
+		}
+	})
+
+	t.Run("does record exist", func(t *testing.T) {
+		backend := newBackend(t)
+		record := insertMedicalRecords(t, backend, "a@orvosi.com", 1)[0]
+		recordID := uint64(record.ID)
+		other := insertUser(t, backend, "b@orvosi.com")
+
+		for _, repo := range []interface {
+			DoesRecordExist(ctx context.Context, id, userID uint64) (bool, *entity.Error)
+		}{backend.AttachmentInserter, backend.AttachmentSelector, backend.AttachmentDeleter} {
+			found, err := repo.DoesRecordExist(context.Background(), recordID, uint64(record.User.ID))
+			assert.Nil(t, err)
+			assert.True(t, found)
+
+			found, err = repo.DoesRecordExist(context.Background(), recordID, uint64(other.ID))
+			assert.Nil(t, err)
+			assert.False(t, found)
+		}
+	})
+
+	t.Run("insert, find, and delete attachments", func(t *testing.T) {
+		backend := newBackend(t)
+		records := insertMedicalRecords(t, backend, "a@orvosi.com", 2)
+		recordID, otherID := uint64(records[0].ID), uint64(records[1].ID)
+		first, second := insertAttachment(t, backend, recordID, "first"), insertAttachment(t, backend, recordID, "second")
+		insertAttachment(t, backend, otherID, "other")
+
+		res, err := backend.AttachmentSelector.FindByMedicalRecordID(context.Background(), recordID)
+		assert.Nil(t, err)
+		if assert.Len(t, res, 2) {
+			assert.Equal(t, first.ID, res[0].ID)
+			assert.Equal(t, second.ID, res[1].ID)
+			assert.Equal(t, first.StorageKey, res[0].StorageKey)
+			assert.Equal(t, first.Size, res[0].Size)
+		}
+
+		att, err := backend.AttachmentSelector.FindByID(context.Background(), recordID, uint64(second.ID))
+		assert.Nil(t, err)
+		if assert.NotNil(t, att) {
+			assert.Equal(t, "second", att.StorageKey)
+			assert.Equal(t, "a@orvosi.com", att.CreatedBy)
+			assert.Equal(t, records[0].ID, att.MedicalRecordID)
+		}
+
+		_, err = backend.AttachmentSelector.FindByID(context.Background(), otherID, uint64(second.ID))
+		assert.Equal(t, entity.ErrAttachmentNotFound, err)
+
+		_, err = backend.AttachmentDeleter.Delete(context.Background(), otherID, uint64(second.ID))
+		assert.Equal(t, entity.ErrAttachmentNotFound, err)
+
+		key, err := backend.AttachmentDeleter.Delete(context.Background(), recordID, uint64(second.ID))
+		assert.Nil(t, err)
+		assert.Equal(t, "second", key)
+
+		pending, err := backend.StorageDeleter.FindPending(context.Background(), 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"second"}, pending)
+
+		_, err = backend.AttachmentSelector.FindByID(context.Background(), recordID, uint64(second.ID))
+		assert.Equal(t, entity.ErrAttachmentNotFound, err)
+
+		_, err = backend.AttachmentDeleter.Delete(context.Background(), recordID, uint64(second.ID))
+		assert.Equal(t, entity.ErrAttachmentNotFound, err)
+	})
+
+	t.Run("find attachments of medical record without attachment", func(t *testing.T) {
+		backend := newBackend(t)
+		recordID := uint64(insertMedicalRecords(t, backend, "a@orvosi.com", 1)[0].ID)
+
+		res, err := backend.AttachmentSelector.FindByMedicalRecordID(context.Background(), recordID)
+
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func createAttachment(recordID uint64, key string) *entity.Attachment {
+	return &entity.Attachment{
+		MedicalRecordID: hashids.ID(recordID),
+		Filename:        key + ".pdf",
+		ContentType:     "application/pdf",
+		Size:            128,
+		Checksum:        "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		StorageKey:      key,
+		Auditable: entity.Auditable{
+			CreatedBy: "a@orvosi.com",
+			UpdatedBy: "a@orvosi.com",
+		},
+	}
+}
+
+func insertAttachment(t *testing.T, backend *repository.Backend, recordID uint64, key string) *entity.Attachment {
+	att := createAttachment(recordID, key)
+	assert.Nil(t, backend.AttachmentInserter.Insert(context.Background(), att))
+	assert.NotZero(t, att.ID)
+	return att
+}
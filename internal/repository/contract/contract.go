@@ -0,0 +1,92 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+const maxID = uint64(1<<63 - 1)
+
+// NewBackend creates a backend whose storage is empty.
+// It is called once for every contract test.
+type NewBackend func(t *testing.T) *repository.Backend
+
+// Run runs the whole contract against the backend.
+func Run(t *testing.T, newBackend NewBackend) {
+	t.Run("medical record", func(t *testing.T) {
+		runMedicalRecord(t, newBackend)
+	})
+	t.Run("user", func(t *testing.T) {
+		runUser(t, newBackend)
+	})
+	t.Run("identity", func(t *testing.T) {
+		runIdentity(t, newBackend)
+	})
+	t.Run("email change", func(t *testing.T) {
+		runEmailChange(t, newBackend)
+	})
+	t.Run("erasure", func(t *testing.T) {
+		runErasure(t, newBackend)
+	})
+	t.Run("attachment", func(t *testing.T) {
+		runAttachment(t, newBackend)
+	})
+	t.Run("transactor", func(t *testing.T) {
+		runTransactor(t, newBackend)
+	})
+	t.Run("pinger", func(t *testing.T) {
+		assert.Nil(t, newBackend(t).Pinger.Ping(context.Background()))
+	})
+}
+
+func createMedicalRecord(owner *entity.User) *entity.MedicalRecord {
+	return &entity.MedicalRecord{
+		User:      owner,
+		Symptom:   "symptom",
+		Diagnosis: "diagnosis",
+		Therapy:   "therapy",
+		Result:    "result",
+	}
+}
+
+// insertMedicalRecords inserts n medical records owned by the user who has the email.
+// The user is inserted as well if it doesn't exist.
+func insertMedicalRecords(t *testing.T, backend *repository.Backend, email string, n int) []*entity.MedicalRecord {
+	owner := insertUser(t, backend, email)
+	var records []*entity.MedicalRecord
+	for i := 0; i < n; i++ {
+		record := createMedicalRecord(owner)
+		assert.Nil(t, backend.MedicalRecordInserter.Insert(context.Background(), record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func recordIDs(records []*entity.MedicalRecord) []uint64 {
+	var ids []uint64
+	for _, record := range records {
+		ids = append(ids, uint64(record.ID))
+	}
+	return ids
+}
+
+// insertUser inserts the user who has the email, or refreshes it if it exists, and returns the stored user.
+func insertUser(t *testing.T, backend *repository.Backend, email string) *entity.User {
+	user, err := backend.UserInserter.Upsert(context.Background(), &entity.User{Email: email, Name: "Name", GoogleID: "google-" + email})
+	assert.Nil(t, err)
+	return user
+}
+
+// userID returns the id of the user who has the email, or zero if there is none.
+func userID(t *testing.T, backend *repository.Backend, email string) uint64 {
+	user, err := backend.UserSelector.FindByEmail(context.Background(), email)
+	if err != nil {
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		return 0
+	}
+	return uint64(user.ID)
+}
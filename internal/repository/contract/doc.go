@@ -0,0 +1,4 @@
+// Package contract holds the behaviour every storage backend must have.
+// Each backend runs the same contract from its own tests,
+// so the in-memory, SQLite, and PostgreSQL backends can be used interchangeably.
+package contract
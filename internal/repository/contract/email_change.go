@@ -0,0 +1,128 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func runEmailChange(t *testing.T, newBackend NewBackend) {
+	t.Run("save empty email change", func(t *testing.T) {
+		backend := newBackend(t)
+
+		err := backend.EmailChanger.Save(context.Background(), nil)
+
+		assert.Equal(t, entity.ErrInvalidEmailChangeRequest, err)
+	})
+
+	t.Run("find missing email change", func(t *testing.T) {
+		backend := newBackend(t)
+		user := insertUser(t, backend, "a@orvosi.com")
+
+		res, err := backend.EmailChanger.FindByUserID(context.Background(), uint64(user.ID))
+
+		assert.Equal(t, entity.ErrInvalidEmailChangeToken, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("save replaces the previous email change", func(t *testing.T) {
+		backend := newBackend(t)
+		user := insertUser(t, backend, "a@orvosi.com")
+		assert.Nil(t, backend.EmailChanger.Save(context.Background(), createEmailChange(user.ID, "b@orvosi.com")))
+		latest := createEmailChange(user.ID, "c@orvosi.com")
+		latest.TokenHash = "latest"
+
+		err := backend.EmailChanger.Save(context.Background(), latest)
+		assert.Nil(t, err)
+
+		res, err := backend.EmailChanger.FindByUserID(context.Background(), uint64(user.ID))
+		assert.Nil(t, err)
+		if assert.NotNil(t, res) {
+			assert.Equal(t, user.ID, res.UserID)
+			assert.Equal(t, "c@orvosi.com", res.Email)
+			assert.Equal(t, "latest", res.TokenHash)
+			assert.WithinDuration(t, latest.RequestedAt, res.RequestedAt, time.Second)
+			assert.WithinDuration(t, latest.ExpiresAt, res.ExpiresAt, time.Second)
+		}
+	})
+
+	t.Run("change email keeps the records and replaces the actor fields", func(t *testing.T) {
+		backend := newBackend(t)
+		records := insertMedicalRecords(t, backend, "a@orvosi.com", 1)
+		insertMedicalRecords(t, backend, "c@orvosi.com", 1)
+		user := records[0].User
+		assert.Nil(t, backend.AttachmentInserter.Insert(context.Background(), createAttachment(uint64(records[0].ID), "key")))
+		assert.Nil(t, backend.EmailChanger.Save(context.Background(), createEmailChange(user.ID, "b@orvosi.com")))
+
+		err := backend.EmailChanger.ChangeEmail(context.Background(), uint64(user.ID), "a@orvosi.com", "b@orvosi.com")
+		assert.Nil(t, err)
+
+		_, err = backend.UserSelector.FindByEmail(context.Background(), "a@orvosi.com")
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		res, err := backend.UserSelector.FindByEmail(context.Background(), "b@orvosi.com")
+		assert.Nil(t, err)
+		if assert.NotNil(t, res) {
+			assert.Equal(t, user.ID, res.ID)
+			assert.Equal(t, "google-a@orvosi.com", res.GoogleID)
+			assert.Equal(t, "b@orvosi.com", res.UpdatedBy)
+		}
+
+		found, err := backend.MedicalRecordSelector.FindByUserID(context.Background(), uint64(user.ID), maxID, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, found, 1) {
+			assert.Equal(t, "b@orvosi.com", found[0].CreatedBy)
+			assert.Equal(t, "b@orvosi.com", found[0].UpdatedBy)
+		}
+		found, err = backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "c@orvosi.com"), maxID, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, found, 1) {
+			assert.Equal(t, "c@orvosi.com", found[0].CreatedBy)
+		}
+		attachments, err := backend.AttachmentSelector.FindByMedicalRecordID(context.Background(), uint64(records[0].ID))
+		assert.Nil(t, err)
+		if assert.Len(t, attachments, 1) {
+			assert.Equal(t, "b@orvosi.com", attachments[0].CreatedBy)
+		}
+
+		_, err = backend.EmailChanger.FindByUserID(context.Background(), uint64(user.ID))
+		assert.Equal(t, entity.ErrInvalidEmailChangeToken, err)
+	})
+
+	t.Run("change email into the email of another user", func(t *testing.T) {
+		backend := newBackend(t)
+		user := insertUser(t, backend, "a@orvosi.com")
+		insertUser(t, backend, "b@orvosi.com")
+
+		err := backend.EmailChanger.ChangeEmail(context.Background(), uint64(user.ID), "a@orvosi.com", "b@orvosi.com")
+
+		if assert.NotNil(t, err) {
+			assert.Equal(t, entity.ErrEmailAlreadyUsed.Code, err.Code)
+		}
+		res, err := backend.UserSelector.FindByEmail(context.Background(), "a@orvosi.com")
+		assert.Nil(t, err)
+		assert.Equal(t, user.ID, res.ID)
+	})
+
+	t.Run("change email of missing user", func(t *testing.T) {
+		backend := newBackend(t)
+
+		err := backend.EmailChanger.ChangeEmail(context.Background(), 1, "a@orvosi.com", "b@orvosi.com")
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+	})
+}
+
+func createEmailChange(userID hashids.ID, email string) *entity.EmailChange {
+	now := time.Now().UTC()
+	return &entity.EmailChange{
+		UserID:      userID,
+		Email:       email,
+		TokenHash:   "hash",
+		RequestedAt: now,
+		ExpiresAt:   now.Add(time.Hour),
+	}
+}
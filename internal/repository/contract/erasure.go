@@ -0,0 +1,147 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func runErasure(t *testing.T, newBackend NewBackend) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("schedule erasure of missing user", func(t *testing.T) {
+		backend := newBackend(t)
+
+		_, err := backend.UserEraser.Schedule(context.Background(), 1, now, now.Add(time.Hour))
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+	})
+
+	t.Run("schedule, find, and cancel erasure", func(t *testing.T) {
+		backend := newBackend(t)
+		a := insertUser(t, backend, "a@orvosi.com")
+
+		_, err := backend.UserEraser.FindByUserID(context.Background(), uint64(a.ID))
+		assert.Equal(t, entity.ErrErasureNotScheduled, err)
+
+		erasure, err := backend.UserEraser.Schedule(context.Background(), uint64(a.ID), now, now.Add(time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, "a@orvosi.com", erasure.Email)
+		assert.Equal(t, a.ID, erasure.UserID)
+		assert.True(t, now.Add(time.Hour).Equal(erasure.EraseAfter))
+
+		again, err := backend.UserEraser.Schedule(context.Background(), uint64(a.ID), now.Add(time.Minute), now.Add(2*time.Hour))
+		assert.Nil(t, err)
+		assert.True(t, erasure.EraseAfter.Equal(again.EraseAfter), "scheduling again must not postpone the erasure")
+
+		found, err := backend.UserEraser.FindByUserID(context.Background(), uint64(a.ID))
+		assert.Nil(t, err)
+		assert.True(t, now.Equal(found.RequestedAt))
+
+		assert.Nil(t, backend.UserEraser.Cancel(context.Background(), uint64(a.ID)))
+		assert.Equal(t, entity.ErrErasureNotScheduled, backend.UserEraser.Cancel(context.Background(), uint64(a.ID)))
+		_, err = backend.UserEraser.FindByUserID(context.Background(), uint64(a.ID))
+		assert.Equal(t, entity.ErrErasureNotScheduled, err)
+	})
+
+	t.Run("find due erasures", func(t *testing.T) {
+		backend := newBackend(t)
+		for i, email := range []string{"a@orvosi.com", "b@orvosi.com", "c@orvosi.com"} {
+			user := insertUser(t, backend, email)
+			_, err := backend.UserEraser.Schedule(context.Background(), uint64(user.ID), now, now.Add(time.Duration(i-1)*time.Hour))
+			assert.Nil(t, err)
+		}
+
+		due, err := backend.UserEraser.FindDue(context.Background(), now, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, due, 2) {
+			assert.Equal(t, "a@orvosi.com", due[0].Email)
+			assert.Equal(t, "b@orvosi.com", due[1].Email)
+		}
+
+		due, err = backend.UserEraser.FindDue(context.Background(), now, 1)
+		assert.Nil(t, err)
+		assert.Len(t, due, 1)
+	})
+
+	t.Run("claim erasure", func(t *testing.T) {
+		backend := newBackend(t)
+		a := insertUser(t, backend, "a@orvosi.com")
+		b := insertUser(t, backend, "b@orvosi.com")
+		_, err := backend.UserEraser.Schedule(context.Background(), uint64(a.ID), now, now)
+		assert.Nil(t, err)
+		_, err = backend.UserEraser.Schedule(context.Background(), uint64(b.ID), now, now.Add(time.Hour))
+		assert.Nil(t, err)
+
+		erasure, err := backend.UserEraser.Claim(context.Background(), uint64(a.ID), now)
+		assert.Nil(t, err)
+		assert.Equal(t, a.ID, erasure.UserID)
+		assert.Equal(t, "a@orvosi.com", erasure.Email)
+
+		_, err = backend.UserEraser.Claim(context.Background(), uint64(b.ID), now)
+		assert.Equal(t, entity.ErrErasureNotScheduled, err, "erasure which isn't due must not be claimed")
+
+		assert.Nil(t, backend.UserEraser.Cancel(context.Background(), uint64(a.ID)))
+		_, err = backend.UserEraser.Claim(context.Background(), uint64(a.ID), now)
+		assert.Equal(t, entity.ErrErasureNotScheduled, err, "cancelled erasure must not be claimed")
+	})
+
+	t.Run("erase user", func(t *testing.T) {
+		backend := newBackend(t)
+		a := insertUser(t, backend, "a@orvosi.com")
+		b := insertUser(t, backend, "b@orvosi.com")
+		_, err := backend.UserEraser.Schedule(context.Background(), uint64(a.ID), now, now)
+		assert.Nil(t, err)
+		assert.Nil(t, backend.ProfileUpdater.UpdateProfile(context.Background(), "a@orvosi.com", &entity.Profile{DisplayName: "dr. A"}))
+		assert.Nil(t, backend.IdentityLinker.Link(context.Background(), createIdentity(a.ID, "google-a@orvosi.com")))
+		assert.Nil(t, backend.EmailChanger.Save(context.Background(), createEmailChange(a.ID, "c@orvosi.com")))
+
+		own := insertMedicalRecords(t, backend, "a@orvosi.com", 2)
+		assert.Nil(t, backend.AttachmentInserter.Insert(context.Background(), createAttachment(uint64(own[0].ID), "key-a")))
+		other := insertMedicalRecords(t, backend, "b@orvosi.com", 1)
+		update := createMedicalRecord(a)
+		assert.Nil(t, backend.MedicalRecordUpdater.Update(context.Background(), uint64(other[0].ID), uint64(b.ID), update))
+		assert.Nil(t, backend.AttachmentInserter.Insert(context.Background(), createAttachment(uint64(other[0].ID), "key-b")))
+
+		keys, err := backend.UserEraser.Erase(context.Background(), uint64(a.ID), "a@orvosi.com", "erased-1@invalid")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"key-a"}, keys)
+
+		pending, err := backend.StorageDeleter.FindPending(context.Background(), 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"key-a"}, pending)
+		assert.Nil(t, backend.StorageDeleter.Done(context.Background(), "key-a"))
+		pending, err = backend.StorageDeleter.FindPending(context.Background(), 10)
+		assert.Nil(t, err)
+		assert.Empty(t, pending)
+
+		_, err = backend.UserSelector.FindByEmail(context.Background(), "a@orvosi.com")
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		_, err = backend.UserInserter.FindByIdentity(context.Background(), entity.ProviderGoogle, "google-a@orvosi.com")
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		_, err = backend.EmailChanger.FindByUserID(context.Background(), uint64(a.ID))
+		assert.Equal(t, entity.ErrInvalidEmailChangeToken, err)
+		user, err := backend.UserInserter.Upsert(context.Background(), &entity.User{Email: "a@orvosi.com", Name: "Name", GoogleID: "google-a@orvosi.com"})
+		assert.Nil(t, err)
+		assert.Equal(t, entity.Profile{}, user.Profile)
+		due, err := backend.UserEraser.FindDue(context.Background(), now, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, due)
+		_, err = backend.MedicalRecordSelector.FindByID(context.Background(), uint64(own[0].ID))
+		assert.Equal(t, entity.ErrMedicalRecordNotFound, err)
+
+		res, err := backend.MedicalRecordSelector.FindByID(context.Background(), uint64(other[0].ID))
+		assert.Nil(t, err)
+		assert.Equal(t, "b@orvosi.com", res.CreatedBy)
+		assert.Equal(t, "erased-1@invalid", res.UpdatedBy)
+
+		attachments, err := backend.AttachmentSelector.FindByMedicalRecordID(context.Background(), uint64(other[0].ID))
+		assert.Nil(t, err)
+		if assert.Len(t, attachments, 1) {
+			assert.Equal(t, "erased-1@invalid", attachments[0].CreatedBy)
+		}
+	})
+}
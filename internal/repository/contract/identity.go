@@ -0,0 +1,165 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func runIdentity(t *testing.T, newBackend NewBackend) {
+	t.Run("link empty identity", func(t *testing.T) {
+		backend := newBackend(t)
+
+		err := backend.IdentityLinker.Link(context.Background(), nil)
+
+		assert.Equal(t, entity.ErrInvalidIdentityRequest, err)
+	})
+
+	t.Run("link assigns id and finds the user by the identity", func(t *testing.T) {
+		backend := newBackend(t)
+		user := insertUser(t, backend, "a@orvosi.com")
+		identity := createIdentity(user.ID, "google-a@orvosi.com")
+
+		err := backend.IdentityLinker.Link(context.Background(), identity)
+		assert.Nil(t, err)
+		assert.NotZero(t, identity.ID)
+		assert.WithinDuration(t, time.Now(), identity.CreatedAt, time.Minute)
+
+		res, err := backend.UserInserter.FindByIdentity(context.Background(), entity.ProviderGoogle, "google-a@orvosi.com")
+		assert.Nil(t, err)
+		if assert.NotNil(t, res) {
+			assert.Equal(t, user.ID, res.ID)
+			assert.Equal(t, "a@orvosi.com", res.Email)
+		}
+	})
+
+	t.Run("find user by unknown identity", func(t *testing.T) {
+		backend := newBackend(t)
+		insertUser(t, backend, "a@orvosi.com")
+
+		res, err := backend.UserInserter.FindByIdentity(context.Background(), entity.ProviderGoogle, "google-a@orvosi.com")
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("identity can only be linked once", func(t *testing.T) {
+		backend := newBackend(t)
+		a := insertUser(t, backend, "a@orvosi.com")
+		b := insertUser(t, backend, "b@orvosi.com")
+		assert.Nil(t, backend.IdentityLinker.Link(context.Background(), createIdentity(a.ID, "subject")))
+
+		err := backend.IdentityLinker.Link(context.Background(), createIdentity(b.ID, "subject"))
+
+		if assert.NotNil(t, err) {
+			assert.Equal(t, entity.ErrIdentityAlreadyLinked.Code, err.Code)
+		}
+	})
+
+	t.Run("find identities of the user ordered by id", func(t *testing.T) {
+		backend := newBackend(t)
+		a := insertUser(t, backend, "a@orvosi.com")
+		b := insertUser(t, backend, "b@orvosi.com")
+		first := createIdentity(a.ID, "subject-1")
+		second := createIdentity(a.ID, "subject-2")
+		assert.Nil(t, backend.IdentityLinker.Link(context.Background(), first))
+		assert.Nil(t, backend.IdentityLinker.Link(context.Background(), createIdentity(b.ID, "subject-3")))
+		assert.Nil(t, backend.IdentityLinker.Link(context.Background(), second))
+
+		res, err := backend.IdentityLinker.FindByUserID(context.Background(), uint64(a.ID))
+
+		assert.Nil(t, err)
+		if assert.Len(t, res, 2) {
+			assert.Equal(t, first.ID, res[0].ID)
+			assert.Equal(t, a.ID, res[0].UserID)
+			assert.Equal(t, entity.ProviderGoogle, res[0].Provider)
+			assert.Equal(t, "subject-1", res[0].Subject)
+			assert.Equal(t, "subject-1@gmail.com", res[0].Email)
+			assert.Equal(t, second.ID, res[1].ID)
+		}
+	})
+
+	t.Run("find identities of the user who has none", func(t *testing.T) {
+		backend := newBackend(t)
+
+		res, err := backend.IdentityLinker.FindByUserID(context.Background(), 1)
+
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("unlink identity of other user returns not found", func(t *testing.T) {
+		backend := newBackend(t)
+		a := insertUser(t, backend, "a@orvosi.com")
+		b := insertUser(t, backend, "b@orvosi.com")
+		identity := createIdentity(a.ID, "subject")
+		assert.Nil(t, backend.IdentityLinker.Link(context.Background(), identity))
+
+		err := backend.IdentityLinker.Unlink(context.Background(), uint64(b.ID), uint64(identity.ID))
+		assert.Equal(t, entity.ErrIdentityNotFound, err)
+
+		res, err := backend.IdentityLinker.FindByUserID(context.Background(), uint64(a.ID))
+		assert.Nil(t, err)
+		assert.Len(t, res, 1)
+	})
+
+	t.Run("unlink identity keeps the google id which refers to another identity", func(t *testing.T) {
+		backend := newBackend(t)
+		user := insertUser(t, backend, "a@orvosi.com")
+		current := createIdentity(user.ID, "google-a@orvosi.com")
+		other := createIdentity(user.ID, "subject")
+		assert.Nil(t, backend.IdentityLinker.Link(context.Background(), current))
+		assert.Nil(t, backend.IdentityLinker.Link(context.Background(), other))
+
+		err := backend.IdentityLinker.Unlink(context.Background(), uint64(user.ID), uint64(other.ID))
+		assert.Nil(t, err)
+
+		res, err := backend.UserSelector.FindByEmail(context.Background(), "a@orvosi.com")
+		assert.Nil(t, err)
+		assert.Equal(t, "google-a@orvosi.com", res.GoogleID)
+		_, err = backend.UserInserter.FindByIdentity(context.Background(), entity.ProviderGoogle, "subject")
+		assert.Equal(t, entity.ErrUserNotFound, err)
+	})
+
+	t.Run("unlink identity of the google id replaces it with the oldest remaining identity", func(t *testing.T) {
+		backend := newBackend(t)
+		user := insertUser(t, backend, "a@orvosi.com")
+		current := createIdentity(user.ID, "google-a@orvosi.com")
+		assert.Nil(t, backend.IdentityLinker.Link(context.Background(), createIdentity(user.ID, "subject-1")))
+		assert.Nil(t, backend.IdentityLinker.Link(context.Background(), current))
+		assert.Nil(t, backend.IdentityLinker.Link(context.Background(), createIdentity(user.ID, "subject-2")))
+
+		err := backend.IdentityLinker.Unlink(context.Background(), uint64(user.ID), uint64(current.ID))
+		assert.Nil(t, err)
+
+		res, err := backend.UserSelector.FindByEmail(context.Background(), "a@orvosi.com")
+		assert.Nil(t, err)
+		assert.Equal(t, "subject-1", res.GoogleID)
+	})
+
+	t.Run("refresh the token fields of the user", func(t *testing.T) {
+		backend := newBackend(t)
+		user := insertUser(t, backend, "a@orvosi.com")
+
+		err := backend.UserInserter.Refresh(context.Background(), uint64(user.ID), "dr. A", "subject")
+		assert.Nil(t, err)
+
+		res, err := backend.UserSelector.FindByEmail(context.Background(), "a@orvosi.com")
+		assert.Nil(t, err)
+		assert.Equal(t, "dr. A", res.Name)
+		assert.Equal(t, "subject", res.GoogleID)
+	})
+}
+
+func createIdentity(userID hashids.ID, subject string) *entity.Identity {
+	return &entity.Identity{
+		UserID:   userID,
+		Provider: entity.ProviderGoogle,
+		Subject:  subject,
+		Email:    subject + "@gmail.com",
+	}
+}
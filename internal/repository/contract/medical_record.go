@@ -0,0 +1,307 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func runMedicalRecord(t *testing.T, newBackend NewBackend) {
+	t.Run("insert empty medical record", func(t *testing.T) {
+		backend := newBackend(t)
+
+		err := backend.MedicalRecordInserter.Insert(context.Background(), nil)
+
+		assert.Equal(t, entity.ErrEmptyMedicalRecord, err)
+	})
+
+	t.Run("insert assigns id and ignores result", func(t *testing.T) {
+		backend := newBackend(t)
+		owner := insertUser(t, backend, "a@orvosi.com")
+		record := createMedicalRecord(owner)
+
+		err := backend.MedicalRecordInserter.Insert(context.Background(), record)
+		assert.Nil(t, err)
+		assert.NotZero(t, record.ID)
+
+		res, err := backend.MedicalRecordSelector.FindByID(context.Background(), uint64(record.ID))
+		assert.Nil(t, err)
+		if assert.NotNil(t, res) {
+			assert.Equal(t, record.ID, res.ID)
+			assert.Equal(t, owner.ID, res.User.ID)
+			assert.Equal(t, "symptom", res.Symptom)
+			assert.Equal(t, "diagnosis", res.Diagnosis)
+			assert.Equal(t, "therapy", res.Therapy)
+			assert.Empty(t, res.Result)
+			assert.Equal(t, "a@orvosi.com", res.CreatedBy)
+			assert.Equal(t, "a@orvosi.com", res.UpdatedBy)
+			assert.WithinDuration(t, time.Now(), res.CreatedAt, time.Minute)
+		}
+	})
+
+	t.Run("insert many keeps result", func(t *testing.T) {
+		backend := newBackend(t)
+		owner := insertUser(t, backend, "a@orvosi.com")
+		records := []*entity.MedicalRecord{createMedicalRecord(owner), createMedicalRecord(owner)}
+
+		err := backend.MedicalRecordInserter.InsertMany(context.Background(), records)
+		assert.Nil(t, err)
+		assert.NotZero(t, records[0].ID)
+		assert.NotEqual(t, records[0].ID, records[1].ID)
+
+		for _, record := range records {
+			res, err := backend.MedicalRecordSelector.FindByID(context.Background(), uint64(record.ID))
+			assert.Nil(t, err)
+			if assert.NotNil(t, res) {
+				assert.Equal(t, "result", res.Result)
+			}
+		}
+	})
+
+	t.Run("insert many inserts nothing if any medical record is empty", func(t *testing.T) {
+		backend := newBackend(t)
+		records := []*entity.MedicalRecord{createMedicalRecord(insertUser(t, backend, "a@orvosi.com")), nil}
+
+		err := backend.MedicalRecordInserter.InsertMany(context.Background(), records)
+		assert.Equal(t, entity.ErrEmptyMedicalRecord, err)
+
+		res, err := backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "a@orvosi.com"), maxID, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("find missing medical record by id", func(t *testing.T) {
+		backend := newBackend(t)
+
+		res, err := backend.MedicalRecordSelector.FindByID(context.Background(), 1)
+
+		assert.Equal(t, entity.ErrMedicalRecordNotFound, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("find by email returns the newest first and paginates by id", func(t *testing.T) {
+		backend := newBackend(t)
+		records := insertMedicalRecords(t, backend, "a@orvosi.com", 3)
+		insertMedicalRecords(t, backend, "b@orvosi.com", 1)
+
+		res, err := backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "a@orvosi.com"), maxID, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{uint64(records[2].ID), uint64(records[1].ID)}, recordIDs(res))
+
+		res, err = backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "a@orvosi.com"), uint64(records[1].ID), 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{uint64(records[0].ID)}, recordIDs(res))
+
+		res, err = backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "a@orvosi.com"), uint64(records[0].ID), 2)
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("find by email walks through all pages exactly once", func(t *testing.T) {
+		backend := newBackend(t)
+		var want []uint64
+		for i := 0; i < 3; i++ {
+			want = append([]uint64{uint64(insertMedicalRecords(t, backend, "a@orvosi.com", 1)[0].ID)}, want...)
+			insertMedicalRecords(t, backend, "b@orvosi.com", 1)
+		}
+		want = append([]uint64{uint64(insertMedicalRecords(t, backend, "a@orvosi.com", 1)[0].ID)}, want...)
+
+		var got []uint64
+		from := maxID
+		for page := 0; page < 10; page++ {
+			res, err := backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "a@orvosi.com"), from, 2)
+			assert.Nil(t, err)
+			if len(res) == 0 {
+				break
+			}
+			got = append(got, recordIDs(res)...)
+			from = uint64(res[len(res)-1].ID)
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("find by email starting from the default of the handler", func(t *testing.T) {
+		backend := newBackend(t)
+		records := insertMedicalRecords(t, backend, "a@orvosi.com", 1)
+
+		res, err := backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "a@orvosi.com"), 1<<32-1, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, recordIDs(records), recordIDs(res))
+	})
+
+	t.Run("find by email from zero", func(t *testing.T) {
+		backend := newBackend(t)
+		insertMedicalRecords(t, backend, "a@orvosi.com", 1)
+
+		res, err := backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "a@orvosi.com"), 0, 10)
+
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("find by email with limit larger than the records", func(t *testing.T) {
+		backend := newBackend(t)
+		records := insertMedicalRecords(t, backend, "a@orvosi.com", 2)
+
+		res, err := backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "a@orvosi.com"), maxID, 100)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{uint64(records[1].ID), uint64(records[0].ID)}, recordIDs(res))
+	})
+
+	t.Run("find by email with zero limit", func(t *testing.T) {
+		backend := newBackend(t)
+		insertMedicalRecords(t, backend, "a@orvosi.com", 1)
+
+		res, err := backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "a@orvosi.com"), maxID, 0)
+
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("find by unknown email", func(t *testing.T) {
+		backend := newBackend(t)
+		insertMedicalRecords(t, backend, "a@orvosi.com", 1)
+
+		res, err := backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "unknown@orvosi.com"), maxID, 10)
+
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("find by email within period", func(t *testing.T) {
+		backend := newBackend(t)
+		since := time.Now().Add(-time.Minute)
+		records := insertMedicalRecords(t, backend, "a@orvosi.com", 2)
+		until := time.Now().Add(time.Minute)
+
+		res, err := backend.MedicalRecordSelector.FindByUserIDWithinPeriod(context.Background(), userID(t, backend, "a@orvosi.com"), since, until, maxID, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{uint64(records[1].ID), uint64(records[0].ID)}, recordIDs(res))
+
+		res, err = backend.MedicalRecordSelector.FindByUserIDWithinPeriod(context.Background(), userID(t, backend, "a@orvosi.com"), until, until.Add(time.Hour), maxID, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+
+		res, err = backend.MedicalRecordSelector.FindByUserIDWithinPeriod(context.Background(), userID(t, backend, "a@orvosi.com"), since.Add(-time.Hour), since, maxID, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("find by email within period in other time zone", func(t *testing.T) {
+		backend := newBackend(t)
+		zone := time.FixedZone("WIB", 7*60*60)
+		since := time.Now().Add(-time.Minute).In(zone)
+		insertMedicalRecords(t, backend, "a@orvosi.com", 1)
+		until := time.Now().Add(time.Minute).In(zone)
+
+		res, err := backend.MedicalRecordSelector.FindByUserIDWithinPeriod(context.Background(), userID(t, backend, "a@orvosi.com"), since, until, maxID, 10)
+
+		assert.Nil(t, err)
+		assert.Len(t, res, 1)
+	})
+
+	t.Run("update the whole record", func(t *testing.T) {
+		backend := newBackend(t)
+		records := insertMedicalRecords(t, backend, "a@orvosi.com", 1)
+		id := uint64(records[0].ID)
+		update := &entity.MedicalRecord{
+			User:      insertUser(t, backend, "b@orvosi.com"),
+			Symptom:   "new symptom",
+			Diagnosis: "new diagnosis",
+			Therapy:   "new therapy",
+			Result:    "new result",
+		}
+
+		err := backend.MedicalRecordUpdater.Update(context.Background(), id, uint64(records[0].User.ID), update)
+		assert.Nil(t, err)
+
+		res, err := backend.MedicalRecordSelector.FindByID(context.Background(), id)
+		assert.Nil(t, err)
+		if assert.NotNil(t, res) {
+			assert.Equal(t, "new symptom", res.Symptom)
+			assert.Equal(t, "new diagnosis", res.Diagnosis)
+			assert.Equal(t, "new therapy", res.Therapy)
+			assert.Equal(t, "new result", res.Result)
+			assert.Equal(t, records[0].User.ID, res.User.ID)
+			assert.Equal(t, "a@orvosi.com", res.CreatedBy)
+			assert.Equal(t, "b@orvosi.com", res.UpdatedBy)
+		}
+	})
+
+	t.Run("update missing record returns not found", func(t *testing.T) {
+		backend := newBackend(t)
+		owner := insertUser(t, backend, "a@orvosi.com")
+
+		err := backend.MedicalRecordUpdater.Update(context.Background(), 1, uint64(owner.ID), createMedicalRecord(owner))
+
+		assert.Equal(t, entity.ErrMedicalRecordNotFound, err)
+	})
+
+	t.Run("update record owned by other user returns not found", func(t *testing.T) {
+		backend := newBackend(t)
+		records := insertMedicalRecords(t, backend, "a@orvosi.com", 1)
+		id := uint64(records[0].ID)
+		other := insertUser(t, backend, "b@orvosi.com")
+		update := createMedicalRecord(other)
+		update.Symptom = "changed"
+
+		err := backend.MedicalRecordUpdater.Update(context.Background(), id, uint64(other.ID), update)
+		assert.Equal(t, entity.ErrMedicalRecordNotFound, err)
+
+		res, err := backend.MedicalRecordSelector.FindByID(context.Background(), id)
+		assert.Nil(t, err)
+		if assert.NotNil(t, res) {
+			assert.Equal(t, "symptom", res.Symptom)
+		}
+	})
+	t.Run("reassign records to another email", func(t *testing.T) {
+		backend := newBackend(t)
+		records := insertMedicalRecords(t, backend, "a@orvosi.com", 2)
+		insertMedicalRecords(t, backend, "c@orvosi.com", 1)
+		insertUser(t, backend, "b@orvosi.com")
+
+		total, err := backend.MedicalRecordReassigner.ReassignByEmail(context.Background(), "a@orvosi.com", "b@orvosi.com")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+
+		res, err := backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "a@orvosi.com"), maxID, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+
+		res, err = backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "b@orvosi.com"), maxID, 10)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, recordIDs(records), recordIDs(res))
+		for _, record := range res {
+			assert.Equal(t, "a@orvosi.com", record.CreatedBy)
+		}
+
+		res, err = backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "c@orvosi.com"), maxID, 10)
+		assert.Nil(t, err)
+		assert.Len(t, res, 1)
+	})
+
+	t.Run("reassign records to missing user", func(t *testing.T) {
+		backend := newBackend(t)
+		insertMedicalRecords(t, backend, "a@orvosi.com", 1)
+
+		total, err := backend.MedicalRecordReassigner.ReassignByEmail(context.Background(), "a@orvosi.com", "b@orvosi.com")
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		assert.Zero(t, total)
+	})
+
+	t.Run("reassign email which owns nothing", func(t *testing.T) {
+		backend := newBackend(t)
+		insertUser(t, backend, "b@orvosi.com")
+
+		total, err := backend.MedicalRecordReassigner.ReassignByEmail(context.Background(), "a@orvosi.com", "b@orvosi.com")
+
+		assert.Nil(t, err)
+		assert.Zero(t, total)
+	})
+}
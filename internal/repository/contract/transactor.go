@@ -0,0 +1,90 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func runTransactor(t *testing.T, newBackend NewBackend) {
+	t.Run("commit keeps all changes", func(t *testing.T) {
+		backend := newBackend(t)
+		owner := insertUser(t, backend, "a@orvosi.com")
+
+		err := backend.Transactor.WithinTransaction(context.Background(), func(ctx context.Context) *entity.Error {
+			if err := backend.MedicalRecordInserter.Insert(ctx, createMedicalRecord(owner)); err != nil {
+				return err
+			}
+			return backend.MedicalRecordInserter.Insert(ctx, createMedicalRecord(owner))
+		})
+		assert.Nil(t, err)
+
+		res, err := backend.MedicalRecordSelector.FindByUserID(context.Background(), uint64(owner.ID), maxID, 10)
+		assert.Nil(t, err)
+		assert.Len(t, res, 2)
+	})
+
+	t.Run("changes are visible inside the transaction", func(t *testing.T) {
+		backend := newBackend(t)
+		owner := insertUser(t, backend, "a@orvosi.com")
+
+		err := backend.Transactor.WithinTransaction(context.Background(), func(ctx context.Context) *entity.Error {
+			record := createMedicalRecord(owner)
+			if err := backend.MedicalRecordInserter.Insert(ctx, record); err != nil {
+				return err
+			}
+			res, err := backend.MedicalRecordSelector.FindByUserID(ctx, uint64(owner.ID), maxID, 10)
+			assert.Len(t, res, 1)
+			return err
+		})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("error rolls back all changes", func(t *testing.T) {
+		backend := newBackend(t)
+		owner := insertUser(t, backend, "a@orvosi.com")
+		records := insertMedicalRecords(t, backend, owner.Email, 1)
+
+		err := backend.Transactor.WithinTransaction(context.Background(), func(ctx context.Context) *entity.Error {
+			if err := backend.MedicalRecordInserter.Insert(ctx, createMedicalRecord(owner)); err != nil {
+				return err
+			}
+			update := createMedicalRecord(owner)
+			update.Symptom = "changed"
+			if err := backend.MedicalRecordUpdater.Update(ctx, uint64(records[0].ID), uint64(owner.ID), update); err != nil {
+				return err
+			}
+			return entity.ErrInternalServer
+		})
+		assert.Equal(t, entity.ErrInternalServer, err)
+
+		res, err := backend.MedicalRecordSelector.FindByUserID(context.Background(), uint64(owner.ID), maxID, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, res, 1) {
+			assert.Equal(t, "symptom", res[0].Symptom)
+		}
+	})
+
+	t.Run("nested transaction joins the outer one", func(t *testing.T) {
+		backend := newBackend(t)
+		owner := insertUser(t, backend, "a@orvosi.com")
+
+		err := backend.Transactor.WithinTransaction(context.Background(), func(ctx context.Context) *entity.Error {
+			err := backend.Transactor.WithinTransaction(ctx, func(ctx context.Context) *entity.Error {
+				return backend.MedicalRecordInserter.Insert(ctx, createMedicalRecord(owner))
+			})
+			if err != nil {
+				return err
+			}
+			return entity.ErrInternalServer
+		})
+		assert.Equal(t, entity.ErrInternalServer, err)
+
+		res, err := backend.MedicalRecordSelector.FindByUserID(context.Background(), uint64(owner.ID), maxID, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+	})
+}
@@ -0,0 +1,191 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func runUser(t *testing.T, newBackend NewBackend) {
+	t.Run("upsert empty user", func(t *testing.T) {
+		backend := newBackend(t)
+
+		res, err := backend.UserInserter.Upsert(context.Background(), nil)
+
+		assert.Equal(t, entity.ErrEmptyUser, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("upsert the same email twice refreshes the token fields", func(t *testing.T) {
+		backend := newBackend(t)
+
+		first, err := backend.UserInserter.Upsert(context.Background(), &entity.User{Email: "a@orvosi.com", Name: "A", GoogleID: "google-a"})
+		assert.Nil(t, err)
+		assert.NotZero(t, first.ID)
+		assert.Equal(t, "A", first.Name)
+
+		second, err := backend.UserInserter.Upsert(context.Background(), &entity.User{Email: "a@orvosi.com", Name: "dr. A", GoogleID: "google-a2"})
+		assert.Nil(t, err)
+		assert.Equal(t, first.ID, second.ID)
+		assert.Equal(t, "dr. A", second.Name)
+		assert.Equal(t, "google-a2", second.GoogleID)
+		assert.Equal(t, "a@orvosi.com", second.CreatedBy)
+
+		users, err := backend.UserSelector.FindAll(context.Background(), 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+	})
+
+	t.Run("google id must be unique", func(t *testing.T) {
+		backend := newBackend(t)
+
+		_, err := backend.UserInserter.Upsert(context.Background(), &entity.User{Email: "a@orvosi.com", GoogleID: "google"})
+		assert.Nil(t, err)
+
+		_, err = backend.UserInserter.Upsert(context.Background(), &entity.User{Email: "b@orvosi.com", GoogleID: "google"})
+		if assert.NotNil(t, err) {
+			assert.Equal(t, entity.ErrAlreadyExists.Code, err.Code)
+		}
+	})
+
+	t.Run("insert doesn't take over the email of another user", func(t *testing.T) {
+		backend := newBackend(t)
+
+		first, err := backend.UserInserter.Insert(context.Background(), &entity.User{Email: "a@orvosi.com", Name: "A", GoogleID: "google-a"})
+		assert.Nil(t, err)
+		assert.NotZero(t, first.ID)
+
+		res, err := backend.UserInserter.Insert(context.Background(), &entity.User{Email: "a@orvosi.com", Name: "B", GoogleID: "google-b"})
+		assert.Equal(t, entity.ErrEmailAlreadyUsed.Code, err.Code)
+		assert.Nil(t, res)
+
+		stored, err := backend.UserSelector.FindByEmail(context.Background(), "a@orvosi.com")
+		assert.Nil(t, err)
+		assert.Equal(t, "A", stored.Name)
+		assert.Equal(t, "google-a", stored.GoogleID)
+	})
+
+	t.Run("update profile of missing user", func(t *testing.T) {
+		backend := newBackend(t)
+
+		err := backend.ProfileUpdater.UpdateProfile(context.Background(), "a@orvosi.com", &entity.Profile{Specialty: "Cardiology"})
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+	})
+
+	t.Run("update profile", func(t *testing.T) {
+		backend := newBackend(t)
+		insertUser(t, backend, "a@orvosi.com")
+		profile := entity.Profile{
+			DisplayName:       "dr. A",
+			Specialty:         "Cardiology",
+			LicenseNumber:     "SIP-123",
+			Timezone:          "Asia/Jakarta",
+			PreferredLanguage: "id",
+		}
+
+		user, err := backend.UserSelector.FindByEmail(context.Background(), "a@orvosi.com")
+		assert.Nil(t, err)
+		assert.Equal(t, entity.Profile{}, user.Profile)
+
+		assert.Nil(t, backend.ProfileUpdater.UpdateProfile(context.Background(), "a@orvosi.com", &profile))
+		user, err = backend.UserSelector.FindByEmail(context.Background(), "a@orvosi.com")
+		assert.Nil(t, err)
+		assert.Equal(t, profile, user.Profile)
+
+		profile.LicenseNumber = ""
+		assert.Nil(t, backend.ProfileUpdater.UpdateProfile(context.Background(), "a@orvosi.com", &profile))
+		user, err = backend.UserInserter.Upsert(context.Background(), &entity.User{Email: "a@orvosi.com", Name: "Name", GoogleID: "google-a@orvosi.com"})
+		assert.Nil(t, err)
+		assert.Equal(t, profile, user.Profile)
+	})
+
+	t.Run("find users", func(t *testing.T) {
+		backend := newBackend(t)
+		for _, email := range []string{"a@orvosi.com", "b@orvosi.com", "c@orvosi.com"} {
+			insertUser(t, backend, email)
+		}
+
+		res, err := backend.UserSelector.FindAll(context.Background(), 0, 2)
+		assert.Nil(t, err)
+		if assert.Len(t, res, 2) {
+			assert.Equal(t, "a@orvosi.com", res[0].Email)
+			assert.Equal(t, "b@orvosi.com", res[1].Email)
+		}
+
+		res, err = backend.UserSelector.FindAll(context.Background(), uint64(res[1].ID), 2)
+		assert.Nil(t, err)
+		if assert.Len(t, res, 1) {
+			assert.Equal(t, "c@orvosi.com", res[0].Email)
+		}
+
+		user, err := backend.UserSelector.FindByEmail(context.Background(), "b@orvosi.com")
+		assert.Nil(t, err)
+		assert.Equal(t, "Name", user.Name)
+		assert.Equal(t, "google-b@orvosi.com", user.GoogleID)
+		assert.Equal(t, "b@orvosi.com", user.CreatedBy)
+		assert.NotZero(t, user.ID)
+
+		_, err = backend.UserSelector.FindByEmail(context.Background(), "d@orvosi.com")
+		assert.Equal(t, entity.ErrUserNotFound, err)
+	})
+
+	t.Run("anonymize missing user", func(t *testing.T) {
+		backend := newBackend(t)
+
+		err := backend.UserAnonymizer.Anonymize(context.Background(), 1, "a@orvosi.com", "anonymized-1@invalid")
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+	})
+
+	t.Run("anonymize user", func(t *testing.T) {
+		backend := newBackend(t)
+		owner := insertUser(t, backend, "a@orvosi.com")
+		assert.Nil(t, backend.ProfileUpdater.UpdateProfile(context.Background(), "a@orvosi.com", &entity.Profile{DisplayName: "dr. A"}))
+		assert.Nil(t, backend.IdentityLinker.Link(context.Background(), createIdentity(owner.ID, "google-a@orvosi.com")))
+		assert.Nil(t, backend.EmailChanger.Save(context.Background(), createEmailChange(owner.ID, "c@orvosi.com")))
+		records := insertMedicalRecords(t, backend, "a@orvosi.com", 2)
+		insertMedicalRecords(t, backend, "b@orvosi.com", 1)
+		assert.Nil(t, backend.AttachmentInserter.Insert(context.Background(), createAttachment(uint64(records[0].ID), "key")))
+
+		err := backend.UserAnonymizer.Anonymize(context.Background(), uint64(owner.ID), "a@orvosi.com", "anonymized-1@invalid")
+		assert.Nil(t, err)
+
+		_, err = backend.UserSelector.FindByEmail(context.Background(), "a@orvosi.com")
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		user, err := backend.UserSelector.FindByEmail(context.Background(), "anonymized-1@invalid")
+		assert.Nil(t, err)
+		assert.Empty(t, user.Name)
+		assert.NotEqual(t, "google-a@orvosi.com", user.GoogleID)
+		assert.Equal(t, entity.Profile{}, user.Profile)
+		assert.Equal(t, "anonymized-1@invalid", user.CreatedBy)
+		_, err = backend.UserInserter.FindByIdentity(context.Background(), entity.ProviderGoogle, "google-a@orvosi.com")
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		_, err = backend.EmailChanger.FindByUserID(context.Background(), uint64(owner.ID))
+		assert.Equal(t, entity.ErrInvalidEmailChangeToken, err)
+
+		res, err := backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "a@orvosi.com"), maxID, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+		res, err = backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "anonymized-1@invalid"), maxID, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, res, 2) {
+			assert.Equal(t, "anonymized-1@invalid", res[0].CreatedBy)
+			assert.Equal(t, "anonymized-1@invalid", res[0].UpdatedBy)
+		}
+		res, err = backend.MedicalRecordSelector.FindByUserID(context.Background(), userID(t, backend, "b@orvosi.com"), maxID, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, res, 1) {
+			assert.Equal(t, "b@orvosi.com", res[0].CreatedBy)
+		}
+
+		attachments, err := backend.AttachmentSelector.FindByMedicalRecordID(context.Background(), uint64(records[0].ID))
+		assert.Nil(t, err)
+		if assert.Len(t, attachments, 1) {
+			assert.Equal(t, "anonymized-1@invalid", attachments[0].CreatedBy)
+			assert.Equal(t, "anonymized-1@invalid", attachments[0].UpdatedBy)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/jackc/pgconn"
+)
+
+const (
+	// pgUniqueViolation is SQLSTATE of error when a unique constraint is violated.
+	pgUniqueViolation = "23505"
+	// pgQueryCanceled is SQLSTATE of error when a query is canceled, mostly due to statement_timeout.
+	pgQueryCanceled = "57014"
+)
+
+// databaseError maps error returned by the database onto entity.Error,
+// so the caller can tell a conflict or a timeout apart from an unexpected failure.
+// The message is put in front of the database error as the internal message.
+func databaseError(err error, message string) *entity.Error {
+	return entity.WrapError(databaseErrorKind(err), message+err.Error())
+}
+
+func databaseErrorKind(err error) *entity.Error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return entity.ErrAlreadyExists
+		case pgSerializationFailure, pgDeadlockDetected:
+			return entity.ErrConcurrentUpdate
+		case pgQueryCanceled:
+			return entity.ErrTimeout
+		}
+		return entity.ErrInternalServer
+	}
+	if errors.Is(err, context.DeadlineExceeded) || pgconn.Timeout(err) {
+		return entity.ErrTimeout
+	}
+	if kind, ok := sqliteErrorKind(err); ok {
+		return kind
+	}
+	return entity.ErrInternalServer
+}
+
+// conflictError is the same as databaseError, except a unique violation is mapped onto conflict,
+// so the caller can tell which data is already used.
+func conflictError(err error, conflict *entity.Error, message string) *entity.Error {
+	kind := databaseErrorKind(err)
+	if kind == entity.ErrAlreadyExists {
+		kind = conflict
+	}
+	return entity.WrapError(kind, message+err.Error())
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// DatabasePinger checks the connection to the database.
+type DatabasePinger struct {
+	db *sql.DB
+}
+
+// NewDatabasePinger creates an instance of DatabasePinger.
+func NewDatabasePinger(db *sql.DB) *DatabasePinger {
+	return &DatabasePinger{db: db}
+}
+
+// Ping pings the database using a connection from the pool.
+func (dp *DatabasePinger) Ping(ctx context.Context) *entity.Error {
+	if err := dp.db.PingContext(ctx); err != nil {
+		return entity.WrapError(entity.ErrServiceUnavailable, "[DatabasePinger-Ping] "+err.Error())
+	}
+	return nil
+}
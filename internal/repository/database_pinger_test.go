@@ -0,0 +1,58 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type DatabasePingerExecutor struct {
+	repo *repository.DatabasePinger
+	sql  sqlmock.Sqlmock
+}
+
+func TestNewDatabasePinger(t *testing.T) {
+	t.Run("successfully create an instance of DatabasePinger", func(t *testing.T) {
+		exec := createDatabasePingerExecutor()
+		assert.NotNil(t, exec.repo)
+	})
+}
+
+func TestDatabasePinger_Ping(t *testing.T) {
+	t.Run("database is unreachable", func(t *testing.T) {
+		exec := createDatabasePingerExecutor()
+		exec.sql.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+		err := exec.repo.Ping(context.Background())
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrServiceUnavailable.Code, err.Code)
+	})
+
+	t.Run("successfully ping database", func(t *testing.T) {
+		exec := createDatabasePingerExecutor()
+		exec.sql.ExpectPing()
+
+		err := exec.repo.Ping(context.Background())
+
+		assert.Nil(t, err)
+	})
+}
+
+func createDatabasePingerExecutor() *DatabasePingerExecutor {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		log.Panicf("[createDatabasePingerExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	return &DatabasePingerExecutor{
+		repo: repository.NewDatabasePinger(db),
+		sql:  mock,
+	}
+}
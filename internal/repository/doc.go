@@ -1,2 +1,6 @@
 // Package repository provides real connection to storage.
+//
+// The SQL repositories only use SQL which is understood by both PostgreSQL and SQLite (3.35 or later),
+// such as numbered placeholders in order of their appearance, `RETURNING`, and `ON CONFLICT`.
+// Time is stored in UTC, so it can be compared as text in SQLite.
 package repository
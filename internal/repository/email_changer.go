@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// EmailChanger connects the database with email change entity
+// and only responsible for keeping the email change requests and changing the email of users.
+// The statements of ChangeEmail should be run in a single transaction, see Transactor.
+type EmailChanger struct {
+	db *sql.DB
+}
+
+// NewEmailChanger creates an instance of EmailChanger.
+func NewEmailChanger(db *sql.DB) *EmailChanger {
+	return &EmailChanger{db: db}
+}
+
+// Save inserts the email change request, replacing the one the user has requested before, if any.
+func (ec *EmailChanger) Save(ctx context.Context, change *entity.EmailChange) *entity.Error {
+	if change == nil {
+		return entity.ErrInvalidEmailChangeRequest
+	}
+
+	query := "INSERT INTO email_changes (user_id, email, token_hash, requested_at, expires_at) VALUES ($1, $2, $3, $4, $5) " +
+		"ON CONFLICT (user_id) DO UPDATE SET email = EXCLUDED.email, token_hash = EXCLUDED.token_hash, requested_at = EXCLUDED.requested_at, expires_at = EXCLUDED.expires_at"
+	_, err := querierFromContext(ctx, ec.db).ExecContext(ctx, query,
+		uint64(change.UserID),
+		change.Email,
+		change.TokenHash,
+		change.RequestedAt.UTC(),
+		change.ExpiresAt.UTC(),
+	)
+	if err != nil {
+		return databaseError(err, "[EmailChanger-Save] exec upsert query: ")
+	}
+	return nil
+}
+
+// FindByUserID finds the email change request of the user.
+// It returns entity.ErrInvalidEmailChangeToken if there is none.
+func (ec *EmailChanger) FindByUserID(ctx context.Context, userID uint64) (*entity.EmailChange, *entity.Error) {
+	query := "SELECT user_id, email, token_hash, requested_at, expires_at FROM email_changes WHERE user_id = $1 LIMIT 1"
+	row := querierFromContext(ctx, ec.db).QueryRowContext(ctx, query, userID)
+
+	var change entity.EmailChange
+	err := row.Scan(&change.UserID, &change.Email, &change.TokenHash, &change.RequestedAt, &change.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrInvalidEmailChangeToken
+	}
+	if err != nil {
+		return nil, databaseError(err, "[EmailChanger-FindByUserID] exec select query: ")
+	}
+	return &change, nil
+}
+
+// ChangeEmail replaces the email of the user who has the id with email to and deletes their email change request.
+// The audit actor fields which refer to email from are replaced as well.
+// It returns entity.ErrEmailAlreadyUsed if email to belongs to another user
+// and entity.ErrUserNotFound if the user doesn't exist.
+func (ec *EmailChanger) ChangeEmail(ctx context.Context, userID uint64, from, to string) *entity.Error {
+	q := querierFromContext(ctx, ec.db)
+
+	query := "UPDATE users SET email = $1, created_by = $1, updated_by = $1, updated_at = $2 WHERE id = $3"
+	res, err := q.ExecContext(ctx, query, to, time.Now().UTC(), userID)
+	if err != nil {
+		return conflictError(err, entity.ErrEmailAlreadyUsed, "[EmailChanger-ChangeEmail] exec update user query: ")
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return databaseError(err, "[EmailChanger-ChangeEmail] get affected rows: ")
+	}
+	if affected == 0 {
+		return entity.ErrUserNotFound
+	}
+
+	for _, query := range replaceActorQueries {
+		if _, err := q.ExecContext(ctx, query, to, from); err != nil {
+			return databaseError(err, "[EmailChanger-ChangeEmail] exec update actor query: ")
+		}
+	}
+	if _, err := q.ExecContext(ctx, "DELETE FROM email_changes WHERE user_id = $1", userID); err != nil {
+		return databaseError(err, "[EmailChanger-ChangeEmail] exec delete query: ")
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	upsertEmailChangeQuery = `INSERT INTO email_changes \(user_id, email, token_hash, requested_at, expires_at\) VALUES \(\$1, \$2, \$3, \$4, \$5\) ON CONFLICT \(user_id\) DO UPDATE SET .+`
+	selectEmailChangeQuery = `SELECT user_id, email, token_hash, requested_at, expires_at FROM email_changes WHERE user_id = \$1 LIMIT 1`
+	changeUserEmailQuery   = `UPDATE users SET email = \$1, created_by = \$1, updated_by = \$1, updated_at = \$2 WHERE id = \$3`
+)
+
+type EmailChangerExecutor struct {
+	repo *repository.EmailChanger
+	sql  sqlmock.Sqlmock
+}
+
+func TestNewEmailChanger(t *testing.T) {
+	t.Run("successfully create an instance of EmailChanger", func(t *testing.T) {
+		exec := createEmailChangerExecutor()
+		assert.NotNil(t, exec.repo)
+	})
+}
+
+func TestEmailChanger_Save(t *testing.T) {
+	t.Run("email change is nil", func(t *testing.T) {
+		exec := createEmailChangerExecutor()
+
+		err := exec.repo.Save(context.Background(), nil)
+
+		assert.Equal(t, entity.ErrInvalidEmailChangeRequest, err)
+	})
+
+	t.Run("upsert query returns error", func(t *testing.T) {
+		exec := createEmailChangerExecutor()
+
+		exec.sql.ExpectExec(upsertEmailChangeQuery).WillReturnError(errors.New("fail to insert to database"))
+		err := exec.repo.Save(context.Background(), createValidEmailChange())
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("successfully save email change", func(t *testing.T) {
+		exec := createEmailChangerExecutor()
+		change := createValidEmailChange()
+
+		exec.sql.ExpectExec(upsertEmailChangeQuery).
+			WithArgs(uint64(1), "new@orvosi.com", "hash", change.RequestedAt, change.ExpiresAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err := exec.repo.Save(context.Background(), change)
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestEmailChanger_FindByUserID(t *testing.T) {
+	t.Run("select query returns error", func(t *testing.T) {
+		exec := createEmailChangerExecutor()
+
+		exec.sql.ExpectQuery(selectEmailChangeQuery).WillReturnError(errors.New("fail to select from database"))
+		res, err := exec.repo.FindByUserID(context.Background(), 1)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, res)
+	})
+
+	t.Run("email change is not found", func(t *testing.T) {
+		exec := createEmailChangerExecutor()
+
+		exec.sql.ExpectQuery(selectEmailChangeQuery).WillReturnRows(sqlmock.NewRows([]string{"user_id", "email", "token_hash", "requested_at", "expires_at"}))
+		res, err := exec.repo.FindByUserID(context.Background(), 1)
+
+		assert.Equal(t, entity.ErrInvalidEmailChangeToken, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("successfully find email change", func(t *testing.T) {
+		exec := createEmailChangerExecutor()
+
+		exec.sql.ExpectQuery(selectEmailChangeQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "email", "token_hash", "requested_at", "expires_at"}).
+				AddRow(1, "new@orvosi.com", "hash", time.Now(), time.Now().Add(time.Hour)))
+		res, err := exec.repo.FindByUserID(context.Background(), 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, hashids.ID(1), res.UserID)
+		assert.Equal(t, "new@orvosi.com", res.Email)
+		assert.Equal(t, "hash", res.TokenHash)
+	})
+}
+
+func TestEmailChanger_ChangeEmail(t *testing.T) {
+	t.Run("update user query returns error", func(t *testing.T) {
+		exec := createEmailChangerExecutor()
+
+		exec.sql.ExpectExec(changeUserEmailQuery).WillReturnError(errors.New("fail to update database"))
+		err := exec.repo.ChangeEmail(context.Background(), 1, "old@orvosi.com", "new@orvosi.com")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("unique violation returns email already used error", func(t *testing.T) {
+		exec := createEmailChangerExecutor()
+
+		exec.sql.ExpectExec(changeUserEmailQuery).WillReturnError(&pgconn.PgError{Code: "23505"})
+		err := exec.repo.ChangeEmail(context.Background(), 1, "old@orvosi.com", "new@orvosi.com")
+
+		assert.Equal(t, entity.ErrEmailAlreadyUsed.Code, err.Code)
+	})
+
+	t.Run("user is not found", func(t *testing.T) {
+		exec := createEmailChangerExecutor()
+
+		exec.sql.ExpectExec(changeUserEmailQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+		err := exec.repo.ChangeEmail(context.Background(), 1, "old@orvosi.com", "new@orvosi.com")
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+	})
+
+	t.Run("update actor query returns error", func(t *testing.T) {
+		exec := createEmailChangerExecutor()
+
+		exec.sql.ExpectExec(changeUserEmailQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectExec(`UPDATE medical_records SET created_by = \$1 WHERE created_by = \$2`).
+			WillReturnError(errors.New("fail to update database"))
+		err := exec.repo.ChangeEmail(context.Background(), 1, "old@orvosi.com", "new@orvosi.com")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("successfully change email", func(t *testing.T) {
+		exec := createEmailChangerExecutor()
+
+		exec.sql.ExpectExec(changeUserEmailQuery).
+			WithArgs("new@orvosi.com", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		for i := 0; i < 4; i++ {
+			exec.sql.ExpectExec(`UPDATE`).WithArgs("new@orvosi.com", "old@orvosi.com").WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		exec.sql.ExpectExec(`DELETE FROM email_changes WHERE user_id = \$1`).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err := exec.repo.ChangeEmail(context.Background(), 1, "old@orvosi.com", "new@orvosi.com")
+
+		assert.Nil(t, err)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+}
+
+func createValidEmailChange() *entity.EmailChange {
+	now := time.Now().UTC()
+	return &entity.EmailChange{
+		UserID:      hashids.ID(1),
+		Email:       "new@orvosi.com",
+		TokenHash:   "hash",
+		RequestedAt: now,
+		ExpiresAt:   now.Add(time.Hour),
+	}
+}
+
+func createEmailChangerExecutor() *EmailChangerExecutor {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Panicf("[createEmailChangerExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	repo := repository.NewEmailChanger(db)
+	return &EmailChangerExecutor{
+		repo: repo,
+		sql:  mock,
+	}
+}
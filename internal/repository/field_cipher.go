@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// FieldCipher encrypts clinical text before it is written to the database
+// and decrypts it after it is read.
+// The row identifies the database row which holds the field,
+// so a value copied to another row can't be decrypted.
+type FieldCipher interface {
+	// Encrypt encrypts the plaintext of the field held by the row.
+	Encrypt(field, row, plaintext string) (string, error)
+	// Decrypt decrypts the value of the field held by the row.
+	// Stale is true if the value needs to be encrypted again using the active key.
+	Decrypt(field, row, value string) (plaintext string, stale bool, err error)
+}
+
+const (
+	fieldSymptom   = "symptom"
+	fieldDiagnosis = "diagnosis"
+	fieldTherapy   = "therapy"
+	fieldResult    = "result"
+)
+
+// medicalRecordRow identifies the row of the medical record which has the id for the cipher.
+func medicalRecordRow(id uint64) string {
+	return "medical_records:" + strconv.FormatUint(id, 10)
+}
+
+// clinicalText holds the encrypted symptom, diagnosis, therapy, and result of a medical record.
+type clinicalText struct {
+	symptom   string
+	diagnosis string
+	therapy   string
+	result    string
+}
+
+func encryptClinicalText(cipher FieldCipher, id uint64, symptom, diagnosis, therapy, result string) (*clinicalText, error) {
+	var err error
+	row := medicalRecordRow(id)
+	text := &clinicalText{}
+	if text.symptom, err = cipher.Encrypt(fieldSymptom, row, symptom); err != nil {
+		return nil, err
+	}
+	if text.diagnosis, err = cipher.Encrypt(fieldDiagnosis, row, diagnosis); err != nil {
+		return nil, err
+	}
+	if text.therapy, err = cipher.Encrypt(fieldTherapy, row, therapy); err != nil {
+		return nil, err
+	}
+	if text.result, err = cipher.Encrypt(fieldResult, row, result); err != nil {
+		return nil, err
+	}
+	return text, nil
+}
+
+// decryptMedicalRecord decrypts the clinical text of the record in place.
+// It returns the clinical text as it was stored and true if any of them is stale.
+func decryptMedicalRecord(cipher FieldCipher, record *entity.MedicalRecord) (*clinicalText, bool, error) {
+	stored := &clinicalText{
+		symptom:   record.Symptom,
+		diagnosis: record.Diagnosis,
+		therapy:   record.Therapy,
+		result:    record.Result,
+	}
+	stale := false
+	row := medicalRecordRow(uint64(record.ID))
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{fieldSymptom, &record.Symptom},
+		{fieldDiagnosis, &record.Diagnosis},
+		{fieldTherapy, &record.Therapy},
+		{fieldResult, &record.Result},
+	}
+	for _, field := range fields {
+		plaintext, s, err := cipher.Decrypt(field.name, row, *field.value)
+		if err != nil {
+			return nil, false, err
+		}
+		*field.value = plaintext
+		stale = stale || s
+	}
+	return stored, stale, nil
+}
+
+// reencryptMedicalRecord writes the clinical text of the record encrypted by the active key.
+// It doesn't touch updated_at and updated_by since the content doesn't change.
+// The row is only written if it still holds the stored clinical text,
+// so an update which happens after the record was read is never overwritten by the old content.
+// It returns false if the row has been changed in the meantime.
+func reencryptMedicalRecord(ctx context.Context, db *sql.DB, cipher FieldCipher, record *entity.MedicalRecord, stored *clinicalText) (bool, error) {
+	text, err := encryptClinicalText(cipher, uint64(record.ID), record.Symptom, record.Diagnosis, record.Therapy, record.Result)
+	if err != nil {
+		return false, err
+	}
+
+	query := "UPDATE medical_records SET symptom = $1, diagnosis = $2, therapy = $3, result = $4 " +
+		"WHERE id = $5 AND symptom = $6 AND diagnosis = $7 AND therapy = $8 AND result = $9"
+	res, err := querierFromContext(ctx, db).ExecContext(ctx, query,
+		text.symptom, text.diagnosis, text.therapy, text.result, uint64(record.ID),
+		stored.symptom, stored.diagnosis, stored.therapy, stored.result)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// IdentityLinker connects the database with identity entity
+// and only responsible for linking and unlinking the identities of users.
+// The statements of Unlink should be run in a single transaction, see Transactor.
+type IdentityLinker struct {
+	db *sql.DB
+}
+
+// NewIdentityLinker creates an instance of IdentityLinker.
+func NewIdentityLinker(db *sql.DB) *IdentityLinker {
+	return &IdentityLinker{db: db}
+}
+
+// Link inserts the identity and sets the inserted id and creation time back to it.
+// It returns entity.ErrIdentityAlreadyLinked if the provider and subject are already linked.
+func (il *IdentityLinker) Link(ctx context.Context, identity *entity.Identity) *entity.Error {
+	if identity == nil {
+		return entity.ErrInvalidIdentityRequest
+	}
+
+	now := time.Now().UTC()
+	query := "INSERT INTO user_identities (user_id, provider, subject, email, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	row := querierFromContext(ctx, il.db).QueryRowContext(ctx, query,
+		uint64(identity.UserID),
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+		now,
+	)
+
+	var id uint64
+	if err := row.Scan(&id); err != nil {
+		return conflictError(err, entity.ErrIdentityAlreadyLinked, "[IdentityLinker-Link] exec insert query: ")
+	}
+	identity.ID = hashids.ID(id)
+	identity.CreatedAt = now
+	return nil
+}
+
+// FindByUserID finds all identities of the user ordered by id.
+func (il *IdentityLinker) FindByUserID(ctx context.Context, userID uint64) ([]*entity.Identity, *entity.Error) {
+	query := "SELECT id, user_id, provider, subject, email, created_at FROM user_identities WHERE user_id = $1 ORDER BY id"
+	rows, err := querierFromContext(ctx, il.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return []*entity.Identity{}, databaseError(err, "[IdentityLinker-FindByUserID] exec select query: ")
+	}
+	defer rows.Close()
+
+	result := []*entity.Identity{}
+	for rows.Next() {
+		var tmp entity.Identity
+		if err := rows.Scan(&tmp.ID, &tmp.UserID, &tmp.Provider, &tmp.Subject, &tmp.Email, &tmp.CreatedAt); err != nil {
+			return []*entity.Identity{}, databaseError(err, "[IdentityLinker-FindByUserID] scan rows: ")
+		}
+		result = append(result, &tmp)
+	}
+	if rows.Err() != nil {
+		return []*entity.Identity{}, databaseError(rows.Err(), "[IdentityLinker-FindByUserID] iterate rows: ")
+	}
+	return result, nil
+}
+
+// Unlink deletes the identity of the user.
+// If the Google ID of the user was the subject of the identity,
+// it is replaced by the subject of the oldest remaining identity.
+// It returns entity.ErrIdentityNotFound if the user has no such identity.
+func (il *IdentityLinker) Unlink(ctx context.Context, userID, id uint64) *entity.Error {
+	q := querierFromContext(ctx, il.db)
+
+	res, err := q.ExecContext(ctx, "DELETE FROM user_identities WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return databaseError(err, "[IdentityLinker-Unlink] exec delete query: ")
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return databaseError(err, "[IdentityLinker-Unlink] get affected rows: ")
+	}
+	if affected == 0 {
+		return entity.ErrIdentityNotFound
+	}
+
+	query := "UPDATE users SET google_id = (SELECT i.subject FROM user_identities i WHERE i.user_id = users.id ORDER BY i.id LIMIT 1), updated_at = $1 " +
+		"WHERE id = $2 AND google_id NOT IN (SELECT subject FROM user_identities WHERE user_id = $2) AND EXISTS (SELECT 1 FROM user_identities WHERE user_id = $2)"
+	if _, err := q.ExecContext(ctx, query, time.Now().UTC(), userID); err != nil {
+		return databaseError(err, "[IdentityLinker-Unlink] exec update query: ")
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	insertIdentityQuery = `INSERT INTO user_identities \(user_id, provider, subject, email, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5\) RETURNING id`
+	selectIdentityQuery = `SELECT id, user_id, provider, subject, email, created_at FROM user_identities WHERE user_id = \$1 ORDER BY id`
+	deleteIdentityQuery = `DELETE FROM user_identities WHERE id = \$1 AND user_id = \$2`
+	repairGoogleIDQuery = `UPDATE users SET google_id = \(SELECT i.subject FROM user_identities i WHERE i.user_id = users.id ORDER BY i.id LIMIT 1\), updated_at = \$1 WHERE id = \$2 .+`
+)
+
+var identityColumns = []string{"id", "user_id", "provider", "subject", "email", "created_at"}
+
+type IdentityLinkerExecutor struct {
+	repo *repository.IdentityLinker
+	sql  sqlmock.Sqlmock
+}
+
+func TestNewIdentityLinker(t *testing.T) {
+	t.Run("successfully create an instance of IdentityLinker", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor()
+		assert.NotNil(t, exec.repo)
+	})
+}
+
+func TestIdentityLinker_Link(t *testing.T) {
+	t.Run("identity is nil", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor()
+
+		err := exec.repo.Link(context.Background(), nil)
+
+		assert.Equal(t, entity.ErrInvalidIdentityRequest, err)
+	})
+
+	t.Run("insert query returns error", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor()
+
+		exec.sql.ExpectQuery(insertIdentityQuery).WillReturnError(errors.New("fail to insert to database"))
+		err := exec.repo.Link(context.Background(), createValidIdentity())
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("unique violation returns already linked error", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor()
+
+		exec.sql.ExpectQuery(insertIdentityQuery).WillReturnError(&pgconn.PgError{Code: "23505"})
+		err := exec.repo.Link(context.Background(), createValidIdentity())
+
+		assert.Equal(t, entity.ErrIdentityAlreadyLinked.Code, err.Code)
+	})
+
+	t.Run("successfully link identity", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor()
+		identity := createValidIdentity()
+
+		exec.sql.ExpectQuery(insertIdentityQuery).
+			WithArgs(uint64(1), entity.ProviderGoogle, "subject", "user@gmail.com", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		err := exec.repo.Link(context.Background(), identity)
+
+		assert.Nil(t, err)
+		assert.Equal(t, hashids.ID(7), identity.ID)
+		assert.False(t, identity.CreatedAt.IsZero())
+	})
+}
+
+func TestIdentityLinker_FindByUserID(t *testing.T) {
+	t.Run("select query returns error", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor()
+
+		exec.sql.ExpectQuery(selectIdentityQuery).WillReturnError(errors.New("fail to select from database"))
+		res, err := exec.repo.FindByUserID(context.Background(), 1)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Empty(t, res)
+	})
+
+	t.Run("scan returns error", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor()
+
+		exec.sql.ExpectQuery(selectIdentityQuery).
+			WillReturnRows(sqlmock.NewRows(identityColumns).AddRow(1, 1, entity.ProviderGoogle, "subject", "user@gmail.com", "time.Now()"))
+		res, err := exec.repo.FindByUserID(context.Background(), 1)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Empty(t, res)
+	})
+
+	t.Run("successfully find identities", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor()
+
+		exec.sql.ExpectQuery(selectIdentityQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(identityColumns).
+				AddRow(1, 1, entity.ProviderGoogle, "subject-1", "user@gmail.com", time.Now()).
+				AddRow(2, 1, entity.ProviderGoogle, "subject-2", "user@gmail.com", time.Now()))
+		res, err := exec.repo.FindByUserID(context.Background(), 1)
+
+		assert.Nil(t, err)
+		if assert.Len(t, res, 2) {
+			assert.Equal(t, hashids.ID(1), res[0].UserID)
+			assert.Equal(t, "subject-2", res[1].Subject)
+		}
+	})
+}
+
+func TestIdentityLinker_Unlink(t *testing.T) {
+	t.Run("delete query returns error", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor()
+
+		exec.sql.ExpectExec(deleteIdentityQuery).WillReturnError(errors.New("fail to delete from database"))
+		err := exec.repo.Unlink(context.Background(), 1, 2)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("identity is not found", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor()
+
+		exec.sql.ExpectExec(deleteIdentityQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+		err := exec.repo.Unlink(context.Background(), 1, 2)
+
+		assert.Equal(t, entity.ErrIdentityNotFound, err)
+	})
+
+	t.Run("update google id query returns error", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor()
+
+		exec.sql.ExpectExec(deleteIdentityQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectExec(repairGoogleIDQuery).WillReturnError(errors.New("fail to update database"))
+		err := exec.repo.Unlink(context.Background(), 1, 2)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("successfully unlink identity", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor()
+
+		exec.sql.ExpectExec(deleteIdentityQuery).
+			WithArgs(2, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectExec(repairGoogleIDQuery).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err := exec.repo.Unlink(context.Background(), 1, 2)
+
+		assert.Nil(t, err)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+}
+
+func createValidIdentity() *entity.Identity {
+	return &entity.Identity{
+		UserID:   hashids.ID(1),
+		Provider: entity.ProviderGoogle,
+		Subject:  "subject",
+		Email:    "user@gmail.com",
+	}
+}
+
+func createIdentityLinkerExecutor() *IdentityLinkerExecutor {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Panicf("[createIdentityLinkerExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	repo := repository.NewIdentityLinker(db)
+	return &IdentityLinkerExecutor{
+		repo: repo,
+		sql:  mock,
+	}
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/indrasaputra/hashids"
@@ -11,15 +13,31 @@ import (
 
 // MedicalRecordInserter connects the database with medical record entity
 // and only responsible for inserting a new data.
+// Symptom, diagnosis, therapy, and result are encrypted by the cipher.
+// Since the cipher binds them to the id of the record, the record is inserted without them first
+// and they are written in the same transaction once the id is known.
+// The router, if any, is told about the insert so the records are read from the primary for a while.
 type MedicalRecordInserter struct {
-	db *sql.DB
+	db     *sql.DB
+	cipher FieldCipher
+	router *ReplicaRouter
+	tx     *Transactor
 }
 
 // NewMedicalRecordInserter creates an instance of MedicalRecordInserter.
-func NewMedicalRecordInserter(db *sql.DB) *MedicalRecordInserter {
-	return &MedicalRecordInserter{db: db}
+// Its transaction is run again at most maxRetries times if it fails to be serialized,
+// unless it joins the transaction of the caller.
+func NewMedicalRecordInserter(db *sql.DB, cipher FieldCipher, router *ReplicaRouter, maxRetries int) *MedicalRecordInserter {
+	return &MedicalRecordInserter{
+		db:     db,
+		cipher: cipher,
+		router: router,
+		tx:     NewTransactor(db, maxRetries),
+	}
 }
 
+const medicalRecordInsertColumns = 5
+
 // Insert inserts a new medical record data into the database.
 func (mri *MedicalRecordInserter) Insert(ctx context.Context, record *entity.MedicalRecord) *entity.Error {
 	if record == nil {
@@ -27,27 +45,125 @@ func (mri *MedicalRecordInserter) Insert(ctx context.Context, record *entity.Med
 	}
 
 	query := "INSERT INTO " +
-		"medical_records (email, symptom, diagnosis, therapy, result, created_at, updated_at, created_by, updated_by) " +
-		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id"
-
-	row := mri.db.QueryRow(query,
-		record.User.Email,
-		record.Symptom,
-		record.Diagnosis,
-		record.Therapy,
-		"",
-		time.Now(),
-		time.Now(),
-		record.User.Email,
-		record.User.Email,
-	)
+		"medical_records (user_id, symptom, diagnosis, therapy, result, created_at, updated_at, created_by, updated_by) " +
+		"VALUES ($1, '', '', '', '', $2, $3, $4, $5) RETURNING id"
 
 	var id uint64
-	err := row.Scan(&id)
+	err := mri.tx.WithinTransaction(ctx, func(ctx context.Context) *entity.Error {
+		row := querierFromContext(ctx, mri.db).QueryRowContext(ctx, query,
+			uint64(record.User.ID),
+			time.Now().UTC(),
+			time.Now().UTC(),
+			record.User.Email,
+			record.User.Email,
+		)
+		if err := row.Scan(&id); err != nil {
+			return databaseError(err, "[MedicalRecordInserter-Insert] exec insert query: ")
+		}
+		return mri.writeClinicalText(ctx, "[MedicalRecordInserter-Insert]", id, record.Symptom, record.Diagnosis, record.Therapy, "")
+	})
 	if err != nil {
-		return entity.WrapError(entity.ErrInternalServer, "[MedicalRecordInserter-Insert] exec insert query: "+err.Error())
+		return err
 	}
 
 	record.ID = hashids.ID(id)
+	markWrite(mri.router, medicalRecordKey(id), userKey(uint64(record.User.ID)))
+	return nil
+}
+
+// InsertMany inserts all medical records into the database using a single insert statement in one transaction.
+// The result column is also inserted since imported records may already have it.
+func (mri *MedicalRecordInserter) InsertMany(ctx context.Context, records []*entity.MedicalRecord) *entity.Error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	values := make([]string, 0, len(records))
+	args := make([]interface{}, 0, len(records)*medicalRecordInsertColumns)
+	for i, record := range records {
+		if record == nil || record.User == nil {
+			return entity.ErrEmptyMedicalRecord
+		}
+
+		params := make([]string, medicalRecordInsertColumns)
+		for j := range params {
+			params[j] = fmt.Sprintf("$%d", i*medicalRecordInsertColumns+j+1)
+		}
+		values = append(values, "("+params[0]+", '', '', '', '', "+strings.Join(params[1:], ", ")+")")
+		args = append(args,
+			uint64(record.User.ID),
+			now,
+			now,
+			record.User.Email,
+			record.User.Email,
+		)
+	}
+
+	query := "INSERT INTO " +
+		"medical_records (user_id, symptom, diagnosis, therapy, result, created_at, updated_at, created_by, updated_by) " +
+		"VALUES " + strings.Join(values, ", ") + " RETURNING id"
+
+	var ids []uint64
+	err := mri.tx.WithinTransaction(ctx, func(ctx context.Context) *entity.Error {
+		var ierr *entity.Error
+		if ids, ierr = mri.insertRows(ctx, query, args); ierr != nil {
+			return ierr
+		}
+		if len(ids) != len(records) {
+			return entity.WrapError(entity.ErrInternalServer, fmt.Sprintf("[MedicalRecordInserter-InsertMany] %d ids are returned for %d records", len(ids), len(records)))
+		}
+		for i, record := range records {
+			if werr := mri.writeClinicalText(ctx, "[MedicalRecordInserter-InsertMany]", ids[i], record.Symptom, record.Diagnosis, record.Therapy, record.Result); werr != nil {
+				return werr
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(records)*2)
+	for i, record := range records {
+		record.ID = hashids.ID(ids[i])
+		keys = append(keys, medicalRecordKey(ids[i]), userKey(uint64(record.User.ID)))
+	}
+	markWrite(mri.router, keys...)
+	return nil
+}
+
+func (mri *MedicalRecordInserter) insertRows(ctx context.Context, query string, args []interface{}) ([]uint64, *entity.Error) {
+	rows, err := querierFromContext(ctx, mri.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, databaseError(err, "[MedicalRecordInserter-InsertMany] exec insert query: ")
+	}
+	defer rows.Close()
+
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			return nil, entity.WrapError(entity.ErrInternalServer, "[MedicalRecordInserter-InsertMany] scan inserted id: "+err.Error())
+		}
+		ids = append(ids, id)
+	}
+	if rows.Err() != nil {
+		return nil, databaseError(rows.Err(), "[MedicalRecordInserter-InsertMany] iterate inserted ids: ")
+	}
+	return ids, nil
+}
+
+// writeClinicalText encrypts the clinical text of the inserted record which has the id and writes it.
+func (mri *MedicalRecordInserter) writeClinicalText(ctx context.Context, caller string, id uint64, symptom, diagnosis, therapy, result string) *entity.Error {
+	text, err := encryptClinicalText(mri.cipher, id, symptom, diagnosis, therapy, result)
+	if err != nil {
+		return entity.WrapError(entity.ErrInternalServer, caller+" encrypt clinical text: "+err.Error())
+	}
+
+	query := "UPDATE medical_records SET symptom = $1, diagnosis = $2, therapy = $3, result = $4 WHERE id = $5"
+	if _, err := querierFromContext(ctx, mri.db).ExecContext(ctx, query, text.symptom, text.diagnosis, text.therapy, text.result, id); err != nil {
+		return databaseError(err, caller+" exec update query: ")
+	}
 	return nil
 }
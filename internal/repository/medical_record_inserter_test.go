@@ -9,10 +9,17 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/indrasaputra/hashids"
 	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/encryption"
 	"github.com/indrasaputra/orvosi-api/internal/repository"
 	"github.com/stretchr/testify/assert"
 )
 
+const (
+	insertMedicalRecordQuery  = `INSERT INTO medical_records \(user_id, symptom, diagnosis, therapy, result, created_at, updated_at, created_by, updated_by\) VALUES \(\$1, '', '', '', '', \$2, \$3, \$4, \$5\) RETURNING id`
+	insertMedicalRecordsQuery = `INSERT INTO medical_records \(user_id, symptom, diagnosis, therapy, result, created_at, updated_at, created_by, updated_by\) VALUES \(\$1, '', '', '', '', \$2, \$3, \$4, \$5\), \(\$6, '', '', '', '', \$7, \$8, \$9, \$10\) RETURNING id`
+	writeClinicalTextQuery    = `UPDATE medical_records SET symptom = \$1, diagnosis = \$2, therapy = \$3, result = \$4 WHERE id = \$5`
+)
+
 type MedicalRecordInserterExecutor struct {
 	repo *repository.MedicalRecordInserter
 	sql  sqlmock.Sqlmock
@@ -39,29 +46,158 @@ func TestMedicalRecordInserter_Insert(t *testing.T) {
 		exec := createMedicalRecordInserterExecutor()
 		record := createValidMedicalRecord()
 
-		exec.sql.ExpectQuery(`INSERT INTO medical_records \(email, symptom, diagnosis, therapy, result, created_at, updated_at, created_by, updated_by\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9\) RETURNING id`).
+		exec.sql.ExpectBegin()
+		exec.sql.ExpectQuery(insertMedicalRecordQuery).
 			WillReturnError(errors.New("fail to insert to database"))
+		exec.sql.ExpectRollback()
 
 		err := exec.repo.Insert(context.Background(), record)
 
 		assert.NotNil(t, err)
 		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+
+	t.Run("clinical text can't be written", func(t *testing.T) {
+		exec := createMedicalRecordInserterExecutor()
+		record := createValidMedicalRecord()
+
+		exec.sql.ExpectBegin()
+		exec.sql.ExpectQuery(insertMedicalRecordQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(999))
+		exec.sql.ExpectExec(writeClinicalTextQuery).
+			WillReturnError(errors.New("fail to update database"))
+		exec.sql.ExpectRollback()
+
+		err := exec.repo.Insert(context.Background(), record)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Equal(t, hashids.ID(1), record.ID)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
 	})
 
 	t.Run("successfully insert a new medical record", func(t *testing.T) {
 		exec := createMedicalRecordInserterExecutor()
 		record := createValidMedicalRecord()
 
-		exec.sql.ExpectQuery(`INSERT INTO medical_records \(email, symptom, diagnosis, therapy, result, created_at, updated_at, created_by, updated_by\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9\) RETURNING id`).
+		exec.sql.ExpectBegin()
+		exec.sql.ExpectQuery(insertMedicalRecordQuery).
 			WillReturnRows(sqlmock.
 				NewRows([]string{"id"}).
 				AddRow(999),
 			)
+		exec.sql.ExpectExec(writeClinicalTextQuery).
+			WithArgs("symptom", "diagnosis", "therapy", "", 999).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectCommit()
 
 		err := exec.repo.Insert(context.Background(), record)
 
 		assert.Nil(t, err)
 		assert.Equal(t, hashids.ID(999), record.ID)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+
+	t.Run("clinical text is encrypted before it is written", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		repo := repository.NewMedicalRecordInserter(db, createEnvelope("v2"), nil, 0)
+		record := createValidMedicalRecord()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertMedicalRecordQuery).
+			WithArgs(uint64(record.User.ID), sqlmock.AnyArg(), sqlmock.AnyArg(), record.User.Email, record.User.Email).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id"}).
+				AddRow(999),
+			)
+		mock.ExpectExec(writeClinicalTextQuery).
+			WithArgs(encryptedValue("v2"), encryptedValue("v2"), encryptedValue("v2"), encryptedValue("v2"), 999).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.Insert(context.Background(), record)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "symptom", record.Symptom)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMedicalRecordInserter_InsertMany(t *testing.T) {
+	t.Run("nothing to insert", func(t *testing.T) {
+		exec := createMedicalRecordInserterExecutor()
+
+		err := exec.repo.InsertMany(context.Background(), []*entity.MedicalRecord{})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("can't proceed due to nil medical record", func(t *testing.T) {
+		exec := createMedicalRecordInserterExecutor()
+
+		err := exec.repo.InsertMany(context.Background(), []*entity.MedicalRecord{createValidMedicalRecord(), nil})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrEmptyMedicalRecord, err)
+	})
+
+	t.Run("query returns error", func(t *testing.T) {
+		exec := createMedicalRecordInserterExecutor()
+		records := []*entity.MedicalRecord{createValidMedicalRecord(), createValidMedicalRecord()}
+
+		exec.sql.ExpectBegin()
+		exec.sql.ExpectQuery(insertMedicalRecordsQuery).
+			WillReturnError(errors.New("fail to insert to database"))
+		exec.sql.ExpectRollback()
+
+		err := exec.repo.InsertMany(context.Background(), records)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("query doesn't return id of every record", func(t *testing.T) {
+		exec := createMedicalRecordInserterExecutor()
+		records := []*entity.MedicalRecord{createValidMedicalRecord(), createValidMedicalRecord()}
+
+		exec.sql.ExpectBegin()
+		exec.sql.ExpectQuery(insertMedicalRecordsQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(998))
+		exec.sql.ExpectRollback()
+
+		err := exec.repo.InsertMany(context.Background(), records)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+
+	t.Run("successfully insert many medical records", func(t *testing.T) {
+		exec := createMedicalRecordInserterExecutor()
+		records := []*entity.MedicalRecord{createValidMedicalRecord(), createValidMedicalRecord()}
+
+		exec.sql.ExpectBegin()
+		exec.sql.ExpectQuery(insertMedicalRecordsQuery).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id"}).
+				AddRow(998).
+				AddRow(999),
+			)
+		exec.sql.ExpectExec(writeClinicalTextQuery).
+			WithArgs("symptom", "diagnosis", "therapy", "result", 998).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectExec(writeClinicalTextQuery).
+			WithArgs("symptom", "diagnosis", "therapy", "result", 999).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectCommit()
+
+		err := exec.repo.InsertMany(context.Background(), records)
+
+		assert.Nil(t, err)
+		assert.Equal(t, hashids.ID(998), records[0].ID)
+		assert.Equal(t, hashids.ID(999), records[1].ID)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
 	})
 }
 
@@ -87,7 +223,7 @@ func createMedicalRecordInserterExecutor() *MedicalRecordInserterExecutor {
 		log.Panicf("error opening a stub database connection: %v\n", err)
 	}
 
-	repo := repository.NewMedicalRecordInserter(db)
+	repo := repository.NewMedicalRecordInserter(db, encryption.Plaintext{}, nil, 0)
 	return &MedicalRecordInserterExecutor{
 		repo: repo,
 		sql:  mock,
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// MedicalRecordReassigner connects the database with medical record entity
+// and only responsible for changing the owner of medical records.
+// The router, if any, is told about both users so their records are read from the primary for a while.
+type MedicalRecordReassigner struct {
+	db     *sql.DB
+	router *ReplicaRouter
+}
+
+// NewMedicalRecordReassigner creates an instance of MedicalRecordReassigner.
+func NewMedicalRecordReassigner(db *sql.DB, router *ReplicaRouter) *MedicalRecordReassigner {
+	return &MedicalRecordReassigner{
+		db:     db,
+		router: router,
+	}
+}
+
+// ReassignByEmail changes the owner of all medical records owned by the user who has email from
+// to the user who has email to and returns the number of changed records.
+// The audit actor fields are kept, since they tell who has actually written the records.
+// It returns entity.ErrUserNotFound if no user has email to.
+func (mr *MedicalRecordReassigner) ReassignByEmail(ctx context.Context, from, to string) (int64, *entity.Error) {
+	q := querierFromContext(ctx, mr.db)
+
+	toID, err := findUserID(ctx, q, to)
+	if err == sql.ErrNoRows {
+		return 0, entity.ErrUserNotFound
+	}
+	if err != nil {
+		return 0, databaseError(err, "[MedicalRecordReassigner-ReassignByEmail] exec select query: ")
+	}
+	fromID, err := findUserID(ctx, q, from)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, databaseError(err, "[MedicalRecordReassigner-ReassignByEmail] exec select query: ")
+	}
+
+	query := "UPDATE medical_records SET user_id = $1, updated_at = $2 WHERE user_id = $3"
+	res, err := q.ExecContext(ctx, query, toID, time.Now().UTC(), fromID)
+	if err != nil {
+		return 0, databaseError(err, "[MedicalRecordReassigner-ReassignByEmail] exec update query: ")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, databaseError(err, "[MedicalRecordReassigner-ReassignByEmail] get affected rows: ")
+	}
+	markWrite(mr.router, userKey(fromID), userKey(toID))
+	return affected, nil
+}
+
+func findUserID(ctx context.Context, q querier, email string) (uint64, error) {
+	var id uint64
+	err := q.QueryRowContext(ctx, "SELECT id FROM users WHERE email = $1 LIMIT 1", email).Scan(&id)
+	return id, err
+}
@@ -0,0 +1,117 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type MedicalRecordReassignerExecutor struct {
+	repo *repository.MedicalRecordReassigner
+	sql  sqlmock.Sqlmock
+}
+
+func TestNewMedicalRecordReassigner(t *testing.T) {
+	t.Run("successfully create an instance of MedicalRecordReassigner", func(t *testing.T) {
+		exec := createMedicalRecordReassignerExecutor()
+		assert.NotNil(t, exec.repo)
+	})
+}
+
+func TestMedicalRecordReassigner_ReassignByEmail(t *testing.T) {
+	const selectUser = `SELECT id FROM users WHERE email = \$1 LIMIT 1`
+	const updateRecords = `UPDATE medical_records SET user_id = \$1, updated_at = \$2 WHERE user_id = \$3`
+
+	t.Run("select target user query returns error", func(t *testing.T) {
+		exec := createMedicalRecordReassignerExecutor()
+
+		exec.sql.ExpectQuery(selectUser).WithArgs("b@orvosi.com").WillReturnError(errors.New("fail to select from database"))
+		total, err := exec.repo.ReassignByEmail(context.Background(), "a@orvosi.com", "b@orvosi.com")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Zero(t, total)
+	})
+
+	t.Run("target user is not found", func(t *testing.T) {
+		exec := createMedicalRecordReassignerExecutor()
+
+		exec.sql.ExpectQuery(selectUser).WithArgs("b@orvosi.com").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		total, err := exec.repo.ReassignByEmail(context.Background(), "a@orvosi.com", "b@orvosi.com")
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		assert.Zero(t, total)
+	})
+
+	t.Run("source user is not found", func(t *testing.T) {
+		exec := createMedicalRecordReassignerExecutor()
+
+		exec.sql.ExpectQuery(selectUser).WithArgs("b@orvosi.com").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		exec.sql.ExpectQuery(selectUser).WithArgs("a@orvosi.com").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		total, err := exec.repo.ReassignByEmail(context.Background(), "a@orvosi.com", "b@orvosi.com")
+
+		assert.Nil(t, err)
+		assert.Zero(t, total)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+
+	t.Run("update query returns error", func(t *testing.T) {
+		exec := createMedicalRecordReassignerExecutor()
+
+		expectReassignedUsers(exec.sql)
+		exec.sql.ExpectExec(updateRecords).
+			WillReturnError(errors.New("fail to update database"))
+		total, err := exec.repo.ReassignByEmail(context.Background(), "a@orvosi.com", "b@orvosi.com")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Zero(t, total)
+	})
+
+	t.Run("affected rows returns error", func(t *testing.T) {
+		exec := createMedicalRecordReassignerExecutor()
+
+		expectReassignedUsers(exec.sql)
+		exec.sql.ExpectExec(updateRecords).
+			WillReturnResult(sqlmock.NewErrorResult(errors.New("fail to get affected rows")))
+		total, err := exec.repo.ReassignByEmail(context.Background(), "a@orvosi.com", "b@orvosi.com")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Zero(t, total)
+	})
+
+	t.Run("successfully reassign medical records", func(t *testing.T) {
+		exec := createMedicalRecordReassignerExecutor()
+
+		expectReassignedUsers(exec.sql)
+		exec.sql.ExpectExec(updateRecords).
+			WithArgs(2, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		total, err := exec.repo.ReassignByEmail(context.Background(), "a@orvosi.com", "b@orvosi.com")
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), total)
+	})
+}
+
+func expectReassignedUsers(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`SELECT id FROM users WHERE email = \$1 LIMIT 1`).WithArgs("b@orvosi.com").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(`SELECT id FROM users WHERE email = \$1 LIMIT 1`).WithArgs("a@orvosi.com").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+func createMedicalRecordReassignerExecutor() *MedicalRecordReassignerExecutor {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Panicf("[createMedicalRecordReassignerExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	repo := repository.NewMedicalRecordReassigner(db, nil)
+	return &MedicalRecordReassignerExecutor{
+		repo: repo,
+		sql:  mock,
+	}
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// MedicalRecordRekeyer connects the database with medical record entity
+// and only responsible for encrypting the clinical text again using the active key.
+type MedicalRecordRekeyer struct {
+	db     *sql.DB
+	cipher FieldCipher
+}
+
+// NewMedicalRecordRekeyer creates an instance of MedicalRecordRekeyer.
+func NewMedicalRecordRekeyer(db *sql.DB, cipher FieldCipher) *MedicalRecordRekeyer {
+	return &MedicalRecordRekeyer{
+		db:     db,
+		cipher: cipher,
+	}
+}
+
+// Rekey walks through all medical records in batches ordered by id
+// and encrypts those which are still plaintext or encrypted by an old key.
+// It returns the number of re-encrypted records.
+// Record which is updated while it is being re-encrypted is skipped, since the update has used the active key.
+// Since each batch starts after the last seen id, it is safe to run it again after it stops.
+func (mr *MedicalRecordRekeyer) Rekey(ctx context.Context, batchSize uint) (int, *entity.Error) {
+	if batchSize == 0 {
+		return 0, entity.WrapError(entity.ErrInternalServer, "[MedicalRecordRekeyer-Rekey] batch size must be positive")
+	}
+
+	total := 0
+	var last uint64
+	for {
+		records, err := mr.selectBatch(ctx, last, batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		for _, record := range records {
+			stored, stale, derr := decryptMedicalRecord(mr.cipher, record)
+			if derr != nil {
+				return total, entity.WrapError(entity.ErrInternalServer, "[MedicalRecordRekeyer-Rekey] decrypt clinical text: "+derr.Error())
+			}
+			if !stale {
+				continue
+			}
+			written, rerr := reencryptMedicalRecord(ctx, mr.db, mr.cipher, record, stored)
+			if rerr != nil {
+				return total, entity.WrapError(entity.ErrInternalServer, "[MedicalRecordRekeyer-Rekey] re-encrypt: "+rerr.Error())
+			}
+			if written {
+				total++
+			}
+		}
+
+		if uint(len(records)) < batchSize {
+			return total, nil
+		}
+		last = uint64(records[len(records)-1].ID)
+	}
+}
+
+func (mr *MedicalRecordRekeyer) selectBatch(ctx context.Context, after uint64, limit uint) ([]*entity.MedicalRecord, *entity.Error) {
+	query := "SELECT id, symptom, diagnosis, therapy, result FROM medical_records WHERE id > $1 ORDER BY id ASC LIMIT $2"
+	rows, err := querierFromContext(ctx, mr.db).QueryContext(ctx, query, after, limit)
+	if err != nil {
+		return nil, databaseError(err, "[MedicalRecordRekeyer-Rekey] exec select query: ")
+	}
+	defer rows.Close()
+
+	var result []*entity.MedicalRecord
+	for rows.Next() {
+		var tmp entity.MedicalRecord
+		if err := rows.Scan(&tmp.ID, &tmp.Symptom, &tmp.Diagnosis, &tmp.Therapy, &tmp.Result); err != nil {
+			return nil, entity.WrapError(entity.ErrInternalServer, "[MedicalRecordRekeyer-Rekey] scan rows: "+err.Error())
+		}
+		result = append(result, &tmp)
+	}
+	if rows.Err() != nil {
+		return nil, databaseError(rows.Err(), "[MedicalRecordRekeyer-Rekey] iterate rows: ")
+	}
+	return result, nil
+}
@@ -0,0 +1,155 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/encryption"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type MedicalRecordRekeyerExecutor struct {
+	repo *repository.MedicalRecordRekeyer
+	sql  sqlmock.Sqlmock
+}
+
+// encryptedValue matches a value encrypted by the key version.
+type encryptedValue string
+
+func (ev encryptedValue) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	return ok && strings.HasPrefix(s, "orvosi:enc:v1:"+string(ev)+":")
+}
+
+func TestNewMedicalRecordRekeyer(t *testing.T) {
+	t.Run("successfully create an instance of MedicalRecordRekeyer", func(t *testing.T) {
+		exec := createMedicalRecordRekeyerExecutor()
+		assert.NotNil(t, exec.repo)
+	})
+}
+
+func TestMedicalRecordRekeyer_Rekey(t *testing.T) {
+	old := createEnvelope("v1")
+	symptom, _ := old.Encrypt("symptom", "medical_records:1", "Symptom")
+
+	t.Run("batch size is zero", func(t *testing.T) {
+		exec := createMedicalRecordRekeyerExecutor()
+
+		total, err := exec.repo.Rekey(context.Background(), 0)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, total)
+	})
+
+	t.Run("select query returns error", func(t *testing.T) {
+		exec := createMedicalRecordRekeyerExecutor()
+
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result FROM medical_records WHERE id > \$1 ORDER BY id ASC LIMIT \$2`).
+			WillReturnError(errors.New("fail to select from database"))
+
+		total, err := exec.repo.Rekey(context.Background(), 2)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Equal(t, 0, total)
+	})
+
+	t.Run("value can't be decrypted", func(t *testing.T) {
+		exec := createMedicalRecordRekeyerExecutor()
+
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result FROM medical_records WHERE id > \$1 ORDER BY id ASC LIMIT \$2`).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result"}).
+				AddRow(1, "orvosi:enc:v1:v1:malformed", "Diagnosis", "Therapy", "Result"),
+			)
+
+		total, err := exec.repo.Rekey(context.Background(), 2)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, total)
+	})
+
+	t.Run("successfully re-encrypt stale records in batches", func(t *testing.T) {
+		exec := createMedicalRecordRekeyerExecutor()
+		current, _ := createEnvelope("v2").Encrypt("symptom", "medical_records:2", "Symptom")
+		diagnosis, _ := createEnvelope("v2").Encrypt("diagnosis", "medical_records:2", "Diagnosis")
+		therapy, _ := createEnvelope("v2").Encrypt("therapy", "medical_records:2", "Therapy")
+		result, _ := createEnvelope("v2").Encrypt("result", "medical_records:2", "Result")
+
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result FROM medical_records WHERE id > \$1 ORDER BY id ASC LIMIT \$2`).
+			WithArgs(0, 2).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result"}).
+				AddRow(1, symptom, "Diagnosis", "Therapy", "Result").
+				AddRow(2, current, diagnosis, therapy, result),
+			)
+		exec.sql.ExpectExec(`UPDATE medical_records SET symptom = \$1, diagnosis = \$2, therapy = \$3, result = \$4 WHERE id = \$5 AND symptom = \$6 AND diagnosis = \$7 AND therapy = \$8 AND result = \$9`).
+			WithArgs(encryptedValue("v2"), encryptedValue("v2"), encryptedValue("v2"), encryptedValue("v2"), 1, symptom, "Diagnosis", "Therapy", "Result").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result FROM medical_records WHERE id > \$1 ORDER BY id ASC LIMIT \$2`).
+			WithArgs(2, 2).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result"}).
+				AddRow(3, "Symptom", "Diagnosis", "Therapy", ""),
+			)
+		exec.sql.ExpectExec(`UPDATE medical_records SET symptom = \$1, diagnosis = \$2, therapy = \$3, result = \$4 WHERE id = \$5 AND symptom = \$6 AND diagnosis = \$7 AND therapy = \$8 AND result = \$9`).
+			WithArgs(encryptedValue("v2"), encryptedValue("v2"), encryptedValue("v2"), encryptedValue("v2"), 3, "Symptom", "Diagnosis", "Therapy", "").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		total, err := exec.repo.Rekey(context.Background(), 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, total)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+
+	t.Run("record which is updated in the meantime is skipped", func(t *testing.T) {
+		exec := createMedicalRecordRekeyerExecutor()
+
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result FROM medical_records WHERE id > \$1 ORDER BY id ASC LIMIT \$2`).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result"}).
+				AddRow(1, "Symptom", "Diagnosis", "Therapy", "Result"),
+			)
+		exec.sql.ExpectExec(`UPDATE medical_records SET symptom = \$1, diagnosis = \$2, therapy = \$3, result = \$4 WHERE id = \$5 AND symptom = \$6 AND diagnosis = \$7 AND therapy = \$8 AND result = \$9`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		total, err := exec.repo.Rekey(context.Background(), 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, total)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+}
+
+func createEnvelope(active string) *encryption.Envelope {
+	kms, err := encryption.NewLocalKMS(map[string][]byte{
+		"v1": bytes.Repeat([]byte{1}, encryption.KeySize),
+		"v2": bytes.Repeat([]byte{2}, encryption.KeySize),
+	}, active)
+	if err != nil {
+		log.Panicf("[createEnvelope] error creating local kms: %v\n", err)
+	}
+	return encryption.NewEnvelope(kms)
+}
+
+func createMedicalRecordRekeyerExecutor() *MedicalRecordRekeyerExecutor {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Panicf("[createMedicalRecordRekeyerExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	repo := repository.NewMedicalRecordRekeyer(db, createEnvelope("v2"))
+	return &MedicalRecordRekeyerExecutor{
+		repo: repo,
+		sql:  mock,
+	}
+}
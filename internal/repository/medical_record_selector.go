@@ -3,57 +3,151 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/indrasaputra/orvosi-api/entity"
 )
 
 // MedicalRecordSelector connects the database with medical record entity
 // and only responsible for retrieving medical record data.
+// Symptom, diagnosis, therapy, and result are decrypted by the cipher.
+// Record which is still plaintext or encrypted by an old key is encrypted again using the active key.
+// Reads are routed by the router, if any, while re-encryption always goes to the primary db.
+// Record which is read from a replica is not encrypted again, since the replica may lag behind the primary.
+// Read which fails on a replica runs again on the primary.
 type MedicalRecordSelector struct {
-	db *sql.DB
+	db     *sql.DB
+	cipher FieldCipher
+	router *ReplicaRouter
 }
 
 // NewMedicalRecordSelector creates an instance of MedicalRecordSelector.
-func NewMedicalRecordSelector(db *sql.DB) *MedicalRecordSelector {
-	return &MedicalRecordSelector{db: db}
+// The router is optional. Without it, all reads go to db.
+func NewMedicalRecordSelector(db *sql.DB, cipher FieldCipher, router *ReplicaRouter) *MedicalRecordSelector {
+	return &MedicalRecordSelector{
+		db:     db,
+		cipher: cipher,
+		router: router,
+	}
 }
 
 // FindByID finds medical record by its id.
 func (ms *MedicalRecordSelector) FindByID(ctx context.Context, id uint64) (*entity.MedicalRecord, *entity.Error) {
-	query := "SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, email FROM medical_records WHERE id = $1 LIMIT 1"
-	row := ms.db.QueryRowContext(ctx, query, id)
+	query := "SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, user_id FROM medical_records WHERE id = $1 LIMIT 1"
+	reader := readerFor(ms.db, ms.router, medicalRecordKey(id))
+	scan := func(reader *sql.DB) (*entity.MedicalRecord, error) {
+		mr := &entity.MedicalRecord{
+			User: &entity.User{},
+		}
+		row := querierFromContext(ctx, reader).QueryRowContext(ctx, query, id)
+		return mr, row.Scan(&mr.ID, &mr.Symptom, &mr.Diagnosis, &mr.Therapy, &mr.Result, &mr.CreatedAt, &mr.CreatedBy, &mr.UpdatedAt, &mr.UpdatedBy, &mr.User.ID)
+	}
 
-	mr := &entity.MedicalRecord{
-		User: &entity.User{},
+	mr, err := scan(reader)
+	if err != nil && err != sql.ErrNoRows && retryOnPrimary(ctx, ms.router, reader, ms.db) {
+		log.Printf("[MedicalRecordSelector-FindByID] read from replica error, retried on primary: %v", err)
+		reader = ms.db
+		mr, err = scan(reader)
+	}
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrMedicalRecordNotFound
 	}
-	if err := row.Scan(&mr.ID, &mr.Symptom, &mr.Diagnosis, &mr.Therapy, &mr.Result, &mr.CreatedAt, &mr.CreatedBy, &mr.UpdatedAt, &mr.UpdatedBy, &mr.User.Email); err != nil {
-		return nil, entity.WrapError(entity.ErrInternalServer, err.Error())
+	if err != nil {
+		return nil, databaseError(err, "[MedicalRecordSelector-FindByID] exec select query: ")
+	}
+
+	stored, stale, err := decryptMedicalRecord(ms.cipher, mr)
+	if err != nil {
+		return nil, entity.WrapError(entity.ErrInternalServer, "[MedicalRecordSelector-FindByID] decrypt clinical text: "+err.Error())
+	}
+	if stale && reader == ms.db {
+		ms.reencrypt(ctx, "[MedicalRecordSelector-FindByID]", mr, stored)
 	}
 	return mr, nil
 }
 
-// FindByEmail finds all medical records bounded to specific email.
-func (ms *MedicalRecordSelector) FindByEmail(ctx context.Context, email string, from uint64, limit uint) ([]*entity.MedicalRecord, *entity.Error) {
-	query := "SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE email = $1 AND id < $2 ORDER BY created_at DESC LIMIT $3"
-	rows, err := ms.db.QueryContext(ctx, query, email, from, limit)
+// FindByUserID finds all medical records owned by the user who has the id.
+func (ms *MedicalRecordSelector) FindByUserID(ctx context.Context, userID, from uint64, limit uint) ([]*entity.MedicalRecord, *entity.Error) {
+	query := "SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE user_id = $1 AND id < $2 ORDER BY created_at DESC LIMIT $3"
+	reader := readerFor(ms.db, ms.router, userKey(userID))
+	rows, err := querierFromContext(ctx, reader).QueryContext(ctx, query, userID, from, limit)
+	if err != nil && retryOnPrimary(ctx, ms.router, reader, ms.db) {
+		log.Printf("[MedicalRecordSelector-FindByUserID] read from replica error, retried on primary: %v", err)
+		reader = ms.db
+		rows, err = querierFromContext(ctx, reader).QueryContext(ctx, query, userID, from, limit)
+	}
 	if err != nil {
-		return []*entity.MedicalRecord{}, entity.WrapError(entity.ErrInternalServer, err.Error())
+		return []*entity.MedicalRecord{}, databaseError(err, "[MedicalRecordSelector-FindByUserID] exec select query: ")
 	}
 	defer rows.Close()
 
+	return ms.scanMedicalRecords(ctx, rows, reader == ms.db, "[MedicalRecordSelector-FindByUserID]")
+}
+
+// FindByUserIDWithinPeriod finds all medical records owned by the user who has the id
+// and created within [since, until).
+func (ms *MedicalRecordSelector) FindByUserIDWithinPeriod(ctx context.Context, userID uint64, since, until time.Time, from uint64, limit uint) ([]*entity.MedicalRecord, *entity.Error) {
+	query := "SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records " +
+		"WHERE user_id = $1 AND created_at >= $2 AND created_at < $3 AND id < $4 ORDER BY created_at DESC LIMIT $5"
+	reader := readerFor(ms.db, ms.router, userKey(userID))
+	rows, err := querierFromContext(ctx, reader).QueryContext(ctx, query, userID, since.UTC(), until.UTC(), from, limit)
+	if err != nil && retryOnPrimary(ctx, ms.router, reader, ms.db) {
+		log.Printf("[MedicalRecordSelector-FindByUserIDWithinPeriod] read from replica error, retried on primary: %v", err)
+		reader = ms.db
+		rows, err = querierFromContext(ctx, reader).QueryContext(ctx, query, userID, since.UTC(), until.UTC(), from, limit)
+	}
+	if err != nil {
+		return []*entity.MedicalRecord{}, databaseError(err, "[MedicalRecordSelector-FindByUserIDWithinPeriod] exec select query: ")
+	}
+	defer rows.Close()
+
+	return ms.scanMedicalRecords(ctx, rows, reader == ms.db, "[MedicalRecordSelector-FindByUserIDWithinPeriod]")
+}
+
+// scanMedicalRecords only encrypts the stale records again if they are read from the primary.
+func (ms *MedicalRecordSelector) scanMedicalRecords(ctx context.Context, rows *sql.Rows, fromPrimary bool, caller string) ([]*entity.MedicalRecord, *entity.Error) {
+	type staleRecord struct {
+		record *entity.MedicalRecord
+		stored *clinicalText
+	}
+
 	var result []*entity.MedicalRecord
+	var stale []staleRecord
 	for rows.Next() {
 		var tmp entity.MedicalRecord
 		if err := rows.Scan(&tmp.ID, &tmp.Symptom, &tmp.Diagnosis, &tmp.Therapy, &tmp.Result, &tmp.CreatedAt, &tmp.CreatedBy, &tmp.UpdatedAt, &tmp.UpdatedBy); err != nil {
-			log.Printf("[MedicalRecordSelector-FindByEmail] scan rows error: %v", err)
+			log.Printf("%s scan rows error: %v", caller, err)
 			continue
 		}
 
+		stored, isStale, err := decryptMedicalRecord(ms.cipher, &tmp)
+		if err != nil {
+			return []*entity.MedicalRecord{}, entity.WrapError(entity.ErrInternalServer, fmt.Sprintf("%s decrypt clinical text of medical record %d: %v", caller, uint64(tmp.ID), err))
+		}
+		if isStale && fromPrimary {
+			stale = append(stale, staleRecord{record: &tmp, stored: stored})
+		}
+
 		result = append(result, &tmp)
 	}
 	if rows.Err() != nil {
-		return []*entity.MedicalRecord{}, entity.WrapError(entity.ErrInternalServer, rows.Err().Error())
+		return []*entity.MedicalRecord{}, databaseError(rows.Err(), caller+" iterate rows: ")
+	}
+
+	// re-encryption waits until all rows are read, so it doesn't need another connection while iterating.
+	for _, s := range stale {
+		ms.reencrypt(ctx, caller, s.record, s.stored)
 	}
 	return result, nil
 }
+
+// reencrypt encrypts the record again using the active key.
+// It is best-effort: failure is only logged since the record has been read successfully.
+// Record which has been changed since it was read is left as is.
+func (ms *MedicalRecordSelector) reencrypt(ctx context.Context, caller string, record *entity.MedicalRecord, stored *clinicalText) {
+	if _, err := reencryptMedicalRecord(ctx, ms.db, ms.cipher, record, stored); err != nil {
+		log.Printf("%s re-encrypt medical record %d error: %v", caller, uint64(record.ID), err)
+	}
+}
@@ -2,6 +2,7 @@ package repository_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"log"
 	"testing"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/encryption"
 	"github.com/indrasaputra/orvosi-api/internal/repository"
 	"github.com/stretchr/testify/assert"
 )
@@ -29,7 +31,7 @@ func TestMedicalRecordSelector_FindByID(t *testing.T) {
 	t.Run("select query returns error", func(t *testing.T) {
 		exec := createMedicalRecordSelectorExecutor()
 
-		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, email FROM medical_records WHERE id = \$1 LIMIT 1`).
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, user_id FROM medical_records WHERE id = \$1 LIMIT 1`).
 			WillReturnError(errors.New("fail to select from database"))
 
 		res, err := exec.repo.FindByID(context.Background(), uint64(1))
@@ -39,13 +41,25 @@ func TestMedicalRecordSelector_FindByID(t *testing.T) {
 		assert.Empty(t, res)
 	})
 
+	t.Run("medical record doesn't exist", func(t *testing.T) {
+		exec := createMedicalRecordSelectorExecutor()
+
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, user_id FROM medical_records WHERE id = \$1 LIMIT 1`).
+			WillReturnError(sql.ErrNoRows)
+
+		res, err := exec.repo.FindByID(context.Background(), uint64(1))
+
+		assert.Equal(t, entity.ErrMedicalRecordNotFound, err)
+		assert.Nil(t, res)
+	})
+
 	t.Run("row scan returns error", func(t *testing.T) {
 		exec := createMedicalRecordSelectorExecutor()
 
-		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, email FROM medical_records WHERE id = \$1 LIMIT 1`).
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, user_id FROM medical_records WHERE id = \$1 LIMIT 1`).
 			WillReturnRows(sqlmock.
-				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by", "email"}).
-				AddRow(1, "Symptom", "Diagnosis", "Therapy", "Result", "time.Now()", "dummy@dummy.com", "time.Now()", "dummy@dummy.com", "dummy@dummy.com"),
+				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by", "user_id"}).
+				AddRow(1, "Symptom", "Diagnosis", "Therapy", "Result", "time.Now()", "dummy@dummy.com", "time.Now()", "dummy@dummy.com", 3),
 			)
 
 		res, err := exec.repo.FindByID(context.Background(), uint64(1))
@@ -57,10 +71,10 @@ func TestMedicalRecordSelector_FindByID(t *testing.T) {
 	t.Run("successfully retrieve one medical record", func(t *testing.T) {
 		exec := createMedicalRecordSelectorExecutor()
 
-		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, email FROM medical_records WHERE id = \$1 LIMIT 1`).
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, user_id FROM medical_records WHERE id = \$1 LIMIT 1`).
 			WillReturnRows(sqlmock.
-				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by", "email"}).
-				AddRow(1, "Symptom", "Diagnosis", "Therapy", "Result", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com", "dummy@dummy.com"),
+				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by", "user_id"}).
+				AddRow(1, "Symptom", "Diagnosis", "Therapy", "Result", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com", 3),
 			)
 
 		res, err := exec.repo.FindByID(context.Background(), uint64(1))
@@ -69,16 +83,53 @@ func TestMedicalRecordSelector_FindByID(t *testing.T) {
 		assert.NotNil(t, res)
 		assert.Equal(t, uint64(1), uint64(res.ID))
 	})
+
+	t.Run("value can't be decrypted", func(t *testing.T) {
+		exec := createMedicalRecordSelectorExecutor()
+
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, user_id FROM medical_records WHERE id = \$1 LIMIT 1`).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by", "user_id"}).
+				AddRow(1, "orvosi:enc:v1:v1:malformed", "Diagnosis", "Therapy", "Result", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com", 3),
+			)
+
+		res, err := exec.repo.FindByID(context.Background(), uint64(1))
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, res)
+	})
+
+	t.Run("stale record is decrypted and encrypted again using the active key", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		repo := repository.NewMedicalRecordSelector(db, createEnvelope("v2"), nil)
+		symptom, _ := createEnvelope("v1").Encrypt("symptom", "medical_records:1", "Symptom")
+
+		mock.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, user_id FROM medical_records WHERE id = \$1 LIMIT 1`).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by", "user_id"}).
+				AddRow(1, symptom, "Diagnosis", "Therapy", "Result", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com", 3),
+			)
+		mock.ExpectExec(`UPDATE medical_records SET symptom = \$1, diagnosis = \$2, therapy = \$3, result = \$4 WHERE id = \$5 AND symptom = \$6 AND diagnosis = \$7 AND therapy = \$8 AND result = \$9`).
+			WithArgs(encryptedValue("v2"), encryptedValue("v2"), encryptedValue("v2"), encryptedValue("v2"), 1, symptom, "Diagnosis", "Therapy", "Result").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		res, err := repo.FindByID(context.Background(), uint64(1))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Symptom", res.Symptom)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
 }
 
-func TestMedicalRecordSelector_FindByEmail(t *testing.T) {
+func TestMedicalRecordSelector_FindByUserID(t *testing.T) {
 	t.Run("select query returns error", func(t *testing.T) {
 		exec := createMedicalRecordSelectorExecutor()
 
-		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE email = \$1 AND id < \$2 ORDER BY created_at DESC LIMIT \$3`).
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE user_id = \$1 AND id < \$2 ORDER BY created_at DESC LIMIT \$3`).
 			WillReturnError(errors.New("fail to select from database"))
 
-		res, err := exec.repo.FindByEmail(context.Background(), "dummy@dummy.com", 100, 10)
+		res, err := exec.repo.FindByUserID(context.Background(), uint64(3), 100, 10)
 
 		assert.NotNil(t, err)
 		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
@@ -88,14 +139,14 @@ func TestMedicalRecordSelector_FindByEmail(t *testing.T) {
 	t.Run("row scan returns error", func(t *testing.T) {
 		exec := createMedicalRecordSelectorExecutor()
 
-		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE email = \$1 AND id < \$2 ORDER BY created_at DESC LIMIT \$3`).
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE user_id = \$1 AND id < \$2 ORDER BY created_at DESC LIMIT \$3`).
 			WillReturnRows(sqlmock.
 				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by"}).
 				AddRow(1, "Symptom", "Diagnosis", "Therapy", "Result", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com").
 				AddRow(2, "Symptom", "Diagnosis", "Therapy", "Result", "time.Now()", "dummy@dummy.com", "time.Now()", "dummy@dummy.com"),
 			)
 
-		res, err := exec.repo.FindByEmail(context.Background(), "dummy@dummy.com", 100, 10)
+		res, err := exec.repo.FindByUserID(context.Background(), uint64(3), 100, 10)
 
 		assert.Nil(t, err)
 		assert.NotEmpty(t, res)
@@ -105,7 +156,7 @@ func TestMedicalRecordSelector_FindByEmail(t *testing.T) {
 	t.Run("rows error occurs after scanning", func(t *testing.T) {
 		exec := createMedicalRecordSelectorExecutor()
 
-		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE email = \$1 AND id < \$2 ORDER BY created_at DESC LIMIT \$3`).
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE user_id = \$1 AND id < \$2 ORDER BY created_at DESC LIMIT \$3`).
 			WillReturnRows(sqlmock.
 				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by"}).
 				AddRow(1, "Symptom", "Diagnosis", "Therapy", "Result", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com").
@@ -113,7 +164,7 @@ func TestMedicalRecordSelector_FindByEmail(t *testing.T) {
 				RowError(1, errors.New("rows error")),
 			)
 
-		res, err := exec.repo.FindByEmail(context.Background(), "dummy@dummy.com", 100, 10)
+		res, err := exec.repo.FindByUserID(context.Background(), uint64(3), 100, 10)
 
 		assert.NotNil(t, err)
 		assert.Empty(t, res)
@@ -122,14 +173,14 @@ func TestMedicalRecordSelector_FindByEmail(t *testing.T) {
 	t.Run("successfully retrieve all rows", func(t *testing.T) {
 		exec := createMedicalRecordSelectorExecutor()
 
-		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE email = \$1 AND id < \$2 ORDER BY created_at DESC LIMIT \$3`).
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE user_id = \$1 AND id < \$2 ORDER BY created_at DESC LIMIT \$3`).
 			WillReturnRows(sqlmock.
 				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by"}).
 				AddRow(1, "Symptom", "Diagnosis", "Therapy", "Result", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com").
 				AddRow(2, "Symptom", "Diagnosis", "Therapy", "Result", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com"),
 			)
 
-		res, err := exec.repo.FindByEmail(context.Background(), "dummy@dummy.com", 100, 10)
+		res, err := exec.repo.FindByUserID(context.Background(), uint64(3), 100, 10)
 
 		assert.Nil(t, err)
 		assert.NotEmpty(t, res)
@@ -137,13 +188,91 @@ func TestMedicalRecordSelector_FindByEmail(t *testing.T) {
 	})
 }
 
+func TestMedicalRecordSelector_FindByUserIDWithinPeriod(t *testing.T) {
+	since := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2021, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("select query returns error", func(t *testing.T) {
+		exec := createMedicalRecordSelectorExecutor()
+
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE user_id = \$1 AND created_at >= \$2 AND created_at < \$3 AND id < \$4 ORDER BY created_at DESC LIMIT \$5`).
+			WithArgs(3, since, until, 100, 10).
+			WillReturnError(errors.New("fail to select from database"))
+
+		res, err := exec.repo.FindByUserIDWithinPeriod(context.Background(), uint64(3), since, until, 100, 10)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Empty(t, res)
+	})
+
+	t.Run("successfully retrieve all rows", func(t *testing.T) {
+		exec := createMedicalRecordSelectorExecutor()
+
+		exec.sql.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE user_id = \$1 AND created_at >= \$2 AND created_at < \$3 AND id < \$4 ORDER BY created_at DESC LIMIT \$5`).
+			WithArgs(3, since, until, 100, 10).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by"}).
+				AddRow(1, "Symptom", "Diagnosis", "Therapy", "Result", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com").
+				AddRow(2, "Symptom", "Diagnosis", "Therapy", "Result", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com"),
+			)
+
+		res, err := exec.repo.FindByUserIDWithinPeriod(context.Background(), uint64(3), since, until, 100, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+	})
+
+	t.Run("value of any record can't be decrypted", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		repo := repository.NewMedicalRecordSelector(db, createEnvelope("v2"), nil)
+		current, _ := createEnvelope("v2").Encrypt("symptom", "medical_records:2", "Symptom")
+
+		mock.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE user_id = \$1 AND created_at >= \$2 AND created_at < \$3 AND id < \$4 ORDER BY created_at DESC LIMIT \$5`).
+			WithArgs(3, since, until, 100, 10).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by"}).
+				AddRow(1, "Symptom", "Diagnosis", "Therapy", "Result", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com").
+				AddRow(2, current, "orvosi:enc:v1:v2:malformed", "Therapy", "Result", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com"),
+			)
+
+		res, err := repo.FindByUserIDWithinPeriod(context.Background(), uint64(3), since, until, 100, 10)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Empty(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure of re-encryption doesn't fail the retrieval", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		repo := repository.NewMedicalRecordSelector(db, createEnvelope("v2"), nil)
+
+		mock.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE user_id = \$1 AND created_at >= \$2 AND created_at < \$3 AND id < \$4 ORDER BY created_at DESC LIMIT \$5`).
+			WithArgs(3, since, until, 100, 10).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by"}).
+				AddRow(1, "Symptom", "Diagnosis", "Therapy", "Result", time.Now(), "dummy@dummy.com", time.Now(), "dummy@dummy.com"),
+			)
+		mock.ExpectExec(`UPDATE medical_records SET symptom = \$1, diagnosis = \$2, therapy = \$3, result = \$4 WHERE id = \$5 AND symptom = \$6 AND diagnosis = \$7 AND therapy = \$8 AND result = \$9`).
+			WillReturnError(errors.New("fail to update database"))
+
+		res, err := repo.FindByUserIDWithinPeriod(context.Background(), uint64(3), since, until, 100, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+		assert.Equal(t, "Symptom", res[0].Symptom)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
 func createMedicalRecordSelectorExecutor() *MedicalRecordSelectorExecutor {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		log.Panicf("[createMedicalRecordSelectorExecutor] error opening a stub database connection: %v\n", err)
 	}
 
-	repo := repository.NewMedicalRecordSelector(db)
+	repo := repository.NewMedicalRecordSelector(db, encryption.Plaintext{}, nil)
 	return &MedicalRecordSelectorExecutor{
 		repo: repo,
 		sql:  mock,
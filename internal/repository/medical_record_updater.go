@@ -10,45 +10,55 @@ import (
 
 // MedicalRecordUpdater connects the database with medical record entity
 // and only responsible for usecase of updating a data.
+// Symptom, diagnosis, therapy, and result are encrypted by the cipher.
+// The router, if any, is told about the update so the record is read from the primary for a while.
 type MedicalRecordUpdater struct {
-	db *sql.DB
+	db     *sql.DB
+	cipher FieldCipher
+	router *ReplicaRouter
 }
 
 // NewMedicalRecordUpdater creates an instance of MedicalRecordUpdater.
-func NewMedicalRecordUpdater(db *sql.DB) *MedicalRecordUpdater {
-	return &MedicalRecordUpdater{db: db}
+func NewMedicalRecordUpdater(db *sql.DB, cipher FieldCipher, router *ReplicaRouter) *MedicalRecordUpdater {
+	return &MedicalRecordUpdater{
+		db:     db,
+		cipher: cipher,
+		router: router,
+	}
 }
 
-// DoesRecordExist checks whether medical record which has certain id and email exists.
-func (mu *MedicalRecordUpdater) DoesRecordExist(ctx context.Context, id uint64, email string) (bool, *entity.Error) {
-	query := "SELECT id FROM medical_records WHERE id = $1 AND email = $2 LIMIT 1"
-	row := mu.db.QueryRowContext(ctx, query, id, email)
-
-	var tmp uint64
-	err := row.Scan(&tmp)
-	if err != nil && err != sql.ErrNoRows {
-		return false, entity.WrapError(entity.ErrInternalServer, err.Error())
-	} else if err != nil && err == sql.ErrNoRows {
-		return false, nil
+// Update updates the whole record data of the medical record which has certain id and is owned by the user who has the userID.
+// The ownership check and the update are run in a single statement,
+// so it needs only one round trip and the record can't change hands in between.
+// It returns ErrMedicalRecordNotFound if no such record exists.
+func (mu *MedicalRecordUpdater) Update(ctx context.Context, id, userID uint64, record *entity.MedicalRecord) *entity.Error {
+	text, cerr := encryptClinicalText(mu.cipher, id, record.Symptom, record.Diagnosis, record.Therapy, record.Result)
+	if cerr != nil {
+		return entity.WrapError(entity.ErrInternalServer, "[MedicalRecordUpdater-Update] encrypt clinical text: "+cerr.Error())
 	}
-	return true, nil
-}
 
-// Update updates the whole record data.
-func (mu *MedicalRecordUpdater) Update(ctx context.Context, id uint64, record *entity.MedicalRecord) *entity.Error {
-	query := "UPDATE medical_records SET symptom = $1, diagnosis = $2, therapy = $3, result = $4, updated_at = $5, updated_by = $6 WHERE id = $7"
-	_, err := mu.db.ExecContext(ctx, query,
-		record.Symptom,
-		record.Diagnosis,
-		record.Therapy,
-		record.Result,
-		time.Now(),
+	query := "UPDATE medical_records SET symptom = $1, diagnosis = $2, therapy = $3, result = $4, updated_at = $5, updated_by = $6 WHERE id = $7 AND user_id = $8"
+	res, err := querierFromContext(ctx, mu.db).ExecContext(ctx, query,
+		text.symptom,
+		text.diagnosis,
+		text.therapy,
+		text.result,
+		time.Now().UTC(),
 		record.User.Email,
 		id,
+		userID,
 	)
+	if err != nil {
+		return databaseError(err, "[MedicalRecordUpdater-Update] exec update query: ")
+	}
 
+	affected, err := res.RowsAffected()
 	if err != nil {
-		return entity.WrapError(entity.ErrInternalServer, err.Error())
+		return databaseError(err, "[MedicalRecordUpdater-Update] get affected rows: ")
+	}
+	if affected == 0 {
+		return entity.ErrMedicalRecordNotFound
 	}
+	markWrite(mu.router, medicalRecordKey(id), userKey(userID))
 	return nil
 }
@@ -2,14 +2,15 @@ package repository_test
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"log"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/encryption"
 	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/jackc/pgconn"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,63 +26,67 @@ func TestNewMedicalRecordUpdater(t *testing.T) {
 	})
 }
 
-func TestMedicalRecordUpdater_DoesRecordExist(t *testing.T) {
+func TestMedicalRecordUpdater_Update(t *testing.T) {
 	t.Run("query returns internal error", func(t *testing.T) {
 		exec := createMedicalRecordUpdaterExecutor()
 
-		exec.sql.ExpectQuery(`SELECT id FROM medical_records WHERE id = \$1 AND email = \$2 LIMIT 1`).
+		exec.sql.ExpectExec(`UPDATE medical_records SET symptom = \$1, diagnosis = \$2, therapy = \$3, result = \$4, updated_at = \$5, updated_by = \$6 WHERE id = \$7 AND user_id = \$8`).
 			WillReturnError(errors.New("fail to select from database"))
-		found, err := exec.repo.DoesRecordExist(context.Background(), uint64(1), "dummy@dummy.com")
+		err := exec.repo.Update(context.Background(), uint64(1), uint64(3), createValidMedicalRecord())
 
 		assert.NotNil(t, err)
 		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
-		assert.False(t, found)
 	})
 
-	t.Run("record not found in repository", func(t *testing.T) {
+	t.Run("unique violation returns already exists error", func(t *testing.T) {
 		exec := createMedicalRecordUpdaterExecutor()
 
-		exec.sql.ExpectQuery(`SELECT id FROM medical_records WHERE id = \$1 AND email = \$2 LIMIT 1`).
-			WillReturnError(sql.ErrNoRows)
-		found, err := exec.repo.DoesRecordExist(context.Background(), uint64(1), "dummy@dummy.com")
+		exec.sql.ExpectExec(`UPDATE medical_records SET symptom = \$1, diagnosis = \$2, therapy = \$3, result = \$4, updated_at = \$5, updated_by = \$6 WHERE id = \$7 AND user_id = \$8`).
+			WillReturnError(&pgconn.PgError{Code: "23505"})
+		err := exec.repo.Update(context.Background(), uint64(1), uint64(3), createValidMedicalRecord())
 
-		assert.Nil(t, err)
-		assert.False(t, found)
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrAlreadyExists.Code, err.Code)
 	})
 
-	t.Run("successfully found the record", func(t *testing.T) {
+	t.Run("canceled statement returns timeout error", func(t *testing.T) {
 		exec := createMedicalRecordUpdaterExecutor()
 
-		exec.sql.ExpectQuery(`SELECT id FROM medical_records WHERE id = \$1 AND email = \$2 LIMIT 1`).
-			WillReturnRows(sqlmock.
-				NewRows([]string{"id"}).
-				AddRow(999),
-			)
-		found, err := exec.repo.DoesRecordExist(context.Background(), uint64(1), "dummy@dummy.com")
+		exec.sql.ExpectExec(`UPDATE medical_records SET symptom = \$1, diagnosis = \$2, therapy = \$3, result = \$4, updated_at = \$5, updated_by = \$6 WHERE id = \$7 AND user_id = \$8`).
+			WillReturnError(&pgconn.PgError{Code: "57014"})
+		err := exec.repo.Update(context.Background(), uint64(1), uint64(3), createValidMedicalRecord())
 
-		assert.Nil(t, err)
-		assert.True(t, found)
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrTimeout.Code, err.Code)
 	})
-}
 
-func TestMedicalRecordUpdater_Update(t *testing.T) {
-	t.Run("query returns internal error", func(t *testing.T) {
+	t.Run("context deadline returns timeout error", func(t *testing.T) {
 		exec := createMedicalRecordUpdaterExecutor()
 
-		exec.sql.ExpectExec(`UPDATE medical_records SET symptom = \$1, diagnosis = \$2, therapy = \$3, result = \$4, updated_at = \$5, updated_by = \$6 WHERE id = \$7`).
-			WillReturnError(errors.New("fail to select from database"))
-		err := exec.repo.Update(context.Background(), uint64(1), createValidMedicalRecord())
+		exec.sql.ExpectExec(`UPDATE medical_records SET symptom = \$1, diagnosis = \$2, therapy = \$3, result = \$4, updated_at = \$5, updated_by = \$6 WHERE id = \$7 AND user_id = \$8`).
+			WillReturnError(context.DeadlineExceeded)
+		err := exec.repo.Update(context.Background(), uint64(1), uint64(3), createValidMedicalRecord())
 
 		assert.NotNil(t, err)
-		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Equal(t, entity.ErrTimeout.Code, err.Code)
+	})
+
+	t.Run("record doesn't exist or belongs to other user", func(t *testing.T) {
+		exec := createMedicalRecordUpdaterExecutor()
+
+		exec.sql.ExpectExec(`UPDATE medical_records SET symptom = \$1, diagnosis = \$2, therapy = \$3, result = \$4, updated_at = \$5, updated_by = \$6 WHERE id = \$7 AND user_id = \$8`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		err := exec.repo.Update(context.Background(), uint64(1), uint64(3), createValidMedicalRecord())
+
+		assert.Equal(t, entity.ErrMedicalRecordNotFound, err)
 	})
 
 	t.Run("successfully update the medical record", func(t *testing.T) {
 		exec := createMedicalRecordUpdaterExecutor()
 
-		exec.sql.ExpectExec(`UPDATE medical_records SET symptom = \$1, diagnosis = \$2, therapy = \$3, result = \$4, updated_at = \$5, updated_by = \$6 WHERE id = \$7`).
+		exec.sql.ExpectExec(`UPDATE medical_records SET symptom = \$1, diagnosis = \$2, therapy = \$3, result = \$4, updated_at = \$5, updated_by = \$6 WHERE id = \$7 AND user_id = \$8`).
 			WillReturnResult(sqlmock.NewResult(1, 1))
-		err := exec.repo.Update(context.Background(), uint64(1), createValidMedicalRecord())
+		err := exec.repo.Update(context.Background(), uint64(1), uint64(3), createValidMedicalRecord())
 
 		assert.Nil(t, err)
 	})
@@ -93,7 +98,7 @@ func createMedicalRecordUpdaterExecutor() *MedicalRecordUpdaterExecutor {
 		log.Panicf("error opening a stub database connection: %v\n", err)
 	}
 
-	repo := repository.NewMedicalRecordUpdater(db)
+	repo := repository.NewMedicalRecordUpdater(db, encryption.Plaintext{}, nil)
 	return &MedicalRecordUpdaterExecutor{
 		repo: repo,
 		sql:  mock,
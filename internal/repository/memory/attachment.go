@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// AttachmentRepository keeps attachments' metadata in memory.
+type AttachmentRepository struct {
+	db *Database
+}
+
+// NewAttachmentRepository creates an instance of AttachmentRepository.
+func NewAttachmentRepository(db *Database) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+// DoesRecordExist checks whether medical record which has certain id and is owned by the user who has the userID exists.
+func (ar *AttachmentRepository) DoesRecordExist(ctx context.Context, id, userID uint64) (bool, *entity.Error) {
+	found := false
+	err := ar.db.run(ctx, func(d *data) *entity.Error {
+		found = d.doesRecordExist(id, userID)
+		return nil
+	})
+	return found, err
+}
+
+// Insert inserts a new attachment.
+// The medical record must exist and the storage key must be unique.
+func (ar *AttachmentRepository) Insert(ctx context.Context, attachment *entity.Attachment) *entity.Error {
+	if attachment == nil {
+		return entity.ErrEmptyAttachment
+	}
+
+	return ar.db.run(ctx, func(d *data) *entity.Error {
+		if _, ok := d.records[uint64(attachment.MedicalRecordID)]; !ok {
+			return entity.WrapError(entity.ErrInternalServer, "[AttachmentRepository-Insert] medical record doesn't exist")
+		}
+		for _, stored := range d.attachments {
+			if stored.StorageKey == attachment.StorageKey {
+				return entity.WrapError(entity.ErrAlreadyExists, "[AttachmentRepository-Insert] storage key already exists")
+			}
+		}
+
+		now := time.Now().UTC()
+		d.attachmentSeq++
+		attachment.ID = hashids.ID(d.attachmentSeq)
+		attachment.CreatedAt = now
+		attachment.UpdatedAt = now
+		d.attachments[d.attachmentSeq] = *attachment
+		return nil
+	})
+}
+
+// FindByMedicalRecordID finds all attachments of the medical record ordered by id.
+func (ar *AttachmentRepository) FindByMedicalRecordID(ctx context.Context, recordID uint64) ([]*entity.Attachment, *entity.Error) {
+	var result []*entity.Attachment
+	err := ar.db.run(ctx, func(d *data) *entity.Error {
+		for _, stored := range d.attachments {
+			if uint64(stored.MedicalRecordID) == recordID {
+				tmp := stored
+				result = append(result, &tmp)
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result, err
+}
+
+// FindByID finds an attachment of the medical record.
+// It returns entity.ErrAttachmentNotFound if the attachment doesn't exist.
+func (ar *AttachmentRepository) FindByID(ctx context.Context, recordID, id uint64) (*entity.Attachment, *entity.Error) {
+	var result *entity.Attachment
+	err := ar.db.run(ctx, func(d *data) *entity.Error {
+		stored, ok := d.attachments[id]
+		if !ok || uint64(stored.MedicalRecordID) != recordID {
+			return entity.ErrAttachmentNotFound
+		}
+		result = &stored
+		return nil
+	})
+	return result, err
+}
+
+// Delete deletes an attachment of the medical record and returns its storage key.
+// The storage key is queued to be deleted from storage in the same unit of work.
+// It returns entity.ErrAttachmentNotFound if the attachment doesn't exist.
+func (ar *AttachmentRepository) Delete(ctx context.Context, recordID, id uint64) (string, *entity.Error) {
+	var key string
+	err := ar.db.run(ctx, func(d *data) *entity.Error {
+		stored, ok := d.attachments[id]
+		if !ok || uint64(stored.MedicalRecordID) != recordID {
+			return entity.ErrAttachmentNotFound
+		}
+		delete(d.attachments, id)
+		key = stored.StorageKey
+		d.queueStorageDeletions([]string{key})
+		return nil
+	})
+	return key, err
+}
@@ -0,0 +1,30 @@
+package memory
+
+import "github.com/indrasaputra/orvosi-api/internal/repository"
+
+// NewBackend creates a backend whose repositories share a new empty in-memory database.
+func NewBackend() *repository.Backend {
+	db := NewDatabase()
+	records := NewMedicalRecordRepository(db)
+	users := NewUserRepository(db)
+	attachments := NewAttachmentRepository(db)
+	return &repository.Backend{
+		MedicalRecordInserter:   records,
+		MedicalRecordSelector:   records,
+		MedicalRecordUpdater:    records,
+		MedicalRecordReassigner: records,
+		UserInserter:            users,
+		UserSelector:            users,
+		ProfileUpdater:          users,
+		UserAnonymizer:          users,
+		UserEraser:              NewErasureRepository(db),
+		StorageDeleter:          NewStorageDeletionRepository(db),
+		IdentityLinker:          NewIdentityRepository(db),
+		EmailChanger:            NewEmailChangeRepository(db),
+		AttachmentInserter:      attachments,
+		AttachmentSelector:      attachments,
+		AttachmentDeleter:       attachments,
+		Transactor:              db,
+		Pinger:                  db,
+	}
+}
@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/indrasaputra/orvosi-api/internal/repository/contract"
+	"github.com/indrasaputra/orvosi-api/internal/repository/memory"
+)
+
+func TestBackend_Contract(t *testing.T) {
+	contract.Run(t, func(t *testing.T) *repository.Backend {
+		return memory.NewBackend()
+	})
+}
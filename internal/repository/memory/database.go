@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// txKey is the key of the database whose unit of work is running in the context.
+type txKey struct{}
+
+// Database keeps all data in memory.
+// Only one unit of work or single operation runs at a time, so every unit of work is serializable.
+type Database struct {
+	mu   sync.Mutex
+	data *data
+}
+
+type data struct {
+	users         map[uint64]entity.User
+	userSeq       uint64
+	records       map[uint64]entity.MedicalRecord
+	recordSeq     uint64
+	attachments   map[uint64]entity.Attachment
+	attachmentSeq uint64
+	// erasures are keyed by user id.
+	erasures    map[uint64]entity.Erasure
+	identities  map[uint64]entity.Identity
+	identitySeq uint64
+	// emailChanges are keyed by user id.
+	emailChanges map[uint64]entity.EmailChange
+	// storageDeletions keeps the time each storage key is queued for deletion.
+	storageDeletions map[string]time.Time
+}
+
+// NewDatabase creates an empty instance of Database.
+func NewDatabase() *Database {
+	return &Database{
+		data: &data{
+			users:        make(map[uint64]entity.User),
+			records:      make(map[uint64]entity.MedicalRecord),
+			attachments:  make(map[uint64]entity.Attachment),
+			erasures:     make(map[uint64]entity.Erasure),
+			identities:   make(map[uint64]entity.Identity),
+			emailChanges: make(map[uint64]entity.EmailChange),
+
+			storageDeletions: make(map[string]time.Time),
+		},
+	}
+}
+
+// WithinTransaction runs fn as a single unit of work.
+// The data is restored to its state before fn if fn returns error.
+// If the context already carries a unit of work, fn joins it.
+func (db *Database) WithinTransaction(ctx context.Context, fn func(ctx context.Context) *entity.Error) *entity.Error {
+	if db.inTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	snapshot := db.data.clone()
+	if err := fn(context.WithValue(ctx, txKey{}, db)); err != nil {
+		db.data = snapshot
+		return err
+	}
+	return nil
+}
+
+// Ping always succeeds since the data lives in the same process.
+func (db *Database) Ping(ctx context.Context) *entity.Error {
+	return nil
+}
+
+// run runs fn while holding the lock, unless it is part of a unit of work which already holds it.
+func (db *Database) run(ctx context.Context, fn func(d *data) *entity.Error) *entity.Error {
+	if db.inTransaction(ctx) {
+		return fn(db.data)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return fn(db.data)
+}
+
+func (db *Database) inTransaction(ctx context.Context) bool {
+	tx, ok := ctx.Value(txKey{}).(*Database)
+	return ok && tx == db
+}
+
+func (d *data) clone() *data {
+	c := &data{
+		users:         make(map[uint64]entity.User, len(d.users)),
+		userSeq:       d.userSeq,
+		records:       make(map[uint64]entity.MedicalRecord, len(d.records)),
+		recordSeq:     d.recordSeq,
+		attachments:   make(map[uint64]entity.Attachment, len(d.attachments)),
+		attachmentSeq: d.attachmentSeq,
+		erasures:      make(map[uint64]entity.Erasure, len(d.erasures)),
+		identities:    make(map[uint64]entity.Identity, len(d.identities)),
+		identitySeq:   d.identitySeq,
+		emailChanges:  make(map[uint64]entity.EmailChange, len(d.emailChanges)),
+
+		storageDeletions: make(map[string]time.Time, len(d.storageDeletions)),
+	}
+	for id, user := range d.users {
+		c.users[id] = user
+	}
+	for id, record := range d.records {
+		c.records[id] = record
+	}
+	for id, attachment := range d.attachments {
+		c.attachments[id] = attachment
+	}
+	for id, erasure := range d.erasures {
+		c.erasures[id] = erasure
+	}
+	for id, identity := range d.identities {
+		c.identities[id] = identity
+	}
+	for id, change := range d.emailChanges {
+		c.emailChanges[id] = change
+	}
+	for key, at := range d.storageDeletions {
+		c.storageDeletions[key] = at
+	}
+	return c
+}
+
+func (d *data) doesRecordExist(id, userID uint64) bool {
+	record, ok := d.records[id]
+	return ok && uint64(record.User.ID) == userID
+}
@@ -0,0 +1,4 @@
+// Package memory provides repositories which keep the data in memory.
+// It is meant for fast tests and local development, so the data is gone once the process stops.
+// It behaves the same as the SQL repositories as verified by the contract tests.
+package memory
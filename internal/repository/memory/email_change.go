@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// EmailChangeRepository keeps the email change requests of users in memory.
+type EmailChangeRepository struct {
+	db *Database
+}
+
+// NewEmailChangeRepository creates an instance of EmailChangeRepository.
+func NewEmailChangeRepository(db *Database) *EmailChangeRepository {
+	return &EmailChangeRepository{db: db}
+}
+
+// Save inserts the email change request, replacing the one the user has requested before, if any.
+func (er *EmailChangeRepository) Save(ctx context.Context, change *entity.EmailChange) *entity.Error {
+	if change == nil {
+		return entity.ErrInvalidEmailChangeRequest
+	}
+
+	return er.db.run(ctx, func(d *data) *entity.Error {
+		stored := *change
+		stored.RequestedAt = change.RequestedAt.UTC()
+		stored.ExpiresAt = change.ExpiresAt.UTC()
+		d.emailChanges[uint64(change.UserID)] = stored
+		return nil
+	})
+}
+
+// FindByUserID finds the email change request of the user.
+// It returns entity.ErrInvalidEmailChangeToken if there is none.
+func (er *EmailChangeRepository) FindByUserID(ctx context.Context, userID uint64) (*entity.EmailChange, *entity.Error) {
+	var result *entity.EmailChange
+	err := er.db.run(ctx, func(d *data) *entity.Error {
+		change, ok := d.emailChanges[userID]
+		if !ok {
+			return entity.ErrInvalidEmailChangeToken
+		}
+		result = &change
+		return nil
+	})
+	return result, err
+}
+
+// ChangeEmail replaces the email of the user who has the id with email to and deletes their email change request.
+// The audit actor fields which refer to email from are replaced as well.
+// It returns entity.ErrEmailAlreadyUsed if email to belongs to another user
+// and entity.ErrUserNotFound if the user doesn't exist.
+func (er *EmailChangeRepository) ChangeEmail(ctx context.Context, userID uint64, from, to string) *entity.Error {
+	return er.db.run(ctx, func(d *data) *entity.Error {
+		stored, ok := d.users[userID]
+		if !ok {
+			return entity.ErrUserNotFound
+		}
+		if id, _, taken := d.findUser(to); taken && id != userID {
+			return entity.WrapError(entity.ErrEmailAlreadyUsed, "[EmailChangeRepository-ChangeEmail] email already exists")
+		}
+
+		stored.Email = to
+		stored.CreatedBy = to
+		stored.UpdatedBy = to
+		stored.UpdatedAt = time.Now().UTC()
+		d.users[userID] = stored
+		delete(d.emailChanges, userID)
+
+		d.replaceActor(from, to)
+		return nil
+	})
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// ErasureRepository keeps the erasure requests of users in memory.
+type ErasureRepository struct {
+	db *Database
+}
+
+// NewErasureRepository creates an instance of ErasureRepository.
+func NewErasureRepository(db *Database) *ErasureRepository {
+	return &ErasureRepository{db: db}
+}
+
+// Schedule inserts the erasure of the user who has the id unless it exists,
+// then returns the erasure which is kept.
+// It returns entity.ErrUserNotFound if the user doesn't exist.
+func (er *ErasureRepository) Schedule(ctx context.Context, userID uint64, requestedAt, eraseAfter time.Time) (*entity.Erasure, *entity.Error) {
+	var result *entity.Erasure
+	err := er.db.run(ctx, func(d *data) *entity.Error {
+		user, ok := d.users[userID]
+		if !ok {
+			return entity.ErrUserNotFound
+		}
+		if _, ok := d.erasures[userID]; !ok {
+			d.erasures[userID] = entity.Erasure{
+				UserID:      user.ID,
+				RequestedAt: requestedAt.UTC(),
+				EraseAfter:  eraseAfter.UTC(),
+			}
+		}
+		result = d.erasureOf(userID)
+		return nil
+	})
+	return result, err
+}
+
+// FindByUserID finds the scheduled erasure of the user who has the id.
+// It returns entity.ErrErasureNotScheduled if there is none.
+func (er *ErasureRepository) FindByUserID(ctx context.Context, userID uint64) (*entity.Erasure, *entity.Error) {
+	var result *entity.Erasure
+	err := er.db.run(ctx, func(d *data) *entity.Error {
+		if result = d.erasureOf(userID); result == nil {
+			return entity.ErrErasureNotScheduled
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Cancel deletes the erasure of the user who has the id.
+// It returns entity.ErrErasureNotScheduled if there is none.
+func (er *ErasureRepository) Cancel(ctx context.Context, userID uint64) *entity.Error {
+	return er.db.run(ctx, func(d *data) *entity.Error {
+		if _, ok := d.erasures[userID]; !ok {
+			return entity.ErrErasureNotScheduled
+		}
+		delete(d.erasures, userID)
+		return nil
+	})
+}
+
+// FindDue finds at most limit erasures whose EraseAfter is not after now, ordered by EraseAfter.
+func (er *ErasureRepository) FindDue(ctx context.Context, now time.Time, limit uint) ([]*entity.Erasure, *entity.Error) {
+	result := []*entity.Erasure{}
+	err := er.db.run(ctx, func(d *data) *entity.Error {
+		for id, erasure := range d.erasures {
+			if !erasure.EraseAfter.After(now) {
+				result = append(result, d.erasureOf(id))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return []*entity.Erasure{}, err
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].EraseAfter.Before(result[j].EraseAfter)
+	})
+	if uint(len(result)) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// Claim returns the erasure of the user along with their current email if it is still due at now.
+// The database is locked by the unit of work, so no other unit of work can take the erasure meanwhile.
+// It returns entity.ErrErasureNotScheduled if the erasure has been cancelled or isn't due.
+func (er *ErasureRepository) Claim(ctx context.Context, userID uint64, now time.Time) (*entity.Erasure, *entity.Error) {
+	var result *entity.Erasure
+	err := er.db.run(ctx, func(d *data) *entity.Error {
+		result = d.erasureOf(userID)
+		if result == nil || result.EraseAfter.After(now) {
+			return entity.ErrErasureNotScheduled
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Erase deletes the user who has the id, their erasure, identities, email change request, medical records, and the attachments of those records,
+// then replaces the email in the audit actor fields of the remaining data with the alias.
+// The storage keys of the deleted attachments are queued for deletion and returned.
+func (er *ErasureRepository) Erase(ctx context.Context, userID uint64, email, alias string) ([]string, *entity.Error) {
+	var keys []string
+	err := er.db.run(ctx, func(d *data) *entity.Error {
+		for id, record := range d.records {
+			if uint64(record.User.ID) != userID {
+				continue
+			}
+			for aid, attachment := range d.attachments {
+				if uint64(attachment.MedicalRecordID) == id {
+					keys = append(keys, attachment.StorageKey)
+					delete(d.attachments, aid)
+				}
+			}
+			delete(d.records, id)
+		}
+		d.queueStorageDeletions(keys)
+		d.deleteIdentities(userID)
+		delete(d.emailChanges, userID)
+		delete(d.erasures, userID)
+		delete(d.users, userID)
+
+		d.replaceActor(email, alias)
+		return nil
+	})
+	return keys, err
+}
+
+// erasureOf returns the erasure of the user along with their current email, or nil if there is none.
+func (d *data) erasureOf(userID uint64) *entity.Erasure {
+	erasure, ok := d.erasures[userID]
+	if !ok {
+		return nil
+	}
+	erasure.Email = d.users[userID].Email
+	return &erasure
+}
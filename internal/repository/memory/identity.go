@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// IdentityRepository keeps the identities of users in memory.
+type IdentityRepository struct {
+	db *Database
+}
+
+// NewIdentityRepository creates an instance of IdentityRepository.
+func NewIdentityRepository(db *Database) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// Link inserts the identity and sets the inserted id and creation time back to it.
+// It returns entity.ErrIdentityAlreadyLinked if the provider and subject are already linked.
+func (ir *IdentityRepository) Link(ctx context.Context, identity *entity.Identity) *entity.Error {
+	if identity == nil {
+		return entity.ErrInvalidIdentityRequest
+	}
+
+	return ir.db.run(ctx, func(d *data) *entity.Error {
+		for _, stored := range d.identities {
+			if stored.Provider == identity.Provider && stored.Subject == identity.Subject {
+				return entity.WrapError(entity.ErrIdentityAlreadyLinked, "[IdentityRepository-Link] provider and subject already exist")
+			}
+		}
+
+		d.identitySeq++
+		identity.ID = hashids.ID(d.identitySeq)
+		identity.CreatedAt = time.Now().UTC()
+		d.identities[d.identitySeq] = *identity
+		return nil
+	})
+}
+
+// FindByUserID finds all identities of the user ordered by id.
+func (ir *IdentityRepository) FindByUserID(ctx context.Context, userID uint64) ([]*entity.Identity, *entity.Error) {
+	result := []*entity.Identity{}
+	err := ir.db.run(ctx, func(d *data) *entity.Error {
+		result = d.identitiesOf(userID)
+		return nil
+	})
+	if err != nil {
+		return []*entity.Identity{}, err
+	}
+	return result, nil
+}
+
+// Unlink deletes the identity of the user.
+// If the Google ID of the user was the subject of the identity,
+// it is replaced by the subject of the oldest remaining identity.
+// It returns entity.ErrIdentityNotFound if the user has no such identity.
+func (ir *IdentityRepository) Unlink(ctx context.Context, userID, id uint64) *entity.Error {
+	return ir.db.run(ctx, func(d *data) *entity.Error {
+		identity, ok := d.identities[id]
+		if !ok || uint64(identity.UserID) != userID {
+			return entity.ErrIdentityNotFound
+		}
+		delete(d.identities, id)
+
+		user, ok := d.users[userID]
+		remaining := d.identitiesOf(userID)
+		if !ok || len(remaining) == 0 {
+			return nil
+		}
+		for _, identity := range remaining {
+			if identity.Subject == user.GoogleID {
+				return nil
+			}
+		}
+		user.GoogleID = remaining[0].Subject
+		user.UpdatedAt = time.Now().UTC()
+		d.users[userID] = user
+		return nil
+	})
+}
+
+func (d *data) identitiesOf(userID uint64) []*entity.Identity {
+	result := []*entity.Identity{}
+	for _, identity := range d.identities {
+		if uint64(identity.UserID) == userID {
+			tmp := identity
+			result = append(result, &tmp)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+func (d *data) deleteIdentities(userID uint64) {
+	for id, identity := range d.identities {
+		if uint64(identity.UserID) == userID {
+			delete(d.identities, id)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// MedicalRecordRepository keeps medical records in memory.
+type MedicalRecordRepository struct {
+	db *Database
+}
+
+// NewMedicalRecordRepository creates an instance of MedicalRecordRepository.
+func NewMedicalRecordRepository(db *Database) *MedicalRecordRepository {
+	return &MedicalRecordRepository{db: db}
+}
+
+// Insert inserts a new medical record.
+// The result is not inserted since a new medical record doesn't have it yet.
+func (mr *MedicalRecordRepository) Insert(ctx context.Context, record *entity.MedicalRecord) *entity.Error {
+	if record == nil {
+		return entity.ErrEmptyMedicalRecord
+	}
+
+	return mr.db.run(ctx, func(d *data) *entity.Error {
+		d.insertRecord(record, "", time.Now().UTC())
+		return nil
+	})
+}
+
+// InsertMany inserts all medical records at once.
+// None of them is inserted if any of them is invalid.
+func (mr *MedicalRecordRepository) InsertMany(ctx context.Context, records []*entity.MedicalRecord) *entity.Error {
+	for _, record := range records {
+		if record == nil || record.User == nil {
+			return entity.ErrEmptyMedicalRecord
+		}
+	}
+
+	return mr.db.run(ctx, func(d *data) *entity.Error {
+		now := time.Now().UTC()
+		for _, record := range records {
+			d.insertRecord(record, record.Result, now)
+		}
+		return nil
+	})
+}
+
+// FindByID finds medical record by its id.
+func (mr *MedicalRecordRepository) FindByID(ctx context.Context, id uint64) (*entity.MedicalRecord, *entity.Error) {
+	var result *entity.MedicalRecord
+	err := mr.db.run(ctx, func(d *data) *entity.Error {
+		record, ok := d.records[id]
+		if !ok {
+			return entity.ErrMedicalRecordNotFound
+		}
+		result = copyRecord(record)
+		return nil
+	})
+	return result, err
+}
+
+// FindByUserID finds all medical records owned by the user who has the id.
+// Only medical records whose id is less than from are returned, ordered by the newest creation time.
+func (mr *MedicalRecordRepository) FindByUserID(ctx context.Context, userID, from uint64, limit uint) ([]*entity.MedicalRecord, *entity.Error) {
+	return mr.find(ctx, limit, func(record entity.MedicalRecord) bool {
+		return uint64(record.User.ID) == userID && uint64(record.ID) < from
+	})
+}
+
+// FindByUserIDWithinPeriod finds all medical records owned by the user who has the id
+// and created within [since, until).
+func (mr *MedicalRecordRepository) FindByUserIDWithinPeriod(ctx context.Context, userID uint64, since, until time.Time, from uint64, limit uint) ([]*entity.MedicalRecord, *entity.Error) {
+	return mr.find(ctx, limit, func(record entity.MedicalRecord) bool {
+		return uint64(record.User.ID) == userID && uint64(record.ID) < from &&
+			!record.CreatedAt.Before(since) && record.CreatedAt.Before(until)
+	})
+}
+
+// Update updates the whole record data of the medical record which has certain id and is owned by the user who has the userID.
+// It returns ErrMedicalRecordNotFound if no such record exists.
+func (mr *MedicalRecordRepository) Update(ctx context.Context, id, userID uint64, record *entity.MedicalRecord) *entity.Error {
+	return mr.db.run(ctx, func(d *data) *entity.Error {
+		if !d.doesRecordExist(id, userID) {
+			return entity.ErrMedicalRecordNotFound
+		}
+		stored := d.records[id]
+		stored.Symptom = record.Symptom
+		stored.Diagnosis = record.Diagnosis
+		stored.Therapy = record.Therapy
+		stored.Result = record.Result
+		stored.UpdatedAt = time.Now().UTC()
+		stored.UpdatedBy = record.User.Email
+		d.records[id] = stored
+		return nil
+	})
+}
+
+// ReassignByEmail changes the owner of all medical records owned by the user who has email from
+// to the user who has email to and returns the number of changed records.
+// It returns entity.ErrUserNotFound if no user has email to.
+func (mr *MedicalRecordRepository) ReassignByEmail(ctx context.Context, from, to string) (int64, *entity.Error) {
+	var total int64
+	err := mr.db.run(ctx, func(d *data) *entity.Error {
+		_, owner, ok := d.findUser(to)
+		if !ok {
+			return entity.ErrUserNotFound
+		}
+		fromID, _, ok := d.findUser(from)
+		if !ok {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		for id, record := range d.records {
+			if uint64(record.User.ID) == fromID {
+				record.User = &entity.User{ID: owner.ID}
+				record.UpdatedAt = now
+				d.records[id] = record
+				total++
+			}
+		}
+		return nil
+	})
+	return total, err
+}
+
+// find returns the records which match the filter, the same way the SQL repository does:
+// ordered by the newest creation time and without user.
+func (mr *MedicalRecordRepository) find(ctx context.Context, limit uint, match func(record entity.MedicalRecord) bool) ([]*entity.MedicalRecord, *entity.Error) {
+	var result []*entity.MedicalRecord
+	err := mr.db.run(ctx, func(d *data) *entity.Error {
+		for _, record := range d.records {
+			if match(record) {
+				tmp := copyRecord(record)
+				tmp.User = nil
+				result = append(result, tmp)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return []*entity.MedicalRecord{}, err
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].ID > result[j].ID
+		}
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	if uint(len(result)) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (d *data) insertRecord(record *entity.MedicalRecord, result string, now time.Time) {
+	d.recordSeq++
+	record.ID = hashids.ID(d.recordSeq)
+	d.records[d.recordSeq] = entity.MedicalRecord{
+		ID:        record.ID,
+		User:      &entity.User{ID: record.User.ID},
+		Symptom:   record.Symptom,
+		Diagnosis: record.Diagnosis,
+		Therapy:   record.Therapy,
+		Result:    result,
+		Auditable: entity.Auditable{
+			CreatedBy: record.User.Email,
+			UpdatedBy: record.User.Email,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	}
+}
+
+func copyRecord(record entity.MedicalRecord) *entity.MedicalRecord {
+	record.User = &entity.User{ID: record.User.ID}
+	return &record
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// StorageDeletionRepository keeps the storage keys whose content is still to be deleted in memory.
+type StorageDeletionRepository struct {
+	db *Database
+}
+
+// NewStorageDeletionRepository creates an instance of StorageDeletionRepository.
+func NewStorageDeletionRepository(db *Database) *StorageDeletionRepository {
+	return &StorageDeletionRepository{db: db}
+}
+
+// FindPending finds at most limit storage keys whose content is still to be deleted, the oldest first.
+func (sr *StorageDeletionRepository) FindPending(ctx context.Context, limit uint) ([]string, *entity.Error) {
+	keys := []string{}
+	var queued map[string]time.Time
+	err := sr.db.run(ctx, func(d *data) *entity.Error {
+		queued = make(map[string]time.Time, len(d.storageDeletions))
+		for key, at := range d.storageDeletions {
+			queued[key] = at
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return []string{}, err
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if !queued[keys[i]].Equal(queued[keys[j]]) {
+			return queued[keys[i]].Before(queued[keys[j]])
+		}
+		return keys[i] < keys[j]
+	})
+	if uint(len(keys)) > limit {
+		keys = keys[:limit]
+	}
+	return keys, nil
+}
+
+// Done removes the storage key from the queue once its content has been deleted.
+func (sr *StorageDeletionRepository) Done(ctx context.Context, key string) *entity.Error {
+	return sr.db.run(ctx, func(d *data) *entity.Error {
+		delete(d.storageDeletions, key)
+		return nil
+	})
+}
+
+// queueStorageDeletions queues the storage keys whose metadata is deleted by the same unit of work.
+func (d *data) queueStorageDeletions(keys []string) {
+	now := time.Now().UTC()
+	for _, key := range keys {
+		if _, ok := d.storageDeletions[key]; !ok {
+			d.storageDeletions[key] = now
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// UserRepository keeps users in memory.
+type UserRepository struct {
+	db *Database
+}
+
+// NewUserRepository creates an instance of UserRepository.
+func NewUserRepository(db *Database) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// Upsert inserts a new user.
+// If the user's email already exists, the name and Google ID are refreshed instead.
+// Google ID must be unique as well.
+func (ur *UserRepository) Upsert(ctx context.Context, user *entity.User) (*entity.User, *entity.Error) {
+	if user == nil {
+		return nil, entity.ErrEmptyUser
+	}
+
+	var result entity.User
+	err := ur.db.run(ctx, func(d *data) *entity.Error {
+		for _, stored := range d.users {
+			if stored.Email != user.Email && stored.GoogleID == user.GoogleID {
+				return entity.WrapError(entity.ErrAlreadyExists, "[UserRepository-Upsert] google id already exists")
+			}
+		}
+
+		now := time.Now().UTC()
+		if id, stored, ok := d.findUser(user.Email); ok {
+			stored.Name = user.Name
+			stored.GoogleID = user.GoogleID
+			stored.UpdatedBy = user.Email
+			stored.UpdatedAt = now
+			d.users[id] = stored
+			result = stored
+			return nil
+		}
+
+		result = d.insertUser(user, now)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Insert inserts a new user.
+// It returns entity.ErrEmailAlreadyUsed if the email belongs to another user.
+// Google ID must be unique as well.
+func (ur *UserRepository) Insert(ctx context.Context, user *entity.User) (*entity.User, *entity.Error) {
+	if user == nil {
+		return nil, entity.ErrEmptyUser
+	}
+
+	var result entity.User
+	err := ur.db.run(ctx, func(d *data) *entity.Error {
+		if _, _, ok := d.findUser(user.Email); ok {
+			return entity.ErrEmailAlreadyUsed
+		}
+		for _, stored := range d.users {
+			if stored.GoogleID == user.GoogleID {
+				return entity.WrapError(entity.ErrAlreadyExists, "[UserRepository-Insert] google id already exists")
+			}
+		}
+
+		result = d.insertUser(user, time.Now().UTC())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateProfile replaces the profile of the user who has the email.
+// It returns entity.ErrUserNotFound if the user doesn't exist.
+func (ur *UserRepository) UpdateProfile(ctx context.Context, email string, profile *entity.Profile) *entity.Error {
+	if profile == nil {
+		return entity.ErrInvalidProfileRequest
+	}
+
+	return ur.db.run(ctx, func(d *data) *entity.Error {
+		id, stored, ok := d.findUser(email)
+		if !ok {
+			return entity.ErrUserNotFound
+		}
+		stored.Profile = *profile
+		d.users[id] = stored
+		return nil
+	})
+}
+
+// FindAll finds at most limit users whose id is greater than from, ordered by id.
+func (ur *UserRepository) FindAll(ctx context.Context, from uint64, limit uint) ([]*entity.User, *entity.Error) {
+	result := []*entity.User{}
+	err := ur.db.run(ctx, func(d *data) *entity.Error {
+		for id, stored := range d.users {
+			if id > from {
+				tmp := stored
+				result = append(result, &tmp)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return []*entity.User{}, err
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	if uint(len(result)) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// FindByEmail finds the user who has the email.
+// It returns entity.ErrUserNotFound if the user doesn't exist.
+func (ur *UserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, *entity.Error) {
+	var result *entity.User
+	err := ur.db.run(ctx, func(d *data) *entity.Error {
+		if _, stored, ok := d.findUser(email); ok {
+			result = &stored
+			return nil
+		}
+		return entity.ErrUserNotFound
+	})
+	return result, err
+}
+
+// Anonymize replaces the name, email, and Google ID of the user who has the id with the alias
+// and clears the profile, the identities, and the email change request, since they may identify the user as well.
+// The medical records are kept under the user, while the audit actor fields which refer to the email are replaced.
+// It returns entity.ErrUserNotFound if the user doesn't exist.
+func (ur *UserRepository) Anonymize(ctx context.Context, id uint64, email, alias string) *entity.Error {
+	return ur.db.run(ctx, func(d *data) *entity.Error {
+		stored, ok := d.users[id]
+		if !ok {
+			return entity.ErrUserNotFound
+		}
+		stored.Name = ""
+		stored.Profile = entity.Profile{}
+		stored.Email = alias
+		stored.GoogleID = alias
+		stored.CreatedBy = alias
+		stored.UpdatedBy = alias
+		stored.UpdatedAt = time.Now().UTC()
+		d.users[id] = stored
+		d.deleteIdentities(id)
+		delete(d.emailChanges, id)
+
+		d.replaceActor(email, alias)
+		return nil
+	})
+}
+
+// FindByIdentity finds the user whose identity has the provider and subject.
+// It returns entity.ErrUserNotFound if no user has such identity.
+func (ur *UserRepository) FindByIdentity(ctx context.Context, provider, subject string) (*entity.User, *entity.Error) {
+	var result *entity.User
+	err := ur.db.run(ctx, func(d *data) *entity.Error {
+		for _, identity := range d.identities {
+			if identity.Provider == provider && identity.Subject == subject {
+				stored := d.users[uint64(identity.UserID)]
+				result = &stored
+				return nil
+			}
+		}
+		return entity.ErrUserNotFound
+	})
+	return result, err
+}
+
+// Refresh replaces the name and Google ID of the user who has the id.
+func (ur *UserRepository) Refresh(ctx context.Context, userID uint64, name, googleID string) *entity.Error {
+	return ur.db.run(ctx, func(d *data) *entity.Error {
+		stored, ok := d.users[userID]
+		if !ok {
+			return nil
+		}
+		stored.Name = name
+		stored.GoogleID = googleID
+		stored.UpdatedAt = time.Now().UTC()
+		d.users[userID] = stored
+		return nil
+	})
+}
+
+// insertUser stores a new user taken from the ID token.
+func (d *data) insertUser(user *entity.User, now time.Time) entity.User {
+	d.userSeq++
+	d.users[d.userSeq] = entity.User{
+		ID:       hashids.ID(d.userSeq),
+		Email:    user.Email,
+		Name:     user.Name,
+		GoogleID: user.GoogleID,
+		Auditable: entity.Auditable{
+			CreatedBy: user.Email,
+			UpdatedBy: user.Email,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	}
+	return d.users[d.userSeq]
+}
+
+func (d *data) findUser(email string) (uint64, entity.User, bool) {
+	for id, stored := range d.users {
+		if stored.Email == email {
+			return id, stored, true
+		}
+	}
+	return 0, entity.User{}, false
+}
+
+// replaceActor replaces the email in the audit actor fields of all medical records and attachments.
+func (d *data) replaceActor(email, alias string) {
+	for id, record := range d.records {
+		record.Auditable = replaceActor(record.Auditable, email, alias)
+		d.records[id] = record
+	}
+	for id, attachment := range d.attachments {
+		attachment.Auditable = replaceActor(attachment.Auditable, email, alias)
+		d.attachments[id] = attachment
+	}
+}
+
+func replaceActor(audit entity.Auditable, email, alias string) entity.Auditable {
+	if audit.CreatedBy == email {
+		audit.CreatedBy = alias
+	}
+	if audit.UpdatedBy == email {
+		audit.UpdatedBy = alias
+	}
+	return audit
+}
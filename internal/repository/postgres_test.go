@@ -0,0 +1,67 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/encryption"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/indrasaputra/orvosi-api/internal/repository/contract"
+	"github.com/indrasaputra/orvosi-api/test/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	postgres.Main(m)
+}
+
+func TestSQLBackend_PostgresContract(t *testing.T) {
+	contract.Run(t, func(t *testing.T) *repository.Backend {
+		return repository.NewSQLBackend(postgres.NewDatabase(t), nil, encryption.Plaintext{}, 3)
+	})
+}
+
+func TestSQLBackend_PostgresContractWithEncryption(t *testing.T) {
+	contract.Run(t, func(t *testing.T) *repository.Backend {
+		return repository.NewSQLBackend(postgres.NewDatabase(t), nil, createEnvelope("v1"), 3)
+	})
+}
+
+func TestMedicalRecordRekeyer_Postgres(t *testing.T) {
+	t.Run("re-encrypt plaintext and old key, then nothing is left", func(t *testing.T) {
+		db := postgres.NewDatabase(t)
+		plain := repository.NewMedicalRecordInserter(db, encryption.Plaintext{}, nil, 0)
+		old := repository.NewMedicalRecordInserter(db, createEnvelope("v1"), nil, 0)
+		current := createEnvelope("v2")
+
+		var ids []uint64
+		for _, ins := range []*repository.MedicalRecordInserter{plain, old, old} {
+			record := &entity.MedicalRecord{User: &entity.User{Email: "a@orvosi.com"}, Symptom: "symptom", Diagnosis: "diagnosis", Therapy: "therapy"}
+			assert.Nil(t, ins.Insert(context.Background(), record))
+			ids = append(ids, uint64(record.ID))
+		}
+
+		rekeyer := repository.NewMedicalRecordRekeyer(db, current)
+		total, err := rekeyer.Rekey(context.Background(), 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, total)
+
+		total, err = rekeyer.Rekey(context.Background(), 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, total)
+
+		var symptom string
+		assert.Nil(t, db.QueryRow("SELECT symptom FROM medical_records WHERE id = $1", ids[0]).Scan(&symptom))
+		assert.True(t, encryptedValue("v2").Match(symptom))
+
+		sel := repository.NewMedicalRecordSelector(db, current, nil)
+		for _, id := range ids {
+			res, err := sel.FindByID(context.Background(), id)
+			assert.Nil(t, err)
+			if assert.NotNil(t, res) {
+				assert.Equal(t, "symptom", res.Symptom)
+			}
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// ProfileUpdater connects the database with user entity
+// and only responsible for updating the profile of a user.
+type ProfileUpdater struct {
+	db *sql.DB
+}
+
+// NewProfileUpdater creates an instance of ProfileUpdater.
+func NewProfileUpdater(db *sql.DB) *ProfileUpdater {
+	return &ProfileUpdater{db: db}
+}
+
+// UpdateProfile replaces the profile of the user who has the email.
+// The profile is inserted if the user has never filled it in.
+// It returns entity.ErrUserNotFound if the user doesn't exist.
+func (pu *ProfileUpdater) UpdateProfile(ctx context.Context, email string, profile *entity.Profile) *entity.Error {
+	if profile == nil {
+		return entity.ErrInvalidProfileRequest
+	}
+
+	query := "INSERT INTO user_profiles (user_id, display_name, specialty, license_number, timezone, preferred_language, updated_at) " +
+		"SELECT id, $1, $2, $3, $4, $5, $6 FROM users WHERE email = $7 " +
+		"ON CONFLICT (user_id) DO UPDATE SET display_name = EXCLUDED.display_name, specialty = EXCLUDED.specialty, license_number = EXCLUDED.license_number, " +
+		"timezone = EXCLUDED.timezone, preferred_language = EXCLUDED.preferred_language, updated_at = EXCLUDED.updated_at"
+	res, err := querierFromContext(ctx, pu.db).ExecContext(ctx, query,
+		profile.DisplayName,
+		profile.Specialty,
+		profile.LicenseNumber,
+		profile.Timezone,
+		profile.PreferredLanguage,
+		time.Now().UTC(),
+		email,
+	)
+	if err != nil {
+		return databaseError(err, "[ProfileUpdater-UpdateProfile] exec upsert query: ")
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return databaseError(err, "[ProfileUpdater-UpdateProfile] get affected rows: ")
+	}
+	if affected == 0 {
+		return entity.ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+const upsertProfileQuery = `INSERT INTO user_profiles \(user_id, display_name, specialty, license_number, timezone, preferred_language, updated_at\) ` +
+	`SELECT id, \$1, \$2, \$3, \$4, \$5, \$6 FROM users WHERE email = \$7 ON CONFLICT \(user_id\) DO UPDATE SET .+`
+
+type ProfileUpdaterExecutor struct {
+	repo *repository.ProfileUpdater
+	sql  sqlmock.Sqlmock
+}
+
+func TestNewProfileUpdater(t *testing.T) {
+	t.Run("successfully create an instance of ProfileUpdater", func(t *testing.T) {
+		exec := createProfileUpdaterExecutor()
+		assert.NotNil(t, exec.repo)
+	})
+}
+
+func TestProfileUpdater_UpdateProfile(t *testing.T) {
+	profile := &entity.Profile{DisplayName: "dr. A", Specialty: "Cardiology", Timezone: "Asia/Jakarta", PreferredLanguage: "id"}
+
+	t.Run("profile is nil", func(t *testing.T) {
+		exec := createProfileUpdaterExecutor()
+
+		err := exec.repo.UpdateProfile(context.Background(), "a@orvosi.com", nil)
+
+		assert.Equal(t, entity.ErrInvalidProfileRequest, err)
+	})
+
+	t.Run("upsert query returns error", func(t *testing.T) {
+		exec := createProfileUpdaterExecutor()
+
+		exec.sql.ExpectExec(upsertProfileQuery).WillReturnError(errors.New("fail to update database"))
+		err := exec.repo.UpdateProfile(context.Background(), "a@orvosi.com", profile)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("user is not found", func(t *testing.T) {
+		exec := createProfileUpdaterExecutor()
+
+		exec.sql.ExpectExec(upsertProfileQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+		err := exec.repo.UpdateProfile(context.Background(), "a@orvosi.com", profile)
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+	})
+
+	t.Run("successfully update profile", func(t *testing.T) {
+		exec := createProfileUpdaterExecutor()
+
+		exec.sql.ExpectExec(upsertProfileQuery).
+			WithArgs("dr. A", "Cardiology", "", "Asia/Jakarta", "id", sqlmock.AnyArg(), "a@orvosi.com").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err := exec.repo.UpdateProfile(context.Background(), "a@orvosi.com", profile)
+
+		assert.Nil(t, err)
+	})
+}
+
+func createProfileUpdaterExecutor() *ProfileUpdaterExecutor {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Panicf("[createProfileUpdaterExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	repo := repository.NewProfileUpdater(db)
+	return &ProfileUpdaterExecutor{
+		repo: repo,
+		sql:  mock,
+	}
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const replicaPingTimeout = 2 * time.Second
+
+// ReplicaRouter chooses the database which serves a read.
+// Reads are spread to healthy replicas in round robin.
+// A read goes to the primary if it touches data written within the read-your-writes window,
+// since the replicas may not have received the write yet.
+// It also goes to the primary if no replica is healthy.
+// The writes are only known by the instance which made them, so with more than one instance of the API
+// a read served by another instance may still go to a replica within the window.
+type ReplicaRouter struct {
+	primary  *sql.DB
+	replicas []*replica
+	next     uint64
+	window   time.Duration
+
+	mu     sync.Mutex
+	writes map[string]time.Time
+}
+
+type replica struct {
+	db      *sql.DB
+	healthy int32
+}
+
+// NewReplicaRouter creates an instance of ReplicaRouter.
+// All replicas are considered healthy until CheckHealth says otherwise.
+func NewReplicaRouter(primary *sql.DB, replicas []*sql.DB, window time.Duration) *ReplicaRouter {
+	rr := &ReplicaRouter{
+		primary: primary,
+		window:  window,
+		writes:  make(map[string]time.Time),
+	}
+	for _, db := range replicas {
+		rr.replicas = append(rr.replicas, &replica{db: db, healthy: 1})
+	}
+	return rr
+}
+
+// Reader returns the database which serves a read of data identified by the keys.
+func (rr *ReplicaRouter) Reader(keys ...string) *sql.DB {
+	if len(rr.replicas) == 0 || rr.wroteRecently(keys) {
+		return rr.primary
+	}
+
+	n := uint64(len(rr.replicas))
+	start := atomic.AddUint64(&rr.next, 1)
+	for i := uint64(0); i < n; i++ {
+		r := rr.replicas[(start+i)%n]
+		if atomic.LoadInt32(&r.healthy) == 1 {
+			return r.db
+		}
+	}
+	return rr.primary
+}
+
+// Wrote records that data identified by the keys has just been written to the primary.
+func (rr *ReplicaRouter) Wrote(keys ...string) {
+	if len(rr.replicas) == 0 || rr.window <= 0 {
+		return
+	}
+
+	now := time.Now()
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	for key, at := range rr.writes {
+		if now.Sub(at) >= rr.window {
+			delete(rr.writes, key)
+		}
+	}
+	for _, key := range keys {
+		rr.writes[key] = now
+	}
+}
+
+// Failed stops sending reads to the replica until CheckHealth finds it healthy again.
+// It does nothing if db is not one of the replicas.
+func (rr *ReplicaRouter) Failed(db *sql.DB) {
+	for _, r := range rr.replicas {
+		if r.db == db {
+			atomic.StoreInt32(&r.healthy, 0)
+		}
+	}
+}
+
+// CheckHealth pings all replicas and only keeps sending reads to those which answer.
+func (rr *ReplicaRouter) CheckHealth(ctx context.Context) {
+	for _, r := range rr.replicas {
+		pctx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+		var healthy int32
+		if err := r.db.PingContext(pctx); err == nil {
+			healthy = 1
+		}
+		cancel()
+		atomic.StoreInt32(&r.healthy, healthy)
+	}
+}
+
+// Watch runs CheckHealth every interval until the context is done.
+// It returns immediately if there is no replica.
+func (rr *ReplicaRouter) Watch(ctx context.Context, interval time.Duration) {
+	if len(rr.replicas) == 0 || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rr.CheckHealth(ctx)
+		}
+	}
+}
+
+// Close closes the connection pools of the replicas.
+// The primary is left open since it isn't opened by the router.
+func (rr *ReplicaRouter) Close() error {
+	var first error
+	for _, r := range rr.replicas {
+		if err := r.db.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func (rr *ReplicaRouter) wroteRecently(keys []string) bool {
+	if rr.window <= 0 {
+		return false
+	}
+
+	now := time.Now()
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	for _, key := range keys {
+		if at, ok := rr.writes[key]; ok && now.Sub(at) < rr.window {
+			return true
+		}
+	}
+	return false
+}
+
+// medicalRecordKey and userKey identify the data for read-your-writes.
+func medicalRecordKey(id uint64) string {
+	return "medical_record:" + strconv.FormatUint(id, 10)
+}
+
+func userKey(id uint64) string {
+	return "user:" + strconv.FormatUint(id, 10)
+}
+
+// readerFor returns the primary if there is no router.
+func readerFor(db *sql.DB, router *ReplicaRouter, keys ...string) *sql.DB {
+	if router == nil {
+		return db
+	}
+	return router.Reader(keys...)
+}
+
+// retryOnPrimary tells whether a read which failed on the reader should run again on the primary.
+// That is when the reader is a replica and the read is neither in a transaction nor cancelled.
+// The replica stops serving reads until the next health check.
+func retryOnPrimary(ctx context.Context, router *ReplicaRouter, reader, primary *sql.DB) bool {
+	if router == nil || reader == primary || ctx.Err() != nil {
+		return false
+	}
+	if _, ok := ctx.Value(txKey{}).(*transaction); ok {
+		return false
+	}
+	router.Failed(reader)
+	return true
+}
+
+// markWrite does nothing if there is no router.
+func markWrite(router *ReplicaRouter, keys ...string) {
+	if router != nil {
+		router.Wrote(keys...)
+	}
+}
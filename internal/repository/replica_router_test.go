@@ -0,0 +1,313 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/encryption"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type ReplicaRouterExecutor struct {
+	router      *repository.ReplicaRouter
+	primary     *sql.DB
+	replicas    []*sql.DB
+	replicaMock []sqlmock.Sqlmock
+}
+
+func TestNewReplicaRouter(t *testing.T) {
+	t.Run("successfully create an instance of ReplicaRouter", func(t *testing.T) {
+		exec := createReplicaRouterExecutor(1, time.Minute)
+		assert.NotNil(t, exec.router)
+	})
+}
+
+func TestReplicaRouter_Reader(t *testing.T) {
+	t.Run("read from primary if there is no replica", func(t *testing.T) {
+		exec := createReplicaRouterExecutor(0, time.Minute)
+
+		assert.Equal(t, exec.primary, exec.router.Reader("key"))
+	})
+
+	t.Run("spread reads to replicas", func(t *testing.T) {
+		exec := createReplicaRouterExecutor(2, time.Minute)
+
+		first, second := exec.router.Reader("key"), exec.router.Reader("key")
+
+		assert.Contains(t, exec.replicas, first)
+		assert.Contains(t, exec.replicas, second)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("read what has just been written from primary", func(t *testing.T) {
+		exec := createReplicaRouterExecutor(1, time.Minute)
+
+		exec.router.Wrote("key")
+
+		assert.Equal(t, exec.primary, exec.router.Reader("key"))
+		assert.Equal(t, exec.primary, exec.router.Reader("other", "key"))
+		assert.Equal(t, exec.replicas[0], exec.router.Reader("other"))
+	})
+
+	t.Run("read from replica once the window passes", func(t *testing.T) {
+		exec := createReplicaRouterExecutor(1, time.Millisecond)
+
+		exec.router.Wrote("key")
+		time.Sleep(5 * time.Millisecond)
+
+		assert.Equal(t, exec.replicas[0], exec.router.Reader("key"))
+	})
+
+	t.Run("zero window never pins reads to primary", func(t *testing.T) {
+		exec := createReplicaRouterExecutor(1, 0)
+
+		exec.router.Wrote("key")
+
+		assert.Equal(t, exec.replicas[0], exec.router.Reader("key"))
+	})
+}
+
+func TestReplicaRouter_CheckHealth(t *testing.T) {
+	t.Run("skip unhealthy replica", func(t *testing.T) {
+		exec := createReplicaRouterExecutor(2, time.Minute)
+		exec.replicaMock[0].ExpectPing().WillReturnError(errors.New("connection refused"))
+		exec.replicaMock[1].ExpectPing()
+
+		exec.router.CheckHealth(context.Background())
+
+		assert.Equal(t, exec.replicas[1], exec.router.Reader("key"))
+		assert.Equal(t, exec.replicas[1], exec.router.Reader("key"))
+	})
+
+	t.Run("fail over to primary if no replica is healthy, then back to replica", func(t *testing.T) {
+		exec := createReplicaRouterExecutor(1, time.Minute)
+		exec.replicaMock[0].ExpectPing().WillReturnError(errors.New("connection refused"))
+
+		exec.router.CheckHealth(context.Background())
+		assert.Equal(t, exec.primary, exec.router.Reader("key"))
+
+		exec.replicaMock[0].ExpectPing()
+
+		exec.router.CheckHealth(context.Background())
+		assert.Equal(t, exec.replicas[0], exec.router.Reader("key"))
+	})
+}
+
+func TestReplicaRouter_Failed(t *testing.T) {
+	t.Run("skip failed replica until it answers the next ping", func(t *testing.T) {
+		exec := createReplicaRouterExecutor(1, time.Minute)
+
+		exec.router.Failed(exec.replicas[0])
+		assert.Equal(t, exec.primary, exec.router.Reader("key"))
+
+		exec.replicaMock[0].ExpectPing()
+
+		exec.router.CheckHealth(context.Background())
+		assert.Equal(t, exec.replicas[0], exec.router.Reader("key"))
+	})
+
+	t.Run("primary is never skipped", func(t *testing.T) {
+		exec := createReplicaRouterExecutor(1, time.Minute)
+
+		exec.router.Failed(exec.primary)
+
+		assert.Equal(t, exec.replicas[0], exec.router.Reader("key"))
+	})
+}
+
+func TestReplicaRouter_Close(t *testing.T) {
+	t.Run("close replicas but not primary", func(t *testing.T) {
+		exec := createReplicaRouterExecutor(2, time.Minute)
+		for _, mock := range exec.replicaMock {
+			mock.ExpectClose()
+		}
+
+		assert.Nil(t, exec.router.Close())
+		for _, mock := range exec.replicaMock {
+			assert.Nil(t, mock.ExpectationsWereMet())
+		}
+		assert.Nil(t, exec.primary.Ping())
+	})
+
+	t.Run("closing replica returns error", func(t *testing.T) {
+		exec := createReplicaRouterExecutor(2, time.Minute)
+		exec.replicaMock[0].ExpectClose().WillReturnError(errors.New("fail to close"))
+		exec.replicaMock[1].ExpectClose()
+
+		assert.NotNil(t, exec.router.Close())
+		assert.Nil(t, exec.replicaMock[1].ExpectationsWereMet())
+	})
+}
+
+func TestReplicaRouter_Watch(t *testing.T) {
+	t.Run("stop when context is done", func(t *testing.T) {
+		exec := createReplicaRouterExecutor(1, time.Minute)
+		exec.replicaMock[0].ExpectPing().WillReturnError(errors.New("connection refused"))
+		ctx, cancel := context.WithCancel(context.Background())
+
+		done := make(chan struct{})
+		go func() {
+			exec.router.Watch(ctx, time.Millisecond)
+			close(done)
+		}()
+
+		assert.Eventually(t, func() bool {
+			return exec.router.Reader("key") == exec.primary
+		}, time.Second, time.Millisecond)
+		cancel()
+		<-done
+	})
+}
+
+func TestReplicaRouter_MedicalRecord(t *testing.T) {
+	t.Run("find medical records from replica", func(t *testing.T) {
+		exec := createReplicaRouterExecutor(1, time.Minute)
+		sel := repository.NewMedicalRecordSelector(exec.primary, encryption.Plaintext{}, exec.router)
+
+		exec.replicaMock[0].ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE user_id = \$1 AND id < \$2 ORDER BY created_at DESC LIMIT \$3`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by"}))
+
+		_, err := sel.FindByUserID(context.Background(), 3, 100, 10)
+
+		assert.Nil(t, err)
+		assert.Nil(t, exec.replicaMock[0].ExpectationsWereMet())
+	})
+
+	t.Run("stale medical record read from replica is not encrypted again", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			log.Panicf("[TestReplicaRouter_MedicalRecord] error opening a stub database connection: %v\n", err)
+		}
+		exec := createReplicaRouterExecutor(1, time.Minute)
+		router := repository.NewReplicaRouter(db, exec.replicas, time.Minute)
+		sel := repository.NewMedicalRecordSelector(db, createEnvelope("v2"), router)
+
+		exec.replicaMock[0].ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, user_id FROM medical_records WHERE id = \$1 LIMIT 1`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by", "user_id"}).
+				AddRow(1, "Symptom", "Diagnosis", "Therapy", "Result", time.Now(), "", time.Now(), "", 3))
+		mock.ExpectExec(`UPDATE medical_records SET symptom = \$1`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		res, ferr := sel.FindByID(context.Background(), 1)
+
+		assert.Nil(t, ferr)
+		assert.Equal(t, "Symptom", res.Symptom)
+		assert.Nil(t, exec.replicaMock[0].ExpectationsWereMet())
+		assert.NotNil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("read which fails on replica runs again on primary", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			log.Panicf("[TestReplicaRouter_MedicalRecord] error opening a stub database connection: %v\n", err)
+		}
+		exec := createReplicaRouterExecutor(1, time.Minute)
+		router := repository.NewReplicaRouter(db, exec.replicas, time.Minute)
+		sel := repository.NewMedicalRecordSelector(db, encryption.Plaintext{}, router)
+
+		exec.replicaMock[0].ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, user_id FROM medical_records WHERE id = \$1 LIMIT 1`).
+			WillReturnError(errors.New("connection reset"))
+		mock.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, user_id FROM medical_records WHERE id = \$1 LIMIT 1`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by", "user_id"}).
+				AddRow(1, "Symptom", "Diagnosis", "Therapy", "Result", time.Now(), "", time.Now(), "", 3))
+		mock.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE user_id = \$1 AND id < \$2`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by"}))
+
+		res, ferr := sel.FindByID(context.Background(), 1)
+		assert.Nil(t, ferr)
+		assert.Equal(t, "Symptom", res.Symptom)
+
+		_, ferr = sel.FindByUserID(context.Background(), 3, 100, 10)
+		assert.Nil(t, ferr)
+		assert.Nil(t, exec.replicaMock[0].ExpectationsWereMet())
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("list which fails on replica runs again on primary", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			log.Panicf("[TestReplicaRouter_MedicalRecord] error opening a stub database connection: %v\n", err)
+		}
+		exec := createReplicaRouterExecutor(1, time.Minute)
+		router := repository.NewReplicaRouter(db, exec.replicas, time.Minute)
+		sel := repository.NewMedicalRecordSelector(db, encryption.Plaintext{}, router)
+
+		exec.replicaMock[0].ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE user_id = \$1 AND created_at >= \$2`).
+			WillReturnError(errors.New("connection reset"))
+		mock.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE user_id = \$1 AND created_at >= \$2`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by"}).
+				AddRow(1, "Symptom", "Diagnosis", "Therapy", "Result", time.Now(), "", time.Now(), ""))
+
+		res, ferr := sel.FindByUserIDWithinPeriod(context.Background(), 3, time.Now().Add(-time.Hour), time.Now(), 100, 10)
+
+		assert.Nil(t, ferr)
+		assert.Len(t, res, 1)
+		assert.Nil(t, exec.replicaMock[0].ExpectationsWereMet())
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("medical record which is not found on replica is not read again", func(t *testing.T) {
+		exec := createReplicaRouterExecutor(1, time.Minute)
+		sel := repository.NewMedicalRecordSelector(exec.primary, encryption.Plaintext{}, exec.router)
+
+		exec.replicaMock[0].ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, user_id FROM medical_records WHERE id = \$1 LIMIT 1`).
+			WillReturnError(sql.ErrNoRows)
+
+		_, ferr := sel.FindByID(context.Background(), 1)
+
+		assert.Equal(t, entity.ErrMedicalRecordNotFound, ferr)
+		assert.Equal(t, exec.replicas[0], exec.router.Reader("key"))
+	})
+
+	t.Run("find updated medical record and records of its owner from primary", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			log.Panicf("[TestReplicaRouter_MedicalRecord] error opening a stub database connection: %v\n", err)
+		}
+		exec := createReplicaRouterExecutor(1, time.Minute)
+		router := repository.NewReplicaRouter(db, exec.replicas, time.Minute)
+		up := repository.NewMedicalRecordUpdater(db, encryption.Plaintext{}, router)
+		sel := repository.NewMedicalRecordSelector(db, encryption.Plaintext{}, router)
+		record := &entity.MedicalRecord{User: &entity.User{Email: "dummy@dummy.com"}}
+
+		mock.ExpectExec(`UPDATE medical_records SET symptom = \$1, diagnosis = \$2, therapy = \$3, result = \$4, updated_at = \$5, updated_by = \$6 WHERE id = \$7`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by, user_id FROM medical_records WHERE id = \$1 LIMIT 1`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by", "user_id"}).
+				AddRow(1, "", "", "", "", time.Now(), "", time.Now(), "", 3))
+		mock.ExpectQuery(`SELECT id, symptom, diagnosis, therapy, result, created_at, created_by, updated_at, updated_by FROM medical_records WHERE user_id = \$1 AND id < \$2`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "symptom", "diagnosis", "therapy", "result", "created_at", "created_by", "updated_at", "updated_by"}))
+
+		assert.Nil(t, up.Update(context.Background(), 1, 3, record))
+		_, ferr := sel.FindByID(context.Background(), 1)
+		assert.Nil(t, ferr)
+		_, ferr = sel.FindByUserID(context.Background(), 3, 100, 10)
+		assert.Nil(t, ferr)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func createReplicaRouterExecutor(replicas int, window time.Duration) *ReplicaRouterExecutor {
+	primary, _, err := sqlmock.New()
+	if err != nil {
+		log.Panicf("[createReplicaRouterExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	exec := &ReplicaRouterExecutor{primary: primary}
+	for i := 0; i < replicas; i++ {
+		db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		if err != nil {
+			log.Panicf("[createReplicaRouterExecutor] error opening a stub database connection: %v\n", err)
+		}
+		exec.replicas = append(exec.replicas, db)
+		exec.replicaMock = append(exec.replicaMock, mock)
+	}
+	exec.router = repository.NewReplicaRouter(primary, exec.replicas, window)
+	return exec
+}
@@ -0,0 +1,32 @@
+//go:build sqlite
+// +build sqlite
+
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/mattn/go-sqlite3"
+)
+
+// isSQLite tells whether db is a SQLite database.
+func isSQLite(db *sql.DB) bool {
+	_, ok := db.Driver().(*sqlite3.SQLiteDriver)
+	return ok
+}
+
+// sqliteErrorKind maps error returned by SQLite onto entity.Error.
+// It returns false if err doesn't come from SQLite.
+func sqliteErrorKind(err error) (*entity.Error, bool) {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return nil, false
+	}
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		return entity.ErrAlreadyExists, true
+	}
+	return entity.ErrInternalServer, true
+}
@@ -0,0 +1,20 @@
+//go:build !sqlite
+// +build !sqlite
+
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// isSQLite always returns false since SQLite is not built in.
+func isSQLite(db *sql.DB) bool {
+	return false
+}
+
+// sqliteErrorKind always returns false since SQLite is not built in.
+func sqliteErrorKind(err error) (*entity.Error, bool) {
+	return nil, false
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// StorageDeleter connects the database with the storage keys whose content is still to be deleted.
+// The keys are queued by the repository which deletes the metadata, in the same transaction,
+// so no key is lost if deleting the content fails afterwards.
+type StorageDeleter struct {
+	db *sql.DB
+}
+
+// NewStorageDeleter creates an instance of StorageDeleter.
+func NewStorageDeleter(db *sql.DB) *StorageDeleter {
+	return &StorageDeleter{db: db}
+}
+
+// FindPending finds at most limit storage keys whose content is still to be deleted, the oldest first.
+func (sd *StorageDeleter) FindPending(ctx context.Context, limit uint) ([]string, *entity.Error) {
+	query := "SELECT storage_key FROM storage_deletions ORDER BY created_at, storage_key LIMIT $1"
+	rows, err := querierFromContext(ctx, sd.db).QueryContext(ctx, query, limit)
+	if err != nil {
+		return []string{}, databaseError(err, "[StorageDeleter-FindPending] exec select query: ")
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return []string{}, databaseError(err, "[StorageDeleter-FindPending] scan rows: ")
+		}
+		keys = append(keys, key)
+	}
+	if rows.Err() != nil {
+		return []string{}, databaseError(rows.Err(), "[StorageDeleter-FindPending] iterate rows: ")
+	}
+	return keys, nil
+}
+
+// Done removes the storage key from the queue once its content has been deleted.
+func (sd *StorageDeleter) Done(ctx context.Context, key string) *entity.Error {
+	query := "DELETE FROM storage_deletions WHERE storage_key = $1"
+	if _, err := querierFromContext(ctx, sd.db).ExecContext(ctx, query, key); err != nil {
+		return databaseError(err, "[StorageDeleter-Done] exec delete query: ")
+	}
+	return nil
+}
+
+// queueStorageDeletions queues the storage keys whose metadata is deleted by the caller.
+// It must run in the same transaction as the deletion.
+func queueStorageDeletions(ctx context.Context, db *sql.DB, keys []string, caller string) *entity.Error {
+	query := "INSERT INTO storage_deletions (storage_key, created_at) VALUES ($1, $2) ON CONFLICT (storage_key) DO NOTHING"
+	now := time.Now().UTC()
+	for _, key := range keys {
+		if _, err := querierFromContext(ctx, db).ExecContext(ctx, query, key, now); err != nil {
+			return databaseError(err, caller+" exec insert storage deletion query: ")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	insertStorageDeletionSQL  = `INSERT INTO storage_deletions \(storage_key, created_at\) VALUES \(\$1, \$2\) ON CONFLICT \(storage_key\) DO NOTHING`
+	selectStorageDeletionsSQL = `SELECT storage_key FROM storage_deletions ORDER BY created_at, storage_key LIMIT \$1`
+	deleteStorageDeletionSQL  = `DELETE FROM storage_deletions WHERE storage_key = \$1`
+)
+
+type StorageDeleterExecutor struct {
+	repo *repository.StorageDeleter
+	sql  sqlmock.Sqlmock
+}
+
+func TestNewStorageDeleter(t *testing.T) {
+	t.Run("successfully create an instance of StorageDeleter", func(t *testing.T) {
+		exec := createStorageDeleterExecutor()
+		assert.NotNil(t, exec.repo)
+	})
+}
+
+func TestStorageDeleter_FindPending(t *testing.T) {
+	t.Run("select query returns error", func(t *testing.T) {
+		exec := createStorageDeleterExecutor()
+
+		exec.sql.ExpectQuery(selectStorageDeletionsSQL).WillReturnError(errors.New("fail to select from database"))
+		keys, err := exec.repo.FindPending(context.Background(), 10)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Empty(t, keys)
+	})
+
+	t.Run("scan rows returns error", func(t *testing.T) {
+		exec := createStorageDeleterExecutor()
+
+		exec.sql.ExpectQuery(selectStorageDeletionsSQL).WillReturnRows(sqlmock.NewRows([]string{"storage_key"}).AddRow(nil))
+		keys, err := exec.repo.FindPending(context.Background(), 10)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Empty(t, keys)
+	})
+
+	t.Run("successfully find pending storage keys", func(t *testing.T) {
+		exec := createStorageDeleterExecutor()
+
+		exec.sql.ExpectQuery(selectStorageDeletionsSQL).
+			WithArgs(uint(10)).
+			WillReturnRows(sqlmock.NewRows([]string{"storage_key"}).AddRow("key-1").AddRow("key-2"))
+		keys, err := exec.repo.FindPending(context.Background(), 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"key-1", "key-2"}, keys)
+	})
+}
+
+func TestStorageDeleter_Done(t *testing.T) {
+	t.Run("delete query returns error", func(t *testing.T) {
+		exec := createStorageDeleterExecutor()
+
+		exec.sql.ExpectExec(deleteStorageDeletionSQL).WillReturnError(errors.New("fail to delete from database"))
+		err := exec.repo.Done(context.Background(), "key-1")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("successfully remove storage key from the queue", func(t *testing.T) {
+		exec := createStorageDeleterExecutor()
+
+		exec.sql.ExpectExec(deleteStorageDeletionSQL).WithArgs("key-1").WillReturnResult(sqlmock.NewResult(0, 1))
+		err := exec.repo.Done(context.Background(), "key-1")
+
+		assert.Nil(t, err)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+}
+
+func createStorageDeleterExecutor() *StorageDeleterExecutor {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Panicf("[createStorageDeleterExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	repo := repository.NewStorageDeleter(db)
+	return &StorageDeleterExecutor{
+		repo: repo,
+		sql:  mock,
+	}
+}
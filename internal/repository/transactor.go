@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/jackc/pgconn"
+)
+
+const (
+	// pgSerializationFailure and pgDeadlockDetected are SQLSTATE of errors
+	// which are gone if the transaction is run again.
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+
+	retryBaseDelay = 20 * time.Millisecond
+)
+
+// skipLockedClause returns the clause which locks the rows of the table alias selected by a query
+// and skips the rows which are locked by another transaction.
+// SQLite has no row lock, and its database is only written by one connection at a time, so the clause is empty for it.
+func skipLockedClause(db *sql.DB, alias string) string {
+	if isSQLite(db) {
+		return ""
+	}
+	return " FOR UPDATE OF " + alias + " SKIP LOCKED"
+}
+
+// txKey is the key of the transaction carried in context.
+type txKey struct{}
+
+// querier is implemented by both *sql.DB and transaction,
+// so the repositories can run the same query whether or not they are inside a unit of work.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// querierFromContext returns the transaction carried in context or the database if there is none.
+func querierFromContext(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*transaction); ok {
+		return tx
+	}
+	return db
+}
+
+// transaction wraps *sql.Tx and remembers whether any of its queries failed to be serialized.
+// The repositories only return *entity.Error which doesn't keep the driver error,
+// so the failure is observed here instead.
+type transaction struct {
+	*sql.Tx
+	retryable bool
+}
+
+// ExecContext executes a query inside the transaction.
+func (t *transaction) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	res, err := t.Tx.ExecContext(ctx, query, args...)
+	t.observe(err)
+	return res, err
+}
+
+// QueryContext executes a query that returns rows inside the transaction.
+func (t *transaction) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := t.Tx.QueryContext(ctx, query, args...)
+	t.observe(err)
+	return rows, err
+}
+
+// QueryRowContext executes a query that returns at most one row inside the transaction.
+func (t *transaction) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	row := t.Tx.QueryRowContext(ctx, query, args...)
+	t.observe(row.Err())
+	return row
+}
+
+func (t *transaction) observe(err error) {
+	if isRetryable(err) {
+		t.retryable = true
+	}
+}
+
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
+	}
+	return false
+}
+
+// Transactor runs a unit of work in a serializable transaction.
+// Repositories called using the context given to the unit of work transparently use the transaction.
+type Transactor struct {
+	db         *sql.DB
+	maxRetries int
+}
+
+// NewTransactor creates an instance of Transactor.
+// The unit of work is run again at most maxRetries times if it fails due to serialization failure or deadlock.
+func NewTransactor(db *sql.DB, maxRetries int) *Transactor {
+	return &Transactor{
+		db:         db,
+		maxRetries: maxRetries,
+	}
+}
+
+// WithinTransaction runs fn in a transaction.
+// The transaction is committed if fn returns nil, otherwise it is rolled back.
+// If the context already carries a transaction, fn joins it.
+// Since fn may be run more than once, it must not have side effects outside the database.
+func (t *Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) *entity.Error) *entity.Error {
+	if _, ok := ctx.Value(txKey{}).(*transaction); ok {
+		return fn(ctx)
+	}
+
+	for attempt := 0; ; attempt++ {
+		tx, err := t.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+		if err != nil {
+			return databaseError(err, "[Transactor-WithinTransaction] begin transaction: ")
+		}
+
+		trx := &transaction{Tx: tx}
+		ferr := fn(context.WithValue(ctx, txKey{}, trx))
+		if ferr == nil {
+			cerr := tx.Commit()
+			if cerr == nil {
+				return nil
+			}
+			trx.observe(cerr)
+			ferr = databaseError(cerr, "[Transactor-WithinTransaction] commit transaction: ")
+		} else {
+			tx.Rollback()
+		}
+
+		if !trx.retryable || attempt >= t.maxRetries {
+			return ferr
+		}
+		if err := sleep(ctx, retryDelay(attempt)); err != nil {
+			return ferr
+		}
+	}
+}
+
+// retryDelay grows linearly with a random jitter,
+// so the conflicting transactions don't collide again.
+func retryDelay(attempt int) time.Duration {
+	base := retryBaseDelay * time.Duration(attempt+1)
+	return base + time.Duration(rand.Int63n(int64(base)))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
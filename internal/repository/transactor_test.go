@@ -0,0 +1,126 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/encryption"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+type TransactorExecutor struct {
+	transactor *repository.Transactor
+	updater    *repository.MedicalRecordUpdater
+	sql        sqlmock.Sqlmock
+}
+
+func TestNewTransactor(t *testing.T) {
+	t.Run("successfully create an instance of Transactor", func(t *testing.T) {
+		exec := createTransactorExecutor()
+		assert.NotNil(t, exec.transactor)
+	})
+}
+
+func TestTransactor_WithinTransaction(t *testing.T) {
+	t.Run("transaction can't begin", func(t *testing.T) {
+		exec := createTransactorExecutor()
+		exec.sql.ExpectBegin().WillReturnError(errors.New("connection refused"))
+
+		err := exec.transactor.WithinTransaction(context.Background(), func(ctx context.Context) *entity.Error {
+			return nil
+		})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("unit of work fails and is rolled back", func(t *testing.T) {
+		exec := createTransactorExecutor()
+		exec.sql.ExpectBegin()
+		exec.sql.ExpectExec(`UPDATE medical_records SET`).WillReturnResult(sqlmock.NewResult(0, 0))
+		exec.sql.ExpectRollback()
+
+		err := exec.transactor.WithinTransaction(context.Background(), func(ctx context.Context) *entity.Error {
+			return exec.updater.Update(ctx, 1, 3, createValidMedicalRecord())
+		})
+
+		assert.Equal(t, entity.ErrMedicalRecordNotFound, err)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+
+	t.Run("serialization failure is retried until it succeeds", func(t *testing.T) {
+		exec := createTransactorExecutor()
+		exec.sql.ExpectBegin()
+		exec.sql.ExpectExec(`UPDATE medical_records SET`).WillReturnError(&pgconn.PgError{Code: "40001"})
+		exec.sql.ExpectRollback()
+		exec.sql.ExpectBegin()
+		exec.sql.ExpectExec(`UPDATE medical_records SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectCommit().WillReturnError(&pgconn.PgError{Code: "40P01"})
+		exec.sql.ExpectBegin()
+		exec.sql.ExpectExec(`UPDATE medical_records SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectCommit()
+
+		attempts := 0
+		err := exec.transactor.WithinTransaction(context.Background(), func(ctx context.Context) *entity.Error {
+			attempts++
+			return exec.updater.Update(ctx, 1, 3, createValidMedicalRecord())
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, attempts)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+
+	t.Run("serialization failure is not retried more than max retries", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		transactor := repository.NewTransactor(db, 0)
+		updater := repository.NewMedicalRecordUpdater(db, encryption.Plaintext{}, nil)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE medical_records SET`).WillReturnError(&pgconn.PgError{Code: "40001"})
+		mock.ExpectRollback()
+
+		err := transactor.WithinTransaction(context.Background(), func(ctx context.Context) *entity.Error {
+			return updater.Update(ctx, 1, 3, createValidMedicalRecord())
+		})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrConcurrentUpdate.Code, err.Code)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nested unit of work joins the outer transaction", func(t *testing.T) {
+		exec := createTransactorExecutor()
+		exec.sql.ExpectBegin()
+		exec.sql.ExpectExec(`UPDATE medical_records SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectCommit()
+
+		err := exec.transactor.WithinTransaction(context.Background(), func(ctx context.Context) *entity.Error {
+			return exec.transactor.WithinTransaction(ctx, func(ctx context.Context) *entity.Error {
+				return exec.updater.Update(ctx, 1, 3, createValidMedicalRecord())
+			})
+		})
+
+		assert.Nil(t, err)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+}
+
+func createTransactorExecutor() *TransactorExecutor {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Panicf("[createTransactorExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	return &TransactorExecutor{
+		transactor: repository.NewTransactor(db, 3),
+		updater:    repository.NewMedicalRecordUpdater(db, encryption.Plaintext{}, nil),
+		sql:        mock,
+	}
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// replaceActorQueries replace the email in the audit actor fields of the data written by the user.
+var replaceActorQueries = []string{
+	"UPDATE medical_records SET created_by = $1 WHERE created_by = $2",
+	"UPDATE medical_records SET updated_by = $1 WHERE updated_by = $2",
+	"UPDATE attachments SET created_by = $1 WHERE created_by = $2",
+	"UPDATE attachments SET updated_by = $1 WHERE updated_by = $2",
+}
+
+// anonymizeUserQueries delete the data which may identify the user.
+var anonymizeUserQueries = []string{
+	"DELETE FROM user_profiles WHERE user_id = $1",
+	"DELETE FROM user_identities WHERE user_id = $1",
+	"DELETE FROM email_changes WHERE user_id = $1",
+}
+
+// UserAnonymizer connects the database with user entity
+// and only responsible for replacing the identity of a user.
+// The statements should be run in a single transaction, see Transactor.
+type UserAnonymizer struct {
+	db *sql.DB
+}
+
+// NewUserAnonymizer creates an instance of UserAnonymizer.
+func NewUserAnonymizer(db *sql.DB) *UserAnonymizer {
+	return &UserAnonymizer{db: db}
+}
+
+// Anonymize replaces the name, email, and Google ID of the user who has the id with the alias
+// and deletes the profile, the identities, and the email change request, since they may identify the user as well.
+// The medical records are kept under the user, while the audit actor fields which refer to the email are replaced.
+// It returns entity.ErrUserNotFound if the user doesn't exist.
+func (ua *UserAnonymizer) Anonymize(ctx context.Context, userID uint64, email, alias string) *entity.Error {
+	q := querierFromContext(ctx, ua.db)
+
+	for _, query := range anonymizeUserQueries {
+		if _, err := q.ExecContext(ctx, query, userID); err != nil {
+			return databaseError(err, "[UserAnonymizer-Anonymize] exec delete query: ")
+		}
+	}
+
+	query := "UPDATE users SET name = '', email = $1, google_id = $1, created_by = $1, updated_by = $1, updated_at = $2 WHERE id = $3"
+	res, err := q.ExecContext(ctx, query, alias, time.Now().UTC(), userID)
+	if err != nil {
+		return databaseError(err, "[UserAnonymizer-Anonymize] exec update user query: ")
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return databaseError(err, "[UserAnonymizer-Anonymize] get affected rows: ")
+	}
+	if affected == 0 {
+		return entity.ErrUserNotFound
+	}
+
+	for _, query := range replaceActorQueries {
+		if _, err := q.ExecContext(ctx, query, alias, email); err != nil {
+			return databaseError(err, "[UserAnonymizer-Anonymize] exec update actor query: ")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type UserAnonymizerExecutor struct {
+	repo *repository.UserAnonymizer
+	sql  sqlmock.Sqlmock
+}
+
+func TestNewUserAnonymizer(t *testing.T) {
+	t.Run("successfully create an instance of UserAnonymizer", func(t *testing.T) {
+		exec := createUserAnonymizerExecutor()
+		assert.NotNil(t, exec.repo)
+	})
+}
+
+func TestUserAnonymizer_Anonymize(t *testing.T) {
+	const updateUser = `UPDATE users SET name = '', email = \$1, google_id = \$1, created_by = \$1, updated_by = \$1, updated_at = \$2 WHERE id = \$3`
+	deletes := []string{
+		`DELETE FROM user_profiles WHERE user_id = \$1`,
+		`DELETE FROM user_identities WHERE user_id = \$1`,
+		`DELETE FROM email_changes WHERE user_id = \$1`,
+	}
+	expectDeletes := func(mock sqlmock.Sqlmock) {
+		for _, query := range deletes {
+			mock.ExpectExec(query).WithArgs(uint64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+	}
+
+	t.Run("delete query returns error", func(t *testing.T) {
+		exec := createUserAnonymizerExecutor()
+
+		exec.sql.ExpectExec(deletes[0]).WillReturnResult(sqlmock.NewResult(0, 0))
+		exec.sql.ExpectExec(deletes[1]).WillReturnError(errors.New("fail to delete from database"))
+		err := exec.repo.Anonymize(context.Background(), 1, "a@orvosi.com", "anonymized-1@invalid")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("update user query returns error", func(t *testing.T) {
+		exec := createUserAnonymizerExecutor()
+
+		expectDeletes(exec.sql)
+		exec.sql.ExpectExec(updateUser).WillReturnError(errors.New("fail to update database"))
+		err := exec.repo.Anonymize(context.Background(), 1, "a@orvosi.com", "anonymized-1@invalid")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("user is not found", func(t *testing.T) {
+		exec := createUserAnonymizerExecutor()
+
+		expectDeletes(exec.sql)
+		exec.sql.ExpectExec(updateUser).WillReturnResult(sqlmock.NewResult(0, 0))
+		err := exec.repo.Anonymize(context.Background(), 1, "a@orvosi.com", "anonymized-1@invalid")
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+	})
+
+	t.Run("update actor query returns error", func(t *testing.T) {
+		exec := createUserAnonymizerExecutor()
+
+		expectDeletes(exec.sql)
+		exec.sql.ExpectExec(updateUser).WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectExec(`UPDATE medical_records SET created_by = \$1 WHERE created_by = \$2`).
+			WillReturnError(errors.New("fail to update database"))
+		err := exec.repo.Anonymize(context.Background(), 1, "a@orvosi.com", "anonymized-1@invalid")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("successfully anonymize user", func(t *testing.T) {
+		exec := createUserAnonymizerExecutor()
+
+		expectDeletes(exec.sql)
+		exec.sql.ExpectExec(updateUser).
+			WithArgs("anonymized-1@invalid", sqlmock.AnyArg(), uint64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		for _, query := range []string{
+			`UPDATE medical_records SET created_by = \$1 WHERE created_by = \$2`,
+			`UPDATE medical_records SET updated_by = \$1 WHERE updated_by = \$2`,
+			`UPDATE attachments SET created_by = \$1 WHERE created_by = \$2`,
+			`UPDATE attachments SET updated_by = \$1 WHERE updated_by = \$2`,
+		} {
+			exec.sql.ExpectExec(query).
+				WithArgs("anonymized-1@invalid", "a@orvosi.com").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		err := exec.repo.Anonymize(context.Background(), 1, "a@orvosi.com", "anonymized-1@invalid")
+
+		assert.Nil(t, err)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+}
+
+func createUserAnonymizerExecutor() *UserAnonymizerExecutor {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Panicf("[createUserAnonymizerExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	repo := repository.NewUserAnonymizer(db)
+	return &UserAnonymizerExecutor{
+		repo: repo,
+		sql:  mock,
+	}
+}
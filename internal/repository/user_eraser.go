@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// eraseUserQueries delete the data owned by the user, from the dependents to the user itself.
+var eraseUserQueries = []string{
+	"DELETE FROM attachments WHERE medical_record_id IN (SELECT id FROM medical_records WHERE user_id = $1)",
+	"DELETE FROM medical_records WHERE user_id = $1",
+	"DELETE FROM erasures WHERE user_id = $1",
+	"DELETE FROM email_changes WHERE user_id = $1",
+	"DELETE FROM user_identities WHERE user_id = $1",
+	"DELETE FROM user_profiles WHERE user_id = $1",
+	"DELETE FROM users WHERE id = $1",
+}
+
+// UserEraser connects the database with erasure entity
+// and only responsible for scheduling and running the erasure of users.
+// The statements of Claim and Erase should be run in a single transaction, see Transactor.
+type UserEraser struct {
+	db *sql.DB
+}
+
+// NewUserEraser creates an instance of UserEraser.
+func NewUserEraser(db *sql.DB) *UserEraser {
+	return &UserEraser{db: db}
+}
+
+// Schedule inserts the erasure of the user who has the id unless it exists,
+// then returns the erasure which is kept.
+// It returns entity.ErrUserNotFound if the user doesn't exist.
+func (ue *UserEraser) Schedule(ctx context.Context, userID uint64, requestedAt, eraseAfter time.Time) (*entity.Erasure, *entity.Error) {
+	query := "INSERT INTO erasures (user_id, requested_at, erase_after) SELECT id, $1, $2 FROM users WHERE id = $3 ON CONFLICT (user_id) DO NOTHING"
+	if _, err := querierFromContext(ctx, ue.db).ExecContext(ctx, query, requestedAt.UTC(), eraseAfter.UTC(), userID); err != nil {
+		return nil, databaseError(err, "[UserEraser-Schedule] exec insert query: ")
+	}
+
+	erasure, err := ue.FindByUserID(ctx, userID)
+	if err == entity.ErrErasureNotScheduled {
+		return nil, entity.ErrUserNotFound
+	}
+	return erasure, err
+}
+
+// FindByUserID finds the scheduled erasure of the user who has the id.
+// It returns entity.ErrErasureNotScheduled if there is none.
+func (ue *UserEraser) FindByUserID(ctx context.Context, userID uint64) (*entity.Erasure, *entity.Error) {
+	query := "SELECT e.user_id, u.email, e.requested_at, e.erase_after FROM erasures e JOIN users u ON u.id = e.user_id WHERE e.user_id = $1"
+	row := querierFromContext(ctx, ue.db).QueryRowContext(ctx, query, userID)
+
+	var erasure entity.Erasure
+	err := row.Scan(&erasure.UserID, &erasure.Email, &erasure.RequestedAt, &erasure.EraseAfter)
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrErasureNotScheduled
+	}
+	if err != nil {
+		return nil, databaseError(err, "[UserEraser-FindByUserID] exec select query: ")
+	}
+	return &erasure, nil
+}
+
+// Cancel deletes the erasure of the user who has the id.
+// It returns entity.ErrErasureNotScheduled if there is none.
+func (ue *UserEraser) Cancel(ctx context.Context, userID uint64) *entity.Error {
+	query := "DELETE FROM erasures WHERE user_id = $1"
+	res, err := querierFromContext(ctx, ue.db).ExecContext(ctx, query, userID)
+	if err != nil {
+		return databaseError(err, "[UserEraser-Cancel] exec delete query: ")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return databaseError(err, "[UserEraser-Cancel] get affected rows: ")
+	}
+	if affected == 0 {
+		return entity.ErrErasureNotScheduled
+	}
+	return nil
+}
+
+// FindDue finds at most limit erasures whose EraseAfter is not after now, ordered by EraseAfter.
+func (ue *UserEraser) FindDue(ctx context.Context, now time.Time, limit uint) ([]*entity.Erasure, *entity.Error) {
+	query := "SELECT e.user_id, u.email, e.requested_at, e.erase_after FROM erasures e JOIN users u ON u.id = e.user_id WHERE e.erase_after <= $1 ORDER BY e.erase_after LIMIT $2" + skipLockedClause(ue.db, "e")
+	rows, err := querierFromContext(ctx, ue.db).QueryContext(ctx, query, now.UTC(), limit)
+	if err != nil {
+		return []*entity.Erasure{}, databaseError(err, "[UserEraser-FindDue] exec select query: ")
+	}
+	defer rows.Close()
+
+	result := []*entity.Erasure{}
+	for rows.Next() {
+		var tmp entity.Erasure
+		if err := rows.Scan(&tmp.UserID, &tmp.Email, &tmp.RequestedAt, &tmp.EraseAfter); err != nil {
+			return []*entity.Erasure{}, databaseError(err, "[UserEraser-FindDue] scan rows: ")
+		}
+		result = append(result, &tmp)
+	}
+	if rows.Err() != nil {
+		return []*entity.Erasure{}, databaseError(rows.Err(), "[UserEraser-FindDue] iterate rows: ")
+	}
+	return result, nil
+}
+
+// Claim locks the erasure of the user until the end of the transaction if it is still due at now,
+// and returns it along with the current email of the user.
+// The erasure which is locked by another transaction is skipped instead of waited for.
+// It returns entity.ErrErasureNotScheduled if the erasure has been cancelled, isn't due, or is locked.
+func (ue *UserEraser) Claim(ctx context.Context, userID uint64, now time.Time) (*entity.Erasure, *entity.Error) {
+	query := "SELECT e.user_id, u.email, e.requested_at, e.erase_after FROM erasures e JOIN users u ON u.id = e.user_id WHERE e.user_id = $1 AND e.erase_after <= $2" + skipLockedClause(ue.db, "e")
+	row := querierFromContext(ctx, ue.db).QueryRowContext(ctx, query, userID, now.UTC())
+
+	var erasure entity.Erasure
+	err := row.Scan(&erasure.UserID, &erasure.Email, &erasure.RequestedAt, &erasure.EraseAfter)
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrErasureNotScheduled
+	}
+	if err != nil {
+		return nil, databaseError(err, "[UserEraser-Claim] exec select query: ")
+	}
+	return &erasure, nil
+}
+
+// Erase deletes the user who has the id, their erasure, identities, email change request, medical records, and the attachments of those records,
+// then replaces the email in the audit actor fields of the remaining data with the alias.
+// The storage keys of the deleted attachments are queued for deletion, see StorageDeleter, and returned.
+func (ue *UserEraser) Erase(ctx context.Context, userID uint64, email, alias string) ([]string, *entity.Error) {
+	q := querierFromContext(ctx, ue.db)
+
+	keys, err := ue.findStorageKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := queueStorageDeletions(ctx, ue.db, keys, "[UserEraser-Erase]"); err != nil {
+		return nil, err
+	}
+	for _, query := range eraseUserQueries {
+		if _, err := q.ExecContext(ctx, query, userID); err != nil {
+			return nil, databaseError(err, "[UserEraser-Erase] exec delete query: ")
+		}
+	}
+	for _, query := range replaceActorQueries {
+		if _, err := q.ExecContext(ctx, query, alias, email); err != nil {
+			return nil, databaseError(err, "[UserEraser-Erase] exec update actor query: ")
+		}
+	}
+	return keys, nil
+}
+
+func (ue *UserEraser) findStorageKeys(ctx context.Context, userID uint64) ([]string, *entity.Error) {
+	query := "SELECT a.storage_key FROM attachments a JOIN medical_records m ON m.id = a.medical_record_id WHERE m.user_id = $1"
+	rows, err := querierFromContext(ctx, ue.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, databaseError(err, "[UserEraser-Erase] exec select query: ")
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, databaseError(err, "[UserEraser-Erase] scan rows: ")
+		}
+		keys = append(keys, key)
+	}
+	if rows.Err() != nil {
+		return nil, databaseError(rows.Err(), "[UserEraser-Erase] iterate rows: ")
+	}
+	return keys, nil
+}
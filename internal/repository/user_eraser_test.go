@@ -0,0 +1,280 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	insertErasureQuery   = `INSERT INTO erasures \(user_id, requested_at, erase_after\) SELECT id, \$1, \$2 FROM users WHERE id = \$3 ON CONFLICT \(user_id\) DO NOTHING`
+	selectErasureQuery   = `SELECT e.user_id, u.email, e.requested_at, e.erase_after FROM erasures e JOIN users u ON u.id = e.user_id WHERE e.user_id = \$1`
+	selectDueQuery       = `SELECT e.user_id, u.email, e.requested_at, e.erase_after FROM erasures e JOIN users u ON u.id = e.user_id WHERE e.erase_after <= \$1 ORDER BY e.erase_after LIMIT \$2 FOR UPDATE OF e SKIP LOCKED`
+	claimErasureQuery    = `SELECT e.user_id, u.email, e.requested_at, e.erase_after FROM erasures e JOIN users u ON u.id = e.user_id WHERE e.user_id = \$1 AND e.erase_after <= \$2 FOR UPDATE OF e SKIP LOCKED`
+	deleteErasureQuery   = `DELETE FROM erasures WHERE user_id = \$1`
+	selectStorageKeysSQL = `SELECT a.storage_key FROM attachments a JOIN medical_records m ON m.id = a.medical_record_id WHERE m.user_id = \$1`
+)
+
+var erasureColumns = []string{"user_id", "email", "requested_at", "erase_after"}
+
+type UserEraserExecutor struct {
+	repo *repository.UserEraser
+	sql  sqlmock.Sqlmock
+}
+
+func TestNewUserEraser(t *testing.T) {
+	t.Run("successfully create an instance of UserEraser", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+		assert.NotNil(t, exec.repo)
+	})
+}
+
+func TestUserEraser_Schedule(t *testing.T) {
+	now := time.Now().UTC()
+
+	t.Run("insert query returns error", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectExec(insertErasureQuery).WillReturnError(errors.New("fail to insert into database"))
+		res, err := exec.repo.Schedule(context.Background(), 1, now, now.Add(time.Hour))
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, res)
+	})
+
+	t.Run("user is not found", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectExec(insertErasureQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+		exec.sql.ExpectQuery(selectErasureQuery).WillReturnError(sql.ErrNoRows)
+		res, err := exec.repo.Schedule(context.Background(), 1, now, now.Add(time.Hour))
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("successfully schedule erasure", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectExec(insertErasureQuery).
+			WithArgs(now, now.Add(time.Hour), uint64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectQuery(selectErasureQuery).
+			WillReturnRows(sqlmock.NewRows(erasureColumns).AddRow(1, "a@orvosi.com", now, now.Add(time.Hour)))
+		res, err := exec.repo.Schedule(context.Background(), 1, now, now.Add(time.Hour))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "a@orvosi.com", res.Email)
+		assert.Equal(t, now.Add(time.Hour), res.EraseAfter)
+	})
+}
+
+func TestUserEraser_FindByUserID(t *testing.T) {
+	t.Run("select query returns error", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectQuery(selectErasureQuery).WillReturnError(errors.New("fail to select from database"))
+		res, err := exec.repo.FindByUserID(context.Background(), 1)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, res)
+	})
+
+	t.Run("erasure is not scheduled", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectQuery(selectErasureQuery).WillReturnError(sql.ErrNoRows)
+		res, err := exec.repo.FindByUserID(context.Background(), 1)
+
+		assert.Equal(t, entity.ErrErasureNotScheduled, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestUserEraser_Cancel(t *testing.T) {
+	t.Run("queueing storage deletion returns error", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectQuery(selectStorageKeysSQL).WillReturnRows(sqlmock.NewRows([]string{"storage_key"}).AddRow("key-1"))
+		exec.sql.ExpectExec(insertStorageDeletionSQL).WillReturnError(errors.New("fail to insert to database"))
+		keys, err := exec.repo.Erase(context.Background(), uint64(1), "a@orvosi.com", "erased-1@invalid")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, keys)
+	})
+
+	t.Run("delete query returns error", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectExec(deleteErasureQuery).WillReturnError(errors.New("fail to delete from database"))
+		err := exec.repo.Cancel(context.Background(), 1)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("erasure is not scheduled", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectExec(deleteErasureQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+		err := exec.repo.Cancel(context.Background(), 1)
+
+		assert.Equal(t, entity.ErrErasureNotScheduled, err)
+	})
+
+	t.Run("successfully cancel erasure", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectExec(deleteErasureQuery).WithArgs(uint64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+		err := exec.repo.Cancel(context.Background(), 1)
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestUserEraser_FindDue(t *testing.T) {
+	now := time.Now().UTC()
+
+	t.Run("select query returns error", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectQuery(selectDueQuery).WillReturnError(errors.New("fail to select from database"))
+		res, err := exec.repo.FindDue(context.Background(), now, 10)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Empty(t, res)
+	})
+
+	t.Run("successfully find due erasures", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectQuery(selectDueQuery).
+			WithArgs(now, uint(10)).
+			WillReturnRows(sqlmock.NewRows(erasureColumns).
+				AddRow(1, "a@orvosi.com", now, now).
+				AddRow(2, "b@orvosi.com", now, now))
+		res, err := exec.repo.FindDue(context.Background(), now, 10)
+
+		assert.Nil(t, err)
+		assert.Len(t, res, 2)
+	})
+}
+
+func TestUserEraser_Claim(t *testing.T) {
+	now := time.Now().UTC()
+
+	t.Run("select query returns error", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectQuery(claimErasureQuery).WillReturnError(errors.New("fail to select from database"))
+		res, err := exec.repo.Claim(context.Background(), 1, now)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, res)
+	})
+
+	t.Run("erasure is cancelled, not due, or locked", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectQuery(claimErasureQuery).WillReturnError(sql.ErrNoRows)
+		res, err := exec.repo.Claim(context.Background(), 1, now)
+
+		assert.Equal(t, entity.ErrErasureNotScheduled, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("successfully claim erasure", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectQuery(claimErasureQuery).
+			WithArgs(uint64(1), now).
+			WillReturnRows(sqlmock.NewRows(erasureColumns).AddRow(1, "a@orvosi.com", now, now))
+		res, err := exec.repo.Claim(context.Background(), 1, now)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "a@orvosi.com", res.Email)
+	})
+}
+
+func TestUserEraser_Erase(t *testing.T) {
+	t.Run("select storage keys returns error", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectQuery(selectStorageKeysSQL).WillReturnError(errors.New("fail to select from database"))
+		keys, err := exec.repo.Erase(context.Background(), uint64(1), "a@orvosi.com", "erased-1@invalid")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, keys)
+	})
+
+	t.Run("queueing storage deletion returns error", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectQuery(selectStorageKeysSQL).WillReturnRows(sqlmock.NewRows([]string{"storage_key"}).AddRow("key-1"))
+		exec.sql.ExpectExec(insertStorageDeletionSQL).WillReturnError(errors.New("fail to insert to database"))
+		keys, err := exec.repo.Erase(context.Background(), uint64(1), "a@orvosi.com", "erased-1@invalid")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, keys)
+	})
+
+	t.Run("delete query returns error", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectQuery(selectStorageKeysSQL).WillReturnRows(sqlmock.NewRows([]string{"storage_key"}))
+		exec.sql.ExpectExec(`DELETE FROM attachments`).WillReturnError(errors.New("fail to delete from database"))
+		keys, err := exec.repo.Erase(context.Background(), uint64(1), "a@orvosi.com", "erased-1@invalid")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, keys)
+	})
+
+	t.Run("successfully erase user", func(t *testing.T) {
+		exec := createUserEraserExecutor()
+
+		exec.sql.ExpectQuery(selectStorageKeysSQL).
+			WithArgs(uint64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"storage_key"}).AddRow("key-1").AddRow("key-2"))
+		exec.sql.ExpectExec(insertStorageDeletionSQL).WithArgs("key-1", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+		exec.sql.ExpectExec(insertStorageDeletionSQL).WithArgs("key-2", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+		for _, query := range []string{
+			`DELETE FROM attachments WHERE medical_record_id IN \(SELECT id FROM medical_records WHERE user_id = \$1\)`,
+			`DELETE FROM medical_records WHERE user_id = \$1`,
+			`DELETE FROM erasures WHERE user_id = \$1`,
+			`DELETE FROM email_changes WHERE user_id = \$1`,
+			`DELETE FROM user_identities WHERE user_id = \$1`,
+			`DELETE FROM user_profiles WHERE user_id = \$1`,
+			`DELETE FROM users WHERE id = \$1`,
+		} {
+			exec.sql.ExpectExec(query).WithArgs(uint64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		for i := 0; i < 4; i++ {
+			exec.sql.ExpectExec(`UPDATE`).WithArgs("erased-1@invalid", "a@orvosi.com").WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		keys, err := exec.repo.Erase(context.Background(), uint64(1), "a@orvosi.com", "erased-1@invalid")
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"key-1", "key-2"}, keys)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+}
+
+func createUserEraserExecutor() *UserEraserExecutor {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Panicf("[createUserEraserExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	repo := repository.NewUserEraser(db)
+	return &UserEraserExecutor{
+		repo: repo,
+		sql:  mock,
+	}
+}
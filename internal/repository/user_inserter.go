@@ -9,7 +9,7 @@ import (
 )
 
 // UserInserter connects the database with user entity
-// and only responsible for inserting a new data.
+// and only responsible for inserting a new data and keeping it up to date during sign-in.
 type UserInserter struct {
 	db *sql.DB
 }
@@ -19,26 +19,89 @@ func NewUserInserter(db *sql.DB) *UserInserter {
 	return &UserInserter{db: db}
 }
 
-// InsertOrIgnore inserts a new data into the database.
-// If the user already exists in the database, it does nothing.
-func (ui *UserInserter) InsertOrIgnore(ctx context.Context, user *entity.User) *entity.Error {
+// Upsert inserts a new data into the database.
+// If the user already exists in the database, the name and Google ID are refreshed instead.
+// It returns the stored user, including the profile.
+func (ui *UserInserter) Upsert(ctx context.Context, user *entity.User) (*entity.User, *entity.Error) {
 	if user == nil {
-		return entity.ErrEmptyUser
+		return nil, entity.ErrEmptyUser
 	}
 
-	query := "INSERT INTO users (name, email, google_id, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (email) DO NOTHING"
-	_, err := ui.db.ExecContext(ctx, query,
+	q := querierFromContext(ctx, ui.db)
+	query := "INSERT INTO users (name, email, google_id, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $7) " +
+		"ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name, google_id = EXCLUDED.google_id, updated_at = EXCLUDED.updated_at, updated_by = EXCLUDED.updated_by"
+	_, err := q.ExecContext(ctx, query,
 		user.Name,
 		user.Email,
 		user.GoogleID,
-		time.Now(),
-		time.Now(),
+		time.Now().UTC(),
+		time.Now().UTC(),
 		user.Email,
 		user.Email,
 	)
 
 	if err != nil {
-		return entity.WrapError(entity.ErrInternalServer, err.Error())
+		return nil, databaseError(err, "[UserInserter-Upsert] exec insert query: ")
+	}
+	return findUserByEmail(ctx, q, user.Email, "[UserInserter-Upsert]")
+}
+
+// Insert inserts a new data into the database.
+// It returns entity.ErrEmailAlreadyUsed if the email belongs to another user,
+// otherwise it returns the stored user, including the profile.
+func (ui *UserInserter) Insert(ctx context.Context, user *entity.User) (*entity.User, *entity.Error) {
+	if user == nil {
+		return nil, entity.ErrEmptyUser
+	}
+
+	q := querierFromContext(ctx, ui.db)
+	query := "INSERT INTO users (name, email, google_id, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $7) " +
+		"ON CONFLICT (email) DO NOTHING"
+	res, err := q.ExecContext(ctx, query,
+		user.Name,
+		user.Email,
+		user.GoogleID,
+		time.Now().UTC(),
+		time.Now().UTC(),
+		user.Email,
+		user.Email,
+	)
+	if err != nil {
+		return nil, databaseError(err, "[UserInserter-Insert] exec insert query: ")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, databaseError(err, "[UserInserter-Insert] get affected rows: ")
+	}
+	if affected == 0 {
+		return nil, entity.ErrEmailAlreadyUsed
+	}
+	return findUserByEmail(ctx, q, user.Email, "[UserInserter-Insert]")
+}
+
+// FindByIdentity finds the user whose identity has the provider and subject.
+// It returns entity.ErrUserNotFound if no user has such identity.
+func (ui *UserInserter) FindByIdentity(ctx context.Context, provider, subject string) (*entity.User, *entity.Error) {
+	query := selectUserQuery + "JOIN user_identities i ON i.user_id = u.id WHERE i.provider = $1 AND i.subject = $2 LIMIT 1"
+	row := querierFromContext(ctx, ui.db).QueryRowContext(ctx, query, provider, subject)
+
+	var user entity.User
+	err := scanUser(row, &user)
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, databaseError(err, "[UserInserter-FindByIdentity] exec select query: ")
+	}
+	return &user, nil
+}
+
+// Refresh replaces the name and Google ID of the user who has the id.
+func (ui *UserInserter) Refresh(ctx context.Context, userID uint64, name, googleID string) *entity.Error {
+	query := "UPDATE users SET name = $1, google_id = $2, updated_at = $3 WHERE id = $4"
+	if _, err := querierFromContext(ctx, ui.db).ExecContext(ctx, query, name, googleID, time.Now().UTC(), userID); err != nil {
+		return databaseError(err, "[UserInserter-Refresh] exec update query: ")
 	}
 	return nil
 }
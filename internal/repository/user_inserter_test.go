@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/indrasaputra/hashids"
@@ -25,39 +26,185 @@ func TestNewUserInserter(t *testing.T) {
 	})
 }
 
-func TestUserInserter_InsertOrIgnore(t *testing.T) {
+func TestUserInserter_Upsert(t *testing.T) {
+	const upsertUser = `INSERT INTO users \(name, email, google_id, created_at, updated_at, created_by, updated_by\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\) ` +
+		`ON CONFLICT \(email\) DO UPDATE SET name = EXCLUDED.name, google_id = EXCLUDED.google_id, updated_at = EXCLUDED.updated_at, updated_by = EXCLUDED.updated_by`
+
 	t.Run("can't proceed due to nil user", func(t *testing.T) {
 		exec := createUserInserterExecutor()
 
-		err := exec.repo.InsertOrIgnore(context.Background(), nil)
+		res, err := exec.repo.Upsert(context.Background(), nil)
 
 		assert.NotNil(t, err)
 		assert.Equal(t, entity.ErrEmptyUser, err)
+		assert.Nil(t, res)
 	})
 
 	t.Run("database returns error", func(t *testing.T) {
 		exec := createUserInserterExecutor()
 
-		exec.sql.ExpectExec(`INSERT INTO users \(name, email, google_id, created_at, updated_at, created_by, updated_by\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\) ON CONFLICT \(email\) DO NOTHING`).
+		exec.sql.ExpectExec(upsertUser).
 			WillReturnError(errors.New("fail to insert to database"))
 
 		user := createValidUser()
-		err := exec.repo.InsertOrIgnore(context.Background(), user)
+		res, err := exec.repo.Upsert(context.Background(), user)
 
 		assert.NotNil(t, err)
 		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, res)
+	})
+
+	t.Run("select query returns error", func(t *testing.T) {
+		exec := createUserInserterExecutor()
+
+		exec.sql.ExpectExec(upsertUser).WillReturnResult(sqlmock.NewResult(1, 1))
+		exec.sql.ExpectQuery(selectUserQuery + `WHERE u.email = \$1 LIMIT 1`).
+			WillReturnError(errors.New("fail to select from database"))
+
+		user := createValidUser()
+		res, err := exec.repo.Upsert(context.Background(), user)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, res)
+	})
+
+	t.Run("successfully upsert user", func(t *testing.T) {
+		exec := createUserInserterExecutor()
+		user := createValidUser()
+
+		exec.sql.ExpectExec(upsertUser).
+			WithArgs(user.Name, user.Email, user.GoogleID, sqlmock.AnyArg(), sqlmock.AnyArg(), user.Email, user.Email).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		exec.sql.ExpectQuery(selectUserQuery + `WHERE u.email = \$1 LIMIT 1`).
+			WithArgs(user.Email).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(1, user.Name, user.Email, user.GoogleID, "dr. User", "", "", "", "", time.Now(), user.Email, time.Now(), user.Email))
+
+		res, err := exec.repo.Upsert(context.Background(), user)
+
+		assert.Nil(t, err)
+		assert.Equal(t, user.Email, res.Email)
+		assert.Equal(t, "dr. User", res.Profile.DisplayName)
+	})
+}
+
+func TestUserInserter_Insert(t *testing.T) {
+	const insertUser = `INSERT INTO users \(name, email, google_id, created_at, updated_at, created_by, updated_by\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\) ` +
+		`ON CONFLICT \(email\) DO NOTHING`
+
+	t.Run("can't proceed due to nil user", func(t *testing.T) {
+		exec := createUserInserterExecutor()
+
+		res, err := exec.repo.Insert(context.Background(), nil)
+
+		assert.Equal(t, entity.ErrEmptyUser, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("database returns error", func(t *testing.T) {
+		exec := createUserInserterExecutor()
+
+		exec.sql.ExpectExec(insertUser).
+			WillReturnError(errors.New("fail to insert to database"))
+
+		res, err := exec.repo.Insert(context.Background(), createValidUser())
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, res)
+	})
+
+	t.Run("email belongs to another user", func(t *testing.T) {
+		exec := createUserInserterExecutor()
+
+		exec.sql.ExpectExec(insertUser).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		res, err := exec.repo.Insert(context.Background(), createValidUser())
+
+		assert.Equal(t, entity.ErrEmailAlreadyUsed, err)
+		assert.Nil(t, res)
 	})
 
-	t.Run("successfully insert or ignore user", func(t *testing.T) {
+	t.Run("successfully insert user", func(t *testing.T) {
 		exec := createUserInserterExecutor()
+		user := createValidUser()
 
-		exec.sql.ExpectExec(`INSERT INTO users \(name, email, google_id, created_at, updated_at, created_by, updated_by\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\) ON CONFLICT \(email\) DO NOTHING`).
+		exec.sql.ExpectExec(insertUser).
+			WithArgs(user.Name, user.Email, user.GoogleID, sqlmock.AnyArg(), sqlmock.AnyArg(), user.Email, user.Email).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		exec.sql.ExpectQuery(selectUserQuery + `WHERE u.email = \$1 LIMIT 1`).
+			WithArgs(user.Email).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(1, user.Name, user.Email, user.GoogleID, "", "", "", "", "", time.Now(), user.Email, time.Now(), user.Email))
+
+		res, err := exec.repo.Insert(context.Background(), user)
+
+		assert.Nil(t, err)
+		assert.Equal(t, user.Email, res.Email)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
+	})
+}
+
+func TestUserInserter_FindByIdentity(t *testing.T) {
+	const findByIdentity = selectUserQuery + `JOIN user_identities i ON i.user_id = u.id WHERE i.provider = \$1 AND i.subject = \$2 LIMIT 1`
+
+	t.Run("select query returns error", func(t *testing.T) {
+		exec := createUserInserterExecutor()
+
+		exec.sql.ExpectQuery(findByIdentity).WillReturnError(errors.New("fail to select from database"))
+		res, err := exec.repo.FindByIdentity(context.Background(), entity.ProviderGoogle, "subject")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, res)
+	})
+
+	t.Run("user is not found", func(t *testing.T) {
+		exec := createUserInserterExecutor()
 
+		exec.sql.ExpectQuery(findByIdentity).WillReturnRows(sqlmock.NewRows(userColumns))
+		res, err := exec.repo.FindByIdentity(context.Background(), entity.ProviderGoogle, "subject")
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("successfully find user by identity", func(t *testing.T) {
+		exec := createUserInserterExecutor()
 		user := createValidUser()
-		err := exec.repo.InsertOrIgnore(context.Background(), user)
+
+		exec.sql.ExpectQuery(findByIdentity).
+			WithArgs(entity.ProviderGoogle, "subject").
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(1, user.Name, user.Email, user.GoogleID, "", "", "", "", "", time.Now(), user.Email, time.Now(), user.Email))
+		res, err := exec.repo.FindByIdentity(context.Background(), entity.ProviderGoogle, "subject")
+
+		assert.Nil(t, err)
+		assert.Equal(t, user.ID, res.ID)
+		assert.Equal(t, user.Email, res.Email)
+	})
+}
+
+func TestUserInserter_Refresh(t *testing.T) {
+	const refreshUser = `UPDATE users SET name = \$1, google_id = \$2, updated_at = \$3 WHERE id = \$4`
+
+	t.Run("update query returns error", func(t *testing.T) {
+		exec := createUserInserterExecutor()
+
+		exec.sql.ExpectExec(refreshUser).WillReturnError(errors.New("fail to update database"))
+		err := exec.repo.Refresh(context.Background(), 1, "User 1", "subject")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("successfully refresh user", func(t *testing.T) {
+		exec := createUserInserterExecutor()
+
+		exec.sql.ExpectExec(refreshUser).
+			WithArgs("User 1", "subject", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err := exec.repo.Refresh(context.Background(), 1, "User 1", "subject")
 
 		assert.Nil(t, err)
+		assert.Nil(t, exec.sql.ExpectationsWereMet())
 	})
 }
 
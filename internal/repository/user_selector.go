@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// selectUserQuery selects the user along with the profile, which may not have been filled in.
+const selectUserQuery = "SELECT u.id, u.name, u.email, u.google_id, " +
+	"COALESCE(p.display_name, ''), COALESCE(p.specialty, ''), COALESCE(p.license_number, ''), COALESCE(p.timezone, ''), COALESCE(p.preferred_language, ''), " +
+	"u.created_at, u.created_by, u.updated_at, u.updated_by " +
+	"FROM users u LEFT JOIN user_profiles p ON p.user_id = u.id "
+
+// UserSelector connects the database with user entity
+// and only responsible for retrieving user data.
+type UserSelector struct {
+	db *sql.DB
+}
+
+// NewUserSelector creates an instance of UserSelector.
+func NewUserSelector(db *sql.DB) *UserSelector {
+	return &UserSelector{db: db}
+}
+
+// FindAll finds at most limit users whose id is greater than from, ordered by id.
+func (us *UserSelector) FindAll(ctx context.Context, from uint64, limit uint) ([]*entity.User, *entity.Error) {
+	query := selectUserQuery + "WHERE u.id > $1 ORDER BY u.id LIMIT $2"
+	rows, err := querierFromContext(ctx, us.db).QueryContext(ctx, query, from, limit)
+	if err != nil {
+		return []*entity.User{}, databaseError(err, "[UserSelector-FindAll] exec select query: ")
+	}
+	defer rows.Close()
+
+	result := []*entity.User{}
+	for rows.Next() {
+		var tmp entity.User
+		if err := scanUser(rows, &tmp); err != nil {
+			return []*entity.User{}, databaseError(err, "[UserSelector-FindAll] scan rows: ")
+		}
+		result = append(result, &tmp)
+	}
+	if rows.Err() != nil {
+		return []*entity.User{}, databaseError(rows.Err(), "[UserSelector-FindAll] iterate rows: ")
+	}
+	return result, nil
+}
+
+// FindByEmail finds the user who has the email.
+// It returns entity.ErrUserNotFound if the user doesn't exist.
+func (us *UserSelector) FindByEmail(ctx context.Context, email string) (*entity.User, *entity.Error) {
+	return findUserByEmail(ctx, querierFromContext(ctx, us.db), email, "[UserSelector-FindByEmail]")
+}
+
+func findUserByEmail(ctx context.Context, q querier, email, caller string) (*entity.User, *entity.Error) {
+	row := q.QueryRowContext(ctx, selectUserQuery+"WHERE u.email = $1 LIMIT 1", email)
+
+	var user entity.User
+	err := scanUser(row, &user)
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, databaseError(err, caller+" exec select query: ")
+	}
+	return &user, nil
+}
+
+func scanUser(row scanner, user *entity.User) error {
+	return row.Scan(
+		&user.ID,
+		&user.Name,
+		&user.Email,
+		&user.GoogleID,
+		&user.Profile.DisplayName,
+		&user.Profile.Specialty,
+		&user.Profile.LicenseNumber,
+		&user.Profile.Timezone,
+		&user.Profile.PreferredLanguage,
+		&user.CreatedAt,
+		&user.CreatedBy,
+		&user.UpdatedAt,
+		&user.UpdatedBy,
+	)
+}
@@ -0,0 +1,124 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var userColumns = []string{"id", "name", "email", "google_id", "display_name", "specialty", "license_number", "timezone", "preferred_language", "created_at", "created_by", "updated_at", "updated_by"}
+
+const selectUserQuery = `SELECT u.id, u.name, u.email, u.google_id, .+ FROM users u LEFT JOIN user_profiles p ON p.user_id = u.id `
+
+type UserSelectorExecutor struct {
+	repo *repository.UserSelector
+	sql  sqlmock.Sqlmock
+}
+
+func TestNewUserSelector(t *testing.T) {
+	t.Run("successfully create an instance of UserSelector", func(t *testing.T) {
+		exec := createUserSelectorExecutor()
+		assert.NotNil(t, exec.repo)
+	})
+}
+
+func TestUserSelector_FindAll(t *testing.T) {
+	t.Run("select query returns error", func(t *testing.T) {
+		exec := createUserSelectorExecutor()
+
+		exec.sql.ExpectQuery(selectUserQuery + `WHERE u.id > \$1 ORDER BY u.id LIMIT \$2`).
+			WillReturnError(errors.New("fail to select from database"))
+		res, err := exec.repo.FindAll(context.Background(), 0, 10)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Empty(t, res)
+	})
+
+	t.Run("scan returns error", func(t *testing.T) {
+		exec := createUserSelectorExecutor()
+
+		exec.sql.ExpectQuery(selectUserQuery + `WHERE u.id > \$1 ORDER BY u.id LIMIT \$2`).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow("not-a-number", "A", "a@orvosi.com", "google-a", "", "", "", "", "", time.Now(), "a@orvosi.com", time.Now(), "a@orvosi.com"))
+		res, err := exec.repo.FindAll(context.Background(), 0, 10)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Empty(t, res)
+	})
+
+	t.Run("successfully find users", func(t *testing.T) {
+		exec := createUserSelectorExecutor()
+
+		exec.sql.ExpectQuery(selectUserQuery+`WHERE u.id > \$1 ORDER BY u.id LIMIT \$2`).
+			WithArgs(uint64(1), uint(10)).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(2, "B", "b@orvosi.com", "google-b", "", "", "", "", "", time.Now(), "b@orvosi.com", time.Now(), "b@orvosi.com").
+				AddRow(3, "C", "c@orvosi.com", "google-c", "", "", "", "", "", time.Now(), "c@orvosi.com", time.Now(), "c@orvosi.com"))
+		res, err := exec.repo.FindAll(context.Background(), 1, 10)
+
+		assert.Nil(t, err)
+		if assert.Len(t, res, 2) {
+			assert.Equal(t, "b@orvosi.com", res[0].Email)
+			assert.Equal(t, "C", res[1].Name)
+		}
+	})
+}
+
+func TestUserSelector_FindByEmail(t *testing.T) {
+	t.Run("user is not found", func(t *testing.T) {
+		exec := createUserSelectorExecutor()
+
+		exec.sql.ExpectQuery(selectUserQuery + `WHERE u.email = \$1 LIMIT 1`).
+			WillReturnError(sql.ErrNoRows)
+		res, err := exec.repo.FindByEmail(context.Background(), "a@orvosi.com")
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("select query returns error", func(t *testing.T) {
+		exec := createUserSelectorExecutor()
+
+		exec.sql.ExpectQuery(selectUserQuery + `WHERE u.email = \$1 LIMIT 1`).
+			WillReturnError(errors.New("fail to select from database"))
+		res, err := exec.repo.FindByEmail(context.Background(), "a@orvosi.com")
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, res)
+	})
+
+	t.Run("successfully find user", func(t *testing.T) {
+		exec := createUserSelectorExecutor()
+
+		exec.sql.ExpectQuery(selectUserQuery + `WHERE u.email = \$1 LIMIT 1`).
+			WithArgs("a@orvosi.com").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "A", "a@orvosi.com", "google-a", "dr. A", "Cardiology", "SIP-123", "Asia/Jakarta", "id", time.Now(), "a@orvosi.com", time.Now(), "a@orvosi.com"))
+		res, err := exec.repo.FindByEmail(context.Background(), "a@orvosi.com")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "A", res.Name)
+		assert.Equal(t, "google-a", res.GoogleID)
+		assert.Equal(t, "Cardiology", res.Profile.Specialty)
+		assert.Equal(t, "id", res.Profile.PreferredLanguage)
+	})
+}
+
+func createUserSelectorExecutor() *UserSelectorExecutor {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Panicf("[createUserSelectorExecutor] error opening a stub database connection: %v\n", err)
+	}
+
+	repo := repository.NewUserSelector(db)
+	return &UserSelectorExecutor{
+		repo: repo,
+		sql:  mock,
+	}
+}
@@ -0,0 +1,3 @@
+// Package settings keeps the runtime configuration which can change while the application runs.
+// The components read the current snapshot on every use instead of keeping their own copy.
+package settings
@@ -0,0 +1,165 @@
+package settings
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/internal/config"
+)
+
+// Snapshot is a version of runtime configuration. It must not be changed.
+type Snapshot struct {
+	config.Runtime
+	// Version starts from 1 and increases every time a different configuration is applied.
+	Version uint64
+	// LoadedAt is when the configuration is applied.
+	LoadedAt time.Time
+	// Origins are the parsed CORSAllowOrigins.
+	Origins []string
+	// Admins are the parsed AdminEmails.
+	Admins []string
+}
+
+// IsAdmin checks whether the email belongs to an admin. The email is compared case-insensitively.
+func (s *Snapshot) IsAdmin(email string) bool {
+	for _, admin := range s.Admins {
+		if strings.EqualFold(admin, email) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOriginAllowed checks whether the origin may call the API.
+func (s *Snapshot) IsOriginAllowed(origin string) bool {
+	for _, o := range s.Origins {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// Loader loads the runtime configuration again from its sources.
+type Loader func() (config.Runtime, error)
+
+// Settings holds the current snapshot of runtime configuration and swaps it atomically on reload.
+type Settings struct {
+	current atomic.Value
+	load    Loader
+
+	mu        sync.Mutex
+	listeners []func(*Snapshot)
+}
+
+// NewSettings creates an instance of Settings with the initial configuration as version 1.
+// The initial configuration must be valid.
+func NewSettings(initial config.Runtime, load Loader) *Settings {
+	s := &Settings{load: load}
+	s.current.Store(newSnapshot(initial, 1))
+	return s
+}
+
+// Current returns the snapshot in use.
+func (s *Settings) Current() *Snapshot {
+	return s.current.Load().(*Snapshot)
+}
+
+// PageSize returns the number of records in a page.
+func (s *Settings) PageSize() uint {
+	return s.Current().PageSize
+}
+
+// OnChange registers fn to be called with the new snapshot after it is applied.
+func (s *Settings) OnChange(fn func(*Snapshot)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Reload loads the configuration and applies it if it is valid and different from the current one.
+// The current snapshot is kept if loading or validation fails.
+// It returns whether a new snapshot is applied.
+func (s *Settings) Reload() (bool, error) {
+	runtime, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	if err := runtime.Validate(); err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur := s.Current()
+	if reflect.DeepEqual(cur.Runtime, runtime) {
+		return false, nil
+	}
+	next := newSnapshot(runtime, cur.Version+1)
+	s.current.Store(next)
+	for _, fn := range s.listeners {
+		fn(next)
+	}
+	return true, nil
+}
+
+// Watch reloads the configuration when SIGHUP is received
+// or when the file is modified, checked every interval.
+// The file is not checked if it is empty or interval is not positive.
+// It runs until the context is done.
+func (s *Settings) Watch(ctx context.Context, file string, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if file != "" && interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	last := config.FileVersion(file)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			s.reloadAndLog("SIGHUP")
+		case <-tick:
+			if v := config.FileVersion(file); v != last {
+				last = v
+				s.reloadAndLog(file + " changed")
+			}
+		}
+	}
+}
+
+func (s *Settings) reloadAndLog(reason string) {
+	applied, err := s.Reload()
+	switch {
+	case err != nil:
+		log.Printf("[Settings-Reload] %s: keep version %d: %v", reason, s.Current().Version, err)
+	case applied:
+		log.Printf("[Settings-Reload] %s: apply version %d", reason, s.Current().Version)
+	}
+}
+
+func newSnapshot(runtime config.Runtime, version uint64) *Snapshot {
+	return &Snapshot{
+		Runtime:  runtime,
+		Version:  version,
+		LoadedAt: time.Now().UTC(),
+		Origins:  config.SplitList(runtime.CORSAllowOrigins),
+		Admins:   config.SplitList(runtime.AdminEmails),
+	}
+}
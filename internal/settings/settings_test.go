@@ -0,0 +1,155 @@
+package settings_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/internal/config"
+	"github.com/indrasaputra/orvosi-api/internal/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+type Loader struct {
+	runtime config.Runtime
+	err     error
+}
+
+func (l *Loader) Load() (config.Runtime, error) {
+	return l.runtime, l.err
+}
+
+func TestNewSettings(t *testing.T) {
+	t.Run("initial configuration is version 1", func(t *testing.T) {
+		s := settings.NewSettings(createRuntime(), nil)
+
+		snap := s.Current()
+		assert.Equal(t, uint64(1), snap.Version)
+		assert.Equal(t, uint(10), s.PageSize())
+		assert.Equal(t, []string{"https://orvosi.id", "https://admin.orvosi.id"}, snap.Origins)
+		assert.Equal(t, []string{"admin@orvosi.id"}, snap.Admins)
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Run("admin is compared case-insensitively", func(t *testing.T) {
+		snap := settings.NewSettings(createRuntime(), nil).Current()
+
+		assert.True(t, snap.IsAdmin("Admin@Orvosi.id"))
+		assert.False(t, snap.IsAdmin("user@orvosi.id"))
+	})
+
+	t.Run("origin must be listed unless any origin is allowed", func(t *testing.T) {
+		rt := createRuntime()
+		snap := settings.NewSettings(rt, nil).Current()
+
+		assert.True(t, snap.IsOriginAllowed("https://orvosi.id"))
+		assert.False(t, snap.IsOriginAllowed("https://evil.com"))
+
+		rt.CORSAllowOrigins = "*"
+		snap = settings.NewSettings(rt, nil).Current()
+		assert.True(t, snap.IsOriginAllowed("https://evil.com"))
+	})
+}
+
+func TestSettings_Reload(t *testing.T) {
+	t.Run("loader fails and current snapshot is kept", func(t *testing.T) {
+		l := &Loader{err: errors.New("invalid config")}
+		s := settings.NewSettings(createRuntime(), l.Load)
+
+		applied, err := s.Reload()
+
+		assert.NotNil(t, err)
+		assert.False(t, applied)
+		assert.Equal(t, uint64(1), s.Current().Version)
+	})
+
+	t.Run("invalid configuration is not applied", func(t *testing.T) {
+		l := &Loader{runtime: createRuntime()}
+		l.runtime.PageSize = 0
+		s := settings.NewSettings(createRuntime(), l.Load)
+
+		applied, err := s.Reload()
+
+		assert.NotNil(t, err)
+		assert.False(t, applied)
+		assert.Equal(t, uint(10), s.PageSize())
+	})
+
+	t.Run("same configuration keeps the version", func(t *testing.T) {
+		l := &Loader{runtime: createRuntime()}
+		s := settings.NewSettings(createRuntime(), l.Load)
+
+		applied, err := s.Reload()
+
+		assert.Nil(t, err)
+		assert.False(t, applied)
+		assert.Equal(t, uint64(1), s.Current().Version)
+	})
+
+	t.Run("different configuration is applied and listeners are notified", func(t *testing.T) {
+		l := &Loader{runtime: createRuntime()}
+		l.runtime.PageSize = 25
+		s := settings.NewSettings(createRuntime(), l.Load)
+		var notified *settings.Snapshot
+		s.OnChange(func(snap *settings.Snapshot) { notified = snap })
+
+		applied, err := s.Reload()
+
+		assert.Nil(t, err)
+		assert.True(t, applied)
+		assert.Equal(t, uint64(2), s.Current().Version)
+		assert.Equal(t, uint(25), s.PageSize())
+		assert.Same(t, s.Current(), notified)
+	})
+}
+
+func TestSettings_Watch(t *testing.T) {
+	t.Run("modified file is reloaded", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "config.yaml")
+		assert.Nil(t, ioutil.WriteFile(file, []byte("a"), 0600))
+
+		l := &Loader{runtime: createRuntime()}
+		l.runtime.PageSize = 30
+		s := settings.NewSettings(createRuntime(), l.Load)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go s.Watch(ctx, file, 10*time.Millisecond)
+
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, uint64(1), s.Current().Version)
+
+		assert.Nil(t, ioutil.WriteFile(file, []byte("ab"), 0600))
+		assert.Eventually(t, func() bool { return s.PageSize() == 30 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("SIGHUP reloads the configuration", func(t *testing.T) {
+		l := &Loader{runtime: createRuntime()}
+		l.runtime.LogLevel = "debug"
+		s := settings.NewSettings(createRuntime(), l.Load)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go s.Watch(ctx, "", 0)
+		time.Sleep(20 * time.Millisecond)
+
+		assert.Nil(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+		assert.Eventually(t, func() bool { return s.Current().LogLevel == "debug" }, time.Second, 10*time.Millisecond)
+	})
+}
+
+func createRuntime() config.Runtime {
+	return config.Runtime{
+		PageSize:         10,
+		RateBurst:        20,
+		LogLevel:         "info",
+		CORSAllowOrigins: "https://orvosi.id, https://admin.orvosi.id",
+		AdminEmails:      "admin@orvosi.id",
+	}
+}
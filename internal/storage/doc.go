@@ -0,0 +1,3 @@
+// Package storage provides real connection to file storage,
+// such as local filesystem and S3-compatible object storage.
+package storage
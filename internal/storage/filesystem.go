@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+const (
+	filesystemDirPerm  = 0o750
+	filesystemFilePerm = 0o640
+)
+
+// Filesystem keeps the content as files under a root directory.
+// The key is used as the path relative to the root directory.
+type Filesystem struct {
+	root string
+}
+
+// NewFilesystem creates an instance of Filesystem.
+func NewFilesystem(root string) *Filesystem {
+	return &Filesystem{root: root}
+}
+
+// Put writes the content into a temporary file first then renames it,
+// so a reader never sees a partially written content.
+func (fs *Filesystem) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) *entity.Error {
+	path, err := fs.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), filesystemDirPerm); err != nil {
+		return entity.WrapError(entity.ErrInternalServer, "[Filesystem-Put] create directory: "+err.Error())
+	}
+
+	tmp, terr := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if terr != nil {
+		return entity.WrapError(entity.ErrInternalServer, "[Filesystem-Put] create file: "+terr.Error())
+	}
+	defer os.Remove(tmp.Name())
+
+	written, werr := io.Copy(tmp, content)
+	if cerr := tmp.Close(); werr == nil {
+		werr = cerr
+	}
+	if werr != nil {
+		return entity.WrapError(entity.ErrInternalServer, "[Filesystem-Put] write file: "+werr.Error())
+	}
+	if written != size {
+		return entity.WrapError(entity.ErrInternalServer, "[Filesystem-Put] written size doesn't match")
+	}
+
+	if err := os.Chmod(tmp.Name(), filesystemFilePerm); err != nil {
+		return entity.WrapError(entity.ErrInternalServer, "[Filesystem-Put] chmod file: "+err.Error())
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return entity.WrapError(entity.ErrInternalServer, "[Filesystem-Put] rename file: "+err.Error())
+	}
+	return nil
+}
+
+// Get opens the file of the key.
+func (fs *Filesystem) Get(ctx context.Context, key string) (io.ReadCloser, *entity.Error) {
+	path, err := fs.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, oerr := os.Open(path)
+	if os.IsNotExist(oerr) {
+		return nil, entity.ErrAttachmentNotFound
+	}
+	if oerr != nil {
+		return nil, entity.WrapError(entity.ErrInternalServer, "[Filesystem-Get] open file: "+oerr.Error())
+	}
+	return file, nil
+}
+
+// Delete removes the file of the key.
+func (fs *Filesystem) Delete(ctx context.Context, key string) *entity.Error {
+	path, err := fs.path(key)
+	if err != nil {
+		return err
+	}
+
+	if rerr := os.Remove(path); rerr != nil && !os.IsNotExist(rerr) {
+		return entity.WrapError(entity.ErrInternalServer, "[Filesystem-Delete] remove file: "+rerr.Error())
+	}
+	return nil
+}
+
+// path converts the key into a path under the root directory.
+// Key that tries to escape the root directory is rejected.
+func (fs *Filesystem) path(key string) (string, *entity.Error) {
+	if key == "" || strings.Contains(key, "..") || strings.HasPrefix(key, "/") {
+		return "", entity.WrapError(entity.ErrInternalServer, "[Filesystem] invalid key: "+key)
+	}
+	return filepath.Join(fs.root, filepath.FromSlash(key)), nil
+}
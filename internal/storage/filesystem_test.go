@@ -0,0 +1,97 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFilesystem(t *testing.T) {
+	t.Run("successfully create an instance of Filesystem", func(t *testing.T) {
+		fs := storage.NewFilesystem(t.TempDir())
+		assert.NotNil(t, fs)
+	})
+}
+
+func TestFilesystem_Put(t *testing.T) {
+	t.Run("key escapes the root directory", func(t *testing.T) {
+		fs := storage.NewFilesystem(t.TempDir())
+
+		for _, key := range []string{"", "../outside", "/etc/passwd", "a/../../b"} {
+			err := fs.Put(context.Background(), key, strings.NewReader("content"), 7, "text/plain")
+			assert.NotNil(t, err)
+			assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		}
+	})
+
+	t.Run("content size doesn't match and nothing is stored", func(t *testing.T) {
+		root := t.TempDir()
+		fs := storage.NewFilesystem(root)
+
+		err := fs.Put(context.Background(), "records/1/key", strings.NewReader("content"), 100, "text/plain")
+		assert.NotNil(t, err)
+
+		entries, _ := os.ReadDir(filepath.Join(root, "records", "1"))
+		assert.Empty(t, entries)
+	})
+
+	t.Run("successfully put content", func(t *testing.T) {
+		root := t.TempDir()
+		fs := storage.NewFilesystem(root)
+
+		err := fs.Put(context.Background(), "records/1/key", strings.NewReader("content"), 7, "text/plain")
+		assert.Nil(t, err)
+
+		data, _ := os.ReadFile(filepath.Join(root, "records", "1", "key"))
+		assert.Equal(t, "content", string(data))
+	})
+}
+
+func TestFilesystem_Get(t *testing.T) {
+	t.Run("key doesn't exist", func(t *testing.T) {
+		fs := storage.NewFilesystem(t.TempDir())
+
+		res, err := fs.Get(context.Background(), "records/1/missing")
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrAttachmentNotFound, err)
+	})
+
+	t.Run("successfully get content", func(t *testing.T) {
+		fs := storage.NewFilesystem(t.TempDir())
+		fs.Put(context.Background(), "records/1/key", strings.NewReader("content"), 7, "text/plain")
+
+		res, err := fs.Get(context.Background(), "records/1/key")
+		assert.Nil(t, err)
+		defer res.Close()
+
+		data, _ := io.ReadAll(res)
+		assert.Equal(t, "content", string(data))
+	})
+}
+
+func TestFilesystem_Delete(t *testing.T) {
+	t.Run("deleting missing key is not an error", func(t *testing.T) {
+		fs := storage.NewFilesystem(t.TempDir())
+
+		err := fs.Delete(context.Background(), "records/1/missing")
+		assert.Nil(t, err)
+	})
+
+	t.Run("successfully delete content", func(t *testing.T) {
+		fs := storage.NewFilesystem(t.TempDir())
+		fs.Put(context.Background(), "records/1/key", strings.NewReader("content"), 7, "text/plain")
+
+		err := fs.Delete(context.Background(), "records/1/key")
+		assert.Nil(t, err)
+
+		_, gerr := fs.Get(context.Background(), "records/1/key")
+		assert.Equal(t, entity.ErrAttachmentNotFound, gerr)
+	})
+}
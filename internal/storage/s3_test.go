@@ -0,0 +1,134 @@
+package storage_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeObjectStorage is a minimal MinIO-style stand-in.
+// It keeps objects in memory and rejects requests that are not signed
+// or whose payload doesn't match the signed hash.
+type fakeObjectStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (f *fakeObjectStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access-key/") ||
+		!strings.Contains(auth, "/us-east-1/s3/aws4_request") ||
+		!strings.Contains(auth, "SignedHeaders=") ||
+		!strings.Contains(auth, "Signature=") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	sum := sha256.Sum256(body)
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, "<Error><Code>XAmzContentSHA256Mismatch</Code></Error>")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[r.URL.Path] = body
+		f.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		obj, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(obj)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestNewS3(t *testing.T) {
+	t.Run("endpoint or bucket is invalid", func(t *testing.T) {
+		cfgs := []storage.S3Config{
+			{Endpoint: "localhost:9000", Bucket: "bucket"},
+			{Endpoint: "http://localhost:9000"},
+		}
+		for _, cfg := range cfgs {
+			s, err := storage.NewS3(cfg, nil)
+			assert.NotNil(t, err)
+			assert.Nil(t, s)
+		}
+	})
+
+	t.Run("successfully create an instance of S3", func(t *testing.T) {
+		s, err := storage.NewS3(storage.S3Config{Endpoint: "http://localhost:9000", Bucket: "bucket"}, nil)
+		assert.Nil(t, err)
+		assert.NotNil(t, s)
+	})
+}
+
+func TestS3(t *testing.T) {
+	fake := &fakeObjectStorage{objects: map[string][]byte{}, types: map[string]string{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	s, _ := storage.NewS3(storage.S3Config{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		Bucket:    "orvosi",
+		AccessKey: "access-key",
+		SecretKey: "secret-key",
+		PathStyle: true,
+	}, srv.Client())
+
+	t.Run("get missing object", func(t *testing.T) {
+		res, err := s.Get(context.Background(), "medical-records/1/missing")
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrAttachmentNotFound, err)
+	})
+
+	t.Run("content size doesn't match", func(t *testing.T) {
+		err := s.Put(context.Background(), "medical-records/1/key", strings.NewReader("content"), 100, "application/pdf")
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("successfully put, get, and delete object", func(t *testing.T) {
+		err := s.Put(context.Background(), "medical-records/1/key", strings.NewReader("content"), 7, "application/pdf")
+		assert.Nil(t, err)
+		assert.Equal(t, "application/pdf", fake.types["/orvosi/medical-records/1/key"])
+
+		res, err := s.Get(context.Background(), "medical-records/1/key")
+		assert.Nil(t, err)
+		data, _ := io.ReadAll(res)
+		res.Close()
+		assert.Equal(t, "content", string(data))
+
+		err = s.Delete(context.Background(), "medical-records/1/key")
+		assert.Nil(t, err)
+		assert.Empty(t, fake.objects)
+	})
+
+	t.Run("storage rejects the request", func(t *testing.T) {
+		unsigned, _ := storage.NewS3(storage.S3Config{Endpoint: srv.URL, Region: "eu-west-1", Bucket: "orvosi", PathStyle: true}, srv.Client())
+
+		err := unsigned.Put(context.Background(), "medical-records/1/key", strings.NewReader("content"), 7, "application/pdf")
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "unexpected status 403")
+	})
+}
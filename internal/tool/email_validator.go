@@ -0,0 +1,111 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/usecase"
+)
+
+// MXResolver defines the contract to look up the MX records of a domain.
+// *net.Resolver satisfies it.
+type MXResolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+}
+
+// MXEmailValidator validates the syntax of email, then checks whether its domain has MX records.
+// The result of the lookup is cached per domain:
+// the domain having MX records for ttl, and the domain having none for negativeTTL.
+// A failure of the resolver itself, such as DNS timeout, doesn't reject the email and isn't cached.
+type MXEmailValidator struct {
+	syntax      *usecase.EmailSyntaxValidator
+	resolver    MXResolver
+	ttl         time.Duration
+	negativeTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]mxCacheEntry
+}
+
+type mxCacheEntry struct {
+	valid     bool
+	expiresAt time.Time
+}
+
+// NewMXEmailValidator creates an instance of MXEmailValidator.
+// If resolver is nil, net.DefaultResolver is used.
+func NewMXEmailValidator(resolver MXResolver, ttl, negativeTTL time.Duration) *MXEmailValidator {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &MXEmailValidator{
+		syntax:      usecase.NewEmailSyntaxValidator(),
+		resolver:    resolver,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		cache:       make(map[string]mxCacheEntry),
+	}
+}
+
+// Validate validates the syntax of email and the MX records of its domain.
+func (mv *MXEmailValidator) Validate(ctx context.Context, email string) *entity.Error {
+	if err := mv.syntax.Validate(ctx, email); err != nil {
+		return err
+	}
+
+	domain := strings.ToLower(email[strings.LastIndex(email, "@")+1:])
+	if valid, ok := mv.cached(domain); ok {
+		return validity(valid)
+	}
+
+	mx, err := mv.resolver.LookupMX(ctx, domain)
+	var dnsErr *net.DNSError
+	if err != nil && !(errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+		return nil
+	}
+
+	valid := err == nil && len(mx) > 0
+	mv.store(domain, valid)
+	return validity(valid)
+}
+
+func (mv *MXEmailValidator) cached(domain string) (bool, bool) {
+	mv.mu.Lock()
+	defer mv.mu.Unlock()
+
+	entry, ok := mv.cache[domain]
+	if !ok {
+		return false, false
+	}
+	if !time.Now().Before(entry.expiresAt) {
+		delete(mv.cache, domain)
+		return false, false
+	}
+	return entry.valid, true
+}
+
+func (mv *MXEmailValidator) store(domain string, valid bool) {
+	ttl := mv.ttl
+	if !valid {
+		ttl = mv.negativeTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	mv.mu.Lock()
+	defer mv.mu.Unlock()
+	mv.cache[domain] = mxCacheEntry{valid: valid, expiresAt: time.Now().Add(ttl)}
+}
+
+func validity(valid bool) *entity.Error {
+	if !valid {
+		return entity.ErrInvalidEmail
+	}
+	return nil
+}
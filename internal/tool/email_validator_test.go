@@ -0,0 +1,104 @@
+package tool_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/tool"
+	"github.com/stretchr/testify/assert"
+)
+
+type MXResolver struct {
+	mx    []*net.MX
+	err   error
+	calls int
+}
+
+func (r *MXResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	r.calls++
+	return r.mx, r.err
+}
+
+func TestNewMXEmailValidator(t *testing.T) {
+	t.Run("successfully create an instance of MXEmailValidator", func(t *testing.T) {
+		assert.NotNil(t, tool.NewMXEmailValidator(nil, time.Minute, time.Minute))
+	})
+}
+
+func TestMXEmailValidator_Validate(t *testing.T) {
+	found := []*net.MX{{Host: "mx.dummy.com.", Pref: 10}}
+	notFound := &net.DNSError{Err: "no such host", Name: "dummy.com", IsNotFound: true}
+
+	t.Run("invalid syntax is rejected without lookup", func(t *testing.T) {
+		res := &MXResolver{mx: found}
+		val := tool.NewMXEmailValidator(res, time.Minute, time.Minute)
+
+		err := val.Validate(context.Background(), "dummy@")
+
+		assert.Equal(t, entity.ErrInvalidEmail, err)
+		assert.Equal(t, 0, res.calls)
+	})
+
+	t.Run("domain without mx records is rejected", func(t *testing.T) {
+		pairs := []*MXResolver{{err: notFound}, {mx: []*net.MX{}}}
+
+		for _, res := range pairs {
+			val := tool.NewMXEmailValidator(res, time.Minute, time.Minute)
+
+			err := val.Validate(context.Background(), "dummy@dummy.com")
+			assert.Equal(t, entity.ErrInvalidEmail, err)
+		}
+	})
+
+	t.Run("failure of the resolver doesn't reject the email and isn't cached", func(t *testing.T) {
+		res := &MXResolver{err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}
+		val := tool.NewMXEmailValidator(res, time.Minute, time.Minute)
+
+		assert.Nil(t, val.Validate(context.Background(), "dummy@dummy.com"))
+		assert.Nil(t, val.Validate(context.Background(), "dummy@dummy.com"))
+		assert.Equal(t, 2, res.calls)
+
+		res.err = errors.New("unknown")
+		assert.Nil(t, val.Validate(context.Background(), "dummy@dummy.com"))
+	})
+
+	t.Run("domain with mx records is cached for ttl", func(t *testing.T) {
+		res := &MXResolver{mx: found}
+		val := tool.NewMXEmailValidator(res, 50*time.Millisecond, time.Minute)
+
+		assert.Nil(t, val.Validate(context.Background(), "dummy@dummy.com"))
+		assert.Nil(t, val.Validate(context.Background(), "other@DUMMY.com"))
+		assert.Equal(t, 1, res.calls)
+
+		time.Sleep(60 * time.Millisecond)
+		assert.Nil(t, val.Validate(context.Background(), "dummy@dummy.com"))
+		assert.Equal(t, 2, res.calls)
+	})
+
+	t.Run("domain without mx records is cached for negative ttl", func(t *testing.T) {
+		res := &MXResolver{err: notFound}
+		val := tool.NewMXEmailValidator(res, time.Minute, 50*time.Millisecond)
+
+		assert.Equal(t, entity.ErrInvalidEmail, val.Validate(context.Background(), "dummy@dummy.com"))
+		assert.Equal(t, entity.ErrInvalidEmail, val.Validate(context.Background(), "dummy@dummy.com"))
+		assert.Equal(t, 1, res.calls)
+
+		time.Sleep(60 * time.Millisecond)
+		res.err, res.mx = nil, found
+		assert.Nil(t, val.Validate(context.Background(), "dummy@dummy.com"))
+		assert.Equal(t, 2, res.calls)
+	})
+
+	t.Run("zero ttl disables the cache", func(t *testing.T) {
+		res := &MXResolver{mx: found}
+		val := tool.NewMXEmailValidator(res, 0, 0)
+
+		assert.Nil(t, val.Validate(context.Background(), "dummy@dummy.com"))
+		assert.Nil(t, val.Validate(context.Background(), "dummy@dummy.com"))
+		assert.Equal(t, 2, res.calls)
+	})
+}
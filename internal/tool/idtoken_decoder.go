@@ -36,8 +36,20 @@ func payloadToUser(payload *idtoken.Payload) *entity.User {
 	email, _ := payload.Claims["email"].(string)
 
 	return &entity.User{
-		GoogleID: id,
-		Name:     name,
-		Email:    email,
+		GoogleID:      id,
+		Name:          name,
+		Email:         email,
+		EmailVerified: emailVerified(payload.Claims["email_verified"]),
 	}
 }
+
+// emailVerified reads the email_verified claim, which may be a boolean or a string.
+func emailVerified(claim interface{}) bool {
+	switch v := claim.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package tool
+
+import (
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+const pdfTimeFormat = "02 January 2006 15:04 MST"
+
+// MedicalRecordPDFRenderer responsibles for rendering a medical record into a printable PDF summary.
+type MedicalRecordPDFRenderer struct {
+	header string
+	footer string
+}
+
+// NewMedicalRecordPDFRenderer creates an instance of MedicalRecordPDFRenderer.
+// Header and footer are printed on every page, usually the clinic's name and address.
+func NewMedicalRecordPDFRenderer(header, footer string) *MedicalRecordPDFRenderer {
+	return &MedicalRecordPDFRenderer{
+		header: header,
+		footer: footer,
+	}
+}
+
+// Render renders the medical record into PDF.
+func (mr *MedicalRecordPDFRenderer) Render(record *entity.MedicalRecord) ([]byte, error) {
+	if record == nil {
+		return nil, entity.ErrEmptyMedicalRecord
+	}
+
+	id, err := hashids.EncodeID(record.ID)
+	if err != nil {
+		return nil, entity.WrapError(entity.ErrInternalServer, "[MedicalRecordPDFRenderer-Render] encode id: "+err.Error())
+	}
+
+	doc := NewPDFDocument("Medical Record "+string(id), mr.header, mr.footer)
+	doc.Title("Medical Record Summary")
+	doc.Paragraph("Record ID: " + string(id))
+	doc.Paragraph("Visit date: " + formatPDFTime(record.CreatedAt))
+
+	doc.Heading("Examination")
+	doc.Field("Symptom", record.Symptom)
+	doc.Field("Diagnosis", record.Diagnosis)
+	doc.Field("Therapy", record.Therapy)
+	doc.Field("Result", record.Result)
+
+	doc.Heading("Audit")
+	doc.Paragraph("Created by " + record.CreatedBy + " at " + formatPDFTime(record.CreatedAt))
+	doc.Paragraph("Last updated by " + record.UpdatedBy + " at " + formatPDFTime(record.UpdatedAt))
+
+	return doc.Bytes(), nil
+}
+
+func formatPDFTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(pdfTimeFormat)
+}
@@ -0,0 +1,54 @@
+package tool_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/tool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMedicalRecordPDFRenderer(t *testing.T) {
+	t.Run("successfully create an instance of MedicalRecordPDFRenderer", func(t *testing.T) {
+		r := tool.NewMedicalRecordPDFRenderer("header", "footer")
+		assert.NotNil(t, r)
+	})
+}
+
+func TestMedicalRecordPDFRenderer_Render(t *testing.T) {
+	t.Run("medical record is empty/nil", func(t *testing.T) {
+		r := tool.NewMedicalRecordPDFRenderer("header", "footer")
+
+		out, err := r.Render(nil)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, out)
+	})
+
+	t.Run("successfully render medical record", func(t *testing.T) {
+		r := tool.NewMedicalRecordPDFRenderer("Orvosi Clinic", "Get well soon")
+		record := &entity.MedicalRecord{
+			ID:        hashids.ID(1),
+			Symptom:   "Fever",
+			Diagnosis: "Influenza",
+			Therapy:   "Rest",
+			Auditable: entity.Auditable{
+				CreatedBy: "doctor@dummy.com",
+				CreatedAt: time.Date(2021, time.January, 28, 15, 00, 00, 00, time.UTC),
+				UpdatedBy: "doctor@dummy.com",
+				UpdatedAt: time.Date(2021, time.January, 28, 15, 00, 00, 00, time.UTC),
+			},
+		}
+
+		out, err := r.Render(record)
+
+		assert.Nil(t, err)
+		assert.True(t, bytes.HasPrefix(out, []byte("%PDF-")))
+		for _, text := range []string{"(Fever)", "(Influenza)", "(Rest)", "(Orvosi Clinic)", "(Get well soon)", "(Record ID: oWx0b8DZ1a)"} {
+			assert.Contains(t, string(out), text)
+		}
+	})
+}
@@ -0,0 +1,261 @@
+package tool
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	pdfPageWidth    = 595.0
+	pdfPageHeight   = 842.0
+	pdfMargin       = 56.0
+	pdfHeaderHeight = 40.0
+	pdfFooterHeight = 36.0
+
+	pdfFontRegular = "F1"
+	pdfFontBold    = "F2"
+
+	pdfTitleSize   = 18.0
+	pdfHeadingSize = 12.0
+	pdfBodySize    = 10.0
+	pdfSmallSize   = 8.0
+	pdfLineSpacing = 1.4
+)
+
+// pdfHelveticaWidths holds the width of Helvetica's printable ASCII glyphs (32-126)
+// in 1/1000 of the font size, taken from the standard Adobe font metrics.
+var pdfHelveticaWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// pdfTransliterations maps the characters outside Latin-1 onto the closest character in it.
+var pdfTransliterations = map[rune]rune{
+	'ő': 'ö',
+	'Ő': 'Ö',
+	'ű': 'ü',
+	'Ű': 'Ü',
+}
+
+// PDFDocument builds a simple A4 text document in PDF format.
+// It only uses the standard Helvetica fonts, so the output doesn't need any embedded font
+// and the text is limited to characters in WinAnsi (Latin-1) encoding.
+// The Hungarian ő and ű, which Latin-1 lacks, are printed as ö and ü.
+// Other characters are replaced by '?'.
+type PDFDocument struct {
+	title  string
+	header string
+	footer string
+
+	pages []*bytes.Buffer
+	y     float64
+}
+
+// NewPDFDocument creates an instance of PDFDocument.
+// Header and footer are printed on every page. Both are optional.
+func NewPDFDocument(title, header, footer string) *PDFDocument {
+	doc := &PDFDocument{
+		title:  title,
+		header: header,
+		footer: footer,
+	}
+	doc.newPage()
+	return doc
+}
+
+// Title writes the document title in big bold letters.
+func (d *PDFDocument) Title(text string) {
+	d.write(pdfFontBold, pdfTitleSize, text)
+	d.Space(pdfBodySize)
+}
+
+// Heading writes a section heading.
+func (d *PDFDocument) Heading(text string) {
+	d.Space(pdfBodySize / 2)
+	d.write(pdfFontBold, pdfHeadingSize, text)
+}
+
+// Paragraph writes a text that is wrapped to the page width.
+// Line breaks in the text are kept.
+func (d *PDFDocument) Paragraph(text string) {
+	d.write(pdfFontRegular, pdfBodySize, text)
+}
+
+// Field writes a label and its value as a paragraph.
+func (d *PDFDocument) Field(label, value string) {
+	d.write(pdfFontBold, pdfBodySize, label)
+	if strings.TrimSpace(value) == "" {
+		value = "-"
+	}
+	d.write(pdfFontRegular, pdfBodySize, value)
+	d.Space(pdfBodySize / 2)
+}
+
+// Space adds vertical space.
+func (d *PDFDocument) Space(height float64) {
+	d.y -= height
+}
+
+// Bytes returns the complete PDF document.
+func (d *PDFDocument) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// the first four objects are catalog, pages, and the two fonts.
+	// each page takes two objects: the page itself and its content stream.
+	const firstPageObject = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObject+i*2)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title %s /Producer (Orvosi API) /CreationDate (D:%s) >>",
+		pdfString(d.title), time.Now().UTC().Format("20060102150405Z")))
+
+	for i, page := range d.pages {
+		content := page.String() + d.pageDecoration(i+1, len(d.pages))
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] "+
+			"/Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, pdfFontRegular, pdfFontBold, firstPageObject+i*2+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+func (d *PDFDocument) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pdfPageHeight - pdfMargin - pdfHeaderHeight
+}
+
+func (d *PDFDocument) write(font string, size float64, text string) {
+	lineHeight := size * pdfLineSpacing
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		for _, line := range wrapPDFText(paragraph, size, pdfPageWidth-2*pdfMargin) {
+			if d.y-lineHeight < pdfMargin+pdfFooterHeight {
+				d.newPage()
+			}
+			d.y -= lineHeight
+			writePDFText(d.pages[len(d.pages)-1], font, size, pdfMargin, d.y, line)
+		}
+	}
+}
+
+// pageDecoration draws header, footer, and page number.
+// It is drawn at the end since the number of pages is only known after all texts are written.
+func (d *PDFDocument) pageDecoration(page, total int) string {
+	var buf bytes.Buffer
+	if d.header != "" {
+		writePDFText(&buf, pdfFontBold, pdfBodySize, pdfMargin, pdfPageHeight-pdfMargin, d.header)
+		fmt.Fprintf(&buf, "0.5 w %.2f %.2f m %.2f %.2f l S\n",
+			pdfMargin, pdfPageHeight-pdfMargin-pdfBodySize/2, pdfPageWidth-pdfMargin, pdfPageHeight-pdfMargin-pdfBodySize/2)
+	}
+
+	fmt.Fprintf(&buf, "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, pdfMargin+pdfSmallSize*2, pdfPageWidth-pdfMargin, pdfMargin+pdfSmallSize*2)
+	if d.footer != "" {
+		writePDFText(&buf, pdfFontRegular, pdfSmallSize, pdfMargin, pdfMargin, d.footer)
+	}
+	number := fmt.Sprintf("Page %d of %d", page, total)
+	writePDFText(&buf, pdfFontRegular, pdfSmallSize, pdfPageWidth-pdfMargin-pdfTextWidth(number, pdfSmallSize), pdfMargin, number)
+	return buf.String()
+}
+
+func writePDFText(buf *bytes.Buffer, font string, size, x, y float64, text string) {
+	fmt.Fprintf(buf, "BT /%s %.1f Tf %.2f %.2f Td %s Tj ET\n", font, size, x, y, pdfString(text))
+}
+
+// wrapPDFText splits text into lines which fit the width.
+// A word longer than the width is split by character.
+func wrapPDFText(text string, size, width float64) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	line := ""
+	for _, word := range words {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if pdfTextWidth(candidate, size) <= width {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		for pdfTextWidth(word, size) > width {
+			cut := len([]rune(word))
+			for cut > 1 && pdfTextWidth(string([]rune(word)[:cut]), size) > width {
+				cut--
+			}
+			lines = append(lines, string([]rune(word)[:cut]))
+			word = string([]rune(word)[cut:])
+		}
+		line = word
+	}
+	return append(lines, line)
+}
+
+func pdfTextWidth(text string, size float64) float64 {
+	total := 0
+	for _, r := range text {
+		if r >= 32 && r <= 126 {
+			total += pdfHelveticaWidths[r-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// pdfString encodes text as PDF literal string in WinAnsi encoding.
+func pdfString(text string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('(')
+	for _, r := range text {
+		if t, ok := pdfTransliterations[r]; ok {
+			r = t
+		}
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case r == '\t':
+			buf.WriteByte(' ')
+		case r >= 32 && r <= 126:
+			buf.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&buf, "\\%03o", r)
+		default:
+			buf.WriteByte('?')
+		}
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
@@ -0,0 +1,84 @@
+package tool_test
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/internal/tool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPDFDocument(t *testing.T) {
+	t.Run("successfully create an instance of PDFDocument", func(t *testing.T) {
+		doc := tool.NewPDFDocument("title", "header", "footer")
+		assert.NotNil(t, doc)
+	})
+}
+
+func TestPDFDocument_Bytes(t *testing.T) {
+	t.Run("document is a valid pdf with header and footer", func(t *testing.T) {
+		doc := tool.NewPDFDocument("title", "Orvosi Clinic", "Jl. Sudirman 1")
+		doc.Title("Summary")
+		doc.Field("Diagnosis", "Influenza (flu)")
+
+		out := doc.Bytes()
+
+		assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4")))
+		assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+		assert.Contains(t, string(out), "(Orvosi Clinic)")
+		assert.Contains(t, string(out), "(Jl. Sudirman 1)")
+		assert.Contains(t, string(out), `(Influenza \(flu\))`)
+		assert.Contains(t, string(out), "(Page 1 of 1)")
+	})
+
+	t.Run("xref offsets point to the objects", func(t *testing.T) {
+		doc := tool.NewPDFDocument("title", "", "")
+		doc.Paragraph("text")
+
+		out := doc.Bytes()
+
+		start := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+		xref, _ := strconv.Atoi(string(start[1]))
+		assert.True(t, bytes.HasPrefix(out[xref:], []byte("xref\n")))
+
+		entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out, -1)
+		for i, entry := range entries {
+			off, _ := strconv.Atoi(string(entry[1]))
+			assert.True(t, bytes.HasPrefix(out[off:], []byte(fmt.Sprintf("%d 0 obj", i+1))))
+		}
+	})
+
+	t.Run("long text is wrapped into many pages", func(t *testing.T) {
+		doc := tool.NewPDFDocument("title", "", "")
+		doc.Paragraph(strings.Repeat("lorem ipsum dolor sit amet ", 2000))
+
+		out := doc.Bytes()
+
+		count := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(out)
+		pages, _ := strconv.Atoi(string(count[1]))
+		assert.True(t, pages > 1)
+		assert.Contains(t, string(out), fmt.Sprintf("(Page %d of %d)", pages, pages))
+	})
+
+	t.Run("characters outside latin-1 are replaced", func(t *testing.T) {
+		doc := tool.NewPDFDocument("title", "", "")
+		doc.Paragraph("é ж")
+
+		out := doc.Bytes()
+
+		assert.Contains(t, string(out), `(\351 ?)`)
+	})
+
+	t.Run("hungarian characters outside latin-1 are transliterated", func(t *testing.T) {
+		doc := tool.NewPDFDocument("title", "", "")
+		doc.Paragraph("Vérnyomás ő ű Ő Ű")
+
+		out := doc.Bytes()
+
+		assert.Contains(t, string(out), `(V\351rnyom\341s \366 \374 \326 \334)`)
+	})
+}
@@ -0,0 +1,4 @@
+// Package validation validates request structs declaratively.
+// The rules are written in `validate` tag of each field
+// and every failing field is reported, not only the first one.
+package validation
@@ -0,0 +1,115 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+const (
+	// TagName is the name of struct tag holding the rules.
+	TagName = "validate"
+
+	// RuleRequired fails when the string is blank after trimmed or the value is the zero value.
+	RuleRequired = "required"
+	// RuleMin fails when the string has less characters than the parameter.
+	RuleMin = "min"
+	// RuleMax fails when the string has more characters than the parameter.
+	RuleMax = "max"
+	// RuleOneOf fails when the string is not one of the space-separated parameter.
+	RuleOneOf = "oneof"
+)
+
+// Validate checks every field of the struct (or pointer to struct) against the rules in its `validate` tag.
+// The rules are separated by comma, and a rule with parameter is written as rule=param, such as `validate:"required,max=255"`.
+// The name of the field is taken from its json tag, so it matches what the user sends.
+// Every failing rule is returned, in the order of the fields.
+// It panics if the tag contains an unknown rule, since that is a programming error.
+func Validate(v interface{}) []entity.InvalidParam {
+	val := reflect.Indirect(reflect.ValueOf(v))
+	if val.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validation: expecting struct, got %s", val.Kind()))
+	}
+
+	var params []entity.InvalidParam
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, ok := field.Tag.Lookup(TagName)
+		if !ok || tag == "" {
+			continue
+		}
+		name := fieldName(field)
+		for _, rule := range strings.Split(tag, ",") {
+			if param, failed := check(name, rule, val.Field(i)); failed {
+				params = append(params, param)
+			}
+		}
+	}
+	return params
+}
+
+func check(name, rule string, val reflect.Value) (entity.InvalidParam, bool) {
+	key, arg := rule, ""
+	if idx := strings.Index(rule, "="); idx >= 0 {
+		key, arg = rule[:idx], rule[idx+1:]
+	}
+
+	param := entity.InvalidParam{Name: name, Rule: key}
+	switch key {
+	case RuleRequired:
+		if val.Kind() == reflect.String && strings.TrimSpace(val.String()) != "" {
+			return param, false
+		}
+		if val.Kind() != reflect.String && !val.IsZero() {
+			return param, false
+		}
+		param.Reason = "must not be blank"
+	case RuleMin:
+		n := intArg(rule, arg)
+		if utf8.RuneCountInString(val.String()) >= n {
+			return param, false
+		}
+		param.Param = arg
+		param.Reason = fmt.Sprintf("must be at least %d characters", n)
+	case RuleMax:
+		n := intArg(rule, arg)
+		if utf8.RuneCountInString(val.String()) <= n {
+			return param, false
+		}
+		param.Param = arg
+		param.Reason = fmt.Sprintf("must be at most %d characters", n)
+	case RuleOneOf:
+		options := strings.Fields(arg)
+		for _, opt := range options {
+			if val.String() == opt {
+				return param, false
+			}
+		}
+		param.Param = strings.Join(options, ", ")
+		param.Reason = "must be one of " + param.Param
+	default:
+		panic(fmt.Sprintf("validation: unknown rule %q of field %s", rule, name))
+	}
+	return param, true
+}
+
+func intArg(rule, arg string) int {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		panic(fmt.Sprintf("validation: rule %q needs integer parameter", rule))
+	}
+	return n
+}
+
+func fieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
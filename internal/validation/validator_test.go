@@ -0,0 +1,67 @@
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/internal/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+type request struct {
+	Name     string `json:"name" validate:"required,max=5"`
+	Code     string `json:"code,omitempty" validate:"min=2"`
+	Gender   string `json:"gender" validate:"oneof=male female"`
+	Age      int    `validate:"required"`
+	Optional string `json:"optional"`
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("valid struct returns no invalid params", func(t *testing.T) {
+		params := validation.Validate(&request{Name: "name", Code: "ab", Gender: "male", Age: 1})
+		assert.Empty(t, params)
+	})
+
+	t.Run("struct value is accepted", func(t *testing.T) {
+		params := validation.Validate(request{Name: "name", Code: "ab", Gender: "female", Age: 1})
+		assert.Empty(t, params)
+	})
+
+	t.Run("every failing field is reported in order", func(t *testing.T) {
+		params := validation.Validate(&request{Name: "   ", Code: "a", Gender: "other"})
+
+		expected := []entity.InvalidParam{
+			{Name: "name", Rule: "required", Reason: "must not be blank"},
+			{Name: "code", Rule: "min", Reason: "must be at least 2 characters", Param: "2"},
+			{Name: "gender", Rule: "oneof", Reason: "must be one of male, female", Param: "male, female"},
+			{Name: "Age", Rule: "required", Reason: "must not be blank"},
+		}
+		assert.Equal(t, expected, params)
+	})
+
+	t.Run("length is counted in characters", func(t *testing.T) {
+		params := validation.Validate(&request{Name: "ééééé", Code: "ab", Gender: "male", Age: 1})
+		assert.Empty(t, params)
+
+		params = validation.Validate(&request{Name: "éééééé", Code: "ab", Gender: "male", Age: 1})
+		assert.Equal(t, []entity.InvalidParam{{Name: "name", Rule: "max", Reason: "must be at most 5 characters", Param: "5"}}, params)
+	})
+
+	t.Run("unknown rule panics", func(t *testing.T) {
+		type unknown struct {
+			Name string `json:"name" validate:"unknown"`
+		}
+		assert.Panics(t, func() { validation.Validate(&unknown{}) })
+	})
+
+	t.Run("non-integer parameter panics", func(t *testing.T) {
+		type invalid struct {
+			Name string `json:"name" validate:"max=many"`
+		}
+		assert.Panics(t, func() { validation.Validate(&invalid{}) })
+	})
+
+	t.Run("non-struct panics", func(t *testing.T) {
+		assert.Panics(t, func() { validation.Validate("string") })
+	})
+}
@@ -7,6 +7,7 @@ package mock_usecase
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/indrasaputra/orvosi-api/entity"
@@ -36,31 +37,47 @@ func (m *MockFindMedicalRecord) EXPECT() *MockFindMedicalRecordMockRecorder {
 }
 
 // FindByID mocks base method
-func (m *MockFindMedicalRecord) FindByID(ctx context.Context, id uint64, email string) (*entity.MedicalRecord, *entity.Error) {
+func (m *MockFindMedicalRecord) FindByID(ctx context.Context, id, userID uint64) (*entity.MedicalRecord, *entity.Error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByID", ctx, id, email)
+	ret := m.ctrl.Call(m, "FindByID", ctx, id, userID)
 	ret0, _ := ret[0].(*entity.MedicalRecord)
 	ret1, _ := ret[1].(*entity.Error)
 	return ret0, ret1
 }
 
 // FindByID indicates an expected call of FindByID
-func (mr *MockFindMedicalRecordMockRecorder) FindByID(ctx, id, email interface{}) *gomock.Call {
+func (mr *MockFindMedicalRecordMockRecorder) FindByID(ctx, id, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockFindMedicalRecord)(nil).FindByID), ctx, id, email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockFindMedicalRecord)(nil).FindByID), ctx, id, userID)
 }
 
-// FindByEmail mocks base method
-func (m *MockFindMedicalRecord) FindByEmail(ctx context.Context, email string, from uint64) ([]*entity.MedicalRecord, *entity.Error) {
+// FindByUserID mocks base method
+func (m *MockFindMedicalRecord) FindByUserID(ctx context.Context, userID, from uint64) ([]*entity.MedicalRecord, *entity.Error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByEmail", ctx, email, from)
+	ret := m.ctrl.Call(m, "FindByUserID", ctx, userID, from)
 	ret0, _ := ret[0].([]*entity.MedicalRecord)
 	ret1, _ := ret[1].(*entity.Error)
 	return ret0, ret1
 }
 
-// FindByEmail indicates an expected call of FindByEmail
-func (mr *MockFindMedicalRecordMockRecorder) FindByEmail(ctx, email, from interface{}) *gomock.Call {
+// FindByUserID indicates an expected call of FindByUserID
+func (mr *MockFindMedicalRecordMockRecorder) FindByUserID(ctx, userID, from interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockFindMedicalRecord)(nil).FindByEmail), ctx, email, from)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockFindMedicalRecord)(nil).FindByUserID), ctx, userID, from)
+}
+
+// FindByUserIDWithinPeriod mocks base method
+func (m *MockFindMedicalRecord) FindByUserIDWithinPeriod(ctx context.Context, userID uint64, since, until time.Time, from uint64) ([]*entity.MedicalRecord, bool, *entity.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserIDWithinPeriod", ctx, userID, since, until, from)
+	ret0, _ := ret[0].([]*entity.MedicalRecord)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(*entity.Error)
+	return ret0, ret1, ret2
+}
+
+// FindByUserIDWithinPeriod indicates an expected call of FindByUserIDWithinPeriod
+func (mr *MockFindMedicalRecordMockRecorder) FindByUserIDWithinPeriod(ctx, userID, since, until, from interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserIDWithinPeriod", reflect.TypeOf((*MockFindMedicalRecord)(nil).FindByUserIDWithinPeriod), ctx, userID, since, until, from)
 }
@@ -7,6 +7,7 @@ package mock_usecase
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/indrasaputra/orvosi-api/entity"
@@ -50,17 +51,32 @@ func (mr *MockFindMedicalRecordRepositoryMockRecorder) FindByID(ctx, id interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockFindMedicalRecordRepository)(nil).FindByID), ctx, id)
 }
 
-// FindByEmail mocks base method
-func (m *MockFindMedicalRecordRepository) FindByEmail(ctx context.Context, email string, from uint64, limit uint) ([]*entity.MedicalRecord, *entity.Error) {
+// FindByUserID mocks base method
+func (m *MockFindMedicalRecordRepository) FindByUserID(ctx context.Context, userID, from uint64, limit uint) ([]*entity.MedicalRecord, *entity.Error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByEmail", ctx, email, from, limit)
+	ret := m.ctrl.Call(m, "FindByUserID", ctx, userID, from, limit)
 	ret0, _ := ret[0].([]*entity.MedicalRecord)
 	ret1, _ := ret[1].(*entity.Error)
 	return ret0, ret1
 }
 
-// FindByEmail indicates an expected call of FindByEmail
-func (mr *MockFindMedicalRecordRepositoryMockRecorder) FindByEmail(ctx, email, from, limit interface{}) *gomock.Call {
+// FindByUserID indicates an expected call of FindByUserID
+func (mr *MockFindMedicalRecordRepositoryMockRecorder) FindByUserID(ctx, userID, from, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockFindMedicalRecordRepository)(nil).FindByEmail), ctx, email, from, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockFindMedicalRecordRepository)(nil).FindByUserID), ctx, userID, from, limit)
+}
+
+// FindByUserIDWithinPeriod mocks base method
+func (m *MockFindMedicalRecordRepository) FindByUserIDWithinPeriod(ctx context.Context, userID uint64, since, until time.Time, from uint64, limit uint) ([]*entity.MedicalRecord, *entity.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserIDWithinPeriod", ctx, userID, since, until, from, limit)
+	ret0, _ := ret[0].([]*entity.MedicalRecord)
+	ret1, _ := ret[1].(*entity.Error)
+	return ret0, ret1
+}
+
+// FindByUserIDWithinPeriod indicates an expected call of FindByUserIDWithinPeriod
+func (mr *MockFindMedicalRecordRepositoryMockRecorder) FindByUserIDWithinPeriod(ctx, userID, since, until, from, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserIDWithinPeriod", reflect.TypeOf((*MockFindMedicalRecordRepository)(nil).FindByUserIDWithinPeriod), ctx, userID, since, until, from, limit)
 }
@@ -36,11 +36,12 @@ func (m *MockSignIn) EXPECT() *MockSignInMockRecorder {
 }
 
 // SignIn mocks base method
-func (m *MockSignIn) SignIn(ctx context.Context, user *entity.User) *entity.Error {
+func (m *MockSignIn) SignIn(ctx context.Context, user *entity.User) (*entity.User, *entity.Error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SignIn", ctx, user)
-	ret0, _ := ret[0].(*entity.Error)
-	return ret0
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(*entity.Error)
+	return ret0, ret1
 }
 
 // SignIn indicates an expected call of SignIn
@@ -35,31 +35,16 @@ func (m *MockUpdateMedicalRecordRepository) EXPECT() *MockUpdateMedicalRecordRep
 	return m.recorder
 }
 
-// DoesRecordExist mocks base method
-func (m *MockUpdateMedicalRecordRepository) DoesRecordExist(ctx context.Context, id uint64, email string) (bool, *entity.Error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DoesRecordExist", ctx, id, email)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(*entity.Error)
-	return ret0, ret1
-}
-
-// DoesRecordExist indicates an expected call of DoesRecordExist
-func (mr *MockUpdateMedicalRecordRepositoryMockRecorder) DoesRecordExist(ctx, id, email interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoesRecordExist", reflect.TypeOf((*MockUpdateMedicalRecordRepository)(nil).DoesRecordExist), ctx, id, email)
-}
-
 // Update mocks base method
-func (m *MockUpdateMedicalRecordRepository) Update(ctx context.Context, id uint64, record *entity.MedicalRecord) *entity.Error {
+func (m *MockUpdateMedicalRecordRepository) Update(ctx context.Context, id, userID uint64, record *entity.MedicalRecord) *entity.Error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, record)
+	ret := m.ctrl.Call(m, "Update", ctx, id, userID, record)
 	ret0, _ := ret[0].(*entity.Error)
 	return ret0
 }
 
 // Update indicates an expected call of Update
-func (mr *MockUpdateMedicalRecordRepositoryMockRecorder) Update(ctx, id, record interface{}) *gomock.Call {
+func (mr *MockUpdateMedicalRecordRepositoryMockRecorder) Update(ctx, id, userID, record interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUpdateMedicalRecordRepository)(nil).Update), ctx, id, record)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUpdateMedicalRecordRepository)(nil).Update), ctx, id, userID, record)
 }
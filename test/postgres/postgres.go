@@ -0,0 +1,242 @@
+// Package postgres provides real PostgreSQL databases for integration tests.
+//
+// It uses the server pointed by the standard PG* environment variables if PGHOST is set.
+// Otherwise, it starts an ephemeral server using initdb and pg_ctl
+// found in POSTGRES_BIN_DIR, PATH, or /usr/lib/postgresql/*/bin.
+// The tests are skipped if neither is available.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/db/migrations"
+	"github.com/indrasaputra/orvosi-api/internal/migration"
+	_ "github.com/jackc/pgx/v4/stdlib" // postgres driver
+)
+
+const (
+	driver = "pgx"
+	port   = "5432"
+)
+
+var (
+	mu       sync.Mutex
+	started  bool
+	srv      *server
+	startErr error
+	seq      uint64
+)
+
+// server is the PostgreSQL server used by the tests of one package.
+type server struct {
+	// conninfo connects to the server without choosing the database.
+	conninfo string
+	// template has all migrations applied and is copied by NewDatabase.
+	template string
+	// dir and pgCtl are only set for an ephemeral server.
+	dir   string
+	pgCtl string
+}
+
+// Main runs the tests of a package and stops the ephemeral server, if any, once they finish.
+// It must be called from TestMain of every package which uses this package.
+func Main(m *testing.M) {
+	code := m.Run()
+	stop()
+	os.Exit(code)
+}
+
+// NewDatabase creates a new database with all migrations applied.
+// The database is dropped when the test finishes.
+func NewDatabase(t *testing.T) *sql.DB {
+	t.Helper()
+	s := start(t)
+	return s.createDatabase(t, s.template)
+}
+
+// NewEmptyDatabase creates a new database without any migration applied.
+// The database is dropped when the test finishes.
+func NewEmptyDatabase(t *testing.T) *sql.DB {
+	t.Helper()
+	s := start(t)
+	return s.createDatabase(t, "template1")
+}
+
+func start(t *testing.T) *server {
+	t.Helper()
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !started {
+		started = true
+		srv, startErr = startServer()
+	}
+	if startErr != nil {
+		t.Skipf("PostgreSQL is not available: %v", startErr)
+	}
+	return srv
+}
+
+func stop() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if srv == nil {
+		return
+	}
+	if srv.template != "" {
+		_ = srv.exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", srv.template))
+	}
+	stopServer(srv)
+	srv = nil
+}
+
+func startServer() (*server, error) {
+	var s *server
+	var err error
+	if os.Getenv("PGHOST") != "" {
+		s = &server{}
+		if os.Getenv("PGSSLMODE") == "" {
+			s.conninfo = "sslmode=disable"
+		}
+	} else if s, err = startEphemeralServer(); err != nil {
+		return nil, err
+	}
+
+	if err := s.createTemplate(); err != nil {
+		if s.template != "" {
+			_ = s.exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", s.template))
+		}
+		stopServer(s)
+		return nil, err
+	}
+	return s, nil
+}
+
+func startEphemeralServer() (*server, error) {
+	if os.Geteuid() == 0 {
+		return nil, fmt.Errorf("initdb can't be run as root, set PGHOST to use an existing server")
+	}
+	initdb, err := lookPath("initdb")
+	if err != nil {
+		return nil, err
+	}
+	pgCtl, err := lookPath("pg_ctl")
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "orvosi-postgres")
+	if err != nil {
+		return nil, err
+	}
+	data := filepath.Join(dir, "data")
+	s := &server{
+		conninfo: fmt.Sprintf("host=%s port=%s user=postgres sslmode=disable", dir, port),
+		dir:      dir,
+		pgCtl:    pgCtl,
+	}
+
+	if out, err := exec.Command(initdb, "-D", data, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-sync").CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("initdb: %v: %s", err, out)
+	}
+	// The server only listens to a unix socket inside dir, so it never clashes with another server.
+	options := fmt.Sprintf("-c listen_addresses='' -k %s -p %s -F", dir, port)
+	if out, err := exec.Command(pgCtl, "-D", data, "-l", filepath.Join(dir, "postgres.log"), "-o", options, "-w", "start").CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("pg_ctl start: %v: %s", err, out)
+	}
+	return s, nil
+}
+
+func stopServer(s *server) {
+	if s.dir != "" {
+		_ = exec.Command(s.pgCtl, "-D", filepath.Join(s.dir, "data"), "-m", "immediate", "stop").Run()
+		_ = os.RemoveAll(s.dir)
+	}
+}
+
+// lookPath finds PostgreSQL binary in POSTGRES_BIN_DIR, PATH, or the newest /usr/lib/postgresql/*/bin.
+func lookPath(name string) (string, error) {
+	if dir := os.Getenv("POSTGRES_BIN_DIR"); dir != "" {
+		return exec.LookPath(filepath.Join(dir, name))
+	}
+	if path, err := exec.LookPath(name); err == nil {
+		return path, nil
+	}
+	matches, _ := filepath.Glob(filepath.Join("/usr/lib/postgresql/*/bin", name))
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%s is not found", name)
+	}
+	sort.Strings(matches)
+	return matches[len(matches)-1], nil
+}
+
+// createTemplate creates the database copied by NewDatabase, so the migrations only run once.
+// Its name is unique per process since tests of many packages may share the same server.
+func (s *server) createTemplate() error {
+	name := fmt.Sprintf("orvosi_test_template_%d", os.Getpid())
+	if err := s.exec(fmt.Sprintf("CREATE DATABASE %s", name)); err != nil {
+		return err
+	}
+	s.template = name
+
+	db, err := sql.Open(driver, s.dsn(name))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := migration.NewMigrator(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up(context.Background())
+	return err
+}
+
+func (s *server) createDatabase(t *testing.T, template string) *sql.DB {
+	t.Helper()
+	name := fmt.Sprintf("orvosi_test_%d_%d", os.Getpid(), atomic.AddUint64(&seq, 1))
+	if err := s.exec(fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", name, template)); err != nil {
+		t.Fatalf("[postgres] create database: %v", err)
+	}
+
+	db, err := sql.Open(driver, s.dsn(name))
+	if err != nil {
+		t.Fatalf("[postgres] open database: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		if err := s.exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", name)); err != nil {
+			t.Errorf("[postgres] drop database: %v", err)
+		}
+	})
+	return db
+}
+
+// exec runs the statement on the maintenance database, since a database can't create or drop itself.
+func (s *server) exec(query string) error {
+	db, err := sql.Open(driver, s.dsn("postgres"))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec(query)
+	return err
+}
+
+func (s *server) dsn(database string) string {
+	return fmt.Sprintf("%s dbname=%s", s.conninfo, database)
+}
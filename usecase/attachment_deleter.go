@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// DeleteAttachment defines the business logic
+// to delete an attachment of a medical record.
+type DeleteAttachment interface {
+	// Delete deletes an attachment of the medical record owned by the user.
+	Delete(ctx context.Context, userID, recordID, id uint64) *entity.Error
+}
+
+// DeleteAttachmentRepository defines the business logic
+// to delete an attachment's metadata from a repository.
+type DeleteAttachmentRepository interface {
+	// DoesRecordExist checks whether medical record which has certain id and is owned by the user exists.
+	DoesRecordExist(ctx context.Context, id, userID uint64) (bool, *entity.Error)
+	// Delete deletes an attachment of the medical record and returns its storage key.
+	// The storage key MUST be queued in StorageDeletionRepository in the same unit of work.
+	// It MUST return entity.ErrAttachmentNotFound if the attachment doesn't exist.
+	Delete(ctx context.Context, recordID, id uint64) (string, *entity.Error)
+}
+
+// AttachmentDeleter responsibles for attachment deletion workflow.
+type AttachmentDeleter struct {
+	repo       DeleteAttachmentRepository
+	deletions  StorageDeletionRepository
+	storage    AttachmentStorage
+	transactor Transactor
+}
+
+// NewAttachmentDeleter creates an instance of AttachmentDeleter.
+func NewAttachmentDeleter(repo DeleteAttachmentRepository, deletions StorageDeletionRepository, storage AttachmentStorage, transactor Transactor) *AttachmentDeleter {
+	return &AttachmentDeleter{
+		repo:       repo,
+		deletions:  deletions,
+		storage:    storage,
+		transactor: transactor,
+	}
+}
+
+// Delete deletes the attachment's metadata then its content.
+// The ownership check and the metadata deletion are run in a single unit of work.
+// The content is deleted after the metadata is committed, so a failure never leaves metadata without content.
+// A failure to delete the content is not returned, since its storage key stays queued
+// and the content is deleted later by the erasure worker.
+func (ad *AttachmentDeleter) Delete(ctx context.Context, userID, recordID, id uint64) *entity.Error {
+	var key string
+	err := ad.transactor.WithinTransaction(ctx, func(ctx context.Context) *entity.Error {
+		found, err := ad.repo.DoesRecordExist(ctx, recordID, userID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return entity.ErrMedicalRecordNotFound
+		}
+
+		key, err = ad.repo.Delete(ctx, recordID, id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_ = deleteContents(ctx, ad.storage, ad.deletions, []string{key})
+	return nil
+}
@@ -0,0 +1,105 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type AttachmentDeleterExecutor struct {
+	usecase    *usecase.AttachmentDeleter
+	repo       *mock_usecase.MockDeleteAttachmentRepository
+	deletions  *mock_usecase.MockStorageDeletionRepository
+	storage    *mock_usecase.MockAttachmentStorage
+	transactor *mock_usecase.MockTransactor
+}
+
+func TestNewAttachmentDeleter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of AttachmentDeleter", func(t *testing.T) {
+		exec := createAttachmentDeleterExecutor(ctrl)
+		assert.NotNil(t, exec.usecase)
+	})
+}
+
+func TestAttachmentDeleter_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("medical record is not owned by the user", func(t *testing.T) {
+		exec := createAttachmentDeleterExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(3)).Return(false, nil)
+		err := exec.usecase.Delete(context.Background(), uint64(3), uint64(1), uint64(2))
+
+		assert.Equal(t, entity.ErrMedicalRecordNotFound, err)
+	})
+
+	t.Run("attachment is not found", func(t *testing.T) {
+		exec := createAttachmentDeleterExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(3)).Return(true, nil)
+		exec.repo.EXPECT().Delete(context.Background(), uint64(1), uint64(2)).Return("", entity.ErrAttachmentNotFound)
+		err := exec.usecase.Delete(context.Background(), uint64(3), uint64(1), uint64(2))
+
+		assert.Equal(t, entity.ErrAttachmentNotFound, err)
+	})
+
+	t.Run("successfully delete attachment and its content", func(t *testing.T) {
+		exec := createAttachmentDeleterExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(3)).Return(true, nil)
+		exec.repo.EXPECT().Delete(context.Background(), uint64(1), uint64(2)).Return("medical-records/1/key", nil)
+		exec.storage.EXPECT().Delete(context.Background(), "medical-records/1/key").Return(nil)
+		exec.deletions.EXPECT().Done(context.Background(), "medical-records/1/key").Return(nil)
+		err := exec.usecase.Delete(context.Background(), uint64(3), uint64(1), uint64(2))
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("content which fails to be deleted stays queued", func(t *testing.T) {
+		exec := createAttachmentDeleterExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(3)).Return(true, nil)
+		exec.repo.EXPECT().Delete(context.Background(), uint64(1), uint64(2)).Return("medical-records/1/key", nil)
+		exec.storage.EXPECT().Delete(context.Background(), "medical-records/1/key").Return(entity.ErrInternalServer)
+		err := exec.usecase.Delete(context.Background(), uint64(3), uint64(1), uint64(2))
+
+		assert.Nil(t, err)
+	})
+}
+
+// passThroughTransaction makes the transactor run the unit of work once and return its result.
+func passThroughTransaction(tx *mock_usecase.MockTransactor) {
+	tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) *entity.Error) *entity.Error {
+			return fn(ctx)
+		})
+}
+
+func createAttachmentDeleterExecutor(ctrl *gomock.Controller) *AttachmentDeleterExecutor {
+	r := mock_usecase.NewMockDeleteAttachmentRepository(ctrl)
+	d := mock_usecase.NewMockStorageDeletionRepository(ctrl)
+	s := mock_usecase.NewMockAttachmentStorage(ctrl)
+	tx := mock_usecase.NewMockTransactor(ctrl)
+	u := usecase.NewAttachmentDeleter(r, d, s, tx)
+
+	return &AttachmentDeleterExecutor{
+		usecase:    u,
+		repo:       r,
+		deletions:  d,
+		storage:    s,
+		transactor: tx,
+	}
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"io"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// FindAttachment defines the business logic
+// to find attachments of a medical record.
+type FindAttachment interface {
+	// FindByMedicalRecordID finds all attachments of the medical record owned by the user.
+	FindByMedicalRecordID(ctx context.Context, userID, recordID uint64) ([]*entity.Attachment, *entity.Error)
+	// Download finds an attachment of the medical record owned by the user and returns its content.
+	Download(ctx context.Context, userID, recordID, id uint64) (*entity.Attachment, []byte, *entity.Error)
+}
+
+// FindAttachmentRepository defines the business logic
+// to find attachments' metadata in a repository.
+type FindAttachmentRepository interface {
+	// DoesRecordExist checks whether medical record which has certain id and is owned by the user exists.
+	DoesRecordExist(ctx context.Context, id, userID uint64) (bool, *entity.Error)
+	// FindByMedicalRecordID finds all attachments of the medical record.
+	FindByMedicalRecordID(ctx context.Context, recordID uint64) ([]*entity.Attachment, *entity.Error)
+	// FindByID finds an attachment of the medical record.
+	// It MUST return entity.ErrAttachmentNotFound if the attachment doesn't exist.
+	FindByID(ctx context.Context, recordID, id uint64) (*entity.Attachment, *entity.Error)
+}
+
+// AttachmentFinder responsibles for attachment find workflow.
+type AttachmentFinder struct {
+	repo    FindAttachmentRepository
+	storage AttachmentStorage
+}
+
+// NewAttachmentFinder creates an instance of AttachmentFinder.
+func NewAttachmentFinder(repo FindAttachmentRepository, storage AttachmentStorage) *AttachmentFinder {
+	return &AttachmentFinder{
+		repo:    repo,
+		storage: storage,
+	}
+}
+
+// FindByMedicalRecordID finds all attachments of the medical record.
+// It returns entity.ErrMedicalRecordNotFound if the medical record is not owned by the user.
+func (af *AttachmentFinder) FindByMedicalRecordID(ctx context.Context, userID, recordID uint64) ([]*entity.Attachment, *entity.Error) {
+	if err := af.checkRecord(ctx, userID, recordID); err != nil {
+		return nil, err
+	}
+	return af.repo.FindByMedicalRecordID(ctx, recordID)
+}
+
+// Download finds an attachment and reads its content from the storage.
+// The content is verified against the size and checksum recorded on upload
+// so a corrupted content is never returned.
+func (af *AttachmentFinder) Download(ctx context.Context, userID, recordID, id uint64) (*entity.Attachment, []byte, *entity.Error) {
+	if err := af.checkRecord(ctx, userID, recordID); err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := af.repo.FindByID(ctx, recordID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := af.storage.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer content.Close()
+
+	data, rerr := io.ReadAll(io.LimitReader(content, attachment.Size+1))
+	if rerr != nil {
+		return nil, nil, entity.WrapError(entity.ErrInternalServer, "[AttachmentFinder-Download] read content: "+rerr.Error())
+	}
+	if int64(len(data)) != attachment.Size || attachmentChecksum(data) != attachment.Checksum {
+		// the stored content is corrupted, it is not the client's fault.
+		return nil, nil, entity.WrapError(entity.ErrInternalServer, "[AttachmentFinder-Download] stored content doesn't match its checksum: "+attachment.StorageKey)
+	}
+	return attachment, data, nil
+}
+
+func (af *AttachmentFinder) checkRecord(ctx context.Context, userID, recordID uint64) *entity.Error {
+	found, err := af.repo.DoesRecordExist(ctx, recordID, userID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return entity.ErrMedicalRecordNotFound
+	}
+	return nil
+}
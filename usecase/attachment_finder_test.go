@@ -0,0 +1,150 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type AttachmentFinderExecutor struct {
+	usecase *usecase.AttachmentFinder
+	repo    *mock_usecase.MockFindAttachmentRepository
+	storage *mock_usecase.MockAttachmentStorage
+}
+
+func TestNewAttachmentFinder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of AttachmentFinder", func(t *testing.T) {
+		exec := createAttachmentFinderExecutor(ctrl)
+		assert.NotNil(t, exec.usecase)
+	})
+}
+
+func TestAttachmentFinder_FindByMedicalRecordID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("medical record is not owned by the user", func(t *testing.T) {
+		exec := createAttachmentFinderExecutor(ctrl)
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(3)).Return(false, nil)
+		res, err := exec.usecase.FindByMedicalRecordID(context.Background(), uint64(3), uint64(1))
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrMedicalRecordNotFound, err)
+	})
+
+	t.Run("repository returns error", func(t *testing.T) {
+		exec := createAttachmentFinderExecutor(ctrl)
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(3)).Return(false, entity.ErrInternalServer)
+		res, err := exec.usecase.FindByMedicalRecordID(context.Background(), uint64(3), uint64(1))
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInternalServer, err)
+	})
+
+	t.Run("successfully find attachments", func(t *testing.T) {
+		exec := createAttachmentFinderExecutor(ctrl)
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(3)).Return(true, nil)
+		exec.repo.EXPECT().FindByMedicalRecordID(context.Background(), uint64(1)).Return([]*entity.Attachment{createValidAttachment()}, nil)
+		res, err := exec.usecase.FindByMedicalRecordID(context.Background(), uint64(3), uint64(1))
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+	})
+}
+
+func TestAttachmentFinder_Download(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("attachment is not found", func(t *testing.T) {
+		exec := createAttachmentFinderExecutor(ctrl)
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(3)).Return(true, nil)
+		exec.repo.EXPECT().FindByID(context.Background(), uint64(1), uint64(2)).Return(nil, entity.ErrAttachmentNotFound)
+		att, data, err := exec.usecase.Download(context.Background(), uint64(3), uint64(1), uint64(2))
+
+		assert.Nil(t, att)
+		assert.Nil(t, data)
+		assert.Equal(t, entity.ErrAttachmentNotFound, err)
+	})
+
+	t.Run("storage returns error", func(t *testing.T) {
+		exec := createAttachmentFinderExecutor(ctrl)
+		attachment := createValidAttachment()
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(3)).Return(true, nil)
+		exec.repo.EXPECT().FindByID(context.Background(), uint64(1), uint64(2)).Return(attachment, nil)
+		exec.storage.EXPECT().Get(context.Background(), attachment.StorageKey).Return(nil, entity.ErrAttachmentNotFound)
+		_, _, err := exec.usecase.Download(context.Background(), uint64(3), uint64(1), uint64(2))
+
+		assert.Equal(t, entity.ErrAttachmentNotFound, err)
+	})
+
+	t.Run("stored content is corrupted", func(t *testing.T) {
+		exec := createAttachmentFinderExecutor(ctrl)
+		attachment := createValidAttachment()
+		corrupted := strings.Replace(testPDFContent, "test", "TEST", 1)
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(3)).Return(true, nil)
+		exec.repo.EXPECT().FindByID(context.Background(), uint64(1), uint64(2)).Return(attachment, nil)
+		exec.storage.EXPECT().Get(context.Background(), attachment.StorageKey).Return(io.NopCloser(strings.NewReader(corrupted)), nil)
+		_, _, err := exec.usecase.Download(context.Background(), uint64(3), uint64(1), uint64(2))
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+	})
+
+	t.Run("successfully download attachment", func(t *testing.T) {
+		exec := createAttachmentFinderExecutor(ctrl)
+		attachment := createValidAttachment()
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(3)).Return(true, nil)
+		exec.repo.EXPECT().FindByID(context.Background(), uint64(1), uint64(2)).Return(attachment, nil)
+		exec.storage.EXPECT().Get(context.Background(), attachment.StorageKey).Return(io.NopCloser(strings.NewReader(testPDFContent)), nil)
+		att, data, err := exec.usecase.Download(context.Background(), uint64(3), uint64(1), uint64(2))
+
+		assert.Nil(t, err)
+		assert.Equal(t, attachment, att)
+		assert.Equal(t, testPDFContent, string(data))
+	})
+}
+
+func createValidAttachment() *entity.Attachment {
+	sum := sha256.Sum256([]byte(testPDFContent))
+	return &entity.Attachment{
+		ID:              hashids.ID(2),
+		MedicalRecordID: hashids.ID(1),
+		Filename:        "lab.pdf",
+		ContentType:     "application/pdf",
+		Size:            int64(len(testPDFContent)),
+		Checksum:        hex.EncodeToString(sum[:]),
+		StorageKey:      "medical-records/1/key",
+	}
+}
+
+func createAttachmentFinderExecutor(ctrl *gomock.Controller) *AttachmentFinderExecutor {
+	r := mock_usecase.NewMockFindAttachmentRepository(ctrl)
+	s := mock_usecase.NewMockAttachmentStorage(ctrl)
+	u := usecase.NewAttachmentFinder(r, s)
+
+	return &AttachmentFinderExecutor{
+		usecase: u,
+		repo:    r,
+		storage: s,
+	}
+}
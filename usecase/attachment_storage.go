@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"io"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// AttachmentStorage defines the contract to keep attachment's content.
+// The metadata is kept in repository while the content is kept in storage.
+type AttachmentStorage interface {
+	// Put stores the content under the key.
+	// Existing content under the same key is replaced.
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) *entity.Error
+	// Get returns the content stored under the key.
+	// It MUST return entity.ErrAttachmentNotFound if the key doesn't exist.
+	Get(ctx context.Context, key string) (io.ReadCloser, *entity.Error)
+	// Delete deletes the content stored under the key.
+	// Deleting a key that doesn't exist is not an error.
+	Delete(ctx context.Context, key string) *entity.Error
+}
+
+// StorageDeletionRepository defines the business logic
+// to track the storage keys whose metadata has been deleted but whose content may still be kept in storage.
+// The keys are queued by the repository which deletes the metadata, in the same unit of work.
+type StorageDeletionRepository interface {
+	// FindPending finds at most limit storage keys whose content is still to be deleted, the oldest first.
+	FindPending(ctx context.Context, limit uint) ([]string, *entity.Error)
+	// Done removes the storage key from the queue once its content has been deleted.
+	Done(ctx context.Context, key string) *entity.Error
+}
+
+// deleteContents deletes the content stored under the keys from storage,
+// then removes each key from the queue of deletions.
+// It stops at the first failure, leaving the remaining keys queued to be deleted later.
+func deleteContents(ctx context.Context, storage AttachmentStorage, deletions StorageDeletionRepository, keys []string) *entity.Error {
+	for _, key := range keys {
+		if err := storage.Delete(ctx, key); err != nil {
+			return err
+		}
+		if err := deletions.Done(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+const (
+	// attachmentSniffLength is the number of bytes needed by http.DetectContentType.
+	attachmentSniffLength = 512
+	attachmentKeyLength   = 16
+	// maxFilenameLength is the maximum number of bytes of a filename.
+	maxFilenameLength = 255
+)
+
+var allowedAttachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+}
+
+// UploadAttachment defines the business logic
+// to attach a file to a medical record.
+type UploadAttachment interface {
+	// Upload stores the content as an attachment of the medical record owned by the user.
+	// Attachment must contain the file name and optionally the expected checksum.
+	Upload(ctx context.Context, user *entity.User, recordID uint64, attachment *entity.Attachment, content io.Reader) (*entity.Attachment, *entity.Error)
+}
+
+// UploadAttachmentRepository defines the business logic
+// to insert an attachment's metadata into a repository.
+type UploadAttachmentRepository interface {
+	// DoesRecordExist checks whether medical record which has certain id and is owned by the user exists.
+	DoesRecordExist(ctx context.Context, id, userID uint64) (bool, *entity.Error)
+	// Insert inserts the attachment into the repository.
+	// This operation MUST set the inserted ID back to the attachment object.
+	Insert(ctx context.Context, attachment *entity.Attachment) *entity.Error
+}
+
+// AttachmentUploader responsibles for attachment upload workflow.
+type AttachmentUploader struct {
+	repo    UploadAttachmentRepository
+	storage AttachmentStorage
+	maxSize int64
+}
+
+// NewAttachmentUploader creates an instance of AttachmentUploader.
+// Content larger than maxSize bytes is rejected.
+func NewAttachmentUploader(repo UploadAttachmentRepository, storage AttachmentStorage, maxSize int64) *AttachmentUploader {
+	return &AttachmentUploader{
+		repo:    repo,
+		storage: storage,
+		maxSize: maxSize,
+	}
+}
+
+// Upload validates the content, puts it into the storage, and inserts its metadata into the repository.
+// The content type is sniffed from the content and only PDF and images are allowed.
+// If the attachment has checksum, it must match the SHA-256 of the content.
+func (au *AttachmentUploader) Upload(ctx context.Context, user *entity.User, recordID uint64, attachment *entity.Attachment, content io.Reader) (*entity.Attachment, *entity.Error) {
+	if user == nil {
+		return nil, entity.ErrEmptyUser
+	}
+	if attachment == nil || content == nil {
+		return nil, entity.ErrEmptyAttachment
+	}
+
+	found, err := au.repo.DoesRecordExist(ctx, recordID, uint64(user.ID))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, entity.ErrMedicalRecordNotFound
+	}
+
+	data, rerr := io.ReadAll(io.LimitReader(content, au.maxSize+1))
+	if rerr != nil {
+		return nil, entity.WrapError(entity.ErrInvalidAttachmentRequest, rerr.Error())
+	}
+	if len(data) == 0 {
+		return nil, entity.ErrEmptyAttachment
+	}
+	if int64(len(data)) > au.maxSize {
+		return nil, entity.ErrAttachmentTooLarge
+	}
+
+	contentType := sniffAttachmentType(data)
+	if !allowedAttachmentTypes[contentType] {
+		return nil, entity.WrapError(entity.ErrUnsupportedAttachmentType, "sniffed content type: "+contentType)
+	}
+
+	checksum := attachmentChecksum(data)
+	if attachment.Checksum != "" && !strings.EqualFold(attachment.Checksum, checksum) {
+		return nil, entity.ErrAttachmentChecksumMismatch
+	}
+
+	key, kerr := newAttachmentStorageKey(recordID)
+	if kerr != nil {
+		return nil, entity.WrapError(entity.ErrInternalServer, "[AttachmentUploader-Upload] generate key: "+kerr.Error())
+	}
+
+	result := &entity.Attachment{
+		MedicalRecordID: hashids.ID(recordID),
+		Filename:        sanitizeFilename(attachment.Filename),
+		ContentType:     contentType,
+		Size:            int64(len(data)),
+		Checksum:        checksum,
+		StorageKey:      key,
+		Auditable: entity.Auditable{
+			CreatedBy: user.Email,
+			UpdatedBy: user.Email,
+		},
+	}
+
+	if err := au.storage.Put(ctx, key, bytes.NewReader(data), result.Size, contentType); err != nil {
+		return nil, err
+	}
+	if err := au.repo.Insert(ctx, result); err != nil {
+		// the content is useless without its metadata.
+		au.storage.Delete(ctx, key)
+		return nil, err
+	}
+	return result, nil
+}
+
+func sniffAttachmentType(data []byte) string {
+	if len(data) > attachmentSniffLength {
+		data = data[:attachmentSniffLength]
+	}
+	contentType := http.DetectContentType(data)
+	if idx := strings.Index(contentType, ";"); idx >= 0 {
+		contentType = contentType[:idx]
+	}
+	return contentType
+}
+
+func attachmentChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// newAttachmentStorageKey generates a random key so the original file name is never used as storage path.
+func newAttachmentStorageKey(recordID uint64) (string, error) {
+	buf := make([]byte, attachmentKeyLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("medical-records/%d/%s", recordID, hex.EncodeToString(buf)), nil
+}
+
+// sanitizeFilename keeps only the base name of the file
+// since some clients send the full path.
+// A long name is cut on a character boundary, so it stays valid UTF-8.
+func sanitizeFilename(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = strings.TrimSpace(path.Base("/" + name))
+	if name == "/" || name == "." || name == "" {
+		return "attachment"
+	}
+	if len(name) > maxFilenameLength {
+		cut := maxFilenameLength
+		for cut > 0 && !utf8.RuneStart(name[cut]) {
+			cut--
+		}
+		name = name[:cut]
+	}
+	return name
+}
@@ -0,0 +1,203 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testAttachmentMaxSize = 1024
+	testPDFContent        = "%PDF-1.4\n%test document\n"
+)
+
+type AttachmentUploaderExecutor struct {
+	usecase *usecase.AttachmentUploader
+	repo    *mock_usecase.MockUploadAttachmentRepository
+	storage *mock_usecase.MockAttachmentStorage
+}
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestNewAttachmentUploader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of AttachmentUploader", func(t *testing.T) {
+		exec := createAttachmentUploaderExecutor(ctrl)
+		assert.NotNil(t, exec.usecase)
+	})
+}
+
+func TestAttachmentUploader_Upload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("user or attachment is empty", func(t *testing.T) {
+		exec := createAttachmentUploaderExecutor(ctrl)
+
+		res, err := exec.usecase.Upload(context.Background(), nil, uint64(1), &entity.Attachment{}, strings.NewReader(testPDFContent))
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrEmptyUser, err)
+
+		res, err = exec.usecase.Upload(context.Background(), createValidUser(), uint64(1), nil, strings.NewReader(testPDFContent))
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrEmptyAttachment, err)
+	})
+
+	t.Run("medical record is not found", func(t *testing.T) {
+		exec := createAttachmentUploaderExecutor(ctrl)
+		user := createValidUser()
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(user.ID)).Return(false, nil)
+		res, err := exec.usecase.Upload(context.Background(), user, uint64(1), &entity.Attachment{Filename: "lab.pdf"}, strings.NewReader(testPDFContent))
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrMedicalRecordNotFound, err)
+	})
+
+	t.Run("content is invalid", func(t *testing.T) {
+		tables := []struct {
+			content  string
+			expected *entity.Error
+		}{
+			{"", entity.ErrEmptyAttachment},
+			{strings.Repeat("a", testAttachmentMaxSize+1), entity.ErrAttachmentTooLarge},
+			{"just a plain text", entity.ErrUnsupportedAttachmentType},
+		}
+
+		for _, table := range tables {
+			exec := createAttachmentUploaderExecutor(ctrl)
+			user := createValidUser()
+
+			exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(user.ID)).Return(true, nil)
+			res, err := exec.usecase.Upload(context.Background(), user, uint64(1), &entity.Attachment{Filename: "lab.pdf"}, strings.NewReader(table.content))
+
+			assert.Nil(t, res)
+			assert.Equal(t, table.expected.Code, err.Code)
+		}
+	})
+
+	t.Run("content can't be read", func(t *testing.T) {
+		exec := createAttachmentUploaderExecutor(ctrl)
+		user := createValidUser()
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(user.ID)).Return(true, nil)
+		res, err := exec.usecase.Upload(context.Background(), user, uint64(1), &entity.Attachment{Filename: "lab.pdf"}, failingReader{})
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInvalidAttachmentRequest.Code, err.Code)
+	})
+
+	t.Run("checksum doesn't match", func(t *testing.T) {
+		exec := createAttachmentUploaderExecutor(ctrl)
+		user := createValidUser()
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(user.ID)).Return(true, nil)
+		res, err := exec.usecase.Upload(context.Background(), user, uint64(1), &entity.Attachment{Filename: "lab.pdf", Checksum: "abc"}, strings.NewReader(testPDFContent))
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrAttachmentChecksumMismatch, err)
+	})
+
+	t.Run("storage returns error", func(t *testing.T) {
+		exec := createAttachmentUploaderExecutor(ctrl)
+		user := createValidUser()
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(user.ID)).Return(true, nil)
+		exec.storage.EXPECT().Put(context.Background(), gomock.Any(), gomock.Any(), int64(len(testPDFContent)), "application/pdf").Return(entity.ErrInternalServer)
+		res, err := exec.usecase.Upload(context.Background(), user, uint64(1), &entity.Attachment{Filename: "lab.pdf"}, strings.NewReader(testPDFContent))
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInternalServer, err)
+	})
+
+	t.Run("repository fails to insert and the content is removed from storage", func(t *testing.T) {
+		exec := createAttachmentUploaderExecutor(ctrl)
+		user := createValidUser()
+
+		var key string
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(user.ID)).Return(true, nil)
+		exec.storage.EXPECT().Put(context.Background(), gomock.Any(), gomock.Any(), int64(len(testPDFContent)), "application/pdf").
+			DoAndReturn(func(_ context.Context, k string, _ interface{}, _ int64, _ string) *entity.Error {
+				key = k
+				return nil
+			})
+		exec.repo.EXPECT().Insert(context.Background(), gomock.Any()).Return(entity.ErrInternalServer)
+		exec.storage.EXPECT().Delete(context.Background(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, k string) *entity.Error {
+				assert.Equal(t, key, k)
+				return nil
+			})
+		res, err := exec.usecase.Upload(context.Background(), user, uint64(1), &entity.Attachment{Filename: "lab.pdf"}, strings.NewReader(testPDFContent))
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInternalServer, err)
+	})
+
+	t.Run("successfully upload attachment", func(t *testing.T) {
+		exec := createAttachmentUploaderExecutor(ctrl)
+		user := createValidUser()
+		sum := sha256.Sum256([]byte(testPDFContent))
+		checksum := hex.EncodeToString(sum[:])
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(user.ID)).Return(true, nil)
+		exec.storage.EXPECT().Put(context.Background(), gomock.Any(), gomock.Any(), int64(len(testPDFContent)), "application/pdf").
+			DoAndReturn(func(_ context.Context, k string, r interface{}, _ int64, _ string) *entity.Error {
+				var buf bytes.Buffer
+				buf.ReadFrom(r.(*bytes.Reader))
+				assert.Equal(t, testPDFContent, buf.String())
+				assert.True(t, strings.HasPrefix(k, "medical-records/1/"))
+				return nil
+			})
+		exec.repo.EXPECT().Insert(context.Background(), gomock.Any()).Return(nil)
+		res, err := exec.usecase.Upload(context.Background(), user, uint64(1), &entity.Attachment{Filename: `C:\scans\lab.pdf`, Checksum: strings.ToUpper(checksum)}, strings.NewReader(testPDFContent))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "lab.pdf", res.Filename)
+		assert.Equal(t, "application/pdf", res.ContentType)
+		assert.Equal(t, checksum, res.Checksum)
+		assert.Equal(t, user.Email, res.CreatedBy)
+	})
+
+	t.Run("long filename is cut on a character boundary", func(t *testing.T) {
+		exec := createAttachmentUploaderExecutor(ctrl)
+		user := createValidUser()
+
+		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), uint64(user.ID)).Return(true, nil)
+		exec.storage.EXPECT().Put(context.Background(), gomock.Any(), gomock.Any(), int64(len(testPDFContent)), "application/pdf").Return(nil)
+		exec.repo.EXPECT().Insert(context.Background(), gomock.Any()).Return(nil)
+		res, err := exec.usecase.Upload(context.Background(), user, uint64(1), &entity.Attachment{Filename: "a" + strings.Repeat("ő", 200) + ".pdf"}, strings.NewReader(testPDFContent))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "a"+strings.Repeat("ő", 127), res.Filename)
+		assert.True(t, utf8.ValidString(res.Filename))
+	})
+}
+
+func createAttachmentUploaderExecutor(ctrl *gomock.Controller) *AttachmentUploaderExecutor {
+	r := mock_usecase.NewMockUploadAttachmentRepository(ctrl)
+	s := mock_usecase.NewMockAttachmentStorage(ctrl)
+	u := usecase.NewAttachmentUploader(r, s, testAttachmentMaxSize)
+
+	return &AttachmentUploaderExecutor{
+		usecase: u,
+		repo:    r,
+		storage: s,
+	}
+}
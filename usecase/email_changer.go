@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+const (
+	// emailChangeTokenSize is the number of random bytes of the token verifying the new email.
+	emailChangeTokenSize = 32
+	// emailChangeTokenPlaceholder is replaced by the token in the verification link.
+	emailChangeTokenPlaceholder = "{token}"
+
+	emailChangeSubject = "Verify your new email for Orvosi"
+)
+
+// ChangeEmail defines the business logic
+// to change the email of a user after the new email is verified.
+type ChangeEmail interface {
+	// Request sends a token to the new email, which must be verified to change the email of the user.
+	Request(ctx context.Context, user *entity.User, email string) (*entity.EmailChange, *entity.Error)
+	// Verify changes the email of the user if the token matches the latest requested change.
+	Verify(ctx context.Context, user *entity.User, token string) (*entity.User, *entity.Error)
+}
+
+// FindEmailChangeRepository defines the business logic
+// to find the email change request of a user in a repository.
+type FindEmailChangeRepository interface {
+	// FindByUserID finds the email change request of the user.
+	// It MUST return entity.ErrInvalidEmailChangeToken if there is none.
+	FindByUserID(ctx context.Context, userID uint64) (*entity.EmailChange, *entity.Error)
+}
+
+// ChangeEmailRepository defines the business logic
+// to keep the email change requests and change the email of a user in a repository.
+type ChangeEmailRepository interface {
+	FindEmailChangeRepository
+	// Save inserts the email change request, replacing the one the user has requested before, if any.
+	Save(ctx context.Context, change *entity.EmailChange) *entity.Error
+	// ChangeEmail replaces the email of the user who has the id with email to and deletes their email change request.
+	// The audit actor fields which refer to email from are replaced as well.
+	// It MUST return entity.ErrEmailAlreadyUsed if email to belongs to another user.
+	ChangeEmail(ctx context.Context, userID uint64, from, to string) *entity.Error
+}
+
+// SendEmail defines the contract to send an email.
+type SendEmail interface {
+	// Send sends the email with the subject and plain text body to the address.
+	Send(ctx context.Context, to, subject, body string) *entity.Error
+}
+
+// EmailChanger responsibles for email change workflow.
+type EmailChanger struct {
+	repo       ChangeEmailRepository
+	users      FindUserRepository
+	emails     ValidateEmail
+	sender     SendEmail
+	transactor Transactor
+	ttl        time.Duration
+	link       string
+}
+
+// NewEmailChanger creates an instance of EmailChanger.
+// The emails validates the new email, and the token sent to it is valid for ttl.
+// If link is set, the email contains the link with "{token}" replaced by the token. Otherwise, it contains only the token.
+func NewEmailChanger(repo ChangeEmailRepository, users FindUserRepository, emails ValidateEmail, sender SendEmail, transactor Transactor, ttl time.Duration, link string) *EmailChanger {
+	return &EmailChanger{
+		repo:       repo,
+		users:      users,
+		emails:     emails,
+		sender:     sender,
+		transactor: transactor,
+		ttl:        ttl,
+		link:       link,
+	}
+}
+
+// Request validates the new email, keeps the request along with the hash of a random token, then sends the token to the new email.
+// Requesting again replaces the previous request, so only the latest token can be verified.
+// It returns entity.ErrEmailAlreadyUsed if the new email belongs to another user.
+func (ec *EmailChanger) Request(ctx context.Context, user *entity.User, email string) (*entity.EmailChange, *entity.Error) {
+	if user == nil {
+		return nil, entity.ErrEmptyUser
+	}
+
+	email = strings.TrimSpace(email)
+	if err := ec.emails.Validate(ctx, email); err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(email, user.Email) {
+		return nil, entity.WrapError(entity.ErrInvalidEmailChangeRequest, "[EmailChanger-Request] new email is the current one")
+	}
+
+	_, err := ec.users.FindByEmail(ctx, email)
+	if err == nil {
+		return nil, entity.ErrEmailAlreadyUsed
+	}
+	if err.Code != entity.ErrUserNotFound.Code {
+		return nil, err
+	}
+
+	token, terr := newEmailChangeToken()
+	if terr != nil {
+		return nil, entity.WrapError(entity.ErrInternalServer, "[EmailChanger-Request] generate token: "+terr.Error())
+	}
+
+	now := time.Now().UTC()
+	change := &entity.EmailChange{
+		UserID:      user.ID,
+		Email:       email,
+		TokenHash:   hashEmailChangeToken(token),
+		RequestedAt: now,
+		ExpiresAt:   now.Add(ec.ttl),
+	}
+	if err := ec.repo.Save(ctx, change); err != nil {
+		return nil, err
+	}
+
+	if err := ec.sender.Send(ctx, email, emailChangeSubject, ec.body(token)); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// Verify changes the email of the user into the requested one if the token matches and hasn't expired.
+// The email is checked once more, since another user may have taken it after the request.
+// It returns entity.ErrInvalidEmailChangeToken if the token is wrong, has expired, or no change is requested.
+func (ec *EmailChanger) Verify(ctx context.Context, user *entity.User, token string) (*entity.User, *entity.Error) {
+	if user == nil {
+		return nil, entity.ErrEmptyUser
+	}
+	if token == "" {
+		return nil, entity.ErrInvalidEmailChangeToken
+	}
+
+	var result *entity.User
+	err := ec.transactor.WithinTransaction(ctx, func(ctx context.Context) *entity.Error {
+		change, err := ec.repo.FindByUserID(ctx, uint64(user.ID))
+		if err != nil {
+			return err
+		}
+		hash := hashEmailChangeToken(token)
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(change.TokenHash)) != 1 || !time.Now().Before(change.ExpiresAt) {
+			return entity.ErrInvalidEmailChangeToken
+		}
+
+		if err := ec.repo.ChangeEmail(ctx, uint64(user.ID), user.Email, change.Email); err != nil {
+			return err
+		}
+		result, err = ec.users.FindByEmail(ctx, change.Email)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (ec *EmailChanger) body(token string) string {
+	verify := "Use this token to verify your new email: " + token
+	if ec.link != "" {
+		verify = "Open this link to verify your new email: " + strings.ReplaceAll(ec.link, emailChangeTokenPlaceholder, token)
+	}
+	return verify + "\n\nIt expires in " + ec.ttl.String() + ". If you didn't ask to change your email, ignore this email."
+}
+
+func newEmailChangeToken() (string, error) {
+	buf := make([]byte, emailChangeTokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashEmailChangeToken hashes the token, so the stored one can't be used if the repository leaks.
+func hashEmailChangeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,282 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type EmailChangerExecutor struct {
+	usecase    *usecase.EmailChanger
+	repo       *mock_usecase.MockChangeEmailRepository
+	users      *mock_usecase.MockFindUserRepository
+	emails     *mock_usecase.MockValidateEmail
+	sender     *mock_usecase.MockSendEmail
+	transactor *mock_usecase.MockTransactor
+}
+
+func TestNewEmailChanger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of EmailChanger", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+		assert.NotNil(t, exec.usecase)
+	})
+}
+
+func TestEmailChanger_Request(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &entity.User{ID: hashids.ID(1), Email: "old@email.com"}
+
+	t.Run("user is empty/nil", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+
+		res, err := exec.usecase.Request(context.Background(), nil, "new@email.com")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrEmptyUser, err)
+	})
+
+	t.Run("new email is invalid", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+
+		exec.emails.EXPECT().Validate(context.Background(), "new@").Return(entity.ErrInvalidEmail)
+		res, err := exec.usecase.Request(context.Background(), user, " new@ ")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInvalidEmail, err)
+	})
+
+	t.Run("new email is the current one", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+
+		exec.emails.EXPECT().Validate(context.Background(), "OLD@email.com").Return(nil)
+		res, err := exec.usecase.Request(context.Background(), user, "OLD@email.com")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInvalidEmailChangeRequest.Code, err.Code)
+	})
+
+	t.Run("new email belongs to another user", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+
+		exec.emails.EXPECT().Validate(context.Background(), "new@email.com").Return(nil)
+		exec.users.EXPECT().FindByEmail(context.Background(), "new@email.com").Return(&entity.User{ID: 2}, nil)
+		res, err := exec.usecase.Request(context.Background(), user, "new@email.com")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrEmailAlreadyUsed, err)
+	})
+
+	t.Run("find user returns error", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+
+		exec.emails.EXPECT().Validate(context.Background(), "new@email.com").Return(nil)
+		exec.users.EXPECT().FindByEmail(context.Background(), "new@email.com").Return(nil, entity.ErrInternalServer)
+		res, err := exec.usecase.Request(context.Background(), user, "new@email.com")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInternalServer, err)
+	})
+
+	t.Run("save returns error", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+
+		exec.emails.EXPECT().Validate(context.Background(), "new@email.com").Return(nil)
+		exec.users.EXPECT().FindByEmail(context.Background(), "new@email.com").Return(nil, entity.ErrUserNotFound)
+		exec.repo.EXPECT().Save(context.Background(), gomock.Any()).Return(entity.ErrInternalServer)
+		res, err := exec.usecase.Request(context.Background(), user, "new@email.com")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInternalServer, err)
+	})
+
+	t.Run("send returns error", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+
+		exec.emails.EXPECT().Validate(context.Background(), "new@email.com").Return(nil)
+		exec.users.EXPECT().FindByEmail(context.Background(), "new@email.com").Return(nil, entity.ErrUserNotFound)
+		exec.repo.EXPECT().Save(context.Background(), gomock.Any()).Return(nil)
+		exec.sender.EXPECT().Send(context.Background(), "new@email.com", gomock.Any(), gomock.Any()).Return(entity.ErrServiceUnavailable)
+		res, err := exec.usecase.Request(context.Background(), user, "new@email.com")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrServiceUnavailable, err)
+	})
+
+	t.Run("successfully send the token whose hash is kept", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+
+		var saved *entity.EmailChange
+		var body string
+		exec.emails.EXPECT().Validate(context.Background(), "new@email.com").Return(nil)
+		exec.users.EXPECT().FindByEmail(context.Background(), "new@email.com").Return(nil, entity.ErrUserNotFound)
+		exec.repo.EXPECT().Save(context.Background(), gomock.Any()).DoAndReturn(func(_ context.Context, change *entity.EmailChange) *entity.Error {
+			saved = change
+			return nil
+		})
+		exec.sender.EXPECT().Send(context.Background(), "new@email.com", gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _, _, b string) *entity.Error {
+			body = b
+			return nil
+		})
+		res, err := exec.usecase.Request(context.Background(), user, "new@email.com")
+
+		assert.Nil(t, err)
+		assert.Equal(t, saved, res)
+		assert.Equal(t, hashids.ID(1), res.UserID)
+		assert.Equal(t, "new@email.com", res.Email)
+		assert.Equal(t, 24*time.Hour, res.ExpiresAt.Sub(res.RequestedAt))
+		assert.Equal(t, hashToken(tokenIn(body, "token to verify your new email: ")), res.TokenHash)
+	})
+
+	t.Run("successfully send the verification link", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "https://app.orvosi.com/verify-email?token={token}")
+
+		var body string
+		exec.emails.EXPECT().Validate(context.Background(), "new@email.com").Return(nil)
+		exec.users.EXPECT().FindByEmail(context.Background(), "new@email.com").Return(nil, entity.ErrUserNotFound)
+		exec.repo.EXPECT().Save(context.Background(), gomock.Any()).Return(nil)
+		exec.sender.EXPECT().Send(context.Background(), "new@email.com", gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _, _, b string) *entity.Error {
+			body = b
+			return nil
+		})
+		res, err := exec.usecase.Request(context.Background(), user, "new@email.com")
+
+		assert.Nil(t, err)
+		assert.Equal(t, hashToken(tokenIn(body, "https://app.orvosi.com/verify-email?token=")), res.TokenHash)
+	})
+}
+
+func TestEmailChanger_Verify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &entity.User{ID: hashids.ID(1), Email: "old@email.com"}
+	change := &entity.EmailChange{
+		UserID:    hashids.ID(1),
+		Email:     "new@email.com",
+		TokenHash: hashToken("token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("user is empty/nil", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+
+		res, err := exec.usecase.Verify(context.Background(), nil, "token")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrEmptyUser, err)
+	})
+
+	t.Run("token is empty", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+
+		res, err := exec.usecase.Verify(context.Background(), user, "")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInvalidEmailChangeToken, err)
+	})
+
+	t.Run("email change is not requested", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+		passThroughTransaction(exec.transactor)
+
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(1)).Return(nil, entity.ErrInvalidEmailChangeToken)
+		res, err := exec.usecase.Verify(context.Background(), user, "token")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInvalidEmailChangeToken, err)
+	})
+
+	t.Run("token doesn't match", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+		passThroughTransaction(exec.transactor)
+
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(1)).Return(change, nil)
+		res, err := exec.usecase.Verify(context.Background(), user, "wrong-token")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInvalidEmailChangeToken, err)
+	})
+
+	t.Run("token has expired", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+		passThroughTransaction(exec.transactor)
+
+		expired := *change
+		expired.ExpiresAt = time.Now().Add(-time.Second)
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(1)).Return(&expired, nil)
+		res, err := exec.usecase.Verify(context.Background(), user, "token")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInvalidEmailChangeToken, err)
+	})
+
+	t.Run("new email has been taken by another user", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+		passThroughTransaction(exec.transactor)
+
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(1)).Return(change, nil)
+		exec.repo.EXPECT().ChangeEmail(context.Background(), uint64(1), "old@email.com", "new@email.com").Return(entity.ErrEmailAlreadyUsed)
+		res, err := exec.usecase.Verify(context.Background(), user, "token")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrEmailAlreadyUsed, err)
+	})
+
+	t.Run("successfully change the email", func(t *testing.T) {
+		exec := createEmailChangerExecutor(ctrl, "")
+		passThroughTransaction(exec.transactor)
+
+		changed := &entity.User{ID: hashids.ID(1), Email: "new@email.com"}
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(1)).Return(change, nil)
+		exec.repo.EXPECT().ChangeEmail(context.Background(), uint64(1), "old@email.com", "new@email.com").Return(nil)
+		exec.users.EXPECT().FindByEmail(context.Background(), "new@email.com").Return(changed, nil)
+		res, err := exec.usecase.Verify(context.Background(), user, "token")
+
+		assert.Nil(t, err)
+		assert.Equal(t, changed, res)
+	})
+}
+
+// tokenIn returns the token which follows the prefix in the body of the email.
+func tokenIn(body, prefix string) string {
+	token := body[strings.Index(body, prefix)+len(prefix):]
+	return token[:strings.Index(token, "\n")]
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func createEmailChangerExecutor(ctrl *gomock.Controller, link string) *EmailChangerExecutor {
+	r := mock_usecase.NewMockChangeEmailRepository(ctrl)
+	f := mock_usecase.NewMockFindUserRepository(ctrl)
+	e := mock_usecase.NewMockValidateEmail(ctrl)
+	s := mock_usecase.NewMockSendEmail(ctrl)
+	tx := mock_usecase.NewMockTransactor(ctrl)
+	u := usecase.NewEmailChanger(r, f, e, s, tx, 24*time.Hour, link)
+
+	return &EmailChangerExecutor{
+		usecase:    u,
+		repo:       r,
+		users:      f,
+		emails:     e,
+		sender:     s,
+		transactor: tx,
+	}
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+var emailRegex = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+
+// ValidateEmail defines the business logic
+// to validate an email.
+type ValidateEmail interface {
+	// Validate returns ErrInvalidEmail if the email is invalid.
+	Validate(ctx context.Context, email string) *entity.Error
+}
+
+// EmailSyntaxValidator validates the syntax of email without any network call.
+// It is enough for the email taken from a verified token.
+type EmailSyntaxValidator struct{}
+
+// NewEmailSyntaxValidator creates an instance of EmailSyntaxValidator.
+func NewEmailSyntaxValidator() *EmailSyntaxValidator {
+	return &EmailSyntaxValidator{}
+}
+
+// Validate validates the email using regex "^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$".
+func (EmailSyntaxValidator) Validate(ctx context.Context, email string) *entity.Error {
+	if !emailRegex.MatchString(email) {
+		return entity.ErrInvalidEmail
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEmailSyntaxValidator(t *testing.T) {
+	t.Run("successfully create an instance of EmailSyntaxValidator", func(t *testing.T) {
+		assert.NotNil(t, usecase.NewEmailSyntaxValidator())
+	})
+}
+
+func TestEmailSyntaxValidator_Validate(t *testing.T) {
+	t.Run("email with invalid syntax", func(t *testing.T) {
+		emails := []string{"", "dummy", "@dummy.com", "dummy@", "dummy@-dummy.com", "dummy@dummy..com"}
+
+		for _, email := range emails {
+			err := usecase.NewEmailSyntaxValidator().Validate(context.Background(), email)
+			assert.Equal(t, entity.ErrInvalidEmail, err, email)
+		}
+	})
+
+	t.Run("email with valid syntax is valid without network", func(t *testing.T) {
+		emails := []string{"dummy@dummy.com", "dummy+tag@dummy-domain.com", "d.u.m.m.y@sub.dummy.co.id"}
+
+		for _, email := range emails {
+			err := usecase.NewEmailSyntaxValidator().Validate(context.Background(), email)
+			assert.Nil(t, err, email)
+		}
+	})
+}
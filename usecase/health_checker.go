@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// CheckHealth defines the business logic to check whether the system can serve requests.
+type CheckHealth interface {
+	// Check returns error if any dependency of the system is unhealthy.
+	Check(ctx context.Context) *entity.Error
+	// Ready returns error if the system shouldn't receive new traffic,
+	// either because it is shutting down or because Check fails.
+	Ready(ctx context.Context) *entity.Error
+}
+
+// ShutdownState tells whether the system is shutting down.
+type ShutdownState interface {
+	// Draining returns true once the shutdown has started.
+	Draining() bool
+}
+
+// PingRepository defines the business logic
+// to check the connection to a repository.
+type PingRepository interface {
+	// Ping returns error if the repository can't be reached.
+	Ping(ctx context.Context) *entity.Error
+}
+
+// HealthChecker responsibles for health check workflow.
+type HealthChecker struct {
+	repo  PingRepository
+	state ShutdownState
+}
+
+// NewHealthChecker creates an instance of HealthChecker.
+func NewHealthChecker(repo PingRepository, state ShutdownState) *HealthChecker {
+	return &HealthChecker{
+		repo:  repo,
+		state: state,
+	}
+}
+
+// Check checks the connection to the repository.
+func (hc *HealthChecker) Check(ctx context.Context) *entity.Error {
+	if err := hc.repo.Ping(ctx); err != nil {
+		return entity.WrapError(entity.ErrServiceUnavailable, err.Error())
+	}
+	return nil
+}
+
+// Ready checks that the system isn't shutting down and the repository can be reached.
+func (hc *HealthChecker) Ready(ctx context.Context) *entity.Error {
+	if hc.state.Draining() {
+		return entity.ErrShuttingDown
+	}
+	return hc.Check(ctx)
+}
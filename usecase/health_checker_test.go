@@ -0,0 +1,98 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type HealthCheckerExecutor struct {
+	usecase *usecase.HealthChecker
+	repo    *mock_usecase.MockPingRepository
+	state   *mock_usecase.MockShutdownState
+}
+
+func TestNewHealthChecker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of HealthChecker", func(t *testing.T) {
+		exec := createHealthCheckerExecutor(ctrl)
+		assert.NotNil(t, exec.usecase)
+	})
+}
+
+func TestHealthChecker_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("repository is unreachable", func(t *testing.T) {
+		exec := createHealthCheckerExecutor(ctrl)
+		exec.repo.EXPECT().Ping(context.Background()).Return(entity.ErrInternalServer)
+
+		err := exec.usecase.Check(context.Background())
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrServiceUnavailable.Code, err.Code)
+	})
+
+	t.Run("repository is reachable", func(t *testing.T) {
+		exec := createHealthCheckerExecutor(ctrl)
+		exec.repo.EXPECT().Ping(context.Background()).Return(nil)
+
+		err := exec.usecase.Check(context.Background())
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestHealthChecker_Ready(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("system is shutting down", func(t *testing.T) {
+		exec := createHealthCheckerExecutor(ctrl)
+		exec.state.EXPECT().Draining().Return(true)
+
+		err := exec.usecase.Ready(context.Background())
+
+		assert.Equal(t, entity.ErrShuttingDown, err)
+	})
+
+	t.Run("repository is unreachable", func(t *testing.T) {
+		exec := createHealthCheckerExecutor(ctrl)
+		exec.state.EXPECT().Draining().Return(false)
+		exec.repo.EXPECT().Ping(context.Background()).Return(entity.ErrInternalServer)
+
+		err := exec.usecase.Ready(context.Background())
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrServiceUnavailable.Code, err.Code)
+	})
+
+	t.Run("system is ready", func(t *testing.T) {
+		exec := createHealthCheckerExecutor(ctrl)
+		exec.state.EXPECT().Draining().Return(false)
+		exec.repo.EXPECT().Ping(context.Background()).Return(nil)
+
+		err := exec.usecase.Ready(context.Background())
+
+		assert.Nil(t, err)
+	})
+}
+
+func createHealthCheckerExecutor(ctrl *gomock.Controller) *HealthCheckerExecutor {
+	r := mock_usecase.NewMockPingRepository(ctrl)
+	s := mock_usecase.NewMockShutdownState(ctrl)
+	u := usecase.NewHealthChecker(r, s)
+	return &HealthCheckerExecutor{
+		usecase: u,
+		repo:    r,
+		state:   s,
+	}
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// LinkIdentity defines the business logic
+// to manage the identities which a user signs in with.
+type LinkIdentity interface {
+	// Link links the identity of the ID token to the user, so the user can sign in using it as well.
+	Link(ctx context.Context, userID uint64, idToken string) (*entity.Identity, *entity.Error)
+	// FindAll finds all identities of the user.
+	FindAll(ctx context.Context, userID uint64) ([]*entity.Identity, *entity.Error)
+	// Unlink unlinks the identity from the user.
+	Unlink(ctx context.Context, userID, id uint64) *entity.Error
+}
+
+// FindIdentityRepository defines the business logic
+// to find the identities of a user in a repository.
+type FindIdentityRepository interface {
+	// FindByUserID finds all identities of the user ordered by id.
+	FindByUserID(ctx context.Context, userID uint64) ([]*entity.Identity, *entity.Error)
+}
+
+// LinkIdentityRepository defines the business logic
+// to keep the identities of users in a repository.
+type LinkIdentityRepository interface {
+	FindIdentityRepository
+	// Link inserts the identity. This operation MUST set the inserted ID and CreatedAt back to the identity.
+	// It MUST return entity.ErrIdentityAlreadyLinked if the provider and subject are already linked.
+	Link(ctx context.Context, identity *entity.Identity) *entity.Error
+	// Unlink deletes the identity of the user.
+	// If the Google ID of the user is the subject of the identity,
+	// it is replaced by the subject of another identity of the user, so it always refers to one of them.
+	// It MUST return entity.ErrIdentityNotFound if the user has no such identity.
+	Unlink(ctx context.Context, userID, id uint64) *entity.Error
+}
+
+// DecodeIDToken defines the contract to verify an ID token
+// and decode it into the user it belongs to.
+type DecodeIDToken interface {
+	// Decode returns the user whose GoogleID is the subject of the token.
+	Decode(token string) (*entity.User, *entity.Error)
+}
+
+// IdentityLinker responsibles for identity linking workflow.
+type IdentityLinker struct {
+	repo       LinkIdentityRepository
+	decoder    DecodeIDToken
+	transactor Transactor
+}
+
+// NewIdentityLinker creates an instance of IdentityLinker.
+func NewIdentityLinker(repo LinkIdentityRepository, decoder DecodeIDToken, transactor Transactor) *IdentityLinker {
+	return &IdentityLinker{
+		repo:       repo,
+		decoder:    decoder,
+		transactor: transactor,
+	}
+}
+
+// Link verifies the ID token, then links its identity to the user.
+// Since the token must be signed by the provider, the user proves that they own the identity as well.
+// Linking an identity which the user already has returns that identity.
+// It returns entity.ErrIdentityAlreadyLinked if the identity belongs to another user.
+func (il *IdentityLinker) Link(ctx context.Context, userID uint64, idToken string) (*entity.Identity, *entity.Error) {
+	if idToken == "" {
+		return nil, entity.ErrInvalidIdentityRequest
+	}
+	owner, err := il.decoder.Decode(idToken)
+	if err != nil {
+		return nil, entity.WrapError(entity.ErrInvalidIdentityRequest, err.Error())
+	}
+
+	var result *entity.Identity
+	err = il.transactor.WithinTransaction(ctx, func(ctx context.Context) *entity.Error {
+		identities, err := il.repo.FindByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, identity := range identities {
+			if identity.Provider == entity.ProviderGoogle && identity.Subject == owner.GoogleID {
+				result = identity
+				return nil
+			}
+		}
+
+		result = &entity.Identity{
+			UserID:   hashids.ID(userID),
+			Provider: entity.ProviderGoogle,
+			Subject:  owner.GoogleID,
+			Email:    owner.Email,
+		}
+		return il.repo.Link(ctx, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindAll finds all identities of the user ordered by id.
+func (il *IdentityLinker) FindAll(ctx context.Context, userID uint64) ([]*entity.Identity, *entity.Error) {
+	return il.repo.FindByUserID(ctx, userID)
+}
+
+// Unlink unlinks the identity from the user.
+// It returns entity.ErrLastIdentity if it is the only identity of the user,
+// since the user couldn't sign in anymore.
+func (il *IdentityLinker) Unlink(ctx context.Context, userID, id uint64) *entity.Error {
+	return il.transactor.WithinTransaction(ctx, func(ctx context.Context) *entity.Error {
+		identities, err := il.repo.FindByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, identity := range identities {
+			found = found || uint64(identity.ID) == id
+		}
+		if !found {
+			return entity.ErrIdentityNotFound
+		}
+		if len(identities) == 1 {
+			return entity.ErrLastIdentity
+		}
+		return il.repo.Unlink(ctx, userID, id)
+	})
+}
@@ -0,0 +1,188 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type IdentityLinkerExecutor struct {
+	usecase    *usecase.IdentityLinker
+	repo       *mock_usecase.MockLinkIdentityRepository
+	decoder    *mock_usecase.MockDecodeIDToken
+	transactor *mock_usecase.MockTransactor
+}
+
+func TestNewIdentityLinker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of IdentityLinker", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor(ctrl)
+		assert.NotNil(t, exec.usecase)
+	})
+}
+
+func TestIdentityLinker_Link(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	owner := &entity.User{Email: "second@email.com", GoogleID: "second-google-id"}
+
+	t.Run("id token is empty", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor(ctrl)
+
+		res, err := exec.usecase.Link(context.Background(), uint64(1), "")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInvalidIdentityRequest, err)
+	})
+
+	t.Run("id token is invalid", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor(ctrl)
+
+		exec.decoder.EXPECT().Decode("token").Return(nil, entity.ErrInvalidGoogleToken)
+		res, err := exec.usecase.Link(context.Background(), uint64(1), "token")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInvalidIdentityRequest.Code, err.Code)
+	})
+
+	t.Run("find identities returns error", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.decoder.EXPECT().Decode("token").Return(owner, nil)
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(1)).Return(nil, entity.ErrInternalServer)
+		res, err := exec.usecase.Link(context.Background(), uint64(1), "token")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInternalServer, err)
+	})
+
+	t.Run("identity which the user already has is returned as is", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		linked := &entity.Identity{ID: 5, UserID: 1, Provider: entity.ProviderGoogle, Subject: "second-google-id"}
+		exec.decoder.EXPECT().Decode("token").Return(owner, nil)
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(1)).Return([]*entity.Identity{linked}, nil)
+		res, err := exec.usecase.Link(context.Background(), uint64(1), "token")
+
+		assert.Nil(t, err)
+		assert.Equal(t, linked, res)
+	})
+
+	t.Run("identity is linked to another user", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.decoder.EXPECT().Decode("token").Return(owner, nil)
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(1)).Return([]*entity.Identity{{ID: 4, Subject: "first-google-id"}}, nil)
+		exec.repo.EXPECT().Link(context.Background(), gomock.Any()).Return(entity.ErrIdentityAlreadyLinked)
+		res, err := exec.usecase.Link(context.Background(), uint64(1), "token")
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrIdentityAlreadyLinked, err)
+	})
+
+	t.Run("successfully link the identity", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.decoder.EXPECT().Decode("token").Return(owner, nil)
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(1)).Return([]*entity.Identity{{ID: 4, Subject: "first-google-id"}}, nil)
+		exec.repo.EXPECT().Link(context.Background(), &entity.Identity{
+			UserID:   hashids.ID(1),
+			Provider: entity.ProviderGoogle,
+			Subject:  "second-google-id",
+			Email:    "second@email.com",
+		}).Return(nil)
+		res, err := exec.usecase.Link(context.Background(), uint64(1), "token")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "second-google-id", res.Subject)
+	})
+}
+
+func TestIdentityLinker_FindAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully find all identities", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor(ctrl)
+
+		identities := []*entity.Identity{{ID: 4}, {ID: 5}}
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(1)).Return(identities, nil)
+		res, err := exec.usecase.FindAll(context.Background(), uint64(1))
+
+		assert.Nil(t, err)
+		assert.Equal(t, identities, res)
+	})
+}
+
+func TestIdentityLinker_Unlink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("find identities returns error", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(1)).Return(nil, entity.ErrInternalServer)
+		err := exec.usecase.Unlink(context.Background(), uint64(1), uint64(5))
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+	})
+
+	t.Run("identity doesn't belong to the user", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(1)).Return([]*entity.Identity{{ID: 4}}, nil)
+		err := exec.usecase.Unlink(context.Background(), uint64(1), uint64(5))
+
+		assert.Equal(t, entity.ErrIdentityNotFound, err)
+	})
+
+	t.Run("the only identity can't be unlinked", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(1)).Return([]*entity.Identity{{ID: 5}}, nil)
+		err := exec.usecase.Unlink(context.Background(), uint64(1), uint64(5))
+
+		assert.Equal(t, entity.ErrLastIdentity, err)
+	})
+
+	t.Run("successfully unlink the identity", func(t *testing.T) {
+		exec := createIdentityLinkerExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(1)).Return([]*entity.Identity{{ID: 4}, {ID: 5}}, nil)
+		exec.repo.EXPECT().Unlink(context.Background(), uint64(1), uint64(5)).Return(nil)
+		err := exec.usecase.Unlink(context.Background(), uint64(1), uint64(5))
+
+		assert.Nil(t, err)
+	})
+}
+
+func createIdentityLinkerExecutor(ctrl *gomock.Controller) *IdentityLinkerExecutor {
+	r := mock_usecase.NewMockLinkIdentityRepository(ctrl)
+	d := mock_usecase.NewMockDecodeIDToken(ctrl)
+	tx := mock_usecase.NewMockTransactor(ctrl)
+	u := usecase.NewIdentityLinker(r, d, tx)
+
+	return &IdentityLinkerExecutor{
+		usecase:    u,
+		repo:       r,
+		decoder:    d,
+		transactor: tx,
+	}
+}
@@ -2,27 +2,26 @@ package usecase
 
 import (
 	"context"
-	"net"
-	"regexp"
-	"strings"
+	"time"
 
 	"github.com/indrasaputra/orvosi-api/entity"
 )
 
-const defaultLimit = 10
-
-var emailRegex = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
-
 // FindMedicalRecord defines the business logic
 // to find a medical record.
 type FindMedicalRecord interface {
-	// FindByID finds all medical records by its ID and validated by user's email.
-	// Only medical record that is owned by the right user's email can be retrieved.
-	// If the medical record is not owned by the user, it will return ErrUnauthorized.
-	FindByID(ctx context.Context, id uint64, email string) (*entity.MedicalRecord, *entity.Error)
-	// FindByEmail finds medical records that belong to specific user (based on email).
+	// FindByID finds all medical records by its ID and validated by the user who owns it.
+	// Only medical record that is owned by the user can be retrieved.
+	// If the medical record is not owned by the user, it will return ErrForbidden.
+	FindByID(ctx context.Context, id, userID uint64) (*entity.MedicalRecord, *entity.Error)
+	// FindByUserID finds medical records that belong to specific user.
 	// It also receives `from` which defines the starting point of the records.
-	FindByEmail(ctx context.Context, email string, from uint64) ([]*entity.MedicalRecord, *entity.Error)
+	FindByUserID(ctx context.Context, userID, from uint64) ([]*entity.MedicalRecord, *entity.Error)
+	// FindByUserIDWithinPeriod finds a page of medical records that belong to specific user
+	// and are created at or after since and before until.
+	// It also receives `from` which defines the starting point of the records,
+	// and tells whether there are more records after the page.
+	FindByUserIDWithinPeriod(ctx context.Context, userID uint64, since, until time.Time, from uint64) ([]*entity.MedicalRecord, bool, *entity.Error)
 }
 
 // FindMedicalRecordRepository defines the business logic
@@ -30,56 +29,70 @@ type FindMedicalRecord interface {
 type FindMedicalRecordRepository interface {
 	// FindByID finds medical records by its id.
 	FindByID(ctx context.Context, id uint64) (*entity.MedicalRecord, *entity.Error)
-	// FindByEmail finds all medical records bounded to specific email.
-	FindByEmail(ctx context.Context, email string, from uint64, limit uint) ([]*entity.MedicalRecord, *entity.Error)
+	// FindByUserID finds all medical records bounded to specific user.
+	FindByUserID(ctx context.Context, userID, from uint64, limit uint) ([]*entity.MedicalRecord, *entity.Error)
+	// FindByUserIDWithinPeriod finds all medical records bounded to specific user
+	// and created within [since, until).
+	FindByUserIDWithinPeriod(ctx context.Context, userID uint64, since, until time.Time, from uint64, limit uint) ([]*entity.MedicalRecord, *entity.Error)
+}
+
+// RuntimeSettings defines the settings
+// which can change while the application runs.
+type RuntimeSettings interface {
+	// PageSize returns the number of records in a page.
+	PageSize() uint
 }
 
 // MedicalRecordFinder responsibles for medical record find workflow.
 type MedicalRecordFinder struct {
-	repo FindMedicalRecordRepository
+	repo     FindMedicalRecordRepository
+	settings RuntimeSettings
 }
 
 // NewMedicalRecordFinder creates an instance of MedicalRecordFinder.
-func NewMedicalRecordFinder(repo FindMedicalRecordRepository) *MedicalRecordFinder {
+// The page size is read from settings on every search.
+func NewMedicalRecordFinder(repo FindMedicalRecordRepository, settings RuntimeSettings) *MedicalRecordFinder {
 	return &MedicalRecordFinder{
-		repo: repo,
+		repo:     repo,
+		settings: settings,
 	}
 }
 
-// FindByID finds all medical records by its ID and validated by user's email.
-// Only medical record that is owned by the right user's email can be retrieved.
-// If the medical record is not owned by the user, it will return ErrUnauthorized.
-func (mf *MedicalRecordFinder) FindByID(ctx context.Context, id uint64, email string) (*entity.MedicalRecord, *entity.Error) {
+// FindByID finds all medical records by its ID and validated by the user who owns it.
+// Only medical record that is owned by the user can be retrieved.
+// If the medical record is not owned by the user, it will return ErrForbidden.
+func (mf *MedicalRecordFinder) FindByID(ctx context.Context, id, userID uint64) (*entity.MedicalRecord, *entity.Error) {
 	mr, err := mf.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if mr.User.Email != email {
-		return nil, entity.ErrUnauthorized
+	if uint64(mr.User.ID) != userID {
+		return nil, entity.ErrForbidden
 	}
 	return mr, nil
 }
 
-// FindByEmail finds medical records that belong to specific user (based on email).
-// The email will be verified first using regex and LookupMX.
-func (mf *MedicalRecordFinder) FindByEmail(ctx context.Context, email string, from uint64) ([]*entity.MedicalRecord, *entity.Error) {
-	if err := validateEmail(email); err != nil {
-		return []*entity.MedicalRecord{}, entity.ErrInvalidEmail
-	}
-
-	return mf.repo.FindByEmail(ctx, email, from, defaultLimit)
+// FindByUserID finds medical records that belong to specific user.
+func (mf *MedicalRecordFinder) FindByUserID(ctx context.Context, userID, from uint64) ([]*entity.MedicalRecord, *entity.Error) {
+	return mf.repo.FindByUserID(ctx, userID, from, mf.settings.PageSize())
 }
 
-func validateEmail(email string) *entity.Error {
-	if !emailRegex.MatchString(email) {
-		return entity.ErrInvalidEmail
+// FindByUserIDWithinPeriod finds medical records that belong to specific user
+// and are created within [since, until).
+// One more record than the page size is read to tell whether there is a next page.
+func (mf *MedicalRecordFinder) FindByUserIDWithinPeriod(ctx context.Context, userID uint64, since, until time.Time, from uint64) ([]*entity.MedicalRecord, bool, *entity.Error) {
+	if !since.Before(until) {
+		return []*entity.MedicalRecord{}, false, entity.ErrInvalidParam
 	}
 
-	parts := strings.Split(email, "@")
-	mx, err := net.LookupMX(parts[1])
-	if err != nil || len(mx) == 0 {
-		return entity.ErrInvalidEmail
+	size := mf.settings.PageSize()
+	records, err := mf.repo.FindByUserIDWithinPeriod(ctx, userID, since, until, from, size+1)
+	if err != nil {
+		return records, false, err
+	}
+	if uint(len(records)) > size {
+		return records[:size], true, nil
 	}
-	return nil
+	return records, false, nil
 }
@@ -3,8 +3,10 @@ package usecase_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/hashids"
 	"github.com/indrasaputra/orvosi-api/entity"
 	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
 	"github.com/indrasaputra/orvosi-api/usecase"
@@ -12,8 +14,9 @@ import (
 )
 
 type MedicalRecordFinderExecutor struct {
-	usecase *usecase.MedicalRecordFinder
-	repo    *mock_usecase.MockFindMedicalRecordRepository
+	usecase  *usecase.MedicalRecordFinder
+	repo     *mock_usecase.MockFindMedicalRecordRepository
+	settings *mock_usecase.MockRuntimeSettings
 }
 
 func TestNewMedicalRecordFinder(t *testing.T) {
@@ -34,7 +37,7 @@ func TestMedicalRecordFinder_FindByID(t *testing.T) {
 		exec := createMedicalRecordFinderExecutor(ctrl)
 
 		exec.repo.EXPECT().FindByID(context.Background(), uint64(1)).Return(&entity.MedicalRecord{}, entity.ErrInternalServer)
-		res, err := exec.usecase.FindByID(context.Background(), uint64(1), "dummy@dummy.com")
+		res, err := exec.usecase.FindByID(context.Background(), uint64(1), uint64(3))
 
 		assert.NotNil(t, err)
 		assert.Equal(t, entity.ErrInternalServer, err)
@@ -44,88 +47,128 @@ func TestMedicalRecordFinder_FindByID(t *testing.T) {
 	t.Run("medical record is not owned by the user", func(t *testing.T) {
 		exec := createMedicalRecordFinderExecutor(ctrl)
 
-		exec.repo.EXPECT().FindByID(context.Background(), uint64(1)).Return(&entity.MedicalRecord{User: &entity.User{Email: "notdummy@dummy.com"}}, nil)
-		res, err := exec.usecase.FindByID(context.Background(), uint64(1), "dummy@dummy.com")
+		exec.repo.EXPECT().FindByID(context.Background(), uint64(1)).Return(&entity.MedicalRecord{User: &entity.User{ID: hashids.ID(4)}}, nil)
+		res, err := exec.usecase.FindByID(context.Background(), uint64(1), uint64(3))
 
 		assert.NotNil(t, err)
-		assert.Equal(t, entity.ErrUnauthorized, err)
+		assert.Equal(t, entity.ErrForbidden, err)
 		assert.Nil(t, res)
 	})
 
-	t.Run("successfully find medical records bounded to specific email", func(t *testing.T) {
+	t.Run("successfully find medical records bounded to specific user", func(t *testing.T) {
 		exec := createMedicalRecordFinderExecutor(ctrl)
 
-		exec.repo.EXPECT().FindByID(context.Background(), uint64(1)).Return(&entity.MedicalRecord{User: &entity.User{Email: "dummy@dummy.com"}}, nil)
-		res, err := exec.usecase.FindByID(context.Background(), uint64(1), "dummy@dummy.com")
+		exec.repo.EXPECT().FindByID(context.Background(), uint64(1)).Return(&entity.MedicalRecord{User: &entity.User{ID: hashids.ID(3)}}, nil)
+		res, err := exec.usecase.FindByID(context.Background(), uint64(1), uint64(3))
 
 		assert.Nil(t, err)
 		assert.NotNil(t, res)
 	})
 }
 
-func TestMedicalRecordFinder_FindByEmail(t *testing.T) {
+func TestMedicalRecordFinder_FindByUserID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	t.Run("email doesn't contain username", func(t *testing.T) {
+	t.Run("repo returns error", func(t *testing.T) {
 		exec := createMedicalRecordFinderExecutor(ctrl)
 
-		res, err := exec.usecase.FindByEmail(context.Background(), "@dummy.com", 0)
+		exec.settings.EXPECT().PageSize().Return(uint(10))
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(3), uint64(0), uint(10)).Return([]*entity.MedicalRecord{}, entity.ErrInternalServer)
+		res, err := exec.usecase.FindByUserID(context.Background(), uint64(3), 0)
 
 		assert.NotNil(t, err)
-		assert.Equal(t, entity.ErrInvalidEmail, err)
+		assert.Equal(t, entity.ErrInternalServer, err)
 		assert.Empty(t, res)
 	})
 
-	t.Run("email doesn't contain domain", func(t *testing.T) {
+	t.Run("page size is read from the current settings", func(t *testing.T) {
 		exec := createMedicalRecordFinderExecutor(ctrl)
 
-		res, err := exec.usecase.FindByEmail(context.Background(), "dummy@", 0)
+		exec.settings.EXPECT().PageSize().Return(uint(25))
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(3), uint64(0), uint(25)).Return([]*entity.MedicalRecord{{}}, nil)
+		res, err := exec.usecase.FindByUserID(context.Background(), uint64(3), 0)
 
-		assert.NotNil(t, err)
-		assert.Equal(t, entity.ErrInvalidEmail, err)
-		assert.Empty(t, res)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+	})
+
+	t.Run("successfully find medical records bounded to specific user", func(t *testing.T) {
+		exec := createMedicalRecordFinderExecutor(ctrl)
+
+		exec.settings.EXPECT().PageSize().Return(uint(10))
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(3), uint64(0), uint(10)).Return([]*entity.MedicalRecord{{}}, nil)
+		res, err := exec.usecase.FindByUserID(context.Background(), uint64(3), 0)
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, res)
+		assert.Equal(t, 1, len(res))
 	})
+}
 
-	t.Run("email contains made-up domain", func(t *testing.T) {
+func TestMedicalRecordFinder_FindByUserIDWithinPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	since := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2021, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("period is empty", func(t *testing.T) {
 		exec := createMedicalRecordFinderExecutor(ctrl)
 
-		res, err := exec.usecase.FindByEmail(context.Background(), "dummy@dummy-domain.com", 0)
+		res, more, err := exec.usecase.FindByUserIDWithinPeriod(context.Background(), uint64(3), until, since, 0)
 
 		assert.NotNil(t, err)
-		assert.Equal(t, entity.ErrInvalidEmail, err)
+		assert.Equal(t, entity.ErrInvalidParam, err)
 		assert.Empty(t, res)
+		assert.False(t, more)
 	})
 
 	t.Run("repo returns error", func(t *testing.T) {
 		exec := createMedicalRecordFinderExecutor(ctrl)
 
-		exec.repo.EXPECT().FindByEmail(context.Background(), "dummy@dummy.com", uint64(0), uint(10)).Return([]*entity.MedicalRecord{}, entity.ErrInternalServer)
-		res, err := exec.usecase.FindByEmail(context.Background(), "dummy@dummy.com", 0)
+		exec.settings.EXPECT().PageSize().Return(uint(10))
+		exec.repo.EXPECT().FindByUserIDWithinPeriod(context.Background(), uint64(3), since, until, uint64(0), uint(11)).Return([]*entity.MedicalRecord{}, entity.ErrInternalServer)
+		res, _, err := exec.usecase.FindByUserIDWithinPeriod(context.Background(), uint64(3), since, until, 0)
 
 		assert.NotNil(t, err)
 		assert.Equal(t, entity.ErrInternalServer, err)
 		assert.Empty(t, res)
 	})
 
-	t.Run("successfully find medical records bounded to specific email", func(t *testing.T) {
+	t.Run("successfully find medical records within period", func(t *testing.T) {
 		exec := createMedicalRecordFinderExecutor(ctrl)
 
-		exec.repo.EXPECT().FindByEmail(context.Background(), "dummy@dummy.com", uint64(0), uint(10)).Return([]*entity.MedicalRecord{{}}, nil)
-		res, err := exec.usecase.FindByEmail(context.Background(), "dummy@dummy.com", 0)
+		exec.settings.EXPECT().PageSize().Return(uint(10))
+		exec.repo.EXPECT().FindByUserIDWithinPeriod(context.Background(), uint64(3), since, until, uint64(0), uint(11)).Return([]*entity.MedicalRecord{{}}, nil)
+		res, more, err := exec.usecase.FindByUserIDWithinPeriod(context.Background(), uint64(3), since, until, 0)
 
 		assert.Nil(t, err)
-		assert.NotEmpty(t, res)
 		assert.Equal(t, 1, len(res))
+		assert.False(t, more)
+	})
+
+	t.Run("more records than the page size means there is a next page", func(t *testing.T) {
+		exec := createMedicalRecordFinderExecutor(ctrl)
+
+		exec.settings.EXPECT().PageSize().Return(uint(2))
+		exec.repo.EXPECT().FindByUserIDWithinPeriod(context.Background(), uint64(3), since, until, uint64(0), uint(3)).Return([]*entity.MedicalRecord{{ID: 3}, {ID: 2}, {ID: 1}}, nil)
+		res, more, err := exec.usecase.FindByUserIDWithinPeriod(context.Background(), uint64(3), since, until, 0)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.MedicalRecord{{ID: 3}, {ID: 2}}, res)
+		assert.True(t, more)
 	})
 }
 
 func createMedicalRecordFinderExecutor(ctrl *gomock.Controller) *MedicalRecordFinderExecutor {
 	r := mock_usecase.NewMockFindMedicalRecordRepository(ctrl)
-	u := usecase.NewMedicalRecordFinder(r)
+	s := mock_usecase.NewMockRuntimeSettings(ctrl)
+	u := usecase.NewMedicalRecordFinder(r, s)
 
 	return &MedicalRecordFinderExecutor{
-		usecase: u,
-		repo:    r,
+		usecase:  u,
+		repo:     r,
+		settings: s,
 	}
 }
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"io"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+const (
+	importChunkSize = 100
+	// maxImportRowErrors is the maximum number of row errors in the report.
+	// The rest are only counted, so a broken file doesn't make a huge report.
+	maxImportRowErrors = 100
+)
+
+// ImportMedicalRecord defines the business logic
+// to import medical records in bulk.
+type ImportMedicalRecord interface {
+	// Import reads all medical records from source, validates each of them,
+	// and persists the valid ones on behalf of the user.
+	// If dryRun is true, nothing is persisted and only the report is returned.
+	Import(ctx context.Context, user *entity.User, source MedicalRecordSource, dryRun bool) (*entity.ImportReport, *entity.Error)
+}
+
+// MedicalRecordSource defines a stream of medical records to be imported.
+type MedicalRecordSource interface {
+	// Next returns the next medical record in the stream.
+	// It returns io.EOF when there is no more record.
+	// A row that can't be parsed MUST be reported as *entity.Error so the stream can keep going.
+	// Any other error stops the import.
+	Next() (*entity.MedicalRecord, error)
+	// Rewind restarts the stream from the first medical record,
+	// so the import can be run again when its transaction fails to be serialized.
+	Rewind() error
+}
+
+// BulkInsertMedicalRecordRepository defines the business logic
+// to insert many medical records into a repository at once.
+type BulkInsertMedicalRecordRepository interface {
+	// InsertMany inserts all medical records into the repository.
+	// This operation MUST set the inserted IDs back to the medical record objects.
+	InsertMany(ctx context.Context, records []*entity.MedicalRecord) *entity.Error
+}
+
+// MedicalRecordImporter responsibles for medical record import workflow.
+type MedicalRecordImporter struct {
+	repo       BulkInsertMedicalRecordRepository
+	transactor Transactor
+}
+
+// NewMedicalRecordImporter creates an instance of MedicalRecordImporter.
+func NewMedicalRecordImporter(repo BulkInsertMedicalRecordRepository, transactor Transactor) *MedicalRecordImporter {
+	return &MedicalRecordImporter{
+		repo:       repo,
+		transactor: transactor,
+	}
+}
+
+// Import reads the source row by row and validates every row using the same rules as creation.
+// Valid rows are persisted in chunks, so a big file never needs to be kept in memory.
+// Invalid rows are skipped and reported along with their position, up to maxImportRowErrors of them.
+//
+// All chunks are persisted in a single transaction, so either all valid rows are imported or none of them.
+// If the transaction is run again, the source is rewound and the report starts over.
+func (mi *MedicalRecordImporter) Import(ctx context.Context, user *entity.User, source MedicalRecordSource, dryRun bool) (*entity.ImportReport, *entity.Error) {
+	if user == nil {
+		return nil, entity.ErrEmptyUser
+	}
+	if source == nil {
+		return nil, entity.ErrInvalidImportFile
+	}
+	if dryRun {
+		return mi.importRows(ctx, user, source, true)
+	}
+
+	var report *entity.ImportReport
+	attempted := false
+	err := mi.transactor.WithinTransaction(ctx, func(ctx context.Context) *entity.Error {
+		if attempted {
+			if err := source.Rewind(); err != nil {
+				return entity.WrapError(entity.ErrInternalServer, "[MedicalRecordImporter-Import] rewind source: "+err.Error())
+			}
+		}
+		attempted = true
+
+		var err *entity.Error
+		report, err = mi.importRows(ctx, user, source, false)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (mi *MedicalRecordImporter) importRows(ctx context.Context, user *entity.User, source MedicalRecordSource, dryRun bool) (*entity.ImportReport, *entity.Error) {
+	report := &entity.ImportReport{DryRun: dryRun}
+	chunk := make([]*entity.MedicalRecord, 0, importChunkSize)
+
+	for {
+		record, err := source.Next()
+		if err == io.EOF {
+			break
+		}
+		report.Total++
+
+		if err != nil {
+			rowErr, ok := err.(*entity.Error)
+			if !ok {
+				return nil, entity.WrapError(entity.ErrInvalidImportFile, err.Error())
+			}
+			addImportRowError(report, rowErr)
+			continue
+		}
+
+		if record != nil {
+			record.User = user
+		}
+		if verr := validateMedicalRecord(record); verr != nil {
+			addImportRowError(report, verr)
+			continue
+		}
+		report.Valid++
+
+		if dryRun {
+			continue
+		}
+		chunk = append(chunk, record)
+		if len(chunk) == importChunkSize {
+			if ierr := mi.repo.InsertMany(ctx, chunk); ierr != nil {
+				return nil, ierr
+			}
+			report.Imported += len(chunk)
+			chunk = make([]*entity.MedicalRecord, 0, importChunkSize)
+		}
+	}
+
+	if len(chunk) > 0 {
+		if ierr := mi.repo.InsertMany(ctx, chunk); ierr != nil {
+			return nil, ierr
+		}
+		report.Imported += len(chunk)
+	}
+	return report, nil
+}
+
+// addImportRowError reports the error of the current row.
+// Only the first maxImportRowErrors errors are kept, the rest are counted as omitted.
+func addImportRowError(report *entity.ImportReport, err *entity.Error) {
+	if len(report.Errors) >= maxImportRowErrors {
+		report.OmittedErrors++
+		return
+	}
+	report.Errors = append(report.Errors, &entity.ImportRowError{Row: report.Total, Err: err})
+}
@@ -0,0 +1,208 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type MedicalRecordImporterExecutor struct {
+	usecase    *usecase.MedicalRecordImporter
+	repo       *mock_usecase.MockBulkInsertMedicalRecordRepository
+	source     *mock_usecase.MockMedicalRecordSource
+	transactor *mock_usecase.MockTransactor
+}
+
+func TestNewMedicalRecordImporter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of MedicalRecordImporter", func(t *testing.T) {
+		exec := createMedicalRecordImporterExecutor(ctrl)
+		assert.NotNil(t, exec.usecase)
+	})
+}
+
+func TestMedicalRecordImporter_Import(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("user is empty/nil", func(t *testing.T) {
+		exec := createMedicalRecordImporterExecutor(ctrl)
+
+		report, err := exec.usecase.Import(context.Background(), nil, exec.source, false)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrEmptyUser, err)
+		assert.Nil(t, report)
+	})
+
+	t.Run("source is empty/nil", func(t *testing.T) {
+		exec := createMedicalRecordImporterExecutor(ctrl)
+
+		report, err := exec.usecase.Import(context.Background(), createValidUser(), nil, false)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInvalidImportFile, err)
+		assert.Nil(t, report)
+	})
+
+	t.Run("source returns unrecoverable error", func(t *testing.T) {
+		exec := createMedicalRecordImporterExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.source.EXPECT().Next().Return(nil, errors.New("connection reset"))
+
+		report, err := exec.usecase.Import(context.Background(), createValidUser(), exec.source, false)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInvalidImportFile.Code, err.Code)
+		assert.Nil(t, report)
+	})
+
+	t.Run("dry run only reports invalid rows", func(t *testing.T) {
+		exec := createMedicalRecordImporterExecutor(ctrl)
+
+		invalid := createValidMedicalRecord()
+		invalid.Diagnosis = "  "
+		gomock.InOrder(
+			exec.source.EXPECT().Next().Return(createValidMedicalRecord(), nil),
+			exec.source.EXPECT().Next().Return(nil, entity.ErrInvalidImportRow),
+			exec.source.EXPECT().Next().Return(invalid, nil),
+			exec.source.EXPECT().Next().Return(nil, io.EOF),
+		)
+
+		report, err := exec.usecase.Import(context.Background(), createValidUser(), exec.source, true)
+
+		assert.Nil(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 3, report.Total)
+		assert.Equal(t, 1, report.Valid)
+		assert.Equal(t, 0, report.Imported)
+		assert.Equal(t, 2, len(report.Errors))
+		assert.Equal(t, 2, report.Errors[0].Row)
+		assert.Equal(t, entity.ErrInvalidImportRow, report.Errors[0].Err)
+		assert.Equal(t, 3, report.Errors[1].Row)
+		assert.Equal(t, entity.ErrInvalidMedicalRecordAttribute, report.Errors[1].Err)
+	})
+
+	t.Run("repo fails to insert", func(t *testing.T) {
+		exec := createMedicalRecordImporterExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		gomock.InOrder(
+			exec.source.EXPECT().Next().Return(createValidMedicalRecord(), nil),
+			exec.source.EXPECT().Next().Return(nil, io.EOF),
+		)
+		exec.repo.EXPECT().InsertMany(context.Background(), gomock.Len(1)).Return(entity.ErrInternalServer)
+
+		report, err := exec.usecase.Import(context.Background(), createValidUser(), exec.source, false)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Nil(t, report)
+	})
+
+	t.Run("source fails to be rewound when transaction is run again", func(t *testing.T) {
+		exec := createMedicalRecordImporterExecutor(ctrl)
+
+		gomock.InOrder(
+			exec.source.EXPECT().Next().Return(createValidMedicalRecord(), nil),
+			exec.source.EXPECT().Next().Return(nil, io.EOF),
+			exec.source.EXPECT().Rewind().Return(errors.New("file is closed")),
+		)
+		exec.repo.EXPECT().InsertMany(context.Background(), gomock.Len(1)).Return(nil)
+		exec.transactor.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(context.Context) *entity.Error) *entity.Error {
+				fn(ctx)
+				return fn(ctx)
+			})
+
+		report, err := exec.usecase.Import(context.Background(), createValidUser(), exec.source, false)
+
+		assert.Equal(t, entity.ErrInternalServer.Code, err.Code)
+		assert.Nil(t, report)
+	})
+
+	t.Run("source is rewound and the report starts over when transaction is run again", func(t *testing.T) {
+		exec := createMedicalRecordImporterExecutor(ctrl)
+
+		gomock.InOrder(
+			exec.source.EXPECT().Next().Return(createValidMedicalRecord(), nil),
+			exec.source.EXPECT().Next().Return(nil, io.EOF),
+			exec.source.EXPECT().Rewind().Return(nil),
+			exec.source.EXPECT().Next().Return(createValidMedicalRecord(), nil),
+			exec.source.EXPECT().Next().Return(nil, io.EOF),
+		)
+		exec.repo.EXPECT().InsertMany(context.Background(), gomock.Len(1)).Return(nil).Times(2)
+		exec.transactor.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(context.Context) *entity.Error) *entity.Error {
+				fn(ctx)
+				return fn(ctx)
+			})
+
+		report, err := exec.usecase.Import(context.Background(), createValidUser(), exec.source, false)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, report.Total)
+		assert.Equal(t, 1, report.Imported)
+	})
+
+	t.Run("only the first row errors are reported", func(t *testing.T) {
+		exec := createMedicalRecordImporterExecutor(ctrl)
+
+		exec.source.EXPECT().Next().Return(nil, entity.ErrInvalidImportRow).Times(150)
+		exec.source.EXPECT().Next().Return(nil, io.EOF)
+
+		report, err := exec.usecase.Import(context.Background(), createValidUser(), exec.source, true)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 150, report.Total)
+		assert.Len(t, report.Errors, 100)
+		assert.Equal(t, 100, report.Errors[99].Row)
+		assert.Equal(t, 50, report.OmittedErrors)
+	})
+
+	t.Run("successfully import valid rows in chunks in a single transaction", func(t *testing.T) {
+		exec := createMedicalRecordImporterExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.source.EXPECT().Next().Return(createValidMedicalRecord(), nil).Times(150)
+		exec.source.EXPECT().Next().Return(nil, io.EOF)
+		gomock.InOrder(
+			exec.repo.EXPECT().InsertMany(context.Background(), gomock.Len(100)).Return(nil),
+			exec.repo.EXPECT().InsertMany(context.Background(), gomock.Len(50)).Return(nil),
+		)
+
+		user := createValidUser()
+		report, err := exec.usecase.Import(context.Background(), user, exec.source, false)
+
+		assert.Nil(t, err)
+		assert.False(t, report.DryRun)
+		assert.Equal(t, 150, report.Total)
+		assert.Equal(t, 150, report.Valid)
+		assert.Equal(t, 150, report.Imported)
+		assert.Empty(t, report.Errors)
+	})
+}
+
+func createMedicalRecordImporterExecutor(ctrl *gomock.Controller) *MedicalRecordImporterExecutor {
+	r := mock_usecase.NewMockBulkInsertMedicalRecordRepository(ctrl)
+	s := mock_usecase.NewMockMedicalRecordSource(ctrl)
+	tx := mock_usecase.NewMockTransactor(ctrl)
+	u := usecase.NewMedicalRecordImporter(r, tx)
+
+	return &MedicalRecordImporterExecutor{
+		usecase:    u,
+		repo:       r,
+		source:     s,
+		transactor: tx,
+	}
+}
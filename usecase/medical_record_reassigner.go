@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// ReassignMedicalRecord defines the business logic
+// to move medical records from one email to another.
+type ReassignMedicalRecord interface {
+	// Reassign moves all medical records owned by email from to email to
+	// and returns the number of moved records.
+	Reassign(ctx context.Context, from, to string) (int64, *entity.Error)
+}
+
+// ReassignMedicalRecordRepository defines the business logic
+// to change the owner of medical records in a repository.
+type ReassignMedicalRecordRepository interface {
+	// ReassignByEmail changes the owner of all medical records owned by email from to email to
+	// and returns the number of changed records.
+	ReassignByEmail(ctx context.Context, from, to string) (int64, *entity.Error)
+}
+
+// MedicalRecordReassigner responsibles for medical record reassignment workflow.
+type MedicalRecordReassigner struct {
+	repo       ReassignMedicalRecordRepository
+	users      FindUserRepository
+	transactor Transactor
+}
+
+// NewMedicalRecordReassigner creates an instance of MedicalRecordReassigner.
+func NewMedicalRecordReassigner(repo ReassignMedicalRecordRepository, users FindUserRepository, transactor Transactor) *MedicalRecordReassigner {
+	return &MedicalRecordReassigner{
+		repo:       repo,
+		users:      users,
+		transactor: transactor,
+	}
+}
+
+// Reassign moves all medical records owned by email from to email to.
+// Both emails must be valid and different, and email to must belong to a registered user,
+// otherwise the records would be owned by nobody who can sign in.
+// The check and the move are run in a single unit of work.
+func (mr *MedicalRecordReassigner) Reassign(ctx context.Context, from, to string) (int64, *entity.Error) {
+	if !emailRegex.MatchString(from) || !emailRegex.MatchString(to) {
+		return 0, entity.ErrInvalidEmail
+	}
+	if from == to {
+		return 0, entity.WrapError(entity.ErrInvalidParam, "[MedicalRecordReassigner-Reassign] both emails are the same")
+	}
+
+	var total int64
+	err := mr.transactor.WithinTransaction(ctx, func(ctx context.Context) *entity.Error {
+		if _, err := mr.users.FindByEmail(ctx, to); err != nil {
+			return err
+		}
+
+		var err *entity.Error
+		total, err = mr.repo.ReassignByEmail(ctx, from, to)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
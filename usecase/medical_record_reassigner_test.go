@@ -0,0 +1,103 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type MedicalRecordReassignerExecutor struct {
+	usecase    *usecase.MedicalRecordReassigner
+	repo       *mock_usecase.MockReassignMedicalRecordRepository
+	users      *mock_usecase.MockFindUserRepository
+	transactor *mock_usecase.MockTransactor
+}
+
+func TestNewMedicalRecordReassigner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of MedicalRecordReassigner", func(t *testing.T) {
+		exec := createMedicalRecordReassignerExecutor(ctrl)
+		assert.NotNil(t, exec.usecase)
+	})
+}
+
+func TestMedicalRecordReassigner_Reassign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("email is invalid", func(t *testing.T) {
+		exec := createMedicalRecordReassignerExecutor(ctrl)
+
+		for _, emails := range [][2]string{{"invalid-email", "b@orvosi.com"}, {"a@orvosi.com", "invalid-email"}} {
+			total, err := exec.usecase.Reassign(context.Background(), emails[0], emails[1])
+
+			assert.Equal(t, entity.ErrInvalidEmail, err)
+			assert.Zero(t, total)
+		}
+	})
+
+	t.Run("both emails are the same", func(t *testing.T) {
+		exec := createMedicalRecordReassignerExecutor(ctrl)
+
+		total, err := exec.usecase.Reassign(context.Background(), "a@orvosi.com", "a@orvosi.com")
+
+		assert.Equal(t, entity.ErrInvalidParam.Code, err.Code)
+		assert.Zero(t, total)
+	})
+
+	t.Run("target user is not registered", func(t *testing.T) {
+		exec := createMedicalRecordReassignerExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.users.EXPECT().FindByEmail(gomock.Any(), "b@orvosi.com").Return(nil, entity.ErrUserNotFound)
+		total, err := exec.usecase.Reassign(context.Background(), "a@orvosi.com", "b@orvosi.com")
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		assert.Zero(t, total)
+	})
+
+	t.Run("repository returns error", func(t *testing.T) {
+		exec := createMedicalRecordReassignerExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.users.EXPECT().FindByEmail(gomock.Any(), "b@orvosi.com").Return(&entity.User{Email: "b@orvosi.com"}, nil)
+		exec.repo.EXPECT().ReassignByEmail(gomock.Any(), "a@orvosi.com", "b@orvosi.com").Return(int64(0), entity.ErrInternalServer)
+		total, err := exec.usecase.Reassign(context.Background(), "a@orvosi.com", "b@orvosi.com")
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Zero(t, total)
+	})
+
+	t.Run("successfully reassign medical records", func(t *testing.T) {
+		exec := createMedicalRecordReassignerExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.users.EXPECT().FindByEmail(gomock.Any(), "b@orvosi.com").Return(&entity.User{Email: "b@orvosi.com"}, nil)
+		exec.repo.EXPECT().ReassignByEmail(gomock.Any(), "a@orvosi.com", "b@orvosi.com").Return(int64(3), nil)
+		total, err := exec.usecase.Reassign(context.Background(), "a@orvosi.com", "b@orvosi.com")
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), total)
+	})
+}
+
+func createMedicalRecordReassignerExecutor(ctrl *gomock.Controller) *MedicalRecordReassignerExecutor {
+	r := mock_usecase.NewMockReassignMedicalRecordRepository(ctrl)
+	ur := mock_usecase.NewMockFindUserRepository(ctrl)
+	tx := mock_usecase.NewMockTransactor(ctrl)
+	u := usecase.NewMedicalRecordReassigner(r, ur, tx)
+
+	return &MedicalRecordReassignerExecutor{
+		usecase:    u,
+		repo:       r,
+		users:      ur,
+		transactor: tx,
+	}
+}
@@ -9,17 +9,17 @@ import (
 // UpdateMedicalRecord defines the business logic
 // to update a medical record.
 type UpdateMedicalRecord interface {
-	// Update updates a new medical record.
-	Update(ctx context.Context, email string, id uint64, record *entity.MedicalRecord) *entity.Error
+	// Update updates the medical record which has the id and belongs to the user.
+	Update(ctx context.Context, id, userID uint64, record *entity.MedicalRecord) *entity.Error
 }
 
 // UpdateMedicalRecordRepository defines the business logic
 // to update a medical record into a repository.
 type UpdateMedicalRecordRepository interface {
-	// FindByID finds medical records by its id.
-	DoesRecordExist(ctx context.Context, id uint64, email string) (bool, *entity.Error)
-	// Update updates certain medical record.
-	Update(ctx context.Context, id uint64, record *entity.MedicalRecord) *entity.Error
+	// Update updates certain medical record which belongs to the user.
+	// It must return ErrMedicalRecordNotFound if there is no such record.
+	// The ownership check and the update must be atomic.
+	Update(ctx context.Context, id, userID uint64, record *entity.MedicalRecord) *entity.Error
 }
 
 // MedicalRecordUpdater responsibles for medical record update workflow.
@@ -35,14 +35,8 @@ func NewMedicalRecordUpdater(repo UpdateMedicalRecordRepository) *MedicalRecordU
 }
 
 // Update updates the medical record.
-func (mu *MedicalRecordUpdater) Update(ctx context.Context, email string, id uint64, record *entity.MedicalRecord) *entity.Error {
-	found, err := mu.repo.DoesRecordExist(ctx, id, email)
-	if err != nil {
-		return err
-	}
-	if !found {
-		return entity.ErrMedicalRecordNotFound
-	}
-
-	return mu.repo.Update(ctx, id, record)
+// The repository checks the ownership in the same statement as the update,
+// so the record can't change hands in between.
+func (mu *MedicalRecordUpdater) Update(ctx context.Context, id, userID uint64, record *entity.MedicalRecord) *entity.Error {
+	return mu.repo.Update(ctx, id, userID, record)
 }
@@ -34,9 +34,9 @@ func TestMedicalRecordUpater_Update(t *testing.T) {
 		exec := createMedicalRecordUpdaterExecutor(ctrl)
 
 		record := createValidMedicalRecord()
-		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), "dummy@dummy.com").Return(false, entity.ErrInternalServer)
+		exec.repo.EXPECT().Update(context.Background(), uint64(1), uint64(3), record).Return(entity.ErrInternalServer)
 
-		err := exec.usecase.Update(context.Background(), "dummy@dummy.com", uint64(1), record)
+		err := exec.usecase.Update(context.Background(), uint64(1), uint64(3), record)
 
 		assert.NotNil(t, err)
 		assert.Equal(t, entity.ErrInternalServer, err)
@@ -46,35 +46,21 @@ func TestMedicalRecordUpater_Update(t *testing.T) {
 		exec := createMedicalRecordUpdaterExecutor(ctrl)
 
 		record := createValidMedicalRecord()
-		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), "dummy@dummy.com").Return(false, nil)
+		exec.repo.EXPECT().Update(context.Background(), uint64(1), uint64(3), record).Return(entity.ErrMedicalRecordNotFound)
 
-		err := exec.usecase.Update(context.Background(), "dummy@dummy.com", uint64(1), record)
+		err := exec.usecase.Update(context.Background(), uint64(1), uint64(3), record)
 
 		assert.NotNil(t, err)
 		assert.Equal(t, entity.ErrMedicalRecordNotFound, err)
 	})
 
-	t.Run("medical record can't be updated", func(t *testing.T) {
-		exec := createMedicalRecordUpdaterExecutor(ctrl)
-
-		record := createValidMedicalRecord()
-		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), "dummy@dummy.com").Return(true, nil)
-		exec.repo.EXPECT().Update(context.Background(), uint64(1), record).Return(entity.ErrInternalServer)
-
-		err := exec.usecase.Update(context.Background(), "dummy@dummy.com", uint64(1), record)
-
-		assert.NotNil(t, err)
-		assert.Equal(t, entity.ErrInternalServer, err)
-	})
-
 	t.Run("successfully update medical record", func(t *testing.T) {
 		exec := createMedicalRecordUpdaterExecutor(ctrl)
 
 		record := createValidMedicalRecord()
-		exec.repo.EXPECT().DoesRecordExist(context.Background(), uint64(1), "dummy@dummy.com").Return(true, nil)
-		exec.repo.EXPECT().Update(context.Background(), uint64(1), record).Return(nil)
+		exec.repo.EXPECT().Update(context.Background(), uint64(1), uint64(3), record).Return(nil)
 
-		err := exec.usecase.Update(context.Background(), "dummy@dummy.com", uint64(1), record)
+		err := exec.usecase.Update(context.Background(), uint64(1), uint64(3), record)
 
 		assert.Nil(t, err)
 	})
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // validate the timezone without relying on the database of the system
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+const (
+	maxDisplayNameLength   = 100
+	maxSpecialtyLength     = 100
+	maxLicenseNumberLength = 50
+)
+
+// UpdateProfile defines the business logic
+// to update the profile of a user.
+type UpdateProfile interface {
+	// Update changes the fields of the profile which are set in the update
+	// and returns the user who has the email, including the new profile.
+	Update(ctx context.Context, email string, update *entity.ProfileUpdate) (*entity.User, *entity.Error)
+}
+
+// UpdateProfileRepository defines the business logic
+// to update the profile of a user in a repository.
+type UpdateProfileRepository interface {
+	// UpdateProfile replaces the profile of the user who has the email.
+	// It MUST return entity.ErrUserNotFound if the user doesn't exist.
+	UpdateProfile(ctx context.Context, email string, profile *entity.Profile) *entity.Error
+}
+
+// ProfileUpdater responsibles for profile update workflow.
+type ProfileUpdater struct {
+	repo       UpdateProfileRepository
+	users      FindUserRepository
+	transactor Transactor
+	languages  []string
+}
+
+// NewProfileUpdater creates an instance of ProfileUpdater.
+// The languages are the ones which can be preferred, such as the languages of the error messages.
+func NewProfileUpdater(repo UpdateProfileRepository, users FindUserRepository, transactor Transactor, languages []string) *ProfileUpdater {
+	return &ProfileUpdater{
+		repo:       repo,
+		users:      users,
+		transactor: transactor,
+		languages:  languages,
+	}
+}
+
+// Update changes the fields of the profile which are set in the update, keeping the others.
+// Every field of the resulting profile is validated and all failures are returned at once.
+// The read and the write are run in a single unit of work, so a concurrent update of another field isn't lost.
+func (pu *ProfileUpdater) Update(ctx context.Context, email string, update *entity.ProfileUpdate) (*entity.User, *entity.Error) {
+	if !emailRegex.MatchString(email) {
+		return nil, entity.ErrInvalidEmail
+	}
+	if update == nil {
+		return nil, entity.ErrInvalidProfileRequest
+	}
+
+	var user *entity.User
+	err := pu.transactor.WithinTransaction(ctx, func(ctx context.Context) *entity.Error {
+		var err *entity.Error
+		user, err = pu.users.FindByEmail(ctx, email)
+		if err != nil {
+			return err
+		}
+
+		user.Profile = applyProfileUpdate(user.Profile, update)
+		if params := pu.validate(&user.Profile); len(params) > 0 {
+			return entity.WithInvalidParams(entity.ErrInvalidProfileAttribute, params)
+		}
+		return pu.repo.UpdateProfile(ctx, email, &user.Profile)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func applyProfileUpdate(profile entity.Profile, update *entity.ProfileUpdate) entity.Profile {
+	set := func(field *string, value *string) {
+		if value != nil {
+			*field = strings.TrimSpace(*value)
+		}
+	}
+	set(&profile.DisplayName, update.DisplayName)
+	set(&profile.Specialty, update.Specialty)
+	set(&profile.LicenseNumber, update.LicenseNumber)
+	set(&profile.Timezone, update.Timezone)
+	set(&profile.PreferredLanguage, update.PreferredLanguage)
+	return profile
+}
+
+func (pu *ProfileUpdater) validate(profile *entity.Profile) []entity.InvalidParam {
+	var params []entity.InvalidParam
+	text := func(name, value string, max int) {
+		switch {
+		case utf8.RuneCountInString(value) > max:
+			params = append(params, entity.InvalidParam{Name: name, Rule: "max", Param: strconv.Itoa(max), Reason: fmt.Sprintf("must be at most %d characters", max)})
+		case strings.IndexFunc(value, unicode.IsControl) >= 0:
+			params = append(params, entity.InvalidParam{Name: name, Rule: "printable", Reason: "must not contain control characters"})
+		}
+	}
+	text("display_name", profile.DisplayName, maxDisplayNameLength)
+	text("specialty", profile.Specialty, maxSpecialtyLength)
+	text("license_number", profile.LicenseNumber, maxLicenseNumberLength)
+
+	if profile.Timezone != "" {
+		if _, err := time.LoadLocation(profile.Timezone); err != nil || profile.Timezone == "Local" {
+			params = append(params, entity.InvalidParam{Name: "timezone", Rule: "timezone", Reason: "must be an IANA time zone, such as Asia/Jakarta"})
+		}
+	}
+	if profile.PreferredLanguage != "" && !contains(pu.languages, profile.PreferredLanguage) {
+		languages := strings.Join(pu.languages, ", ")
+		params = append(params, entity.InvalidParam{Name: "preferred_language", Rule: "oneof", Param: languages, Reason: "must be one of " + languages})
+	}
+	return params
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type ProfileUpdaterExecutor struct {
+	usecase    *usecase.ProfileUpdater
+	repo       *mock_usecase.MockUpdateProfileRepository
+	users      *mock_usecase.MockFindUserRepository
+	transactor *mock_usecase.MockTransactor
+}
+
+func TestNewProfileUpdater(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of ProfileUpdater", func(t *testing.T) {
+		exec := createProfileUpdaterExecutor(ctrl)
+		assert.NotNil(t, exec.usecase)
+	})
+}
+
+func TestProfileUpdater_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("email is invalid", func(t *testing.T) {
+		exec := createProfileUpdaterExecutor(ctrl)
+
+		res, err := exec.usecase.Update(context.Background(), "invalid-email", &entity.ProfileUpdate{})
+
+		assert.Equal(t, entity.ErrInvalidEmail, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("update is nil", func(t *testing.T) {
+		exec := createProfileUpdaterExecutor(ctrl)
+
+		res, err := exec.usecase.Update(context.Background(), "a@orvosi.com", nil)
+
+		assert.Equal(t, entity.ErrInvalidProfileRequest, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("user is not found", func(t *testing.T) {
+		exec := createProfileUpdaterExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.users.EXPECT().FindByEmail(gomock.Any(), "a@orvosi.com").Return(nil, entity.ErrUserNotFound)
+		res, err := exec.usecase.Update(context.Background(), "a@orvosi.com", &entity.ProfileUpdate{})
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("every invalid attribute is reported", func(t *testing.T) {
+		exec := createProfileUpdaterExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.users.EXPECT().FindByEmail(gomock.Any(), "a@orvosi.com").Return(&entity.User{Email: "a@orvosi.com"}, nil)
+		update := &entity.ProfileUpdate{
+			DisplayName:       stringPointer(strings.Repeat("a", 101)),
+			Specialty:         stringPointer("Cardio\nlogy"),
+			LicenseNumber:     stringPointer(strings.Repeat("1", 51)),
+			Timezone:          stringPointer("Mars/Olympus"),
+			PreferredLanguage: stringPointer("fr"),
+		}
+		res, err := exec.usecase.Update(context.Background(), "a@orvosi.com", update)
+
+		assert.Equal(t, entity.ErrInvalidProfileAttribute.Code, err.Code)
+		assert.Nil(t, res)
+		var names []string
+		for _, param := range err.InvalidParams {
+			names = append(names, param.Name)
+		}
+		assert.Equal(t, []string{"display_name", "specialty", "license_number", "timezone", "preferred_language"}, names)
+	})
+
+	t.Run("repository returns error", func(t *testing.T) {
+		exec := createProfileUpdaterExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.users.EXPECT().FindByEmail(gomock.Any(), "a@orvosi.com").Return(&entity.User{Email: "a@orvosi.com"}, nil)
+		exec.repo.EXPECT().UpdateProfile(gomock.Any(), "a@orvosi.com", gomock.Any()).Return(entity.ErrInternalServer)
+		res, err := exec.usecase.Update(context.Background(), "a@orvosi.com", &entity.ProfileUpdate{Specialty: stringPointer("Cardiology")})
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("successfully update only the fields which are set", func(t *testing.T) {
+		exec := createProfileUpdaterExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		stored := &entity.User{
+			ID:    1,
+			Email: "a@orvosi.com",
+			Profile: entity.Profile{
+				DisplayName:   "dr. A",
+				Specialty:     "Pediatrics",
+				LicenseNumber: "SIP-123",
+			},
+		}
+		expected := entity.Profile{
+			DisplayName:       "dr. A",
+			Specialty:         "Cardiology",
+			Timezone:          "Asia/Jakarta",
+			PreferredLanguage: "id",
+		}
+		update := &entity.ProfileUpdate{
+			Specialty:         stringPointer(" Cardiology "),
+			LicenseNumber:     stringPointer(""),
+			Timezone:          stringPointer("Asia/Jakarta"),
+			PreferredLanguage: stringPointer("id"),
+		}
+
+		exec.users.EXPECT().FindByEmail(gomock.Any(), "a@orvosi.com").Return(stored, nil)
+		exec.repo.EXPECT().UpdateProfile(gomock.Any(), "a@orvosi.com", &expected).Return(nil)
+		res, err := exec.usecase.Update(context.Background(), "a@orvosi.com", update)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, res.Profile)
+		assert.Equal(t, "a@orvosi.com", res.Email)
+	})
+}
+
+func stringPointer(s string) *string {
+	return &s
+}
+
+func createProfileUpdaterExecutor(ctrl *gomock.Controller) *ProfileUpdaterExecutor {
+	r := mock_usecase.NewMockUpdateProfileRepository(ctrl)
+	u := mock_usecase.NewMockFindUserRepository(ctrl)
+	tx := mock_usecase.NewMockTransactor(ctrl)
+	uc := usecase.NewProfileUpdater(r, u, tx, []string{"en", "id", "hu"})
+
+	return &ProfileUpdaterExecutor{
+		usecase:    uc,
+		repo:       r,
+		users:      u,
+		transactor: tx,
+	}
+}
@@ -8,36 +8,113 @@ import (
 
 // SignIn defines the business logic to sign in.
 type SignIn interface {
-	// SignIn signs a user in to the system.
-	SignIn(ctx context.Context, user *entity.User) *entity.Error
+	// SignIn signs a user in to the system and returns the stored user, including the profile.
+	SignIn(ctx context.Context, user *entity.User) (*entity.User, *entity.Error)
 }
 
-// InsertUserRepository defines the business logic
-// to insert a user into a repository.
-type InsertUserRepository interface {
-	// InsertOrIgnore inserts a user into the repository.
-	// If the user already exists in the repository, it ignores and returns nil error.
-	InsertOrIgnore(ctx context.Context, user *entity.User) *entity.Error
+// ResolveUser defines the business logic
+// to find the stored user of the identity which signs a request.
+type ResolveUser interface {
+	// Resolve finds the stored user whose identity has the Google ID of the user taken from the ID token.
+	// The user is registered if it is the first time the identity is used.
+	// It returns entity.ErrEmailAlreadyUsed if the email of the new identity isn't verified and belongs to another user.
+	Resolve(ctx context.Context, user *entity.User) (*entity.User, *entity.Error)
+}
+
+// SignInRepository defines the business logic
+// to find, insert, or update a user in a repository during sign-in.
+type SignInRepository interface {
+	// FindByIdentity finds the user whose identity has the provider and subject, including the profile.
+	// It MUST return entity.ErrUserNotFound if no user has such identity.
+	FindByIdentity(ctx context.Context, provider, subject string) (*entity.User, *entity.Error)
+	// Upsert inserts a user into the repository.
+	// If the email already exists, the name and Google ID are refreshed instead.
+	// It returns the stored user, including the profile.
+	Upsert(ctx context.Context, user *entity.User) (*entity.User, *entity.Error)
+	// Insert inserts a user into the repository and returns the stored user, including the profile.
+	// It MUST return entity.ErrEmailAlreadyUsed if the email belongs to another user.
+	Insert(ctx context.Context, user *entity.User) (*entity.User, *entity.Error)
+	// Refresh replaces the name and Google ID of the user who has the id.
+	Refresh(ctx context.Context, userID uint64, name, googleID string) *entity.Error
 }
 
 // Signer responsibles for sign-in workflow.
 type Signer struct {
-	repo InsertUserRepository
+	repo       SignInRepository
+	identities LinkIdentityRepository
+	transactor Transactor
 }
 
 // NewSigner creates an instance of Signer.
-func NewSigner(repo InsertUserRepository) *Signer {
+func NewSigner(repo SignInRepository, identities LinkIdentityRepository, transactor Transactor) *Signer {
 	return &Signer{
-		repo: repo,
+		repo:       repo,
+		identities: identities,
+		transactor: transactor,
 	}
 }
 
 // SignIn signs in a user to the system.
-// If the user doesn't exist yet in the system, it will register the user.
-func (s *Signer) SignIn(ctx context.Context, user *entity.User) *entity.Error {
+// It is the same as Resolve, so the user who signs in explicitly gets the same stored user as any other request.
+func (s *Signer) SignIn(ctx context.Context, user *entity.User) (*entity.User, *entity.Error) {
+	return s.Resolve(ctx, user)
+}
+
+// Resolve finds the stored user whose identity has the Google ID of the user taken from the ID token.
+// The name and Google ID are refreshed if they have changed, since they may change on Google's side
+// or the user may sign in using another linked identity.
+//
+// If the identity is used for the first time and Google has verified its email,
+// the user who has the email gets the identity linked.
+// Such user exists when the identity was never linked before, e.g. their medical records were kept under that email.
+// Otherwise, a new user is registered along with the identity.
+// An unverified email never links the identity to the user who has it, since anyone may claim it.
+// If it belongs to another user, entity.ErrEmailAlreadyUsed is returned
+// and the identity can only be linked by that user via POST /me/identities.
+func (s *Signer) Resolve(ctx context.Context, user *entity.User) (*entity.User, *entity.Error) {
 	if user == nil {
-		return entity.ErrEmptyUser
+		return nil, entity.ErrEmptyUser
+	}
+
+	stored, err := s.repo.FindByIdentity(ctx, entity.ProviderGoogle, user.GoogleID)
+	if err == nil {
+		return s.refresh(ctx, stored, user)
 	}
+	if err.Code != entity.ErrUserNotFound.Code {
+		return nil, err
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) *entity.Error {
+		var err *entity.Error
+		if user.EmailVerified {
+			stored, err = s.repo.Upsert(ctx, user)
+		} else {
+			stored, err = s.repo.Insert(ctx, user)
+		}
+		if err != nil {
+			return err
+		}
+		return s.identities.Link(ctx, &entity.Identity{
+			UserID:   stored.ID,
+			Provider: entity.ProviderGoogle,
+			Subject:  user.GoogleID,
+			Email:    user.Email,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
 
-	return s.repo.InsertOrIgnore(ctx, user)
+func (s *Signer) refresh(ctx context.Context, stored, user *entity.User) (*entity.User, *entity.Error) {
+	if stored.Name == user.Name && stored.GoogleID == user.GoogleID {
+		return stored, nil
+	}
+	if err := s.repo.Refresh(ctx, uint64(stored.ID), user.Name, user.GoogleID); err != nil {
+		return nil, err
+	}
+	stored.Name = user.Name
+	stored.GoogleID = user.GoogleID
+	return stored, nil
 }
@@ -13,8 +13,10 @@ import (
 )
 
 type SignInExecutor struct {
-	usecase *usecase.Signer
-	repo    *mock_usecase.MockInsertUserRepository
+	usecase    *usecase.Signer
+	repo       *mock_usecase.MockSignInRepository
+	identities *mock_usecase.MockLinkIdentityRepository
+	transactor *mock_usecase.MockTransactor
 }
 
 func TestNewSignIn(t *testing.T) {
@@ -34,33 +36,188 @@ func TestSigner_SignIn(t *testing.T) {
 	t.Run("user is empty/nil", func(t *testing.T) {
 		exec := createSignInExecutor(ctrl)
 
-		err := exec.usecase.SignIn(context.Background(), nil)
+		res, err := exec.usecase.SignIn(context.Background(), nil)
 
 		assert.NotNil(t, err)
+		assert.Nil(t, res)
 		assert.Equal(t, entity.ErrEmptyUser, err)
 	})
 
-	t.Run("repo returns error", func(t *testing.T) {
+	t.Run("find by identity returns error", func(t *testing.T) {
 		exec := createSignInExecutor(ctrl)
 
 		user := createValidUser()
-		exec.repo.EXPECT().InsertOrIgnore(context.Background(), user).Return(entity.ErrInternalServer)
+		exec.repo.EXPECT().FindByIdentity(context.Background(), entity.ProviderGoogle, user.GoogleID).Return(nil, entity.ErrInternalServer)
 
-		err := exec.usecase.SignIn(context.Background(), user)
+		res, err := exec.usecase.SignIn(context.Background(), user)
 
-		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInternalServer, err)
+	})
+
+	t.Run("linked identity returns the stored user as is", func(t *testing.T) {
+		exec := createSignInExecutor(ctrl)
+
+		user := createValidUser()
+		stored := createValidUser()
+		stored.Email = "changed@provider.com"
+		stored.Profile.Specialty = "Cardiology"
+		exec.repo.EXPECT().FindByIdentity(context.Background(), entity.ProviderGoogle, user.GoogleID).Return(stored, nil)
+
+		res, err := exec.usecase.SignIn(context.Background(), user)
+
+		assert.Nil(t, err)
+		assert.Equal(t, stored, res)
+	})
+
+	t.Run("linked identity refreshes the changed name and google id", func(t *testing.T) {
+		exec := createSignInExecutor(ctrl)
+
+		user := createValidUser()
+		stored := createValidUser()
+		stored.Name = "Old Name"
+		stored.GoogleID = "another-linked-google-id"
+		exec.repo.EXPECT().FindByIdentity(context.Background(), entity.ProviderGoogle, user.GoogleID).Return(stored, nil)
+		exec.repo.EXPECT().Refresh(context.Background(), uint64(1), user.Name, user.GoogleID).Return(nil)
+
+		res, err := exec.usecase.SignIn(context.Background(), user)
+
+		assert.Nil(t, err)
+		assert.Equal(t, user.Name, res.Name)
+		assert.Equal(t, user.GoogleID, res.GoogleID)
+	})
+
+	t.Run("refresh returns error", func(t *testing.T) {
+		exec := createSignInExecutor(ctrl)
+
+		user := createValidUser()
+		stored := createValidUser()
+		stored.Name = "Old Name"
+		exec.repo.EXPECT().FindByIdentity(context.Background(), entity.ProviderGoogle, user.GoogleID).Return(stored, nil)
+		exec.repo.EXPECT().Refresh(context.Background(), uint64(1), user.Name, user.GoogleID).Return(entity.ErrInternalServer)
+
+		res, err := exec.usecase.SignIn(context.Background(), user)
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrInternalServer, err)
+	})
+
+	t.Run("upsert returns error", func(t *testing.T) {
+		exec := createSignInExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		user := createValidUser()
+		user.EmailVerified = true
+		exec.repo.EXPECT().FindByIdentity(context.Background(), entity.ProviderGoogle, user.GoogleID).Return(nil, entity.ErrUserNotFound)
+		exec.repo.EXPECT().Upsert(context.Background(), user).Return(nil, entity.ErrInternalServer)
+
+		res, err := exec.usecase.SignIn(context.Background(), user)
+
+		assert.Nil(t, res)
 		assert.Equal(t, entity.ErrInternalServer, err)
 	})
 
-	t.Run("successfully insert or ignore user", func(t *testing.T) {
+	t.Run("link returns error", func(t *testing.T) {
+		exec := createSignInExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		user := createValidUser()
+		user.EmailVerified = true
+		exec.repo.EXPECT().FindByIdentity(context.Background(), entity.ProviderGoogle, user.GoogleID).Return(nil, entity.ErrUserNotFound)
+		exec.repo.EXPECT().Upsert(context.Background(), user).Return(createValidUser(), nil)
+		exec.identities.EXPECT().Link(context.Background(), gomock.Any()).Return(entity.ErrIdentityAlreadyLinked)
+
+		res, err := exec.usecase.SignIn(context.Background(), user)
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrIdentityAlreadyLinked, err)
+	})
+
+	t.Run("successfully register the user and link the identity used for the first time", func(t *testing.T) {
+		exec := createSignInExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		user := createValidUser()
+		user.EmailVerified = true
+		stored := createValidUser()
+		stored.ID = hashids.ID(7)
+		exec.repo.EXPECT().FindByIdentity(context.Background(), entity.ProviderGoogle, user.GoogleID).Return(nil, entity.ErrUserNotFound)
+		exec.repo.EXPECT().Upsert(context.Background(), user).Return(stored, nil)
+		exec.identities.EXPECT().Link(context.Background(), &entity.Identity{
+			UserID:   hashids.ID(7),
+			Provider: entity.ProviderGoogle,
+			Subject:  user.GoogleID,
+			Email:    user.Email,
+		}).Return(nil)
+
+		res, err := exec.usecase.SignIn(context.Background(), user)
+
+		assert.Nil(t, err)
+		assert.Equal(t, stored, res)
+	})
+
+	t.Run("unverified email of another user is rejected", func(t *testing.T) {
+		exec := createSignInExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		user := createValidUser()
+		exec.repo.EXPECT().FindByIdentity(context.Background(), entity.ProviderGoogle, user.GoogleID).Return(nil, entity.ErrUserNotFound)
+		exec.repo.EXPECT().Insert(context.Background(), user).Return(nil, entity.ErrEmailAlreadyUsed)
+
+		res, err := exec.usecase.SignIn(context.Background(), user)
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrEmailAlreadyUsed, err)
+	})
+
+	t.Run("successfully register a separate user for the identity whose email is unverified", func(t *testing.T) {
+		exec := createSignInExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		user := createValidUser()
+		stored := createValidUser()
+		stored.ID = hashids.ID(8)
+		exec.repo.EXPECT().FindByIdentity(context.Background(), entity.ProviderGoogle, user.GoogleID).Return(nil, entity.ErrUserNotFound)
+		exec.repo.EXPECT().Insert(context.Background(), user).Return(stored, nil)
+		exec.identities.EXPECT().Link(context.Background(), &entity.Identity{
+			UserID:   hashids.ID(8),
+			Provider: entity.ProviderGoogle,
+			Subject:  user.GoogleID,
+			Email:    user.Email,
+		}).Return(nil)
+
+		res, err := exec.usecase.SignIn(context.Background(), user)
+
+		assert.Nil(t, err)
+		assert.Equal(t, stored, res)
+	})
+}
+
+func TestSigner_Resolve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("user is empty/nil", func(t *testing.T) {
+		exec := createSignInExecutor(ctrl)
+
+		res, err := exec.usecase.Resolve(context.Background(), nil)
+
+		assert.Nil(t, res)
+		assert.Equal(t, entity.ErrEmptyUser, err)
+	})
+
+	t.Run("successfully resolve the user of the identity", func(t *testing.T) {
 		exec := createSignInExecutor(ctrl)
 
 		user := createValidUser()
-		exec.repo.EXPECT().InsertOrIgnore(context.Background(), user).Return(nil)
+		stored := createValidUser()
+		stored.ID = hashids.ID(7)
+		exec.repo.EXPECT().FindByIdentity(context.Background(), entity.ProviderGoogle, user.GoogleID).Return(stored, nil)
 
-		err := exec.usecase.SignIn(context.Background(), user)
+		res, err := exec.usecase.Resolve(context.Background(), user)
 
 		assert.Nil(t, err)
+		assert.Equal(t, hashids.ID(7), res.ID)
 	})
 }
 
@@ -74,11 +231,15 @@ func createValidUser() *entity.User {
 }
 
 func createSignInExecutor(ctrl *gomock.Controller) *SignInExecutor {
-	r := mock_usecase.NewMockInsertUserRepository(ctrl)
-	u := usecase.NewSigner(r)
+	r := mock_usecase.NewMockSignInRepository(ctrl)
+	i := mock_usecase.NewMockLinkIdentityRepository(ctrl)
+	tx := mock_usecase.NewMockTransactor(ctrl)
+	u := usecase.NewSigner(r, i, tx)
 
 	return &SignInExecutor{
-		usecase: u,
-		repo:    r,
+		usecase:    u,
+		repo:       r,
+		identities: i,
+		transactor: tx,
 	}
 }
@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// Transactor defines the contract to run a unit of work atomically.
+type Transactor interface {
+	// WithinTransaction runs fn as a single unit of work.
+	// All repositories called using the context given to fn take part in the same transaction.
+	// The work is committed if fn returns nil, otherwise it is rolled back.
+	// fn may be run more than once, so it must not have side effects outside the repositories.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) *entity.Error) *entity.Error
+}
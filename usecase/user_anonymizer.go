@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// AnonymizeUser defines the business logic
+// to remove the identity of a user while keeping the data they have written.
+type AnonymizeUser interface {
+	// Anonymize replaces the identity of the user who has the email with an alias
+	// and returns the alias.
+	Anonymize(ctx context.Context, email string) (string, *entity.Error)
+}
+
+// AnonymizeUserRepository defines the business logic
+// to replace the identity of a user in a repository.
+type AnonymizeUserRepository interface {
+	// Anonymize replaces the name, email, and Google ID of the user who has the id with the alias,
+	// and deletes the profile, the identities, and the email change request of the user.
+	// The medical records stay with the user, while the audit actor fields which refer to the email are replaced.
+	// It MUST return entity.ErrUserNotFound if the user doesn't exist.
+	Anonymize(ctx context.Context, userID uint64, email, alias string) *entity.Error
+}
+
+// UserAnonymizer responsibles for user anonymization workflow.
+type UserAnonymizer struct {
+	repo       AnonymizeUserRepository
+	users      FindUserRepository
+	transactor Transactor
+}
+
+// NewUserAnonymizer creates an instance of UserAnonymizer.
+func NewUserAnonymizer(repo AnonymizeUserRepository, users FindUserRepository, transactor Transactor) *UserAnonymizer {
+	return &UserAnonymizer{
+		repo:       repo,
+		users:      users,
+		transactor: transactor,
+	}
+}
+
+// Anonymize replaces the identity of the user with alias "anonymized-<user id>@invalid".
+// The alias can't be traced back to the email, yet it stays unique and is a syntactically valid email,
+// so the data keeps satisfying the rules of the repository.
+// The lookup and the replacement are run in a single unit of work.
+func (ua *UserAnonymizer) Anonymize(ctx context.Context, email string) (string, *entity.Error) {
+	if !emailRegex.MatchString(email) {
+		return "", entity.ErrInvalidEmail
+	}
+
+	var alias string
+	err := ua.transactor.WithinTransaction(ctx, func(ctx context.Context) *entity.Error {
+		user, err := ua.users.FindByEmail(ctx, email)
+		if err != nil {
+			return err
+		}
+
+		alias = fmt.Sprintf("anonymized-%d@invalid", uint64(user.ID))
+		return ua.repo.Anonymize(ctx, uint64(user.ID), user.Email, alias)
+	})
+	if err != nil {
+		return "", err
+	}
+	return alias, nil
+}
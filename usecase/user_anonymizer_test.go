@@ -0,0 +1,92 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type UserAnonymizerExecutor struct {
+	usecase    *usecase.UserAnonymizer
+	repo       *mock_usecase.MockAnonymizeUserRepository
+	users      *mock_usecase.MockFindUserRepository
+	transactor *mock_usecase.MockTransactor
+}
+
+func TestNewUserAnonymizer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of UserAnonymizer", func(t *testing.T) {
+		exec := createUserAnonymizerExecutor(ctrl)
+		assert.NotNil(t, exec.usecase)
+	})
+}
+
+func TestUserAnonymizer_Anonymize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("email is invalid", func(t *testing.T) {
+		exec := createUserAnonymizerExecutor(ctrl)
+
+		alias, err := exec.usecase.Anonymize(context.Background(), "invalid-email")
+
+		assert.Equal(t, entity.ErrInvalidEmail, err)
+		assert.Empty(t, alias)
+	})
+
+	t.Run("user is not found", func(t *testing.T) {
+		exec := createUserAnonymizerExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.users.EXPECT().FindByEmail(gomock.Any(), "a@orvosi.com").Return(nil, entity.ErrUserNotFound)
+		alias, err := exec.usecase.Anonymize(context.Background(), "a@orvosi.com")
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		assert.Empty(t, alias)
+	})
+
+	t.Run("repository returns error", func(t *testing.T) {
+		exec := createUserAnonymizerExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.users.EXPECT().FindByEmail(gomock.Any(), "a@orvosi.com").Return(&entity.User{ID: 7, Email: "a@orvosi.com"}, nil)
+		exec.repo.EXPECT().Anonymize(gomock.Any(), uint64(7), "a@orvosi.com", "anonymized-7@invalid").Return(entity.ErrInternalServer)
+		alias, err := exec.usecase.Anonymize(context.Background(), "a@orvosi.com")
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Empty(t, alias)
+	})
+
+	t.Run("successfully anonymize user", func(t *testing.T) {
+		exec := createUserAnonymizerExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.users.EXPECT().FindByEmail(gomock.Any(), "a@orvosi.com").Return(&entity.User{ID: 7, Email: "a@orvosi.com"}, nil)
+		exec.repo.EXPECT().Anonymize(gomock.Any(), uint64(7), "a@orvosi.com", "anonymized-7@invalid").Return(nil)
+		alias, err := exec.usecase.Anonymize(context.Background(), "a@orvosi.com")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "anonymized-7@invalid", alias)
+	})
+}
+
+func createUserAnonymizerExecutor(ctrl *gomock.Controller) *UserAnonymizerExecutor {
+	r := mock_usecase.NewMockAnonymizeUserRepository(ctrl)
+	ur := mock_usecase.NewMockFindUserRepository(ctrl)
+	tx := mock_usecase.NewMockTransactor(ctrl)
+	u := usecase.NewUserAnonymizer(r, ur, tx)
+
+	return &UserAnonymizerExecutor{
+		usecase:    u,
+		repo:       r,
+		users:      ur,
+		transactor: tx,
+	}
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+const (
+	// exportPageSize is the number of medical records read at once while exporting.
+	exportPageSize = uint(100)
+	// maxRecordID is the starting point to read medical records from the newest one.
+	maxRecordID = uint64(1<<63 - 1)
+)
+
+// ExportUserData defines the business logic
+// to collect everything stored about a user.
+type ExportUserData interface {
+	// Export collects the user, their medical records, the metadata of their attachments, their identities,
+	// and their pending erasure and email change.
+	Export(ctx context.Context, email string) (*entity.UserData, *entity.Error)
+}
+
+// UserDataExporter responsibles for user data export workflow.
+type UserDataExporter struct {
+	users       FindUserRepository
+	records     FindMedicalRecordRepository
+	attachments FindAttachmentRepository
+	erasures    FindErasureRepository
+	identities  FindIdentityRepository
+	changes     FindEmailChangeRepository
+}
+
+// NewUserDataExporter creates an instance of UserDataExporter.
+func NewUserDataExporter(users FindUserRepository, records FindMedicalRecordRepository, attachments FindAttachmentRepository, erasures FindErasureRepository, identities FindIdentityRepository, changes FindEmailChangeRepository) *UserDataExporter {
+	return &UserDataExporter{
+		users:       users,
+		records:     records,
+		attachments: attachments,
+		erasures:    erasures,
+		identities:  identities,
+		changes:     changes,
+	}
+}
+
+// Export collects the user, all of their medical records ordered by the newest creation time,
+// the metadata of the attachments of those records, the identities of the user,
+// and the pending erasure and email change, if any.
+// It returns entity.ErrUserNotFound if the email doesn't belong to a registered user.
+func (ue *UserDataExporter) Export(ctx context.Context, email string) (*entity.UserData, *entity.Error) {
+	if !emailRegex.MatchString(email) {
+		return nil, entity.ErrInvalidEmail
+	}
+
+	user, err := ue.users.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &entity.UserData{
+		User:           user,
+		MedicalRecords: []*entity.MedicalRecord{},
+		Attachments:    []*entity.Attachment{},
+	}
+	data.Erasure, err = ue.erasures.FindByUserID(ctx, uint64(user.ID))
+	if err != nil && err.Code == entity.ErrErasureNotScheduled.Code {
+		data.Erasure, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data.Identities, err = ue.identities.FindByUserID(ctx, uint64(user.ID))
+	if err != nil {
+		return nil, err
+	}
+	data.EmailChange, err = ue.changes.FindByUserID(ctx, uint64(user.ID))
+	if err != nil && err.Code == entity.ErrInvalidEmailChangeToken.Code {
+		data.EmailChange, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for from := maxRecordID; ; {
+		records, err := ue.records.FindByUserID(ctx, uint64(user.ID), from, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			attachments, err := ue.attachments.FindByMedicalRecordID(ctx, uint64(record.ID))
+			if err != nil {
+				return nil, err
+			}
+			data.Attachments = append(data.Attachments, attachments...)
+		}
+		data.MedicalRecords = append(data.MedicalRecords, records...)
+
+		if uint(len(records)) < exportPageSize {
+			return data, nil
+		}
+		from = uint64(records[len(records)-1].ID)
+	}
+}
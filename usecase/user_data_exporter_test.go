@@ -0,0 +1,194 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/hashids"
+	"github.com/indrasaputra/orvosi-api/entity"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type UserDataExporterExecutor struct {
+	usecase     *usecase.UserDataExporter
+	users       *mock_usecase.MockFindUserRepository
+	records     *mock_usecase.MockFindMedicalRecordRepository
+	attachments *mock_usecase.MockFindAttachmentRepository
+	erasures    *mock_usecase.MockFindErasureRepository
+	identities  *mock_usecase.MockFindIdentityRepository
+	changes     *mock_usecase.MockFindEmailChangeRepository
+}
+
+func TestNewUserDataExporter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of UserDataExporter", func(t *testing.T) {
+		exec := createUserDataExporterExecutor(ctrl)
+		assert.NotNil(t, exec.usecase)
+	})
+}
+
+func TestUserDataExporter_Export(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const maxID = uint64(1<<63 - 1)
+	user := &entity.User{ID: 1, Email: "a@orvosi.com"}
+
+	t.Run("email is invalid", func(t *testing.T) {
+		exec := createUserDataExporterExecutor(ctrl)
+
+		res, err := exec.usecase.Export(context.Background(), "invalid-email")
+
+		assert.Equal(t, entity.ErrInvalidEmail, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("user is not found", func(t *testing.T) {
+		exec := createUserDataExporterExecutor(ctrl)
+
+		exec.users.EXPECT().FindByEmail(context.Background(), "a@orvosi.com").Return(nil, entity.ErrUserNotFound)
+		res, err := exec.usecase.Export(context.Background(), "a@orvosi.com")
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("erasure repository returns error", func(t *testing.T) {
+		exec := createUserDataExporterExecutor(ctrl)
+
+		exec.users.EXPECT().FindByEmail(context.Background(), "a@orvosi.com").Return(user, nil)
+		exec.erasures.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return(nil, entity.ErrInternalServer)
+		res, err := exec.usecase.Export(context.Background(), "a@orvosi.com")
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("identity repository returns error", func(t *testing.T) {
+		exec := createUserDataExporterExecutor(ctrl)
+
+		exec.users.EXPECT().FindByEmail(context.Background(), "a@orvosi.com").Return(user, nil)
+		exec.erasures.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return(nil, entity.ErrErasureNotScheduled)
+		exec.identities.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return(nil, entity.ErrInternalServer)
+		res, err := exec.usecase.Export(context.Background(), "a@orvosi.com")
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("email change repository returns error", func(t *testing.T) {
+		exec := createUserDataExporterExecutor(ctrl)
+
+		exec.users.EXPECT().FindByEmail(context.Background(), "a@orvosi.com").Return(user, nil)
+		exec.erasures.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return(nil, entity.ErrErasureNotScheduled)
+		exec.identities.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return([]*entity.Identity{}, nil)
+		exec.changes.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return(nil, entity.ErrInternalServer)
+		res, err := exec.usecase.Export(context.Background(), "a@orvosi.com")
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("medical record repository returns error", func(t *testing.T) {
+		exec := createUserDataExporterExecutor(ctrl)
+
+		exec.users.EXPECT().FindByEmail(context.Background(), "a@orvosi.com").Return(user, nil)
+		exec.erasures.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return(nil, entity.ErrErasureNotScheduled)
+		exec.identities.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return([]*entity.Identity{}, nil)
+		exec.changes.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return(nil, entity.ErrInvalidEmailChangeToken)
+		exec.records.EXPECT().FindByUserID(context.Background(), uint64(1), maxID, uint(100)).Return([]*entity.MedicalRecord{}, entity.ErrInternalServer)
+		res, err := exec.usecase.Export(context.Background(), "a@orvosi.com")
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("attachment repository returns error", func(t *testing.T) {
+		exec := createUserDataExporterExecutor(ctrl)
+
+		exec.users.EXPECT().FindByEmail(context.Background(), "a@orvosi.com").Return(user, nil)
+		exec.erasures.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return(nil, entity.ErrErasureNotScheduled)
+		exec.identities.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return([]*entity.Identity{}, nil)
+		exec.changes.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return(nil, entity.ErrInvalidEmailChangeToken)
+		exec.records.EXPECT().FindByUserID(context.Background(), uint64(1), maxID, uint(100)).Return([]*entity.MedicalRecord{{ID: 3}}, nil)
+		exec.attachments.EXPECT().FindByMedicalRecordID(context.Background(), uint64(3)).Return(nil, entity.ErrInternalServer)
+		res, err := exec.usecase.Export(context.Background(), "a@orvosi.com")
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("user has no medical record", func(t *testing.T) {
+		exec := createUserDataExporterExecutor(ctrl)
+
+		exec.users.EXPECT().FindByEmail(context.Background(), "a@orvosi.com").Return(user, nil)
+		exec.erasures.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return(nil, entity.ErrErasureNotScheduled)
+		exec.identities.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return([]*entity.Identity{}, nil)
+		exec.changes.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return(nil, entity.ErrInvalidEmailChangeToken)
+		exec.records.EXPECT().FindByUserID(context.Background(), uint64(1), maxID, uint(100)).Return([]*entity.MedicalRecord{}, nil)
+		res, err := exec.usecase.Export(context.Background(), "a@orvosi.com")
+
+		assert.Nil(t, err)
+		assert.Equal(t, user, res.User)
+		assert.Empty(t, res.MedicalRecords)
+		assert.Empty(t, res.Attachments)
+		assert.Nil(t, res.Erasure)
+		assert.Nil(t, res.EmailChange)
+	})
+
+	t.Run("successfully export all pages", func(t *testing.T) {
+		exec := createUserDataExporterExecutor(ctrl)
+
+		var first []*entity.MedicalRecord
+		for id := 150; id > 50; id-- {
+			first = append(first, &entity.MedicalRecord{ID: hashids.ID(id)})
+		}
+		second := []*entity.MedicalRecord{{ID: 50}}
+
+		erasure := &entity.Erasure{UserID: 1, Email: "a@orvosi.com"}
+		exec.users.EXPECT().FindByEmail(context.Background(), "a@orvosi.com").Return(user, nil)
+		identities := []*entity.Identity{{ID: 4, UserID: 1, Provider: entity.ProviderGoogle, Subject: "google-id"}}
+		change := &entity.EmailChange{UserID: 1, Email: "b@orvosi.com"}
+		exec.erasures.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return(erasure, nil)
+		exec.identities.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return(identities, nil)
+		exec.changes.EXPECT().FindByUserID(context.Background(), uint64(user.ID)).Return(change, nil)
+		exec.records.EXPECT().FindByUserID(context.Background(), uint64(1), maxID, uint(100)).Return(first, nil)
+		exec.records.EXPECT().FindByUserID(context.Background(), uint64(1), uint64(51), uint(100)).Return(second, nil)
+		exec.attachments.EXPECT().FindByMedicalRecordID(context.Background(), gomock.Any()).Return([]*entity.Attachment{}, nil).Times(100)
+		exec.attachments.EXPECT().FindByMedicalRecordID(context.Background(), uint64(50)).Return([]*entity.Attachment{{ID: 9, MedicalRecordID: 50}}, nil)
+		res, err := exec.usecase.Export(context.Background(), "a@orvosi.com")
+
+		assert.Nil(t, err)
+		assert.Equal(t, user, res.User)
+		assert.Len(t, res.MedicalRecords, 101)
+		assert.Equal(t, []*entity.Attachment{{ID: 9, MedicalRecordID: 50}}, res.Attachments)
+		assert.Equal(t, erasure, res.Erasure)
+		assert.Equal(t, identities, res.Identities)
+		assert.Equal(t, change, res.EmailChange)
+	})
+}
+
+func createUserDataExporterExecutor(ctrl *gomock.Controller) *UserDataExporterExecutor {
+	ur := mock_usecase.NewMockFindUserRepository(ctrl)
+	mr := mock_usecase.NewMockFindMedicalRecordRepository(ctrl)
+	ar := mock_usecase.NewMockFindAttachmentRepository(ctrl)
+	er := mock_usecase.NewMockFindErasureRepository(ctrl)
+	ir := mock_usecase.NewMockFindIdentityRepository(ctrl)
+	cr := mock_usecase.NewMockFindEmailChangeRepository(ctrl)
+	u := usecase.NewUserDataExporter(ur, mr, ar, er, ir, cr)
+
+	return &UserDataExporterExecutor{
+		usecase:     u,
+		users:       ur,
+		records:     mr,
+		attachments: ar,
+		erasures:    er,
+		identities:  ir,
+		changes:     cr,
+	}
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// EraseUser defines the business logic
+// to erase all data of a user on their request.
+type EraseUser interface {
+	// Schedule schedules the erasure of the data of the user who has the id after the grace period.
+	// If the erasure has been scheduled, the existing schedule is kept and returned.
+	Schedule(ctx context.Context, userID uint64) (*entity.Erasure, *entity.Error)
+	// Find finds the scheduled erasure of the user who has the id.
+	Find(ctx context.Context, userID uint64) (*entity.Erasure, *entity.Error)
+	// Cancel cancels the scheduled erasure of the user who has the id.
+	Cancel(ctx context.Context, userID uint64) *entity.Error
+	// EraseDue erases the data of at most limit users whose grace period has passed
+	// and returns the number of erased users.
+	EraseDue(ctx context.Context, limit uint) (int, *entity.Error)
+}
+
+// FindErasureRepository defines the business logic
+// to find an erasure in a repository.
+type FindErasureRepository interface {
+	// FindByUserID finds the scheduled erasure of the user who has the id.
+	// It MUST return entity.ErrErasureNotScheduled if there is none.
+	FindByUserID(ctx context.Context, userID uint64) (*entity.Erasure, *entity.Error)
+}
+
+// EraseUserRepository defines the business logic
+// to schedule and run the erasure of a user in a repository.
+type EraseUserRepository interface {
+	FindErasureRepository
+	// Schedule inserts the erasure of the user who has the id unless it exists,
+	// then returns the erasure which is kept.
+	// It MUST return entity.ErrUserNotFound if the user doesn't exist.
+	Schedule(ctx context.Context, userID uint64, requestedAt, eraseAfter time.Time) (*entity.Erasure, *entity.Error)
+	// Cancel deletes the erasure of the user who has the id.
+	// It MUST return entity.ErrErasureNotScheduled if there is none.
+	Cancel(ctx context.Context, userID uint64) *entity.Error
+	// FindDue finds at most limit erasures whose EraseAfter is not after now, ordered by EraseAfter.
+	// The erasures being erased by another unit of work may be skipped.
+	FindDue(ctx context.Context, now time.Time, limit uint) ([]*entity.Erasure, *entity.Error)
+	// Claim locks the erasure of the user until the end of the unit of work if it is still due at now,
+	// and returns it along with the current email of the user.
+	// It MUST return entity.ErrErasureNotScheduled if the erasure has been cancelled, isn't due,
+	// or is being erased by another unit of work.
+	Claim(ctx context.Context, userID uint64, now time.Time) (*entity.Erasure, *entity.Error)
+	// Erase deletes the user who has the id, their erasure, their medical records, and the attachments of those records,
+	// then replaces the email in the audit actor fields of the remaining data with the alias.
+	// The storage keys of the deleted attachments MUST be queued for deletion in the same unit of work,
+	// see StorageDeletionRepository, and returned.
+	Erase(ctx context.Context, userID uint64, email, alias string) ([]string, *entity.Error)
+}
+
+// UserEraser responsibles for user erasure workflow.
+type UserEraser struct {
+	repo        EraseUserRepository
+	deletions   StorageDeletionRepository
+	storage     AttachmentStorage
+	transactor  Transactor
+	gracePeriod time.Duration
+}
+
+// NewUserEraser creates an instance of UserEraser.
+// The data is erased once gracePeriod has passed since the erasure is scheduled.
+func NewUserEraser(repo EraseUserRepository, deletions StorageDeletionRepository, storage AttachmentStorage, transactor Transactor, gracePeriod time.Duration) *UserEraser {
+	return &UserEraser{
+		repo:        repo,
+		deletions:   deletions,
+		storage:     storage,
+		transactor:  transactor,
+		gracePeriod: gracePeriod,
+	}
+}
+
+// Schedule schedules the erasure of the data of the user after the grace period.
+// Scheduling it again doesn't postpone the erasure.
+func (ue *UserEraser) Schedule(ctx context.Context, userID uint64) (*entity.Erasure, *entity.Error) {
+	now := time.Now().UTC()
+	return ue.repo.Schedule(ctx, userID, now, now.Add(ue.gracePeriod))
+}
+
+// Find finds the scheduled erasure of the user.
+// It returns entity.ErrErasureNotScheduled if there is none.
+func (ue *UserEraser) Find(ctx context.Context, userID uint64) (*entity.Erasure, *entity.Error) {
+	return ue.repo.FindByUserID(ctx, userID)
+}
+
+// Cancel cancels the scheduled erasure of the user.
+// It returns entity.ErrErasureNotScheduled if there is none, including when the data has been erased.
+func (ue *UserEraser) Cancel(ctx context.Context, userID uint64) *entity.Error {
+	return ue.repo.Cancel(ctx, userID)
+}
+
+// EraseDue erases the data of the users whose grace period has passed, one user per unit of work.
+// Each erasure is claimed again inside its unit of work, so an erasure which is cancelled after it is found
+// or is taken by another worker in the meantime is skipped instead of erased.
+// The email left in the audit actor fields of other users' data is replaced with "erased-<user id>@invalid",
+// which can't be traced back to the email.
+// The content of the attachments is deleted after their metadata is committed.
+// The storage keys stay queued until their content is deleted, so the content which failed to be deleted earlier,
+// at most limit keys of it, is deleted again before the due erasures run.
+// It stops at the first failure and returns the number of users erased before it.
+func (ue *UserEraser) EraseDue(ctx context.Context, limit uint) (int, *entity.Error) {
+	pending, err := ue.deletions.FindPending(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+	if err := deleteContents(ctx, ue.storage, ue.deletions, pending); err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	due, err := ue.repo.FindDue(ctx, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, erasure := range due {
+		erased, err := ue.erase(ctx, uint64(erasure.UserID), now)
+		if err != nil {
+			return n, err
+		}
+		if erased {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (ue *UserEraser) erase(ctx context.Context, userID uint64, now time.Time) (bool, *entity.Error) {
+	alias := fmt.Sprintf("erased-%d@invalid", userID)
+
+	var keys []string
+	erased := false
+	err := ue.transactor.WithinTransaction(ctx, func(ctx context.Context) *entity.Error {
+		erasure, err := ue.repo.Claim(ctx, userID, now)
+		if err == entity.ErrErasureNotScheduled {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		keys, err = ue.repo.Erase(ctx, userID, erasure.Email, alias)
+		erased = err == nil
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if err := deleteContents(ctx, ue.storage, ue.deletions, keys); err != nil {
+		return false, err
+	}
+	return erased, nil
+}
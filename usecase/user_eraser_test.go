@@ -0,0 +1,268 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+const gracePeriod = 72 * time.Hour
+
+type UserEraserExecutor struct {
+	usecase    *usecase.UserEraser
+	repo       *mock_usecase.MockEraseUserRepository
+	deletions  *mock_usecase.MockStorageDeletionRepository
+	storage    *mock_usecase.MockAttachmentStorage
+	transactor *mock_usecase.MockTransactor
+}
+
+func TestNewUserEraser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of UserEraser", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+		assert.NotNil(t, exec.usecase)
+	})
+}
+
+func TestUserEraser_Schedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("user is not found", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+
+		exec.repo.EXPECT().Schedule(context.Background(), uint64(1), gomock.Any(), gomock.Any()).Return(nil, entity.ErrUserNotFound)
+		res, err := exec.usecase.Schedule(context.Background(), 1)
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("successfully schedule erasure after the grace period", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+
+		exec.repo.EXPECT().Schedule(context.Background(), uint64(1), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, userID uint64, requestedAt, eraseAfter time.Time) (*entity.Erasure, *entity.Error) {
+				assert.Equal(t, gracePeriod, eraseAfter.Sub(requestedAt))
+				assert.WithinDuration(t, time.Now(), requestedAt, time.Minute)
+				return &entity.Erasure{UserID: 1, Email: "a@orvosi.com", RequestedAt: requestedAt, EraseAfter: eraseAfter}, nil
+			})
+		res, err := exec.usecase.Schedule(context.Background(), 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "a@orvosi.com", res.Email)
+	})
+}
+
+func TestUserEraser_Find(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully find erasure", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+		erasure := &entity.Erasure{UserID: 1, Email: "a@orvosi.com"}
+
+		exec.repo.EXPECT().FindByUserID(context.Background(), uint64(1)).Return(erasure, nil)
+		res, err := exec.usecase.Find(context.Background(), 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, erasure, res)
+	})
+}
+
+func TestUserEraser_Cancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("erasure is not scheduled", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+
+		exec.repo.EXPECT().Cancel(context.Background(), uint64(1)).Return(entity.ErrErasureNotScheduled)
+		err := exec.usecase.Cancel(context.Background(), 1)
+
+		assert.Equal(t, entity.ErrErasureNotScheduled, err)
+	})
+
+	t.Run("successfully cancel erasure", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+
+		exec.repo.EXPECT().Cancel(context.Background(), uint64(1)).Return(nil)
+		err := exec.usecase.Cancel(context.Background(), 1)
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestUserEraser_EraseDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	due := []*entity.Erasure{{UserID: 1, Email: "a@orvosi.com"}, {UserID: 2, Email: "b@orvosi.com"}}
+
+	t.Run("finding pending storage deletions returns error", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+
+		exec.deletions.EXPECT().FindPending(context.Background(), uint(10)).Return([]string{}, entity.ErrInternalServer)
+		n, err := exec.usecase.EraseDue(context.Background(), 10)
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("pending content which fails to be deleted again stays queued", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+
+		exec.deletions.EXPECT().FindPending(context.Background(), uint(10)).Return([]string{"medical-records/1/a", "medical-records/1/b"}, nil)
+		exec.storage.EXPECT().Delete(context.Background(), "medical-records/1/a").Return(nil)
+		exec.deletions.EXPECT().Done(context.Background(), "medical-records/1/a").Return(nil)
+		exec.storage.EXPECT().Delete(context.Background(), "medical-records/1/b").Return(entity.ErrInternalServer)
+		n, err := exec.usecase.EraseDue(context.Background(), 10)
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("removing deleted content from the queue returns error", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.deletions.EXPECT().FindPending(context.Background(), uint(10)).Return([]string{}, nil)
+		exec.repo.EXPECT().FindDue(context.Background(), gomock.Any(), uint(10)).Return(due[:1], nil)
+		exec.repo.EXPECT().Claim(gomock.Any(), uint64(1), gomock.Any()).Return(due[0], nil)
+		exec.repo.EXPECT().Erase(gomock.Any(), uint64(1), "a@orvosi.com", "erased-1@invalid").Return([]string{"medical-records/1/key"}, nil)
+		exec.storage.EXPECT().Delete(context.Background(), "medical-records/1/key").Return(nil)
+		exec.deletions.EXPECT().Done(context.Background(), "medical-records/1/key").Return(entity.ErrInternalServer)
+		n, err := exec.usecase.EraseDue(context.Background(), 10)
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("successfully delete pending content before erasing due users", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+
+		exec.deletions.EXPECT().FindPending(context.Background(), uint(10)).Return([]string{"medical-records/1/a"}, nil)
+		exec.storage.EXPECT().Delete(context.Background(), "medical-records/1/a").Return(nil)
+		exec.deletions.EXPECT().Done(context.Background(), "medical-records/1/a").Return(nil)
+		exec.repo.EXPECT().FindDue(context.Background(), gomock.Any(), uint(10)).Return([]*entity.Erasure{}, nil)
+		n, err := exec.usecase.EraseDue(context.Background(), 10)
+
+		assert.Nil(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("finding due erasures returns error", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+
+		exec.deletions.EXPECT().FindPending(context.Background(), uint(10)).Return([]string{}, nil)
+		exec.repo.EXPECT().FindDue(context.Background(), gomock.Any(), uint(10)).Return(nil, entity.ErrInternalServer)
+		n, err := exec.usecase.EraseDue(context.Background(), 10)
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("erasure stops at the first failure", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+		passThroughTransaction(exec.transactor)
+
+		exec.deletions.EXPECT().FindPending(context.Background(), uint(10)).Return([]string{}, nil)
+		exec.repo.EXPECT().FindDue(context.Background(), gomock.Any(), uint(10)).Return(due, nil)
+		exec.repo.EXPECT().Claim(gomock.Any(), uint64(1), gomock.Any()).Return(due[0], nil)
+		exec.repo.EXPECT().Erase(gomock.Any(), uint64(1), "a@orvosi.com", "erased-1@invalid").Return(nil, nil)
+		exec.repo.EXPECT().Claim(gomock.Any(), uint64(2), gomock.Any()).Return(due[1], nil)
+		exec.repo.EXPECT().Erase(gomock.Any(), uint64(2), "b@orvosi.com", "erased-2@invalid").Return(nil, entity.ErrInternalServer)
+		n, err := exec.usecase.EraseDue(context.Background(), 10)
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("claiming erasure returns error", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.deletions.EXPECT().FindPending(context.Background(), uint(10)).Return([]string{}, nil)
+		exec.repo.EXPECT().FindDue(context.Background(), gomock.Any(), uint(10)).Return(due[:1], nil)
+		exec.repo.EXPECT().Claim(gomock.Any(), uint64(1), gomock.Any()).Return(nil, entity.ErrInternalServer)
+		n, err := exec.usecase.EraseDue(context.Background(), 10)
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("erasure which is cancelled or taken after it is found is skipped", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+		passThroughTransaction(exec.transactor)
+
+		exec.deletions.EXPECT().FindPending(context.Background(), uint(10)).Return([]string{}, nil)
+		exec.repo.EXPECT().FindDue(context.Background(), gomock.Any(), uint(10)).Return(due, nil)
+		exec.repo.EXPECT().Claim(gomock.Any(), uint64(1), gomock.Any()).Return(nil, entity.ErrErasureNotScheduled)
+		exec.repo.EXPECT().Claim(gomock.Any(), uint64(2), gomock.Any()).Return(due[1], nil)
+		exec.repo.EXPECT().Erase(gomock.Any(), uint64(2), "b@orvosi.com", "erased-2@invalid").Return(nil, nil)
+		n, err := exec.usecase.EraseDue(context.Background(), 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("storage returns error", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+
+		exec.deletions.EXPECT().FindPending(context.Background(), uint(10)).Return([]string{}, nil)
+		exec.repo.EXPECT().FindDue(context.Background(), gomock.Any(), uint(10)).Return(due[:1], nil)
+		exec.repo.EXPECT().Claim(gomock.Any(), uint64(1), gomock.Any()).Return(due[0], nil)
+		exec.repo.EXPECT().Erase(gomock.Any(), uint64(1), "a@orvosi.com", "erased-1@invalid").Return([]string{"medical-records/1/key"}, nil)
+		exec.storage.EXPECT().Delete(context.Background(), "medical-records/1/key").Return(entity.ErrInternalServer)
+		n, err := exec.usecase.EraseDue(context.Background(), 10)
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("successfully erase due users and their attachments", func(t *testing.T) {
+		exec := createUserEraserExecutor(ctrl)
+		passThroughTransaction(exec.transactor)
+		passThroughTransaction(exec.transactor)
+
+		exec.deletions.EXPECT().FindPending(context.Background(), uint(10)).Return([]string{}, nil)
+		exec.repo.EXPECT().FindDue(context.Background(), gomock.Any(), uint(10)).Return(due, nil)
+		exec.repo.EXPECT().Claim(gomock.Any(), uint64(1), gomock.Any()).Return(&entity.Erasure{UserID: 1, Email: "changed@orvosi.com"}, nil)
+		exec.repo.EXPECT().Erase(gomock.Any(), uint64(1), "changed@orvosi.com", "erased-1@invalid").Return([]string{"medical-records/1/key"}, nil)
+		exec.repo.EXPECT().Claim(gomock.Any(), uint64(2), gomock.Any()).Return(due[1], nil)
+		exec.repo.EXPECT().Erase(gomock.Any(), uint64(2), "b@orvosi.com", "erased-2@invalid").Return(nil, nil)
+		exec.storage.EXPECT().Delete(context.Background(), "medical-records/1/key").Return(nil)
+		exec.deletions.EXPECT().Done(context.Background(), "medical-records/1/key").Return(nil)
+		n, err := exec.usecase.EraseDue(context.Background(), 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, n)
+	})
+}
+
+func createUserEraserExecutor(ctrl *gomock.Controller) *UserEraserExecutor {
+	r := mock_usecase.NewMockEraseUserRepository(ctrl)
+	d := mock_usecase.NewMockStorageDeletionRepository(ctrl)
+	s := mock_usecase.NewMockAttachmentStorage(ctrl)
+	tx := mock_usecase.NewMockTransactor(ctrl)
+	u := usecase.NewUserEraser(r, d, s, tx, gracePeriod)
+
+	return &UserEraserExecutor{
+		usecase:    u,
+		repo:       r,
+		deletions:  d,
+		storage:    s,
+		transactor: tx,
+	}
+}
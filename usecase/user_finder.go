@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/indrasaputra/orvosi-api/entity"
+)
+
+// FindUser defines the business logic
+// to find users.
+type FindUser interface {
+	// FindAll finds at most limit users whose id is greater than from, ordered by id.
+	FindAll(ctx context.Context, from uint64, limit uint) ([]*entity.User, *entity.Error)
+	// FindByEmail finds the user who has the email.
+	FindByEmail(ctx context.Context, email string) (*entity.User, *entity.Error)
+}
+
+// FindUserRepository defines the business logic
+// to find users in a repository.
+type FindUserRepository interface {
+	// FindAll finds at most limit users whose id is greater than from, ordered by id.
+	FindAll(ctx context.Context, from uint64, limit uint) ([]*entity.User, *entity.Error)
+	// FindByEmail finds the user who has the email.
+	// It MUST return entity.ErrUserNotFound if the user doesn't exist.
+	FindByEmail(ctx context.Context, email string) (*entity.User, *entity.Error)
+}
+
+// UserFinder responsibles for user searching workflow.
+type UserFinder struct {
+	repo FindUserRepository
+}
+
+// NewUserFinder creates an instance of UserFinder.
+func NewUserFinder(repo FindUserRepository) *UserFinder {
+	return &UserFinder{
+		repo: repo,
+	}
+}
+
+// FindAll finds at most limit users whose id is greater than from, ordered by id.
+// Limit must be positive.
+func (uf *UserFinder) FindAll(ctx context.Context, from uint64, limit uint) ([]*entity.User, *entity.Error) {
+	if limit == 0 {
+		return []*entity.User{}, entity.ErrInvalidParam
+	}
+	return uf.repo.FindAll(ctx, from, limit)
+}
+
+// FindByEmail finds the user who has the email.
+func (uf *UserFinder) FindByEmail(ctx context.Context, email string) (*entity.User, *entity.Error) {
+	if !emailRegex.MatchString(email) {
+		return nil, entity.ErrInvalidEmail
+	}
+	return uf.repo.FindByEmail(ctx, email)
+}
@@ -0,0 +1,107 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/indrasaputra/orvosi-api/entity"
+	mock_usecase "github.com/indrasaputra/orvosi-api/test/mock/usecase"
+	"github.com/indrasaputra/orvosi-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type UserFinderExecutor struct {
+	usecase *usecase.UserFinder
+	repo    *mock_usecase.MockFindUserRepository
+}
+
+func TestNewUserFinder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("successfully create an instance of UserFinder", func(t *testing.T) {
+		exec := createUserFinderExecutor(ctrl)
+		assert.NotNil(t, exec.usecase)
+	})
+}
+
+func TestUserFinder_FindAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("limit is zero", func(t *testing.T) {
+		exec := createUserFinderExecutor(ctrl)
+
+		res, err := exec.usecase.FindAll(context.Background(), 0, 0)
+
+		assert.Equal(t, entity.ErrInvalidParam, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("repository returns error", func(t *testing.T) {
+		exec := createUserFinderExecutor(ctrl)
+
+		exec.repo.EXPECT().FindAll(context.Background(), uint64(0), uint(10)).Return([]*entity.User{}, entity.ErrInternalServer)
+		res, err := exec.usecase.FindAll(context.Background(), 0, 10)
+
+		assert.Equal(t, entity.ErrInternalServer, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("successfully find users", func(t *testing.T) {
+		exec := createUserFinderExecutor(ctrl)
+		users := []*entity.User{{ID: 1, Email: "a@orvosi.com"}, {ID: 2, Email: "b@orvosi.com"}}
+
+		exec.repo.EXPECT().FindAll(context.Background(), uint64(0), uint(10)).Return(users, nil)
+		res, err := exec.usecase.FindAll(context.Background(), 0, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, users, res)
+	})
+}
+
+func TestUserFinder_FindByEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("email is invalid", func(t *testing.T) {
+		exec := createUserFinderExecutor(ctrl)
+
+		res, err := exec.usecase.FindByEmail(context.Background(), "invalid-email")
+
+		assert.Equal(t, entity.ErrInvalidEmail, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("user is not found", func(t *testing.T) {
+		exec := createUserFinderExecutor(ctrl)
+
+		exec.repo.EXPECT().FindByEmail(context.Background(), "a@orvosi.com").Return(nil, entity.ErrUserNotFound)
+		res, err := exec.usecase.FindByEmail(context.Background(), "a@orvosi.com")
+
+		assert.Equal(t, entity.ErrUserNotFound, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("successfully find user", func(t *testing.T) {
+		exec := createUserFinderExecutor(ctrl)
+		user := &entity.User{ID: 1, Email: "a@orvosi.com"}
+
+		exec.repo.EXPECT().FindByEmail(context.Background(), "a@orvosi.com").Return(user, nil)
+		res, err := exec.usecase.FindByEmail(context.Background(), "a@orvosi.com")
+
+		assert.Nil(t, err)
+		assert.Equal(t, user, res)
+	})
+}
+
+func createUserFinderExecutor(ctrl *gomock.Controller) *UserFinderExecutor {
+	r := mock_usecase.NewMockFindUserRepository(ctrl)
+	u := usecase.NewUserFinder(r)
+
+	return &UserFinderExecutor{
+		usecase: u,
+		repo:    r,
+	}
+}